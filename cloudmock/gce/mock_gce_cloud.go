@@ -188,3 +188,8 @@ func (c *MockGCECloud) DeregisterInstance(i *cloudinstances.CloudInstance) error
 func (c *MockGCECloud) DetachInstance(i *cloudinstances.CloudInstance) error {
 	return nil
 }
+
+// RollbackGroup is not implemented yet. It needs to revert a MIG to the instance template of its instances needing update.
+func (c *MockGCECloud) RollbackGroup(group *cloudinstances.CloudInstanceGroup) error {
+	return nil
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/certinventory"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/vfs"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// certificateExpiry is the soonest expiry of a certificate in, or issued from, each keyset.
+var certificateExpiry = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "kops_certificate_expiration_timestamp_seconds",
+		Help: "Unix time at which the soonest expiring trusted certificate of the keyset, or leaf certificate issued from it on this node, expires.",
+	},
+	[]string{"keyset"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(certificateExpiry)
+}
+
+// NewCertificateReporter is the constructor for a CertificateReporter
+func NewCertificateReporter(mgr manager.Manager, vfsContext *vfs.VFSContext, secretStore string, inventoryDir string) (*CertificateReporter, error) {
+	r := &CertificateReporter{
+		inventoryDir: inventoryDir,
+		interval:     10 * time.Minute,
+	}
+
+	coreClient, err := corev1client.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return nil, fmt.Errorf("error building corev1 client: %v", err)
+	}
+	r.coreV1Client = coreClient
+
+	p, err := vfsContext.BuildVfsPath(secretStore)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse SecretStore %q: %w", secretStore, err)
+	}
+	r.keystore = fi.NewVFSCAStore(nil, p)
+
+	return r, nil
+}
+
+// CertificateReporter periodically reports the expiry of the cluster's keysets and of the
+// leaf certificates nodeup issued on this node. It exports the soonest expiry per keyset
+// as a metric and publishes the node's certificates in the certinventory.NodeAnnotation.
+// It runs on every control-plane node, because each node has its own leaf certificates.
+type CertificateReporter struct {
+	// coreV1Client is a client-go client for patching nodes
+	coreV1Client *corev1client.CoreV1Client
+
+	// keystore reads the keysets from the state store
+	keystore fi.CAStore
+
+	// inventoryDir is the directory in which nodeup records the certificates it issues
+	inventoryDir string
+
+	// interval is the time between reports
+	interval time.Duration
+}
+
+var _ manager.LeaderElectionRunnable = &CertificateReporter{}
+
+func (r *CertificateReporter) NeedLeaderElection() bool {
+	return false
+}
+
+func (r *CertificateReporter) Start(ctx context.Context) error {
+	wait.UntilWithContext(ctx, r.report, r.interval)
+	return nil
+}
+
+func (r *CertificateReporter) report(ctx context.Context) {
+	expiries := map[string]time.Time{}
+
+	keysets, err := r.keystore.ListKeysets()
+	if err != nil {
+		klog.Warningf("unable to list keysets: %v", err)
+	} else {
+		for name, keyset := range keysets {
+			for _, item := range keyset.Items {
+				if item.DistrustTimestamp != nil || item.Certificate == nil {
+					continue
+				}
+				recordExpiry(expiries, name, item.Certificate.Certificate.NotAfter)
+			}
+		}
+	}
+
+	certs, err := certinventory.Read(r.inventoryDir)
+	if err != nil {
+		klog.Warningf("unable to read certificate inventory: %v", err)
+	}
+	nodes := map[string][]*certinventory.Certificate{}
+	for _, cert := range certs {
+		if cert.Keyset != "" {
+			recordExpiry(expiries, cert.Keyset, cert.NotAfter)
+		}
+		if cert.Node != "" {
+			nodes[cert.Node] = append(nodes[cert.Node], cert)
+		}
+	}
+
+	certificateExpiry.Reset()
+	for keyset, expiry := range expiries {
+		certificateExpiry.WithLabelValues(keyset).Set(float64(expiry.Unix()))
+	}
+
+	for node, certs := range nodes {
+		if err := patchNodeCertificates(ctx, r.coreV1Client, node, certs); err != nil {
+			klog.Warningf("unable to publish certificates of node %q: %v", node, err)
+		}
+	}
+}
+
+// recordExpiry keeps the soonest expiry seen for a keyset.
+func recordExpiry(expiries map[string]time.Time, keyset string, notAfter time.Time) {
+	if existing, found := expiries[keyset]; !found || notAfter.Before(existing) {
+		expiries[keyset] = notAfter
+	}
+}
+
+// patchNodeCertificates sets the certinventory.NodeAnnotation on the node.
+func patchNodeCertificates(ctx context.Context, client *corev1client.CoreV1Client, nodeName string, certs []*certinventory.Certificate) error {
+	value, err := certinventory.EncodeAnnotation(certs)
+	if err != nil {
+		return err
+	}
+
+	nodePatch := &nodePatch{
+		Metadata: &nodePatchMetadata{
+			Annotations: map[string]*string{
+				certinventory.NodeAnnotation: &value,
+			},
+		},
+	}
+	nodePatchJson, err := json.Marshal(nodePatch)
+	if err != nil {
+		return fmt.Errorf("error building node patch: %v", err)
+	}
+
+	_, err = client.Nodes().Patch(ctx, nodeName, types.StrategicMergePatchType, nodePatchJson, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("error applying patch to node: %v", err)
+	}
+	return nil
+}
@@ -137,7 +137,8 @@ type nodePatch struct {
 }
 
 type nodePatchMetadata struct {
-	Labels map[string]*string `json:"labels,omitempty"`
+	Labels      map[string]*string `json:"labels,omitempty"`
+	Annotations map[string]*string `json:"annotations,omitempty"`
 }
 
 // patchNodeLabels patches the node labels to set the specified labels
@@ -21,6 +21,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
 
 	coordinationv1 "k8s.io/api/coordination/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -35,6 +36,9 @@ import (
 	"k8s.io/kops/pkg/apis/kops/v1alpha2"
 	"k8s.io/kops/pkg/bootstrap"
 	"k8s.io/kops/pkg/bootstrap/pkibootstrap"
+	"k8s.io/kops/pkg/bootstrap/tokenbootstrap"
+	"k8s.io/kops/pkg/bootstrap/tpmbootstrap"
+	"k8s.io/kops/pkg/certinventory"
 	"k8s.io/kops/pkg/nodeidentity"
 	nodeidentityaws "k8s.io/kops/pkg/nodeidentity/aws"
 	nodeidentityazure "k8s.io/kops/pkg/nodeidentity/azure"
@@ -187,6 +191,15 @@ func main() {
 			verifiers = append(verifiers, verifier)
 		}
 
+		uncachedClient, err := client.New(mgr.GetConfig(), client.Options{
+			Scheme: mgr.GetScheme(),
+			Mapper: mgr.GetRESTMapper(),
+		})
+		if err != nil {
+			setupLog.Error(err, "error creating uncached client")
+			os.Exit(1)
+		}
+
 		if opt.Server.PKI != nil {
 			verifier, err := pkibootstrap.NewVerifier(opt.Server.PKI, mgr.GetClient())
 			if err != nil {
@@ -195,20 +208,28 @@ func main() {
 			}
 			verifiers = append(verifiers, verifier)
 		}
+		if opt.Server.TPM != nil {
+			verifier, err := tpmbootstrap.NewVerifier(opt.Server.TPM, mgr.GetClient())
+			if err != nil {
+				setupLog.Error(err, "unable to create verifier")
+				os.Exit(1)
+			}
+			verifiers = append(verifiers, verifier)
+		}
+		if opt.Server.JoinToken != nil {
+			// Join tokens are secrets; read them without caching every secret of the cluster
+			verifier, err := tokenbootstrap.NewVerifier(opt.Server.JoinToken, uncachedClient)
+			if err != nil {
+				setupLog.Error(err, "unable to create verifier")
+				os.Exit(1)
+			}
+			verifiers = append(verifiers, verifier)
+		}
 
 		if len(verifiers) == 0 {
 			klog.Fatalf("server verifiers not provided")
 		}
 
-		uncachedClient, err := client.New(mgr.GetConfig(), client.Options{
-			Scheme: mgr.GetScheme(),
-			Mapper: mgr.GetRESTMapper(),
-		})
-		if err != nil {
-			setupLog.Error(err, "error creating uncached client")
-			os.Exit(1)
-		}
-
 		verifier := bootstrap.NewChainVerifier(verifiers...)
 
 		srv, err := server.NewServer(vfsContext, &opt, verifier, uncachedClient)
@@ -217,6 +238,15 @@ func main() {
 			os.Exit(1)
 		}
 		mgr.Add(srv)
+
+		// nodeup records the certificates it issues in a directory next to our PKI
+		inventoryDir := filepath.Join(opt.Server.CABasePath, filepath.Base(certinventory.Dir))
+		reporter, err := controllers.NewCertificateReporter(mgr, vfsContext, opt.SecretStore, inventoryDir)
+		if err != nil {
+			setupLog.Error(err, "unable to create certificate reporter")
+			os.Exit(1)
+		}
+		mgr.Add(reporter)
 	}
 
 	if opt.EnableCloudIPAM {
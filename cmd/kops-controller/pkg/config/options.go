@@ -18,6 +18,8 @@ package config
 
 import (
 	"k8s.io/kops/pkg/bootstrap/pkibootstrap"
+	"k8s.io/kops/pkg/bootstrap/tokenbootstrap"
+	"k8s.io/kops/pkg/bootstrap/tpmbootstrap"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/azure"
 	"k8s.io/kops/upup/pkg/fi/cloudup/do"
@@ -55,6 +57,12 @@ type ServerOptions struct {
 	// PKI configures private/public key node authentication.
 	PKI *pkibootstrap.Options `json:"pki,omitempty"`
 
+	// TPM configures TPM 2.0 attestation node authentication.
+	TPM *tpmbootstrap.Options `json:"tpm,omitempty"`
+
+	// JoinToken configures pre-shared join token node authentication.
+	JoinToken *tokenbootstrap.Options `json:"joinToken,omitempty"`
+
 	// ServerKeyPath is the path to our TLS serving private key.
 	ServerKeyPath string `json:"serverKeyPath,omitempty"`
 	// ServerCertificatePath is the path to our TLS serving certificate.
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"strings"
 
 	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/pki"
@@ -75,6 +76,20 @@ func (k *keystore) ListKeysets() (map[string]*fi.Keyset, error) {
 	return nil, fmt.Errorf("server-side client does not support ListKeysets")
 }
 
+// hostPKIDir is the directory on the host that is mounted at the base path of our PKI.
+const hostPKIDir = "/etc/kubernetes/kops-controller/"
+
+// remapExternalSignerTokenFile points an external signer at its token file inside our container,
+// if the token file is in the host directory that is mounted at basePath.
+func remapExternalSignerTokenFile(signer *pki.ExternalSigner, basePath string) {
+	tokenFile := signer.Config().TokenFile
+	if rel, ok := strings.CutPrefix(tokenFile, hostPKIDir); ok {
+		signer.SetTokenFile(path.Join(basePath, rel))
+	} else if tokenFile != "" {
+		klog.Warningf("external signer token file %q is not under %s, so may not be readable by kops-controller", tokenFile, hostPKIDir)
+	}
+}
+
 func newKeystore(basePath string, cas []string) (*keystore, map[string]string, error) {
 	keystore := &keystore{
 		keys:    map[string]keystoreEntry{},
@@ -99,6 +114,9 @@ func newKeystore(basePath string, cas []string) (*keystore, map[string]string, e
 		if err != nil {
 			return nil, nil, fmt.Errorf("parsing %q key: %v", name, err)
 		}
+		if signer, ok := key.Key.(*pki.ExternalSigner); ok {
+			remapExternalSignerTokenFile(signer, basePath)
+		}
 
 		keystore.keys[name] = keystoreEntry{
 			certificate: certificate,
@@ -0,0 +1,204 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/nodeup"
+	"k8s.io/kops/pkg/bootstrap"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/pkg/rbac"
+)
+
+// renew issues new certificates to a node that authenticates with the kubelet client certificate it was issued,
+// so that long-lived nodes can renew their certificates before they expire, without being replaced.
+// Unlike bootstrap, it is for nodes that are already registered; deleting the node revokes its ability to renew.
+func (s *Server) renew(w http.ResponseWriter, r *http.Request) {
+	if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		klog.Infof("renew %s no client certificate", r.RemoteAddr)
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte("client certificate required"))
+		return
+	}
+
+	nodeName, err := s.verifyNodeCertificate(r.TLS.PeerCertificates)
+	if err != nil {
+		klog.Infof("renew %s verify err: %v", r.RemoteAddr, err)
+		w.WriteHeader(http.StatusForbidden)
+		// don't return the error; this allows us to have richer errors without security implications
+		_, _ = w.Write([]byte("failed to verify client certificate"))
+		return
+	}
+
+	ctx := r.Context()
+
+	node := &corev1.Node{}
+	if err := s.uncachedClient.Get(ctx, types.NamespacedName{Name: nodeName}, node); err != nil {
+		if errors.IsNotFound(err) {
+			klog.Infof("renew %s node %q not found", r.RemoteAddr, nodeName)
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte("node not registered"))
+			return
+		}
+		klog.Infof("renew %s error querying for node %q: %v", r.RemoteAddr, nodeName, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("internal error"))
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		klog.Infof("renew %s read err: %v", r.RemoteAddr, err)
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(fmt.Sprintf("renew %s failed to read body: %v", r.RemoteAddr, err)))
+		return
+	}
+
+	req := &nodeup.RenewRequest{}
+	if err := json.Unmarshal(body, req); err != nil {
+		klog.Infof("renew %s decode err: %v", r.RemoteAddr, err)
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(fmt.Sprintf("failed to decode: %v", err)))
+		return
+	}
+
+	if req.APIVersion != nodeup.BootstrapAPIVersion {
+		klog.Infof("renew %s wrong APIVersion", r.RemoteAddr)
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("unexpected APIVersion"))
+		return
+	}
+
+	id := &bootstrap.VerifyResult{
+		NodeName: nodeName,
+	}
+	if _, ok := req.Certs["kubelet-server"]; ok {
+		// The names were checked when the node bootstrapped, so we keep the names of the current serving certificate.
+		names, err := s.verifyKubeletServerCertificate(nodeName, req.KubeletServerCertificate)
+		if err != nil {
+			klog.Infof("renew %s kubelet-server certificate err: %v", r.RemoteAddr, err)
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte("failed to verify kubelet-server certificate"))
+			return
+		}
+		id.CertificateNames = names
+	}
+
+	resp := &nodeup.BootstrapResponse{
+		Certs: map[string]string{},
+	}
+
+	validHours := certificateValidHours(nodeName)
+	for name, pubKey := range req.Certs {
+		cert, err := s.issueCert(ctx, name, pubKey, id, validHours, req.KeypairIDs)
+		if err != nil {
+			klog.Infof("renew %s cert %q issue err: %v", r.RemoteAddr, name, err)
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(fmt.Sprintf("failed to issue %q: %v", name, err)))
+			return
+		}
+		resp.Certs[name] = cert
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+	klog.Infof("renew %s %s success", r.RemoteAddr, nodeName)
+}
+
+// verifyNodeCertificate checks that the client certificate chain is a kubelet client certificate issued by our CA,
+// and returns the name of the node.
+func (s *Server) verifyNodeCertificate(chain []*x509.Certificate) (string, error) {
+	leaf, err := s.verifyClientCertificate(chain)
+	if err != nil {
+		return "", err
+	}
+
+	nodeName, ok := strings.CutPrefix(leaf.Subject.CommonName, "system:node:")
+	if !ok || nodeName == "" || !slices.Contains(leaf.Subject.Organization, rbac.NodesGroup) {
+		return "", fmt.Errorf("certificate %q is not a kubelet client certificate", leaf.Subject.CommonName)
+	}
+	return nodeName, nil
+}
+
+// verifyClientCertificate checks that the presented chain is a client certificate issued by the cluster CA, and returns the leaf.
+func (s *Server) verifyClientCertificate(chain []*x509.Certificate) (*x509.Certificate, error) {
+	leaf := chain[0]
+	intermediates := x509.NewCertPool()
+	for _, cert := range chain[1:] {
+		intermediates.AddCert(cert)
+	}
+	if _, err := leaf.Verify(x509.VerifyOptions{
+		Roots:         s.nodeCAs,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}); err != nil {
+		return nil, err
+	}
+	return leaf, nil
+}
+
+// verifyKubeletServerCertificate checks that the PEM-encoded certificate is the kubelet serving certificate
+// we issued to the node, and returns its names.
+func (s *Server) verifyKubeletServerCertificate(nodeName string, certificate string) ([]string, error) {
+	if certificate == "" {
+		return nil, fmt.Errorf("current certificate not provided")
+	}
+	cert, err := pki.ParsePEMCertificate([]byte(certificate))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := cert.Certificate.Verify(x509.VerifyOptions{
+		Roots:     s.nodeCAs,
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}); err != nil {
+		return nil, err
+	}
+	if cert.Subject.CommonName != nodeName {
+		return nil, fmt.Errorf("certificate %q is not for node %q", cert.Subject.CommonName, nodeName)
+	}
+
+	names := slices.Clone(cert.Certificate.DNSNames)
+	for _, ip := range cert.Certificate.IPAddresses {
+		names = append(names, ip.String())
+	}
+	return names, nil
+}
+
+// readCertPool reads a file of PEM-encoded CA certificates.
+func readCertPool(p string) (*x509.CertPool, error) {
+	b, err := os.ReadFile(p)
+	if err != nil {
+		return nil, fmt.Errorf("reading %q: %w", p, err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(b) {
+		return nil, fmt.Errorf("no certificates found in %q", p)
+	}
+	return pool, nil
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/kops/pkg/rbac"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "kubernetes-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCA{cert: cert, key: key}
+}
+
+func (ca *testCA) pool() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	return pool
+}
+
+func (ca *testCA) issue(t *testing.T, template *x509.Certificate) *x509.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template.SerialNumber = big.NewInt(2)
+	template.NotBefore = time.Now().Add(-time.Hour)
+	if template.NotAfter.IsZero() {
+		template.NotAfter = time.Now().Add(time.Hour)
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+func TestVerifyNodeCertificate(t *testing.T) {
+	ca := newTestCA(t)
+	s := &Server{nodeCAs: ca.pool()}
+
+	kubelet := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "system:node:node1", Organization: []string{rbac.NodesGroup}},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	nodeName, err := s.verifyNodeCertificate([]*x509.Certificate{ca.issue(t, kubelet)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if nodeName != "node1" {
+		t.Errorf("expected node1, got %q", nodeName)
+	}
+
+	grid := []struct {
+		name     string
+		cert     *x509.Certificate
+		expected string
+	}{
+		{
+			name:     "other CA",
+			cert:     newTestCA(t).issue(t, kubelet),
+			expected: "certificate signed by unknown authority",
+		},
+		{
+			name: "expired",
+			cert: ca.issue(t, &x509.Certificate{
+				Subject:     kubelet.Subject,
+				ExtKeyUsage: kubelet.ExtKeyUsage,
+				NotAfter:    time.Now().Add(-time.Minute),
+			}),
+			expected: "expired",
+		},
+		{
+			name: "kube-proxy",
+			cert: ca.issue(t, &x509.Certificate{
+				Subject:     pkix.Name{CommonName: rbac.KubeProxy},
+				ExtKeyUsage: kubelet.ExtKeyUsage,
+			}),
+			expected: "is not a kubelet client certificate",
+		},
+		{
+			name: "not in nodes group",
+			cert: ca.issue(t, &x509.Certificate{
+				Subject:     pkix.Name{CommonName: "system:node:node1"},
+				ExtKeyUsage: kubelet.ExtKeyUsage,
+			}),
+			expected: "is not a kubelet client certificate",
+		},
+		{
+			name: "serving certificate",
+			cert: ca.issue(t, &x509.Certificate{
+				Subject:     kubelet.Subject,
+				ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+			}),
+			expected: "incompatible key usage",
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			_, err := s.verifyNodeCertificate([]*x509.Certificate{g.cert})
+			if err == nil {
+				t.Fatalf("expected error %q", g.expected)
+			}
+			if !strings.Contains(err.Error(), g.expected) {
+				t.Errorf("expected error %q, got %q", g.expected, err)
+			}
+		})
+	}
+}
+
+func TestVerifyKubeletServerCertificate(t *testing.T) {
+	ca := newTestCA(t)
+	s := &Server{nodeCAs: ca.pool()}
+
+	encode := func(cert *x509.Certificate) string {
+		return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+	}
+
+	serving := ca.issue(t, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "node1"},
+		DNSNames:    []string{"node1", "node1.example.com"},
+		IPAddresses: []net.IP{net.ParseIP("10.0.0.1")},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+
+	names, err := s.verifyKubeletServerCertificate("node1", encode(serving))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []string{"node1", "node1.example.com", "10.0.0.1"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected names %v, got %v", expected, names)
+	}
+
+	if _, err := s.verifyKubeletServerCertificate("node2", encode(serving)); err == nil || !strings.Contains(err.Error(), "is not for node") {
+		t.Errorf("expected certificate of another node to be rejected, got %v", err)
+	}
+	if _, err := s.verifyKubeletServerCertificate("node1", encode(newTestCA(t).issue(t, serving))); err == nil {
+		t.Errorf("expected certificate from another CA to be rejected")
+	}
+	if _, err := s.verifyKubeletServerCertificate("node1", ""); err == nil {
+		t.Errorf("expected missing certificate to be rejected")
+	}
+}
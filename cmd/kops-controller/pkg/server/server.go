@@ -27,9 +27,11 @@ import (
 	"hash/fnv"
 	"io"
 	"net/http"
+	"path"
 	"runtime/debug"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
@@ -49,6 +51,7 @@ import (
 	"k8s.io/kops/util/pkg/vfs"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
 type Server struct {
@@ -70,6 +73,9 @@ type Server struct {
 
 	// challengeClient performs our callback-challenge into the node
 	challengeClient *bootstrap.ChallengeClient
+
+	// nodeCAs are the CAs that issue the kubelet client certificates, which nodes use to renew their certificates.
+	nodeCAs *x509.CertPool
 }
 
 var _ manager.LeaderElectionRunnable = &Server{}
@@ -79,6 +85,10 @@ func NewServer(vfsContext *vfs.VFSContext, opt *config.Options, verifier bootstr
 		Addr: opt.Server.Listen,
 		TLSConfig: &tls.Config{
 			MinVersion: tls.VersionTLS12,
+			// Nodes present their kubelet client certificate to renew their certificates, and metrics scrapers
+			// a client certificate issued by the cluster CA; these are verified by the handlers,
+			// as bootstrap requests are authenticated by the verifier.
+			ClientAuth: tls.RequestClientCert,
 		},
 	}
 
@@ -101,6 +111,11 @@ func NewServer(vfsContext *vfs.VFSContext, opt *config.Options, verifier bootstr
 		return nil, err
 	}
 
+	s.nodeCAs, err = readCertPool(path.Join(opt.Server.CABasePath, fi.CertificateIDCA+".crt"))
+	if err != nil {
+		return nil, err
+	}
+
 	p, err := vfsContext.BuildVfsPath(opt.SecretStore)
 	if err != nil {
 		return nil, fmt.Errorf("cannot parse SecretStore %q: %w", opt.SecretStore, err)
@@ -121,6 +136,11 @@ func NewServer(vfsContext *vfs.VFSContext, opt *config.Options, verifier bootstr
 
 	r := http.NewServeMux()
 	r.Handle("/bootstrap", http.HandlerFunc(s.bootstrap))
+	r.Handle("/renew", http.HandlerFunc(s.renew))
+	// The controller-runtime metrics server is disabled because we run on the host network,
+	// so we serve the metrics (which include certificate expiry) on our existing TLS endpoint,
+	// to clients presenting a certificate issued by the cluster CA.
+	r.Handle("/metrics", s.requireClientCertificate(promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{})))
 	server.Handler = recovery(r)
 
 	return s, nil
@@ -150,6 +170,25 @@ func (s *Server) Start(ctx context.Context) error {
 	return s.server.ListenAndServeTLS(s.opt.Server.ServerCertificatePath, s.opt.Server.ServerKeyPath)
 }
 
+// requireClientCertificate only passes requests presenting a client certificate issued by the cluster CA to next.
+func (s *Server) requireClientCertificate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+			klog.Infof("%s %s no client certificate", r.URL.Path, r.RemoteAddr)
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte("client certificate required"))
+			return
+		}
+		if _, err := s.verifyClientCertificate(r.TLS.PeerCertificates); err != nil {
+			klog.Infof("%s %s verify err: %v", r.URL.Path, r.RemoteAddr, err)
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte("failed to verify client certificate"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 func (s *Server) bootstrap(w http.ResponseWriter, r *http.Request) {
 	if r.Body == nil {
 		klog.Infof("bootstrap %s no body", r.RemoteAddr)
@@ -252,12 +291,7 @@ func (s *Server) bootstrap(w http.ResponseWriter, r *http.Request) {
 		resp.NodeConfig = nodeConfig
 	}
 
-	// Skew the certificate lifetime by up to 30 days based on information about the requesting node.
-	// This is so that different nodes created at the same time have the certificates they generated
-	// expire at different times, but all certificates on a given node expire around the same time.
-	hash := fnv.New32()
-	_, _ = hash.Write([]byte(r.RemoteAddr))
-	validHours := (455 * 24) + (hash.Sum32() % (30 * 24))
+	validHours := certificateValidHours(r.RemoteAddr)
 
 	for name, pubKey := range req.Certs {
 		cert, err := s.issueCert(ctx, name, pubKey, id, validHours, req.KeypairIDs)
@@ -275,6 +309,16 @@ func (s *Server) bootstrap(w http.ResponseWriter, r *http.Request) {
 	klog.Infof("bootstrap %s %s success", r.RemoteAddr, id.NodeName)
 }
 
+// certificateValidHours returns the lifetime of the certificates issued to a node.
+// The lifetime is skewed by up to 30 days based on information about the requesting node.
+// This is so that different nodes created at the same time have the certificates they generated
+// expire at different times, but all certificates on a given node expire around the same time.
+func certificateValidHours(nodeInfo string) uint32 {
+	hash := fnv.New32()
+	_, _ = hash.Write([]byte(nodeInfo))
+	return (455 * 24) + (hash.Sum32() % (30 * 24))
+}
+
 func (s *Server) issueCert(ctx context.Context, name string, pubKey string, id *bootstrap.VerifyResult, validHours uint32, keypairIDs map[string]string) (string, error) {
 	block, _ := pem.Decode([]byte(pubKey))
 	if block.Type != "RSA PUBLIC KEY" {
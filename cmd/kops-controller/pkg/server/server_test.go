@@ -0,0 +1,76 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequireClientCertificate(t *testing.T) {
+	ca := newTestCA(t)
+	s := &Server{nodeCAs: ca.pool()}
+	handler := s.requireClientCertificate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	client := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "prometheus"},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	grid := []struct {
+		name     string
+		state    *tls.ConnectionState
+		expected int
+	}{
+		{
+			name:     "no TLS",
+			expected: http.StatusUnauthorized,
+		},
+		{
+			name:     "no client certificate",
+			state:    &tls.ConnectionState{},
+			expected: http.StatusUnauthorized,
+		},
+		{
+			name:     "other CA",
+			state:    &tls.ConnectionState{PeerCertificates: []*x509.Certificate{newTestCA(t).issue(t, client)}},
+			expected: http.StatusForbidden,
+		},
+		{
+			name:     "cluster CA",
+			state:    &tls.ConnectionState{PeerCertificates: []*x509.Certificate{ca.issue(t, client)}},
+			expected: http.StatusOK,
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			r.TLS = g.state
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			if w.Code != g.expected {
+				t.Errorf("expected status %d, got %d", g.expected, w.Code)
+			}
+		})
+	}
+}
@@ -26,11 +26,15 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/klog/v2"
 	"k8s.io/kops/cmd/kops/util"
 	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/client/simple"
 	"k8s.io/kops/pkg/kopscodecs"
 	"k8s.io/kops/pkg/kubemanifest"
+	"k8s.io/kops/pkg/policy"
+	"k8s.io/kops/pkg/statelock"
 	"k8s.io/kops/upup/pkg/fi/cloudup"
 	"k8s.io/kops/util/pkg/text"
 	"k8s.io/kubectl/pkg/util/i18n"
@@ -108,6 +112,28 @@ func RunCreate(ctx context.Context, f *util.Factory, out io.Writer, c *CreateOpt
 	var addons kubemanifest.ObjectList
 	var clusters []*kopsapi.Cluster
 
+	// objects holds the clusters, instance groups and SSH credentials to create, in the order they were read
+	var objects []runtime.Object
+
+	// locks holds the state store locks taken, by cluster name, so that each cluster is locked once
+	locks := make(map[string]*statelock.Lock)
+	defer func() {
+		for _, lock := range locks {
+			lock.Release(ctx)
+		}
+	}()
+	lockCluster := func(cluster *kopsapi.Cluster) error {
+		if _, found := locks[cluster.ObjectMeta.Name]; found {
+			return nil
+		}
+		lock, err := statelock.Acquire(ctx, clientset.VFSContext(), cluster, "create")
+		if err != nil {
+			return err
+		}
+		locks[cluster.ObjectMeta.Name] = lock
+		return nil
+	}
+
 	for _, f := range c.Filenames {
 		var contents []byte
 		if f == "-" {
@@ -142,73 +168,100 @@ func RunCreate(ctx context.Context, f *util.Factory, out io.Writer, c *CreateOpt
 				if err != nil {
 					return fmt.Errorf("error populating configuration: %v", err)
 				}
-				_, err = clientset.CreateCluster(ctx, v)
-				if err != nil {
-					if apierrors.IsAlreadyExists(err) {
-						return fmt.Errorf("cluster %q already exists", v.ObjectMeta.Name)
-					}
-					return fmt.Errorf("error creating cluster: %v", err)
-				}
-				fmt.Fprintf(&sb, "Created cluster/%s\n", v.ObjectMeta.Name)
-				clusters = append(clusters, v)
-				// cSpec = true
-
-			case *kopsapi.InstanceGroup:
-				clusterName = v.ObjectMeta.Labels[kopsapi.LabelClusterName]
-				if clusterName == "" {
-					return fmt.Errorf("must specify %q label with cluster name to create instanceGroup", kopsapi.LabelClusterName)
-				}
-				cluster, err := clientset.GetCluster(ctx, clusterName)
-				if err != nil {
-					return fmt.Errorf("error querying cluster %q: %v", clusterName, err)
-				}
+				objects = append(objects, v)
 
-				if cluster == nil {
-					return fmt.Errorf("cluster %q not found", clusterName)
-				}
+			case *kopsapi.InstanceGroup, *kopsapi.SSHCredential:
+				objects = append(objects, v)
 
-				_, err = clientset.InstanceGroupsFor(cluster).Create(ctx, v, metav1.CreateOptions{})
-				if err != nil {
-					if apierrors.IsAlreadyExists(err) {
-						return fmt.Errorf("instanceGroup %q already exists", v.ObjectMeta.Name)
-					}
-					return fmt.Errorf("error creating instanceGroup: %v", err)
-				}
-				fmt.Fprintf(&sb, "Created instancegroup/%s\n", v.ObjectMeta.Name)
+			case *unstructured.Unstructured:
+				addons = append(addons, kubemanifest.NewObject(v.Object))
 
-			case *kopsapi.SSHCredential:
-				clusterName = v.ObjectMeta.Labels[kopsapi.LabelClusterName]
-				if clusterName == "" {
-					return fmt.Errorf("must specify %q label with cluster name to create SSHCredential", kopsapi.LabelClusterName)
-				}
-				if v.Spec.PublicKey == "" {
-					return fmt.Errorf("spec.PublicKey is required")
-				}
+			default:
+				klog.V(2).Infof("Type of object was %T", v)
+				return fmt.Errorf("unhandled kind %q in %s", gvk, f)
+			}
+		}
+	}
 
-				cluster, err := clientset.GetCluster(ctx, clusterName)
-				if err != nil {
-					return err
-				}
+	// The policies are enforced before anything is written to the state store
+	if err := enforceCreatePolicies(ctx, clientset, objects, addons); err != nil {
+		return err
+	}
 
-				sshCredentialStore, err := clientset.SSHCredentialStore(cluster)
-				if err != nil {
-					return err
+	for _, o := range objects {
+		switch v := o.(type) {
+		case *kopsapi.Cluster:
+			created, err := clientset.CreateCluster(ctx, v)
+			if err != nil {
+				if apierrors.IsAlreadyExists(err) {
+					return fmt.Errorf("cluster %q already exists", v.ObjectMeta.Name)
 				}
+				return fmt.Errorf("error creating cluster: %v", err)
+			}
+			// The lock is kept in the state store of the cluster, so it can only be taken once the cluster is created
+			if err := lockCluster(created); err != nil {
+				return err
+			}
+			fmt.Fprintf(&sb, "Created cluster/%s\n", v.ObjectMeta.Name)
+			clusters = append(clusters, v)
+			// cSpec = true
+
+		case *kopsapi.InstanceGroup:
+			clusterName = v.ObjectMeta.Labels[kopsapi.LabelClusterName]
+			if clusterName == "" {
+				return fmt.Errorf("must specify %q label with cluster name to create instanceGroup", kopsapi.LabelClusterName)
+			}
+			cluster, err := clientset.GetCluster(ctx, clusterName)
+			if err != nil {
+				return fmt.Errorf("error querying cluster %q: %v", clusterName, err)
+			}
 
-				sshKeyArr := []byte(v.Spec.PublicKey)
-				err = sshCredentialStore.AddSSHPublicKey(ctx, sshKeyArr)
-				if err != nil {
-					return err
+			if cluster == nil {
+				return fmt.Errorf("cluster %q not found", clusterName)
+			}
+
+			if err := lockCluster(cluster); err != nil {
+				return err
+			}
+
+			_, err = clientset.InstanceGroupsFor(cluster).Create(ctx, v, metav1.CreateOptions{})
+			if err != nil {
+				if apierrors.IsAlreadyExists(err) {
+					return fmt.Errorf("instanceGroup %q already exists", v.ObjectMeta.Name)
 				}
-				fmt.Fprintf(&sb, "Added ssh credential\n")
+				return fmt.Errorf("error creating instanceGroup: %v", err)
+			}
+			fmt.Fprintf(&sb, "Created instancegroup/%s\n", v.ObjectMeta.Name)
 
-			case *unstructured.Unstructured:
-				addons = append(addons, kubemanifest.NewObject(v.Object))
+		case *kopsapi.SSHCredential:
+			clusterName = v.ObjectMeta.Labels[kopsapi.LabelClusterName]
+			if clusterName == "" {
+				return fmt.Errorf("must specify %q label with cluster name to create SSHCredential", kopsapi.LabelClusterName)
+			}
+			if v.Spec.PublicKey == "" {
+				return fmt.Errorf("spec.PublicKey is required")
+			}
 
-			default:
-				klog.V(2).Infof("Type of object was %T", v)
-				return fmt.Errorf("unhandled kind %q in %s", gvk, f)
+			cluster, err := clientset.GetCluster(ctx, clusterName)
+			if err != nil {
+				return err
+			}
+
+			if err := lockCluster(cluster); err != nil {
+				return err
+			}
+
+			sshCredentialStore, err := clientset.SSHCredentialStore(cluster)
+			if err != nil {
+				return err
+			}
+
+			sshKeyArr := []byte(v.Spec.PublicKey)
+			err = sshCredentialStore.AddSSHPublicKey(ctx, sshKeyArr)
+			if err != nil {
+				return err
 			}
+			fmt.Fprintf(&sb, "Added ssh credential\n")
 		}
 	}
 
@@ -245,3 +298,59 @@ func RunCreate(ctx context.Context, f *util.Factory, out io.Writer, c *CreateOpt
 	}
 	return nil
 }
+
+// enforceCreatePolicies evaluates the ClusterPolicy objects against the clusters and instance groups to be created,
+// as written in the files. A new cluster and its instance groups must satisfy the policies created with it, and new
+// instance groups of an existing cluster must satisfy the instance group rules of the policies stored with that cluster.
+// The specs are evaluated again, with the defaults filled in, by kops update cluster.
+func enforceCreatePolicies(ctx context.Context, clientset simple.Clientset, objects []runtime.Object, addons kubemanifest.ObjectList) error {
+	var clusterNames []string
+	clusters := make(map[string]*kopsapi.Cluster)
+	instanceGroups := make(map[string][]*kopsapi.InstanceGroup)
+	for _, o := range objects {
+		switch v := o.(type) {
+		case *kopsapi.Cluster:
+			if clusters[v.ObjectMeta.Name] == nil && instanceGroups[v.ObjectMeta.Name] == nil {
+				clusterNames = append(clusterNames, v.ObjectMeta.Name)
+			}
+			clusters[v.ObjectMeta.Name] = v
+		case *kopsapi.InstanceGroup:
+			clusterName := v.ObjectMeta.Labels[kopsapi.LabelClusterName]
+			if clusterName == "" {
+				// Reported when the instance group is created
+				continue
+			}
+			if clusters[clusterName] == nil && instanceGroups[clusterName] == nil {
+				clusterNames = append(clusterNames, clusterName)
+			}
+			instanceGroups[clusterName] = append(instanceGroups[clusterName], v)
+		}
+	}
+
+	for _, clusterName := range clusterNames {
+		cluster := clusters[clusterName]
+		if cluster != nil {
+			if err := policy.Enforce(addons, cluster, instanceGroups[clusterName]); err != nil {
+				return err
+			}
+			continue
+		}
+
+		existing, err := clientset.GetCluster(ctx, clusterName)
+		if err != nil {
+			return fmt.Errorf("error querying cluster %q: %v", clusterName, err)
+		}
+		if existing == nil {
+			return fmt.Errorf("cluster %q not found", clusterName)
+		}
+		stored, err := clientset.AddonsFor(existing).List(ctx)
+		if err != nil {
+			return fmt.Errorf("error reading additional objects: %v", err)
+		}
+		// The existing cluster is not being created, so only the rules for instance groups apply
+		if err := policy.Enforce(stored, nil, instanceGroups[clusterName]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -52,6 +52,8 @@ import (
 	"k8s.io/kops/pkg/commands/commandutils"
 	"k8s.io/kops/pkg/featureflag"
 	"k8s.io/kops/pkg/kubeconfig"
+	"k8s.io/kops/pkg/policy"
+	"k8s.io/kops/pkg/statelock"
 	"k8s.io/kops/pkg/wellknownoperators"
 	"k8s.io/kops/pkg/zones"
 	"k8s.io/kops/upup/pkg/fi"
@@ -102,6 +104,9 @@ type CreateClusterOptions struct {
 
 	// AddonPaths specify paths to additional components that we can add to a cluster
 	AddonPaths []string
+
+	// PolicyPaths specify paths to ClusterPolicy objects that the cluster must satisfy
+	PolicyPaths []string
 }
 
 func (o *CreateClusterOptions) InitDefaults() {
@@ -243,6 +248,8 @@ func NewCmdCreateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	cmd.Flags().StringSliceVar(&options.ControlPlaneZones, "control-plane-zones", options.ControlPlaneZones, "Zones in which to run control-plane nodes. (must be an odd number)")
 	cmd.RegisterFlagCompletionFunc("control-plane-zones", completeZone(options, &rootCommand))
 
+	cmd.Flags().StringSliceVar(&options.PolicyPaths, "policy", options.PolicyPaths, "Paths to ClusterPolicy objects the cluster must satisfy")
+
 	if featureflag.ClusterAddons.Enabled() {
 		cmd.Flags().StringSliceVar(&options.AddonPaths, "add", options.AddonPaths, "Paths to addons we should add to the cluster")
 		// TODO complete VFS paths
@@ -678,6 +685,19 @@ func RunCreateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Cr
 		addons = append(addons, addon.Objects...)
 	}
 
+	for _, p := range c.PolicyPaths {
+		addon, err := clusteraddons.LoadClusterAddon(clientset.VFSContext(), p)
+		if err != nil {
+			return fmt.Errorf("error loading cluster policy %s: %v", p, err)
+		}
+		for _, o := range addon.Objects {
+			if !policy.IsClusterPolicy(o.GroupVersionKind()) {
+				return fmt.Errorf("%s contains a %s, only %s objects can be added with --policy", p, o.GroupVersionKind(), policy.KindClusterPolicy)
+			}
+		}
+		addons = append(addons, addon.Objects...)
+	}
+
 	{
 		// Build full IG spec to ensure we end up with a valid IG
 		fullInstanceGroups := []*api.InstanceGroup{}
@@ -693,6 +713,10 @@ func RunCreateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Cr
 		if err != nil {
 			return fmt.Errorf("validation of the full cluster and instance group specs failed: %w", err)
 		}
+
+		if err := policy.Enforce(addons, fullCluster, fullInstanceGroups); err != nil {
+			return err
+		}
 	}
 
 	if c.DryRun {
@@ -746,6 +770,15 @@ func RunCreateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Cr
 		return fmt.Errorf("error writing updated configuration: %v", err)
 	}
 
+	// The lock is kept in the state store of the cluster, so it can only be taken once the cluster is created.
+	// It is held while the SSH keys are added and the cluster is updated, which sees that it is already held.
+	lock, err := statelock.Acquire(ctx, clientset.VFSContext(), cluster, "create cluster")
+	if err != nil {
+		return err
+	}
+	defer lock.Release(ctx)
+	ctx = statelock.WithLock(ctx, lock)
+
 	if len(c.SSHPublicKeys) == 0 {
 		autoloadSSHPublicKeys := true
 		switch c.CloudProvider {
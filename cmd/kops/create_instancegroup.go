@@ -36,6 +36,7 @@ import (
 	"k8s.io/kops/pkg/commands/commandutils"
 	"k8s.io/kops/pkg/featureflag"
 	"k8s.io/kops/pkg/kopscodecs"
+	"k8s.io/kops/pkg/statelock"
 	"k8s.io/kops/pkg/try"
 	"k8s.io/kops/upup/pkg/fi/cloudup"
 	"k8s.io/kubectl/pkg/cmd/util/editor"
@@ -159,6 +160,12 @@ func RunCreateInstanceGroup(ctx context.Context, f *util.Factory, out io.Writer,
 		return err
 	}
 
+	lock, err := statelock.Acquire(ctx, clientset.VFSContext(), cluster, "create instancegroup")
+	if err != nil {
+		return err
+	}
+	defer lock.Release(ctx)
+
 	channel, err := cloudup.ChannelForCluster(clientset.VFSContext(), cluster)
 	if err != nil {
 		klog.Warningf("%v", err)
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
@@ -32,10 +33,12 @@ import (
 
 	"k8s.io/kops/cmd/kops/util"
 	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/pkg/statelock"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/utils"
 	"k8s.io/kubectl/pkg/util/i18n"
 	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
 )
 
 var (
@@ -57,8 +60,16 @@ var (
 	"service-account" keyset, service-account tokens). As a consequence, a
 	keypair added to an empty keyset must be made primary.
 
+	If an external signer is provided, the private key is held by that
+	signing service (an HTTP signing service or a HashiCorp Vault transit
+	key) and only a reference to it is stored in the state store. A
+	self-signed certificate is generated, signed by the external signer.
+	External signers are supported for the "kubernetes-ca" and
+	"apiserver-aggregator-ca" keysets.
+
 	If the keyset is specified as "all", a newly generated secondary
-	certificate and private key will be added to each rotatable keyset.
+	certificate and private key will be added to each rotatable keyset
+	whose primary keypair is not held by an external signer.
 	`))
 
 	createKeypairExample = templates.Examples(i18n.T(`
@@ -67,6 +78,10 @@ var (
 		--cert ~/ca.pem --key ~/ca-key.pem \
 		--name k8s-cluster.example.com --state s3://my-state-store
 
+	# Add a CA certificate whose private key is held in HashiCorp Vault.
+	kops create keypair kubernetes-ca --external-signer ~/vault-signer.yaml \
+		--name k8s-cluster.example.com --state s3://my-state-store
+
 	# Add a newly generated certificate and private key to each rotatable keyset.
 	kops create keypair all \
 		--name k8s-cluster.example.com --state s3://my-state-store
@@ -81,8 +96,16 @@ type CreateKeypairOptions struct {
 	PrivateKeyPath string
 	CertPath       string
 	Primary        bool
+
+	// ExternalSignerPath is the path to an ExternalSignerConfig referencing a key held by an external signer.
+	ExternalSignerPath string
 }
 
+// externalSignerKeysets are the keysets whose private key may be held by an external signer.
+// The private keys of the other keysets are read from files by components outside of kOps,
+// such as etcd-manager and kube-apiserver.
+var externalSignerKeysets = []string{fi.CertificateIDCA, "apiserver-aggregator-ca"}
+
 func rotatableKeysetFilter(name string, _ *fi.Keyset) bool {
 	return name == "all" || name == "service-account" || strings.Contains(name, "-ca")
 }
@@ -123,6 +146,18 @@ func NewCmdCreateKeypair(f *util.Factory, out io.Writer) *cobra.Command {
 				if options.Primary {
 					return fmt.Errorf("cannot specify --primary with \"all\"")
 				}
+				if options.ExternalSignerPath != "" {
+					return fmt.Errorf("cannot specify --external-signer with \"all\"")
+				}
+			}
+
+			if options.ExternalSignerPath != "" {
+				if options.PrivateKeyPath != "" || options.CertPath != "" {
+					return fmt.Errorf("cannot specify --external-signer with --cert or --key")
+				}
+				if !slices.Contains(externalSignerKeysets, options.Keyset) {
+					return fmt.Errorf("external signers are only supported for keysets %s", strings.Join(externalSignerKeysets, ", "))
+				}
 			}
 
 			return nil
@@ -138,6 +173,7 @@ func NewCmdCreateKeypair(f *util.Factory, out io.Writer) *cobra.Command {
 	cmd.Flags().StringVar(&options.CertPath, "cert", options.CertPath, "Path to CA certificate")
 	cmd.Flags().StringVar(&options.PrivateKeyPath, "key", options.PrivateKeyPath, "Path to CA private key")
 	cmd.Flags().BoolVar(&options.Primary, "primary", options.Primary, "Make the keypair the one used to issue certificates")
+	cmd.Flags().StringVar(&options.ExternalSignerPath, "external-signer", options.ExternalSignerPath, "Path to the configuration of an external signer holding the private key")
 
 	return cmd
 }
@@ -158,6 +194,12 @@ func RunCreateKeypair(ctx context.Context, f *util.Factory, out io.Writer, optio
 		return fmt.Errorf("error getting clientset: %v", err)
 	}
 
+	lock, err := statelock.Acquire(ctx, clientSet.VFSContext(), cluster, "create keypair")
+	if err != nil {
+		return err
+	}
+	defer lock.Release(ctx)
+
 	keyStore, err := clientSet.KeyStore(cluster)
 	if err != nil {
 		return fmt.Errorf("error getting keystore: %v", err)
@@ -172,8 +214,12 @@ func RunCreateKeypair(ctx context.Context, f *util.Factory, out io.Writer, optio
 		return fmt.Errorf("listing keysets: %v", err)
 	}
 
-	for name := range keysets {
+	for name, keyset := range keysets {
 		if rotatableKeysetFilter(name, nil) {
+			if keyset.Primary != nil && pki.IsExternalSigner(keyset.Primary.PrivateKey) {
+				fmt.Fprintf(out, "Skipping %s: its private key is held by an external signer; use --external-signer to add a keypair\n", name)
+				continue
+			}
 			if err := createKeypair(ctx, out, options, name, keyStore); err != nil {
 				return fmt.Errorf("creating keypair for %s: %v", name, err)
 			}
@@ -198,6 +244,12 @@ func createKeypair(ctx context.Context, out io.Writer, options *CreateKeypairOpt
 			return fmt.Errorf("error loading private key %q: %v", privateKeyBytes, err)
 		}
 	}
+	if options.ExternalSignerPath != "" {
+		privateKey, err = loadExternalSigner(ctx, utils.ExpandPath(options.ExternalSignerPath))
+		if err != nil {
+			return err
+		}
+	}
 
 	var cert *pki.Certificate
 	if options.CertPath == "" {
@@ -263,10 +315,34 @@ func createKeypair(ctx context.Context, out io.Writer, options *CreateKeypairOpt
 	if options.PrivateKeyPath != "" {
 		fmt.Fprintf(out, "using user provided private key: %v\n", options.PrivateKeyPath)
 	}
+	if options.ExternalSignerPath != "" {
+		fmt.Fprintf(out, "using external signer: %v\n", options.ExternalSignerPath)
+	}
 	fmt.Fprintf(out, "Created %s %s\n", name, item.Id)
 	return nil
 }
 
+// loadExternalSigner reads the configuration of an external signer and fetches the public key of its key.
+func loadExternalSigner(ctx context.Context, p string) (*pki.PrivateKey, error) {
+	b, err := os.ReadFile(p)
+	if err != nil {
+		return nil, fmt.Errorf("error reading external signer configuration %q: %v", p, err)
+	}
+	jsonBytes, err := yaml.YAMLToJSON(b)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing external signer configuration %q: %v", p, err)
+	}
+	config, err := pki.ParseExternalSignerConfig(jsonBytes)
+	if err != nil {
+		return nil, err
+	}
+	signer, err := pki.NewExternalSigner(ctx, *config)
+	if err != nil {
+		return nil, err
+	}
+	return &pki.PrivateKey{Key: signer}, nil
+}
+
 func completeKeyset(ctx context.Context, cluster *kopsapi.Cluster, clientSet simple.Clientset, args []string, filter func(name string, keyset *fi.Keyset) bool) (keyset *fi.Keyset, keyStore fi.CAStore, completions []string, directive cobra.ShellCompDirective) {
 	keyStore, err := clientSet.KeyStore(cluster)
 	if err != nil {
@@ -26,6 +26,7 @@ import (
 	"github.com/spf13/cobra"
 	"k8s.io/kops/cmd/kops/util"
 	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/statelock"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kubectl/pkg/util/i18n"
 	"k8s.io/kubectl/pkg/util/templates"
@@ -101,6 +102,12 @@ func RunCreateSecretDockerConfig(ctx context.Context, f commandutils.Factory, ou
 		return err
 	}
 
+	lock, err := statelock.Acquire(ctx, clientset.VFSContext(), cluster, "create secret dockerconfig")
+	if err != nil {
+		return err
+	}
+	defer lock.Release(ctx)
+
 	secretStore, err := clientset.SecretStore(cluster)
 	if err != nil {
 		return err
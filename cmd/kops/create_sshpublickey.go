@@ -25,6 +25,7 @@ import (
 	"github.com/spf13/cobra"
 	"k8s.io/kops/cmd/kops/util"
 	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/statelock"
 	"k8s.io/kubectl/pkg/util/i18n"
 	"k8s.io/kubectl/pkg/util/templates"
 )
@@ -84,6 +85,12 @@ func RunCreateSSHPublicKey(ctx context.Context, f *util.Factory, out io.Writer,
 		return err
 	}
 
+	lock, err := statelock.Acquire(ctx, clientset.VFSContext(), cluster, "create sshpublickey")
+	if err != nil {
+		return err
+	}
+	defer lock.Release(ctx)
+
 	sshCredentialStore, err := clientset.SSHCredentialStore(cluster)
 	if err != nil {
 		return err
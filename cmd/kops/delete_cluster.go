@@ -31,6 +31,7 @@ import (
 	"k8s.io/kops/pkg/kubeconfig"
 	"k8s.io/kops/pkg/resources"
 	resourceops "k8s.io/kops/pkg/resources/ops"
+	"k8s.io/kops/pkg/statelock"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
@@ -128,6 +129,18 @@ func RunDeleteCluster(ctx context.Context, f *util.Factory, out io.Writer, optio
 		if err != nil {
 			return err
 		}
+
+		if options.Yes {
+			clientset, err := f.KopsClient()
+			if err != nil {
+				return err
+			}
+			lock, err := statelock.Acquire(ctx, clientset.VFSContext(), cluster, "delete cluster")
+			if err != nil {
+				return err
+			}
+			defer lock.Release(ctx)
+		}
 	}
 
 	wouldDeleteCloudResources := false
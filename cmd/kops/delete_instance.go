@@ -36,6 +36,7 @@ import (
 	"k8s.io/kops/pkg/cloudinstances"
 	"k8s.io/kops/pkg/commands/commandutils"
 	"k8s.io/kops/pkg/instancegroups"
+	"k8s.io/kops/pkg/statelock"
 	"k8s.io/kops/pkg/validation"
 	"k8s.io/kops/upup/pkg/fi/cloudup"
 	"k8s.io/kubectl/pkg/util/i18n"
@@ -234,6 +235,12 @@ func RunDeleteInstance(ctx context.Context, f *util.Factory, out io.Writer, opti
 		return nil
 	}
 
+	lock, err := statelock.Acquire(ctx, clientSet.VFSContext(), cluster, "delete instance")
+	if err != nil {
+		return err
+	}
+	defer lock.Release(ctx)
+
 	d := &instancegroups.RollingUpdateCluster{
 		Clientset:         clientSet,
 		Cluster:           cluster,
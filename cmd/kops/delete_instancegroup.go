@@ -27,6 +27,7 @@ import (
 	"k8s.io/kops/cmd/kops/util"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/instancegroups"
+	"k8s.io/kops/pkg/statelock"
 	"k8s.io/kops/upup/pkg/fi/cloudup"
 	"k8s.io/kops/util/pkg/ui"
 	"k8s.io/kubectl/pkg/util/i18n"
@@ -171,6 +172,12 @@ func RunDeleteInstanceGroup(ctx context.Context, f *util.Factory, out io.Writer,
 		return nil
 	}
 
+	lock, err := statelock.Acquire(ctx, clientset.VFSContext(), cluster, "delete instancegroup")
+	if err != nil {
+		return err
+	}
+	defer lock.Release(ctx)
+
 	cloud, err := cloudup.BuildCloud(cluster)
 	if err != nil {
 		return err
@@ -0,0 +1,106 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/pretty"
+	"k8s.io/kops/pkg/statelock"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	deleteLockLong = pretty.LongDesc(i18n.T(`
+	Remove the state store lock of a cluster.
+
+	A lock that has expired, because the command holding it was killed, is removed.
+	Removing a lock that is still held requires ` + pretty.Bash("--force") + `; only do so if the command holding it is no longer running.`))
+
+	deleteLockExample = templates.Examples(i18n.T(`
+	# Remove the lock of a cluster, held by a command that is no longer running
+	kops delete lock k8s-cluster.example.com --force
+	`))
+
+	deleteLockShort = i18n.T(`Remove a state store lock.`)
+)
+
+type DeleteLockOptions struct {
+	ClusterName string
+	Force       bool
+}
+
+func NewCmdDeleteLock(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &DeleteLockOptions{}
+
+	cmd := &cobra.Command{
+		Use:               "lock [CLUSTER]",
+		Short:             deleteLockShort,
+		Long:              deleteLockLong,
+		Example:           deleteLockExample,
+		Args:              rootCommand.clusterNameArgs(&options.ClusterName),
+		ValidArgsFunction: commandutils.CompleteClusterName(f, true, false),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunDeleteLock(cmd.Context(), f, out, options)
+		},
+	}
+
+	cmd.Flags().BoolVar(&options.Force, "force", options.Force, "Remove the lock even if it has not expired")
+
+	return cmd
+}
+
+func RunDeleteLock(ctx context.Context, f *util.Factory, out io.Writer, options *DeleteLockOptions) error {
+	clientset, err := f.KopsClient()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	p, err := statelock.LockPath(clientset.VFSContext(), cluster)
+	if err != nil {
+		return err
+	}
+	lease, err := statelock.ReadLease(ctx, p)
+	if err != nil {
+		return err
+	}
+	if lease == nil {
+		fmt.Fprintf(out, "Cluster %q is not locked\n", cluster.ObjectMeta.Name)
+		return nil
+	}
+	if !lease.Expired() && !options.Force {
+		return fmt.Errorf("the lock of cluster %q is held by %s on %s, running %q; specify --force to remove it", cluster.ObjectMeta.Name, lease.Owner, lease.Host, lease.Operation)
+	}
+
+	if err := p.Remove(ctx); err != nil {
+		return fmt.Errorf("error removing lock %s: %w", p, err)
+	}
+
+	fmt.Fprintf(out, "Removed the lock of cluster %q held by %s on %s\n", cluster.ObjectMeta.Name, lease.Owner, lease.Host)
+	return nil
+}
@@ -37,6 +37,7 @@ import (
 	"k8s.io/kops/pkg/edit"
 	"k8s.io/kops/pkg/kopscodecs"
 	"k8s.io/kops/pkg/pretty"
+	"k8s.io/kops/pkg/statelock"
 	"k8s.io/kops/pkg/try"
 	"k8s.io/kops/upup/pkg/fi/cloudup"
 	util_editor "k8s.io/kubectl/pkg/cmd/util/editor"
@@ -115,6 +116,12 @@ func RunEditCluster(ctx context.Context, f *util.Factory, out io.Writer, options
 		return err
 	}
 
+	lock, err := statelock.Acquire(ctx, clientset.VFSContext(), oldCluster, "edit cluster")
+	if err != nil {
+		return err
+	}
+	defer lock.Release(ctx)
+
 	instanceGroups, err := commands.ReadAllInstanceGroups(ctx, clientset, oldCluster)
 	if err != nil {
 		return err
@@ -21,7 +21,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"time"
 
+	"k8s.io/klog/v2"
+	"k8s.io/kops"
 	"k8s.io/kops/pkg/assets"
 	"k8s.io/kops/pkg/commands/commandutils"
 	"k8s.io/kops/pkg/pretty"
@@ -42,7 +45,13 @@ var (
 	(original) and download (local repository) locations.
 
 	When invoked with the ` + pretty.Bash("--copy") + ` flag, will copy each asset from the
-	canonical to the download location.`))
+	canonical to the download location.
+
+	When invoked with the ` + pretty.Bash("--verify") + ` flag, will download each asset from its
+	download location and check that it matches the canonical asset.
+
+	When invoked with the ` + pretty.Bash("--sbom") + ` flag, will output a software bill of materials
+	listing the assets, in SPDX or CycloneDX format.`))
 
 	getAssetsExample = templates.Examples(i18n.T(`
 	# Display all assets.
@@ -50,6 +59,12 @@ var (
 
 	# Copy assets to the local repositories configured in the cluster spec.
 	kops get assets --copy 
+
+	# Check that the assets in the local repositories match the canonical assets.
+	kops get assets --verify
+
+	# Output a CycloneDX software bill of materials.
+	kops get assets --sbom cyclonedx > sbom.json
 	`))
 
 	getAssetsShort = i18n.T(`Display assets for cluster.`)
@@ -57,7 +72,9 @@ var (
 
 type GetAssetsOptions struct {
 	*GetOptions
-	Copy bool
+	Copy   bool
+	Verify bool
+	SBOM   string
 }
 
 type Image struct {
@@ -76,6 +93,8 @@ type AssetResult struct {
 	Images []*Image `json:"images,omitempty"`
 	// FileAssets are the file assets we use (output).
 	Files []*File `json:"files,omitempty"`
+	// Verification holds the results of verifying the mirrored assets (output).
+	Verification []*assets.VerifyResult `json:"verification,omitempty"`
 }
 
 func NewCmdGetAssets(f *util.Factory, out io.Writer, getOptions *GetOptions) *cobra.Command {
@@ -96,11 +115,22 @@ func NewCmdGetAssets(f *util.Factory, out io.Writer, getOptions *GetOptions) *co
 	}
 
 	cmd.Flags().BoolVar(&options.Copy, "copy", options.Copy, "copy assets to local repository")
+	cmd.Flags().BoolVar(&options.Verify, "verify", options.Verify, "verify that assets in the local repository match the canonical assets")
+	cmd.Flags().StringVar(&options.SBOM, "sbom", options.SBOM, "output a software bill of materials of the assets, in format spdx or cyclonedx")
+	cmd.RegisterFlagCompletionFunc("sbom", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{assets.SBOMFormatSPDX, assets.SBOMFormatCycloneDX}, cobra.ShellCompDirectiveNoFileComp
+	})
 
 	return cmd
 }
 
 func RunGetAssets(ctx context.Context, f *util.Factory, out io.Writer, options *GetAssetsOptions) error {
+	switch options.SBOM {
+	case "", assets.SBOMFormatSPDX, assets.SBOMFormatCycloneDX:
+	default:
+		return fmt.Errorf("unsupported SBOM format %q, must be %s or %s", options.SBOM, assets.SBOMFormatSPDX, assets.SBOMFormatCycloneDX)
+	}
+
 	updateClusterResults, err := RunUpdateCluster(ctx, f, out, &UpdateClusterOptions{
 		CoreUpdateClusterOptions: CoreUpdateClusterOptions{
 			Target:      cloudup.TargetDryRun,
@@ -149,12 +179,77 @@ func RunGetAssets(ctx context.Context, f *util.Factory, out io.Writer, options *
 		}
 	}
 
-	switch options.Output {
+	var verifyErr error
+	if options.Verify {
+		result.Verification = assets.Verify(updateClusterResults.ImageAssets, updateClusterResults.FileAssets, f.VFSContext())
+		failed := 0
+		for _, r := range result.Verification {
+			if !r.Verified() {
+				klog.Warningf("%s %q does not match %q: %s", r.Kind, r.Download, r.Canonical, r.Error)
+				failed++
+			}
+		}
+		if failed != 0 {
+			verifyErr = fmt.Errorf("%d of %d mirrored assets failed verification", failed, len(result.Verification))
+		}
+	}
+
+	if options.SBOM != "" {
+		if err := writeAssetsSBOM(out, options.SBOM, updateClusterResults.Cluster.Name, updateClusterResults.ImageAssets, updateClusterResults.FileAssets); err != nil {
+			return err
+		}
+		return verifyErr
+	}
+
+	if err := writeAssetResult(out, options.Output, &result); err != nil {
+		return err
+	}
+	return verifyErr
+}
+
+// writeAssetsSBOM writes a software bill of materials of the assets; the digests of the images are looked up in their registries.
+func writeAssetsSBOM(out io.Writer, format string, clusterName string, imageAssets []*assets.ImageAsset, fileAssets []*assets.FileAsset) error {
+	imageDigests := make(map[string]string)
+	for _, imageAsset := range imageAssets {
+		if _, found := imageDigests[imageAsset.DownloadLocation]; found {
+			continue
+		}
+		if imageAsset.Digest != "" {
+			imageDigests[imageAsset.DownloadLocation] = imageAsset.Digest
+			continue
+		}
+		digest, err := assets.ImageDigest(imageAsset.DownloadLocation)
+		if err != nil {
+			klog.Warningf("unable to determine digest of image %q: %v", imageAsset.DownloadLocation, err)
+		}
+		imageDigests[imageAsset.DownloadLocation] = digest
+	}
+
+	sbom := &assets.SBOM{
+		ClusterName: clusterName,
+		ToolVersion: kops.Version,
+		Timestamp:   time.Now(),
+		Components:  assets.BuildSBOMComponents(imageAssets, fileAssets, imageDigests),
+	}
+	if format == assets.SBOMFormatCycloneDX {
+		return sbom.WriteCycloneDX(out)
+	}
+	return sbom.WriteSPDX(out)
+}
+
+func writeAssetResult(out io.Writer, output string, result *AssetResult) error {
+	switch output {
 	case OutputTable:
-		if err = imageOutputTable(result.Images, out); err != nil {
+		if err := imageOutputTable(result.Images, out); err != nil {
 			return err
 		}
-		return fileOutputTable(result.Files, out)
+		if err := fileOutputTable(result.Files, out); err != nil {
+			return err
+		}
+		if result.Verification != nil {
+			return verificationOutputTable(result.Verification, out)
+		}
+		return nil
 	case OutputYaml:
 		y, err := yaml.Marshal(result)
 		if err != nil {
@@ -172,7 +267,7 @@ func RunGetAssets(ctx context.Context, f *util.Factory, out io.Writer, options *
 			return fmt.Errorf("error writing to output: %v", err)
 		}
 	default:
-		return fmt.Errorf("unsupported output format: %q", options.Output)
+		return fmt.Errorf("unsupported output format: %q", output)
 	}
 
 	return nil
@@ -208,3 +303,26 @@ func fileOutputTable(files []*File, out io.Writer) error {
 	columns := []string{"CANONICAL", "DOWNLOAD", "SHA"}
 	return t.Render(files, out, columns...)
 }
+
+func verificationOutputTable(results []*assets.VerifyResult, out io.Writer) error {
+	fmt.Println("")
+	t := &tables.Table{}
+	t.AddColumn("KIND", func(r *assets.VerifyResult) string {
+		return r.Kind
+	})
+	t.AddColumn("DOWNLOAD", func(r *assets.VerifyResult) string {
+		return r.Download
+	})
+	t.AddColumn("STATUS", func(r *assets.VerifyResult) string {
+		if r.Verified() {
+			return "Verified"
+		}
+		return "Failed"
+	})
+	t.AddColumn("MESSAGE", func(r *assets.VerifyResult) string {
+		return r.Error
+	})
+
+	columns := []string{"KIND", "DOWNLOAD", "STATUS", "MESSAGE"}
+	return t.Render(results, out, columns...)
+}
@@ -0,0 +1,328 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/certinventory"
+	"k8s.io/kops/util/pkg/tables"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	getCertificatesLong = templates.LongDesc(i18n.T(`
+	List the certificates of the cluster and when they expire.
+
+	This lists the trusted certificates of every keyset in the state store,
+	and the leaf certificates that nodeup issued on the control-plane nodes,
+	as published on the nodes by kops-controller. Certificates that expire
+	within the warning or critical threshold are reported.`))
+
+	getCertificatesExample = templates.Examples(i18n.T(`
+	# List all certificates of the cluster.
+	kops get certificates
+
+	# List the certificates of the etcd-manager CAs, warning two months ahead.
+	kops get certificates etcd-manager-ca-main etcd-manager-ca-events --warning-threshold 1440h`))
+
+	getCertificatesShort = i18n.T(`Get the certificates of a cluster and their expiry.`)
+)
+
+type GetCertificatesOptions struct {
+	*GetOptions
+	KeysetNames []string
+
+	// WarningThreshold is the remaining lifetime below which a certificate is reported as a warning.
+	WarningThreshold time.Duration
+	// CriticalThreshold is the remaining lifetime below which a certificate is reported as critical.
+	CriticalThreshold time.Duration
+}
+
+func NewCmdGetCertificates(f *util.Factory, out io.Writer, getOptions *GetOptions) *cobra.Command {
+	options := &GetCertificatesOptions{
+		GetOptions:        getOptions,
+		WarningThreshold:  certinventory.DefaultThresholds.Warning,
+		CriticalThreshold: certinventory.DefaultThresholds.Critical,
+	}
+	cmd := &cobra.Command{
+		Use:     "certificates [KEYSET]...",
+		Aliases: []string{"certificate", "certs"},
+		Short:   getCertificatesShort,
+		Long:    getCertificatesLong,
+		Example: getCertificatesExample,
+		Args: func(cmd *cobra.Command, args []string) error {
+			options.ClusterName = rootCommand.ClusterName(true)
+			if options.ClusterName == "" {
+				return fmt.Errorf("--name is required")
+			}
+
+			options.KeysetNames = args
+			return nil
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completeGetKeypairs(cmd.Context(), f, &GetKeypairsOptions{GetOptions: options.GetOptions}, args, toComplete)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunGetCertificates(cmd.Context(), f, out, options)
+		},
+	}
+
+	cmd.Flags().DurationVar(&options.WarningThreshold, "warning-threshold", options.WarningThreshold, "Report certificates that expire within this duration as a warning")
+	cmd.Flags().DurationVar(&options.CriticalThreshold, "critical-threshold", options.CriticalThreshold, "Report certificates that expire within this duration as critical")
+
+	return cmd
+}
+
+type certificateItem struct {
+	Keyset string `json:"keyset"`
+	// ID is the id of the keypair, for certificates of a keyset.
+	ID string `json:"id,omitempty"`
+	// Node and Name identify a leaf certificate issued on a node.
+	Node           string               `json:"node,omitempty"`
+	Name           string               `json:"name,omitempty"`
+	Subject        string               `json:"subject,omitempty"`
+	Issuer         string               `json:"issuer,omitempty"`
+	AlternateNames []string             `json:"alternateNames,omitempty"`
+	IsCA           bool                 `json:"isCA,omitempty"`
+	NotAfter       *time.Time           `json:"notAfter,omitempty"`
+	Status         certinventory.Status `json:"status"`
+}
+
+func RunGetCertificates(ctx context.Context, f *util.Factory, out io.Writer, options *GetCertificatesOptions) error {
+	if options.CriticalThreshold > options.WarningThreshold {
+		return fmt.Errorf("--critical-threshold must not be greater than --warning-threshold")
+	}
+
+	clientset, err := f.KopsClient()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := clientset.GetCluster(ctx, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	keyStore, err := clientset.KeyStore(cluster)
+	if err != nil {
+		return err
+	}
+
+	keypairs, err := listKeypairs(keyStore, options.KeysetNames, false)
+	if err != nil {
+		return err
+	}
+
+	var nodes []corev1.Node
+	{
+		restConfig, err := f.RESTConfig(cluster)
+		if err != nil {
+			return err
+		}
+
+		httpClient, err := f.HTTPClient(cluster)
+		if err != nil {
+			return err
+		}
+
+		k8sClient, err := kubernetes.NewForConfigAndClient(restConfig, httpClient)
+		if err != nil {
+			return fmt.Errorf("building kubernetes client: %w", err)
+		}
+
+		nodeList, err := k8sClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			klog.Warningf("cannot list node certificates. Kubernetes API unavailable: %v", err)
+		} else {
+			nodes = nodeList.Items
+		}
+	}
+
+	thresholds := certinventory.Thresholds{
+		Warning:  options.WarningThreshold,
+		Critical: options.CriticalThreshold,
+	}
+	items := buildCertificateItems(keypairs, nodes, options.KeysetNames, thresholds, time.Now())
+	if len(items) == 0 {
+		return fmt.Errorf("no certificates found")
+	}
+
+	for _, item := range items {
+		if item.NotAfter == nil || item.Status == certinventory.StatusOK {
+			continue
+		}
+		klog.Warningf("%s: certificate %s expires %s (%s)", item.Status, item.description(), item.NotAfter.Local().Format(time.RFC3339), humanizeRemaining(time.Until(*item.NotAfter)))
+	}
+
+	switch options.Output {
+	case OutputTable:
+		t := &tables.Table{}
+		t.AddColumn("KEYSET", func(i *certificateItem) string {
+			return i.Keyset
+		})
+		t.AddColumn("ID", func(i *certificateItem) string {
+			return i.ID
+		})
+		t.AddColumn("NODE", func(i *certificateItem) string {
+			return i.Node
+		})
+		t.AddColumn("NAME", func(i *certificateItem) string {
+			return i.Name
+		})
+		t.AddColumn("SUBJECT", func(i *certificateItem) string {
+			return i.Subject
+		})
+		t.AddColumn("EXPIRES", func(i *certificateItem) string {
+			if t := i.NotAfter; t != nil {
+				return t.Local().Format("2006-01-02")
+			}
+			return ""
+		})
+		t.AddColumn("STATUS", func(i *certificateItem) string {
+			return string(i.Status)
+		})
+		return t.Render(items, out, "KEYSET", "ID", "NODE", "NAME", "SUBJECT", "EXPIRES", "STATUS")
+
+	case OutputYaml:
+		y, err := yaml.Marshal(items)
+		if err != nil {
+			return fmt.Errorf("unable to marshal YAML: %v", err)
+		}
+		if _, err := out.Write(y); err != nil {
+			return fmt.Errorf("error writing to output: %v", err)
+		}
+	case OutputJSON:
+		j, err := json.Marshal(items)
+		if err != nil {
+			return fmt.Errorf("unable to marshal JSON: %v", err)
+		}
+		if _, err := out.Write(j); err != nil {
+			return fmt.Errorf("error writing to output: %v", err)
+		}
+
+	default:
+		return fmt.Errorf("unknown output format: %q", options.Output)
+	}
+
+	return nil
+}
+
+// buildCertificateItems combines the keypairs of the keystore with the leaf certificates published on the nodes,
+// and classifies their expiry.
+func buildCertificateItems(keypairs []*keypairItem, nodes []corev1.Node, keysetNames []string, thresholds certinventory.Thresholds, now time.Time) []*certificateItem {
+	var items []*certificateItem
+
+	for _, keypair := range keypairs {
+		items = append(items, &certificateItem{
+			Keyset:         keypair.Name,
+			ID:             keypair.ID,
+			Subject:        keypair.Subject,
+			Issuer:         keypair.Issuer,
+			AlternateNames: keypair.AlternateNames,
+			IsCA:           keypair.IsCA,
+			NotAfter:       keypair.NotAfter,
+		})
+	}
+
+	for i := range nodes {
+		node := &nodes[i]
+		value, found := node.Annotations[certinventory.NodeAnnotation]
+		if !found {
+			continue
+		}
+		certs, err := certinventory.DecodeAnnotation(value)
+		if err != nil {
+			klog.Warningf("ignoring certificates of node %q: %v", node.Name, err)
+			continue
+		}
+		for _, cert := range certs {
+			if len(keysetNames) != 0 && !slices.Contains(keysetNames, cert.Keyset) {
+				continue
+			}
+			notAfter := cert.NotAfter
+			items = append(items, &certificateItem{
+				Keyset:         cert.Keyset,
+				Node:           node.Name,
+				Name:           cert.Name,
+				Subject:        cert.Subject,
+				Issuer:         cert.Issuer,
+				AlternateNames: cert.AlternateNames,
+				NotAfter:       &notAfter,
+			})
+		}
+	}
+
+	for _, item := range items {
+		if item.NotAfter != nil {
+			item.Status = thresholds.Status(*item.NotAfter, now)
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if a.Keyset != b.Keyset {
+			return a.Keyset < b.Keyset
+		}
+		if a.Node != b.Node {
+			return a.Node < b.Node
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.ID < b.ID
+	})
+
+	return items
+}
+
+func (i *certificateItem) description() string {
+	if i.Node != "" {
+		return fmt.Sprintf("%q on node %q (keyset %q)", i.Name, i.Node, i.Keyset)
+	}
+	return fmt.Sprintf("%q of keyset %q", i.ID, i.Keyset)
+}
+
+// humanizeRemaining formats the remaining lifetime of a certificate in days.
+func humanizeRemaining(d time.Duration) string {
+	if d <= 0 {
+		return "expired"
+	}
+	days := int(d.Hours() / 24)
+	switch days {
+	case 0:
+		return "in less than a day"
+	case 1:
+		return "in 1 day"
+	default:
+		return fmt.Sprintf("in %d days", days)
+	}
+}
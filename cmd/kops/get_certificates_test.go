@@ -0,0 +1,116 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/certinventory"
+)
+
+func TestBuildCertificateItems(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	at := func(d time.Duration) *time.Time {
+		t := now.Add(d)
+		return &t
+	}
+
+	keypairs := []*keypairItem{
+		{Name: "kubernetes-ca", ID: "2", Subject: "CN=kubernetes-ca", IsCA: true, NotAfter: at(3650 * day)},
+		{Name: "etcd-manager-ca-main", ID: "1", Subject: "CN=etcd-manager-ca-main", IsCA: true, NotAfter: at(5 * day)},
+	}
+
+	annotation, err := certinventory.EncodeAnnotation([]*certinventory.Certificate{
+		{Name: "kube-apiserver", Keyset: "kubernetes-ca", Subject: "CN=kubernetes-master", NotAfter: now.Add(20 * day)},
+		{Name: "etcd-client", Keyset: "etcd-clients-ca", Subject: "CN=kube-apiserver", NotAfter: now.Add(-day)},
+	})
+	if err != nil {
+		t.Fatalf("encoding annotation: %v", err)
+	}
+	nodes := []corev1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "control-plane-a", Annotations: map[string]string{certinventory.NodeAnnotation: annotation}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "broken", Annotations: map[string]string{certinventory.NodeAnnotation: "{"}}},
+	}
+
+	type result struct {
+		Keyset string
+		ID     string
+		Node   string
+		Name   string
+		Status certinventory.Status
+	}
+	grid := []struct {
+		name        string
+		keysetNames []string
+		thresholds  certinventory.Thresholds
+		expected    []result
+	}{
+		{
+			name:       "default thresholds",
+			thresholds: certinventory.DefaultThresholds,
+			expected: []result{
+				{Keyset: "etcd-clients-ca", Node: "control-plane-a", Name: "etcd-client", Status: certinventory.StatusExpired},
+				{Keyset: "etcd-manager-ca-main", ID: "1", Status: certinventory.StatusCritical},
+				{Keyset: "kubernetes-ca", ID: "2", Status: certinventory.StatusOK},
+				{Keyset: "kubernetes-ca", Node: "control-plane-a", Name: "kube-apiserver", Status: certinventory.StatusWarning},
+			},
+		},
+		{
+			name:       "custom thresholds",
+			thresholds: certinventory.Thresholds{Warning: 10 * day, Critical: time.Hour},
+			expected: []result{
+				{Keyset: "etcd-clients-ca", Node: "control-plane-a", Name: "etcd-client", Status: certinventory.StatusExpired},
+				{Keyset: "etcd-manager-ca-main", ID: "1", Status: certinventory.StatusWarning},
+				{Keyset: "kubernetes-ca", ID: "2", Status: certinventory.StatusOK},
+				{Keyset: "kubernetes-ca", Node: "control-plane-a", Name: "kube-apiserver", Status: certinventory.StatusOK},
+			},
+		},
+		{
+			// The keypairs are already filtered by listKeypairs
+			name:        "keyset filter",
+			keysetNames: []string{"kubernetes-ca"},
+			thresholds:  certinventory.DefaultThresholds,
+			expected: []result{
+				{Keyset: "etcd-manager-ca-main", ID: "1", Status: certinventory.StatusCritical},
+				{Keyset: "kubernetes-ca", ID: "2", Status: certinventory.StatusOK},
+				{Keyset: "kubernetes-ca", Node: "control-plane-a", Name: "kube-apiserver", Status: certinventory.StatusWarning},
+			},
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			items := buildCertificateItems(keypairs, nodes, g.keysetNames, g.thresholds, now)
+			var actual []result
+			for _, item := range items {
+				actual = append(actual, result{Keyset: item.Keyset, ID: item.ID, Node: item.Node, Name: item.Name, Status: item.Status})
+			}
+			if len(actual) != len(g.expected) {
+				t.Fatalf("expected %d items, got %d: %+v", len(g.expected), len(actual), actual)
+			}
+			for i := range actual {
+				if actual[i] != g.expected[i] {
+					t.Errorf("item %d: expected %+v, got %+v", i, g.expected[i], actual[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,377 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/registry"
+	"k8s.io/kops/pkg/commands/commandutils"
+	nodeidentityaws "k8s.io/kops/pkg/nodeidentity/aws"
+	nodeidentityazure "k8s.io/kops/pkg/nodeidentity/azure"
+	"k8s.io/kops/pkg/pretty"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
+	"k8s.io/kops/upup/pkg/fi/cloudup/hetzner"
+	"k8s.io/kops/util/pkg/tables"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	getDriftLong = pretty.LongDesc(i18n.T(`
+	Display cloud resources whose live state differs from the kOps model.
+
+	The cluster is evaluated as in a dry-run of ` + pretty.Bash("kops update cluster") + `, but only
+	cloud resources are reported, grouped by instance group. Nothing is changed.
+
+	The command exits with status 2 if drift was found, so that it can be used from
+	scheduled jobs. If the cluster spec has been changed since it was last applied,
+	the cloud resources are compared against the last applied cluster spec instead,
+	so that the pending changes are not reported. Changes to instance groups that
+	have not been applied yet are reported as drift.`))
+
+	getDriftExample = templates.Examples(i18n.T(`
+	# Display resources that have drifted from the kOps model.
+	kops get drift
+
+	# Display drift as JSON.
+	kops get drift -o json
+	`))
+
+	getDriftShort = i18n.T(`Display cloud resources that have drifted from the cluster spec.`)
+)
+
+// DriftType describes how a resource differs from the model.
+type DriftType string
+
+const (
+	// DriftTypeMissing is a resource that is in the model, but not in the cloud.
+	DriftTypeMissing DriftType = "Missing"
+	// DriftTypeModified is a resource whose live state differs from the model.
+	DriftTypeModified DriftType = "Modified"
+	// DriftTypeExtra is a resource that is in the cloud, but no longer in the model.
+	DriftTypeExtra DriftType = "Extra"
+)
+
+// driftIgnoredKinds are task kinds that do not correspond to cloud resources;
+// they manage objects in the state store.
+var driftIgnoredKinds = map[string]bool{
+	"Keypair":        true,
+	"ManagedFile":    true,
+	"MirrorKeystore": true,
+	"MirrorSecrets":  true,
+	"Secret":         true,
+}
+
+// instanceGroupTagKeys are the cloud tags and labels that hold the name of the instance group owning a resource.
+var instanceGroupTagKeys = []string{
+	nodeidentityaws.CloudTagInstanceGroupName,
+	nodeidentityazure.InstanceGroupNameTag,
+	gce.GceLabelNameInstanceGroup,
+	hetzner.TagKubernetesInstanceGroup,
+}
+
+type GetDriftOptions struct {
+	*GetOptions
+}
+
+// DriftResource is a cloud resource that has drifted from the model.
+type DriftResource struct {
+	// InstanceGroup is the instance group the resource belongs to; it is empty for cluster-wide resources.
+	InstanceGroup string    `json:"instanceGroup,omitempty"`
+	Kind          string    `json:"kind"`
+	Name          string    `json:"name"`
+	Drift         DriftType `json:"drift"`
+	// Fields are the fields whose live value differs from the model.
+	Fields []*fi.PlanField `json:"fields,omitempty"`
+}
+
+// DriftResult is the result of drift detection.
+type DriftResult struct {
+	// PendingSpecChanges is true if the cluster spec has changed since it was last applied.
+	PendingSpecChanges bool `json:"pendingSpecChanges,omitempty"`
+	// Resources are the resources that have drifted.
+	Resources []*DriftResource `json:"resources,omitempty"`
+}
+
+func NewCmdGetDrift(f *util.Factory, out io.Writer, getOptions *GetOptions) *cobra.Command {
+	options := GetDriftOptions{
+		GetOptions: getOptions,
+	}
+
+	cmd := &cobra.Command{
+		Use:               "drift [CLUSTER]",
+		Short:             getDriftShort,
+		Long:              getDriftLong,
+		Example:           getDriftExample,
+		Args:              rootCommand.clusterNameArgs(&options.ClusterName),
+		ValidArgsFunction: commandutils.CompleteClusterName(f, true, false),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			result, err := RunGetDrift(cmd.Context(), f, out, &options)
+			if err != nil {
+				return err
+			}
+
+			// Exit non-zero if drift was found, even though no error occurred.
+			if len(result.Resources) != 0 {
+				return &exitCodeError{code: 2, err: fmt.Errorf("drift detected in %d resources", len(result.Resources))}
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func RunGetDrift(ctx context.Context, f *util.Factory, out io.Writer, options *GetDriftOptions) (*DriftResult, error) {
+	result, cluster, err := detectDrift(ctx, f, out, options, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.PendingSpecChanges {
+		// Compare against the spec the cloud resources were last updated to, so that changes
+		// that have not been applied yet are not reported as drift.
+		klog.Warningf("cluster spec has changed since it was last applied; comparing against the last applied spec")
+		clientset, err := f.KopsClient()
+		if err != nil {
+			return nil, err
+		}
+		lastApplied, err := fullClusterSpecs(ctx, clientset.VFSContext(), []*kops.Cluster{cluster})
+		if err != nil {
+			return nil, err
+		}
+		result, _, err = detectDrift(ctx, f, out, options, lastApplied[0])
+		if err != nil {
+			return nil, err
+		}
+		result.PendingSpecChanges = true
+	}
+
+	switch options.Output {
+	case OutputTable:
+		if len(result.Resources) == 0 {
+			fmt.Fprintf(out, "No drift detected\n")
+			return result, nil
+		}
+		return result, driftOutputTable(result.Resources, out)
+	case OutputYaml:
+		y, err := yaml.Marshal(result)
+		if err != nil {
+			return nil, fmt.Errorf("unable to marshal YAML: %v", err)
+		}
+		if _, err := out.Write(y); err != nil {
+			return nil, fmt.Errorf("error writing to output: %v", err)
+		}
+	case OutputJSON:
+		j, err := json.Marshal(result)
+		if err != nil {
+			return nil, fmt.Errorf("unable to marshal JSON: %v", err)
+		}
+		if _, err := out.Write(append(j, '\n')); err != nil {
+			return nil, fmt.Errorf("error writing to output: %v", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported output format: %q", options.Output)
+	}
+
+	return result, nil
+}
+
+// detectDrift evaluates the cluster as in a dry-run, and returns the drift of the cloud resources from the model.
+// If lastApplied is set, it is evaluated instead of the cluster in the state store.
+func detectDrift(ctx context.Context, f *util.Factory, out io.Writer, options *GetDriftOptions, lastApplied *kops.Cluster) (*DriftResult, *kops.Cluster, error) {
+	updateClusterResults, err := RunUpdateCluster(ctx, f, out, &UpdateClusterOptions{
+		CoreUpdateClusterOptions: CoreUpdateClusterOptions{
+			Target:      cloudup.TargetDryRun,
+			DetectDrift: true,
+			ClusterName: options.ClusterName,
+			Cluster:     lastApplied,
+		},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	target := updateClusterResults.Target.(*fi.CloudupDryRunTarget)
+	plan, err := target.BuildPlan(updateClusterResults.TaskMap)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error building plan: %w", err)
+	}
+
+	return buildDriftResult(plan, updateClusterResults.TaskMap, updateClusterResults.InstanceGroups), updateClusterResults.Cluster, nil
+}
+
+// buildDriftResult extracts the cloud resources from the plan, and assigns them to the instance groups owning them.
+func buildDriftResult(plan *fi.Plan, taskMap map[string]fi.CloudupTask, instanceGroups []*kops.InstanceGroup) *DriftResult {
+	result := &DriftResult{}
+
+	igNames := make(map[string]bool)
+	for _, ig := range instanceGroups {
+		igNames[ig.ObjectMeta.Name] = true
+	}
+
+	for _, c := range plan.Changes {
+		if c.Kind == "ManagedFile" && c.Name == registry.PathClusterCompleted && c.Action == fi.PlanActionUpdate {
+			result.PendingSpecChanges = true
+		}
+		if driftIgnoredKinds[c.Kind] {
+			continue
+		}
+		drift := DriftTypeModified
+		if c.Action == fi.PlanActionCreate {
+			drift = DriftTypeMissing
+		}
+		result.Resources = append(result.Resources, &DriftResource{
+			InstanceGroup: instanceGroupForTask(taskMap[c.Kind+"/"+c.Name], igNames),
+			Kind:          c.Kind,
+			Name:          c.Name,
+			Drift:         drift,
+			Fields:        c.Fields,
+		})
+	}
+
+	for _, d := range plan.Deletions {
+		if driftIgnoredKinds[d.Kind] {
+			continue
+		}
+		// Resources are usually deleted because no task manages them any more; those that
+		// have the name of a task, such as old versions of it, belong to the task's instance group.
+		result.Resources = append(result.Resources, &DriftResource{
+			InstanceGroup: instanceGroupForTask(taskMap[d.Kind+"/"+d.Item], igNames),
+			Kind:          d.Kind,
+			Name:          d.Item,
+			Drift:         DriftTypeExtra,
+		})
+	}
+
+	sort.SliceStable(result.Resources, func(i, j int) bool {
+		a, b := result.Resources[i], result.Resources[j]
+		if a.InstanceGroup != b.InstanceGroup {
+			return a.InstanceGroup < b.InstanceGroup
+		}
+		return a.Kind < b.Kind
+	})
+
+	return result
+}
+
+// instanceGroupForTask returns the name of the instance group owning the resource of a task, or "" if none.
+// The resources of an instance group are tagged or labelled with its name; a resource without such tags,
+// such as a GCE instance group manager, belongs to the instance group of the task it references.
+func instanceGroupForTask(task fi.CloudupTask, instanceGroups map[string]bool) string {
+	if task == nil {
+		return ""
+	}
+	if name := instanceGroupFromTags(task, instanceGroups); name != "" {
+		return name
+	}
+
+	v := reflect.ValueOf(task)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return ""
+	}
+	v = v.Elem()
+	owner := ""
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		if field.Kind() != reflect.Ptr || field.IsNil() || !field.CanInterface() {
+			continue
+		}
+		referenced, ok := field.Interface().(fi.CloudupTask)
+		if !ok {
+			continue
+		}
+		name := instanceGroupFromTags(referenced, instanceGroups)
+		if name == "" {
+			continue
+		}
+		if owner != "" && owner != name {
+			// References resources of several instance groups
+			return ""
+		}
+		owner = name
+	}
+	return owner
+}
+
+// instanceGroupFromTags returns the instance group named in the Tags or Labels of a task, or "" if none.
+func instanceGroupFromTags(task fi.CloudupTask, instanceGroups map[string]bool) string {
+	v := reflect.ValueOf(task)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return ""
+	}
+	v = v.Elem()
+	for _, fieldName := range []string{"Tags", "Labels"} {
+		field := v.FieldByName(fieldName)
+		if !field.IsValid() || field.Kind() != reflect.Map || field.Type().Key().Kind() != reflect.String {
+			continue
+		}
+		for _, key := range instanceGroupTagKeys {
+			value := field.MapIndex(reflect.ValueOf(key).Convert(field.Type().Key()))
+			if value.IsValid() && value.Kind() == reflect.Ptr {
+				if value.IsNil() {
+					continue
+				}
+				value = value.Elem()
+			}
+			if value.IsValid() && value.Kind() == reflect.String && instanceGroups[value.String()] {
+				return value.String()
+			}
+		}
+	}
+	return ""
+}
+
+func driftOutputTable(resources []*DriftResource, out io.Writer) error {
+	t := &tables.Table{}
+	t.AddColumn("INSTANCEGROUP", func(r *DriftResource) string {
+		return r.InstanceGroup
+	})
+	t.AddColumn("KIND", func(r *DriftResource) string {
+		return r.Kind
+	})
+	t.AddColumn("NAME", func(r *DriftResource) string {
+		return r.Name
+	})
+	t.AddColumn("DRIFT", func(r *DriftResource) string {
+		return string(r.Drift)
+	})
+	t.AddColumn("FIELDS", func(r *DriftResource) string {
+		var fields []string
+		for _, field := range r.Fields {
+			fields = append(fields, field.Name)
+		}
+		return strings.Join(fields, ",")
+	})
+
+	columns := []string{"INSTANCEGROUP", "KIND", "NAME", "DRIFT", "FIELDS"}
+	return t.Render(resources, out, columns...)
+}
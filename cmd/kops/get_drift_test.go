@@ -0,0 +1,93 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awstasks"
+	"k8s.io/kops/upup/pkg/fi/cloudup/gcetasks"
+)
+
+func TestBuildDriftResult(t *testing.T) {
+	instanceGroups := []*kops.InstanceGroup{
+		{ObjectMeta: metav1.ObjectMeta{Name: "nodes"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "nodes-gpu"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "control-plane-us-test-1a"}},
+	}
+
+	igTags := func(name string) map[string]string {
+		return map[string]string{"Name": name, "kops.k8s.io/instancegroup": name}
+	}
+	instanceTemplate := &gcetasks.InstanceTemplate{
+		Name:   fi.PtrTo("nodes-gce"),
+		Labels: map[string]string{"k8s-io-instance-group": "nodes"},
+	}
+	taskMap := map[string]fi.CloudupTask{
+		// Shared by the nodes of all instance groups, despite its name
+		"SecurityGroup/nodes.minimal.example.com":                               &awstasks.SecurityGroup{Name: fi.PtrTo("nodes.minimal.example.com"), Tags: map[string]string{"Name": "nodes.minimal.example.com"}},
+		"LaunchTemplate/nodes-gpu.minimal.example.com":                          &awstasks.LaunchTemplate{Name: fi.PtrTo("nodes-gpu.minimal.example.com"), Tags: igTags("nodes-gpu")},
+		"AutoscalingGroup/control-plane-us-test-1a.masters.minimal.example.com": &awstasks.AutoscalingGroup{Name: fi.PtrTo("control-plane-us-test-1a.masters.minimal.example.com"), Tags: igTags("control-plane-us-test-1a")},
+		"InstanceTemplate/nodes-gce":                                            instanceTemplate,
+		"InstanceGroupManager/nodes-gce":                                        &gcetasks.InstanceGroupManager{Name: fi.PtrTo("nodes-gce"), InstanceTemplate: instanceTemplate},
+	}
+
+	tags := &fi.PlanField{Name: "Tags", Old: "{}", New: "{team: a}"}
+	plan := &fi.Plan{
+		Changes: []*fi.PlanChange{
+			{Kind: "ManagedFile", Name: "cluster-completed.spec", Action: fi.PlanActionUpdate},
+			{Kind: "Keypair", Name: "kubernetes-ca", Action: fi.PlanActionCreate},
+			{Kind: "SecurityGroup", Name: "nodes.minimal.example.com", Action: fi.PlanActionUpdate, Fields: []*fi.PlanField{tags}},
+			{Kind: "LaunchTemplate", Name: "nodes-gpu.minimal.example.com", Action: fi.PlanActionUpdate, Fields: []*fi.PlanField{tags}},
+			{Kind: "AutoscalingGroup", Name: "control-plane-us-test-1a.masters.minimal.example.com", Action: fi.PlanActionCreate},
+			{Kind: "InstanceGroupManager", Name: "nodes-gce", Action: fi.PlanActionCreate},
+		},
+		Deletions: []*fi.PlanDeletion{
+			{Kind: "LaunchTemplate", Item: "nodes.minimal.example.com-2", Action: fi.PlanActionDelete},
+			{Kind: "LaunchTemplate", Item: "nodes-gpu.minimal.example.com", Action: fi.PlanActionDelete},
+			{Kind: "VPC", Item: "minimal.example.com", Action: fi.PlanActionDelete},
+		},
+	}
+
+	expected := &DriftResult{
+		PendingSpecChanges: true,
+		Resources: []*DriftResource{
+			{Kind: "LaunchTemplate", Name: "nodes.minimal.example.com-2", Drift: DriftTypeExtra},
+			{Kind: "SecurityGroup", Name: "nodes.minimal.example.com", Drift: DriftTypeModified, Fields: []*fi.PlanField{tags}},
+			{Kind: "VPC", Name: "minimal.example.com", Drift: DriftTypeExtra},
+			{InstanceGroup: "control-plane-us-test-1a", Kind: "AutoscalingGroup", Name: "control-plane-us-test-1a.masters.minimal.example.com", Drift: DriftTypeMissing},
+			{InstanceGroup: "nodes", Kind: "InstanceGroupManager", Name: "nodes-gce", Drift: DriftTypeMissing},
+			{InstanceGroup: "nodes-gpu", Kind: "LaunchTemplate", Name: "nodes-gpu.minimal.example.com", Drift: DriftTypeModified, Fields: []*fi.PlanField{tags}},
+			{InstanceGroup: "nodes-gpu", Kind: "LaunchTemplate", Name: "nodes-gpu.minimal.example.com", Drift: DriftTypeExtra},
+		},
+	}
+
+	assert.Equal(t, expected, buildDriftResult(plan, taskMap, instanceGroups))
+}
+
+func TestBuildDriftResultNeverApplied(t *testing.T) {
+	plan := &fi.Plan{
+		Changes: []*fi.PlanChange{
+			{Kind: "ManagedFile", Name: "cluster-completed.spec", Action: fi.PlanActionCreate},
+		},
+	}
+	assert.False(t, buildDriftResult(plan, nil, nil).PendingSpecChanges)
+}
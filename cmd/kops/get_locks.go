@@ -0,0 +1,176 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/cmd/kops/util"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/pretty"
+	"k8s.io/kops/pkg/statelock"
+	"k8s.io/kops/util/pkg/tables"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	getLocksLong = pretty.LongDesc(i18n.T(`
+	Display the state store locks held by commands that are changing clusters.
+
+	Commands that change a cluster, such as ` + pretty.Bash("kops edit cluster") + ` and ` + pretty.Bash("kops update cluster --yes") + `,
+	hold a lock in the state store while they run. If no cluster is specified, the locks of all clusters are displayed.`))
+
+	getLocksExample = templates.Examples(i18n.T(`
+	# Display the locks of all clusters.
+	kops get locks
+
+	# Display the lock of a cluster as YAML.
+	kops get locks k8s-cluster.example.com -o yaml
+	`))
+
+	getLocksShort = i18n.T(`Display state store locks.`)
+)
+
+type GetLocksOptions struct {
+	*GetOptions
+}
+
+func NewCmdGetLocks(f *util.Factory, out io.Writer, getOptions *GetOptions) *cobra.Command {
+	options := GetLocksOptions{
+		GetOptions: getOptions,
+	}
+
+	cmd := &cobra.Command{
+		Use:               "locks [CLUSTER]",
+		Aliases:           []string{"lock"},
+		Short:             getLocksShort,
+		Long:              getLocksLong,
+		Example:           getLocksExample,
+		Args:              rootCommand.clusterNameArgsAllowNoCluster(&options.ClusterName),
+		ValidArgsFunction: commandutils.CompleteClusterName(f, true, false),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunGetLocks(cmd.Context(), f, out, &options)
+		},
+	}
+
+	return cmd
+}
+
+func RunGetLocks(ctx context.Context, f *util.Factory, out io.Writer, options *GetLocksOptions) error {
+	clientset, err := f.KopsClient()
+	if err != nil {
+		return err
+	}
+
+	var clusters []*kopsapi.Cluster
+	if options.ClusterName != "" {
+		cluster, err := GetCluster(ctx, f, options.ClusterName)
+		if err != nil {
+			return err
+		}
+		clusters = append(clusters, cluster)
+	} else {
+		list, err := clientset.ListClusters(ctx, metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+		for i := range list.Items {
+			clusters = append(clusters, &list.Items[i])
+		}
+	}
+
+	var leases []*statelock.Lease
+	for _, cluster := range clusters {
+		p, err := statelock.LockPath(clientset.VFSContext(), cluster)
+		if err != nil {
+			return err
+		}
+		lease, err := statelock.ReadLease(ctx, p)
+		if err != nil {
+			return err
+		}
+		if lease != nil {
+			leases = append(leases, lease)
+		}
+	}
+
+	switch options.Output {
+	case OutputTable:
+		if len(leases) == 0 {
+			fmt.Fprintf(out, "No locks found\n")
+			return nil
+		}
+		return locksOutputTable(leases, out)
+	case OutputYaml:
+		y, err := yaml.Marshal(leases)
+		if err != nil {
+			return fmt.Errorf("unable to marshal YAML: %v", err)
+		}
+		if _, err := out.Write(y); err != nil {
+			return fmt.Errorf("error writing to output: %v", err)
+		}
+	case OutputJSON:
+		j, err := json.Marshal(leases)
+		if err != nil {
+			return fmt.Errorf("unable to marshal JSON: %v", err)
+		}
+		if _, err := out.Write(j); err != nil {
+			return fmt.Errorf("error writing to output: %v", err)
+		}
+	default:
+		return fmt.Errorf("unsupported output format: %q", options.Output)
+	}
+
+	return nil
+}
+
+func locksOutputTable(leases []*statelock.Lease, out io.Writer) error {
+	t := &tables.Table{}
+	t.AddColumn("CLUSTER", func(l *statelock.Lease) string {
+		return l.ClusterName
+	})
+	t.AddColumn("OWNER", func(l *statelock.Lease) string {
+		return l.Owner
+	})
+	t.AddColumn("HOST", func(l *statelock.Lease) string {
+		return l.Host
+	})
+	t.AddColumn("OPERATION", func(l *statelock.Lease) string {
+		return l.Operation
+	})
+	t.AddColumn("ACQUIRED", func(l *statelock.Lease) string {
+		return l.AcquireTime.Local().Format(time.RFC3339)
+	})
+	t.AddColumn("EXPIRES", func(l *statelock.Lease) string {
+		if l.Expired() {
+			return "Expired"
+		}
+		return l.ExpireTime.Local().Format(time.RFC3339)
+	})
+
+	columns := []string{"CLUSTER", "OWNER", "HOST", "OPERATION", "ACQUIRED", "EXPIRES"}
+	return t.Render(leases, out, columns...)
+}
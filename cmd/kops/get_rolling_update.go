@@ -0,0 +1,166 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/instancegroups"
+	"k8s.io/kops/pkg/pretty"
+	"k8s.io/kops/util/pkg/tables"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	getRollingUpdateLong = pretty.LongDesc(i18n.T(`
+	Display the progress of the most recent rolling update of a cluster.
+
+	Progress is recorded in the state store by ` + pretty.Bash("kops rolling-update cluster --yes") + `, so it
+	can be displayed from any machine with access to the state store.`))
+
+	getRollingUpdateExample = templates.Examples(i18n.T(`
+	# Display the progress of the rolling update.
+	kops get rolling-update
+
+	# Display the full progress, including instances and validations, as YAML.
+	kops get rolling-update -o yaml
+	`))
+
+	getRollingUpdateShort = i18n.T(`Display the progress of a rolling update.`)
+)
+
+type GetRollingUpdateOptions struct {
+	*GetOptions
+}
+
+func NewCmdGetRollingUpdate(f *util.Factory, out io.Writer, getOptions *GetOptions) *cobra.Command {
+	options := GetRollingUpdateOptions{
+		GetOptions: getOptions,
+	}
+
+	cmd := &cobra.Command{
+		Use:               "rolling-update [CLUSTER]",
+		Short:             getRollingUpdateShort,
+		Long:              getRollingUpdateLong,
+		Example:           getRollingUpdateExample,
+		Args:              rootCommand.clusterNameArgs(&options.ClusterName),
+		ValidArgsFunction: commandutils.CompleteClusterName(f, true, false),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunGetRollingUpdate(cmd.Context(), f, out, &options)
+		},
+	}
+
+	return cmd
+}
+
+func RunGetRollingUpdate(ctx context.Context, f *util.Factory, out io.Writer, options *GetRollingUpdateOptions) error {
+	clientset, err := f.KopsClient()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	journalPath, err := instancegroups.RollingUpdateJournalPath(clientset.VFSContext(), cluster)
+	if err != nil {
+		return err
+	}
+	progress, err := instancegroups.ReadRollingUpdateProgress(ctx, journalPath)
+	if err != nil {
+		return err
+	}
+	if progress == nil {
+		return fmt.Errorf("no rolling update has been recorded for cluster %q", cluster.ObjectMeta.Name)
+	}
+
+	switch options.Output {
+	case OutputTable:
+		fmt.Fprintf(out, "Rolling update started %s on %q: %s\n", progress.StartTime.Format(time.RFC3339), progress.Host, progress.Status)
+		if progress.Error != "" {
+			fmt.Fprintf(out, "Error: %s\n", progress.Error)
+		}
+		fmt.Fprintf(out, "\n")
+		return rollingUpdateOutputTable(progress.InstanceGroups, out)
+	case OutputYaml:
+		y, err := yaml.Marshal(progress)
+		if err != nil {
+			return fmt.Errorf("unable to marshal YAML: %v", err)
+		}
+		if _, err := out.Write(y); err != nil {
+			return fmt.Errorf("error writing to output: %v", err)
+		}
+	case OutputJSON:
+		j, err := json.Marshal(progress)
+		if err != nil {
+			return fmt.Errorf("unable to marshal JSON: %v", err)
+		}
+		if _, err := out.Write(j); err != nil {
+			return fmt.Errorf("error writing to output: %v", err)
+		}
+	default:
+		return fmt.Errorf("unsupported output format: %q", options.Output)
+	}
+
+	return nil
+}
+
+func rollingUpdateOutputTable(instanceGroups []*instancegroups.InstanceGroupProgress, out io.Writer) error {
+	t := &tables.Table{}
+	t.AddColumn("NAME", func(ig *instancegroups.InstanceGroupProgress) string {
+		return ig.Name
+	})
+	t.AddColumn("STATUS", func(ig *instancegroups.InstanceGroupProgress) string {
+		return string(ig.Status)
+	})
+	t.AddColumn("INSTANCES", func(ig *instancegroups.InstanceGroupProgress) string {
+		return strconv.Itoa(len(ig.Order))
+	})
+	t.AddColumn("DRAINED", func(ig *instancegroups.InstanceGroupProgress) string {
+		return strconv.Itoa(len(ig.Drained))
+	})
+	t.AddColumn("TERMINATED", func(ig *instancegroups.InstanceGroupProgress) string {
+		return strconv.Itoa(len(ig.Terminated))
+	})
+	t.AddColumn("LAST-VALIDATION", func(ig *instancegroups.InstanceGroupProgress) string {
+		if len(ig.Validations) == 0 {
+			return ""
+		}
+		if ig.Validations[len(ig.Validations)-1].Succeeded {
+			return "Succeeded"
+		}
+		return "Failed"
+	})
+	t.AddColumn("ERROR", func(ig *instancegroups.InstanceGroupProgress) string {
+		return ig.Error
+	})
+
+	columns := []string{"NAME", "STATUS", "INSTANCES", "DRAINED", "TERMINATED", "LAST-VALIDATION", "ERROR"}
+	return t.Render(instanceGroups, out, columns...)
+}
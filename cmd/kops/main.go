@@ -18,6 +18,7 @@ package main // import "k8s.io/kops/cmd/kops"
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 
@@ -27,10 +28,28 @@ import (
 func main() {
 	ctx := context.Background()
 	if err := run(ctx); err != nil {
+		var exitErr *exitCodeError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.code)
+		}
 		os.Exit(1)
 	}
 }
 
+// exitCodeError is returned by commands that report a result, rather than a failure, with their exit status.
+type exitCodeError struct {
+	code int
+	err  error
+}
+
+func (e *exitCodeError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitCodeError) Unwrap() error {
+	return e.err
+}
+
 func run(ctx context.Context) error {
 	// Set up OpenTelemetry.
 	serviceName := "kops"
@@ -25,6 +25,7 @@ import (
 	"github.com/spf13/cobra"
 	"k8s.io/kops/cmd/kops/util"
 	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/statelock"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kubectl/pkg/util/i18n"
 	"k8s.io/kubectl/pkg/util/templates"
@@ -128,6 +129,12 @@ func RunPromoteKeypair(ctx context.Context, f *util.Factory, out io.Writer, opti
 		return fmt.Errorf("getting clientset: %v", err)
 	}
 
+	lock, err := statelock.Acquire(ctx, clientSet.VFSContext(), cluster, "promote keypair")
+	if err != nil {
+		return err
+	}
+	defer lock.Release(ctx)
+
 	keyStore, err := clientSet.KeyStore(cluster)
 	if err != nil {
 		return fmt.Errorf("getting keystore: %v", err)
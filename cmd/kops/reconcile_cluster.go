@@ -86,6 +86,7 @@ func NewCmdReconcileCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	// cmd.Flags().BoolVar(&options.Internal, "internal", options.Internal, "Use the cluster's internal DNS name. Implies --create-kube-config")
 
 	cmd.Flags().BoolVar(&options.AllowKopsDowngrade, "allow-kops-downgrade", options.AllowKopsDowngrade, "Allow an older version of kOps to update the cluster than last used")
+	cmd.Flags().BoolVar(&options.AcknowledgeRollback, "acknowledge-rollback", options.AcknowledgeRollback, "Update instance groups that were rolled back after their canary failed")
 
 	// These flags from the update command are not obviously needed by reconcile, though we can add them if needed:
 	//
@@ -24,10 +24,14 @@ import (
 	"github.com/spf13/cobra"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/klog/v2"
 	"k8s.io/kops/cmd/kops/util"
 	kopsapi "k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/kopscodecs"
+	"k8s.io/kops/pkg/kubemanifest"
+	"k8s.io/kops/pkg/policy"
+	"k8s.io/kops/pkg/statelock"
 	"k8s.io/kops/upup/pkg/fi/cloudup"
 	"k8s.io/kops/util/pkg/text"
 	"k8s.io/kubectl/pkg/util/i18n"
@@ -45,6 +49,9 @@ var (
 		# Replace an instancegroup using YAML passed into stdin.
 		cat instancegroup.yaml | kops replace -f -
 
+		# Add or replace a cluster policy, labeled with the name of its cluster
+		kops replace -f cluster-policy.yaml
+
 		# Note, if the resource does not exist the command will error, use --force to provision resource
 		kops replace -f my-cluster.yaml --force
 		`))
@@ -91,6 +98,25 @@ func RunReplace(ctx context.Context, f *util.Factory, out io.Writer, c *ReplaceO
 
 	vfsContext := f.VFSContext()
 
+	// locks holds the state store locks taken, by cluster name, so that each cluster is locked once
+	locks := make(map[string]*statelock.Lock)
+	defer func() {
+		for _, lock := range locks {
+			lock.Release(ctx)
+		}
+	}()
+	lockCluster := func(cluster *kopsapi.Cluster) error {
+		if _, found := locks[cluster.ObjectMeta.Name]; found {
+			return nil
+		}
+		lock, err := statelock.Acquire(ctx, clientset.VFSContext(), cluster, "replace")
+		if err != nil {
+			return err
+		}
+		locks[cluster.ObjectMeta.Name] = lock
+		return nil
+	}
+
 	for _, f := range c.Filenames {
 		var contents []byte
 		if f == "-" {
@@ -150,6 +176,9 @@ func RunReplace(ctx context.Context, f *util.Factory, out io.Writer, c *ReplaceO
 							return fmt.Errorf("error creating cluster: %v", err)
 						}
 					} else {
+						if err := lockCluster(cluster); err != nil {
+							return err
+						}
 						_, err = clientset.UpdateCluster(ctx, v, status)
 						if err != nil {
 							return fmt.Errorf("error replacing cluster: %v", err)
@@ -169,6 +198,9 @@ func RunReplace(ctx context.Context, f *util.Factory, out io.Writer, c *ReplaceO
 					}
 					return fmt.Errorf("error fetching cluster %q: %v", clusterName, err)
 				}
+				if err := lockCluster(cluster); err != nil {
+					return err
+				}
 				// check if the instancegroup exists already
 				igName := v.ObjectMeta.Name
 				ig, err := clientset.InstanceGroupsFor(cluster).Get(ctx, igName, metav1.GetOptions{})
@@ -218,6 +250,45 @@ func RunReplace(ctx context.Context, f *util.Factory, out io.Writer, c *ReplaceO
 				if err != nil {
 					return fmt.Errorf("error replacing SSHCredential: %v", err)
 				}
+
+			case *unstructured.Unstructured:
+				if !policy.IsClusterPolicy(v.GroupVersionKind()) {
+					klog.V(2).Infof("Type of object was %T", v)
+					return fmt.Errorf("unhandled kind %q in %q", gvk, f)
+				}
+				clusterName := v.GetLabels()[kopsapi.LabelClusterName]
+				if clusterName == "" {
+					return fmt.Errorf("must specify %q label with cluster name to replace %s", kopsapi.LabelClusterName, policy.KindClusterPolicy)
+				}
+				cluster, err := clientset.GetCluster(ctx, clusterName)
+				if err != nil {
+					if errors.IsNotFound(err) {
+						return fmt.Errorf("cluster %q not found", clusterName)
+					}
+					return fmt.Errorf("error fetching cluster %q: %v", clusterName, err)
+				}
+				if err := lockCluster(cluster); err != nil {
+					return err
+				}
+
+				// Policies are stored with the other additional objects of the cluster, so replace just this one
+				addonsClient := clientset.AddonsFor(cluster)
+				objects, err := addonsClient.List(ctx)
+				if err != nil {
+					return fmt.Errorf("error reading additional objects: %v", err)
+				}
+				var replaced kubemanifest.ObjectList
+				for _, o := range objects {
+					if policy.IsClusterPolicy(o.GroupVersionKind()) && o.GetName() == v.GetName() {
+						continue
+					}
+					replaced = append(replaced, o)
+				}
+				replaced = append(replaced, kubemanifest.NewObject(v.Object))
+				if err := addonsClient.Replace(replaced); err != nil {
+					return fmt.Errorf("error replacing %s: %v", policy.KindClusterPolicy, err)
+				}
+
 			default:
 				klog.V(2).Infof("Type of object was %T", v)
 				return fmt.Errorf("unhandled kind %q in %q", gvk, f)
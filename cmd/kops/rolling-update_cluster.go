@@ -32,6 +32,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/kubernetes"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
+	"k8s.io/klog/v2"
 	"k8s.io/kops/cmd/kops/util"
 	kopsapi "k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/cloudinstances"
@@ -39,6 +40,7 @@ import (
 	"k8s.io/kops/pkg/instancegroups"
 	"k8s.io/kops/pkg/kubeconfig"
 	"k8s.io/kops/pkg/pretty"
+	"k8s.io/kops/pkg/statelock"
 	"k8s.io/kops/pkg/validation"
 	"k8s.io/kops/upup/pkg/fi/cloudup"
 	"k8s.io/kops/util/pkg/tables"
@@ -89,6 +91,17 @@ var (
 		# Update only the "nodes-1a" instance group of the k8s-cluster.example.com kOps cluster.
 		kops rolling-update cluster k8s-cluster.example.com --yes \
 		  --instance-group nodes-1a
+
+		# Continue a rolling update of the k8s-cluster.example.com kOps cluster that was interrupted.
+		kops rolling-update cluster k8s-cluster.example.com --yes --resume
+
+		# Update the k8s-cluster.example.com kOps cluster, replacing one instance in each instance group first.
+		# Roll back an instance group if the cluster does not keep validating for 15 minutes
+		# or the probe fails.
+		kops rolling-update cluster k8s-cluster.example.com --yes \
+		  --canary 1 \
+		  --canary-soak-period 15m \
+		  --canary-probe "curl -sf https://app.example.com/healthz"
 		`))
 
 	rollingupdateShort = i18n.T(`Rolling update a cluster.`)
@@ -144,6 +157,18 @@ type RollingUpdateOptions struct {
 	// if not specified, all instance groups will be updated
 	InstanceGroupRoles []string
 
+	// Resume continues the rolling update recorded in the state store, skipping instance groups that were completed.
+	Resume bool
+
+	// Canary is the number of instances in each instance group to replace before the rest of the group.
+	Canary int
+
+	// CanarySoakPeriod is the amount of time the cluster must validate and the canary probes succeed after the canary instances are replaced.
+	CanarySoakPeriod time.Duration
+
+	// CanaryProbes are shell commands that must succeed during the canary soak period.
+	CanaryProbes []string
+
 	// TODO: Move more/all above options to RollingUpdateOptions
 	instancegroups.RollingUpdateOptions
 
@@ -168,11 +193,51 @@ func (o *RollingUpdateOptions) InitDefaults() {
 
 	o.DrainTimeout = 15 * time.Minute
 
+	o.CanarySoakPeriod = 10 * time.Minute
+
 	o.Admin = kubeconfig.DefaultKubecfgAdminLifetime
 
 	o.RollingUpdateOptions.InitDefaults()
 }
 
+// record returns the options that are recorded with the progress of the rolling update, so that it can be resumed with them.
+func (o *RollingUpdateOptions) record() *instancegroups.RecordedOptions {
+	return &instancegroups.RecordedOptions{
+		CloudOnly:            o.CloudOnly,
+		FailOnDrainError:     o.FailOnDrainError,
+		FailOnValidate:       o.FailOnValidate,
+		PostDrainDelay:       metav1.Duration{Duration: o.PostDrainDelay},
+		ValidationTimeout:    metav1.Duration{Duration: o.ValidationTimeout},
+		ValidateCount:        o.ValidateCount,
+		DrainTimeout:         metav1.Duration{Duration: o.DrainTimeout},
+		ControlPlaneInterval: metav1.Duration{Duration: o.ControlPlaneInterval},
+		NodeInterval:         metav1.Duration{Duration: o.NodeInterval},
+		BastionInterval:      metav1.Duration{Duration: o.BastionInterval},
+		Canary:               o.Canary,
+		CanarySoakPeriod:     metav1.Duration{Duration: o.CanarySoakPeriod},
+		CanaryProbes:         o.CanaryProbes,
+		TopologyAware:        o.TopologyAware,
+	}
+}
+
+// restore sets the options that were recorded with the progress of the rolling update being resumed.
+func (o *RollingUpdateOptions) restore(recorded *instancegroups.RecordedOptions) {
+	o.CloudOnly = recorded.CloudOnly
+	o.FailOnDrainError = recorded.FailOnDrainError
+	o.FailOnValidate = recorded.FailOnValidate
+	o.PostDrainDelay = recorded.PostDrainDelay.Duration
+	o.ValidationTimeout = recorded.ValidationTimeout.Duration
+	o.ValidateCount = recorded.ValidateCount
+	o.DrainTimeout = recorded.DrainTimeout.Duration
+	o.ControlPlaneInterval = recorded.ControlPlaneInterval.Duration
+	o.NodeInterval = recorded.NodeInterval.Duration
+	o.BastionInterval = recorded.BastionInterval.Duration
+	o.Canary = recorded.Canary
+	o.CanarySoakPeriod = recorded.CanarySoakPeriod.Duration
+	o.CanaryProbes = recorded.CanaryProbes
+	o.TopologyAware = recorded.TopologyAware
+}
+
 func NewCmdRollingUpdateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	var options RollingUpdateOptions
 	options.InitDefaults()
@@ -218,6 +283,11 @@ func NewCmdRollingUpdateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 
 	cmd.Flags().BoolVar(&options.FailOnDrainError, "fail-on-drain-error", true, "Fail if draining a node fails")
 	cmd.Flags().BoolVar(&options.FailOnValidate, "fail-on-validate-error", true, "Fail if the cluster fails to validate")
+	cmd.Flags().BoolVar(&options.Resume, "resume", options.Resume, "Continue an interrupted rolling update with the options it was started with, skipping instance groups it completed")
+	cmd.Flags().BoolVar(&options.TopologyAware, "topology-aware", options.TopologyAware, "Replace nodes in an order that respects PodDisruptionBudgets and does not drain two nodes in the same zone at once")
+	cmd.Flags().IntVar(&options.Canary, "canary", options.Canary, "Number of instances in each instance group to replace and soak before the rest of the group; the group is rolled back if they fail")
+	cmd.Flags().DurationVar(&options.CanarySoakPeriod, "canary-soak-period", options.CanarySoakPeriod, "Time the cluster must keep validating after the canary instances are replaced")
+	cmd.Flags().StringSliceVar(&options.CanaryProbes, "canary-probe", options.CanaryProbes, "Shell command that must succeed during the canary soak period (may be repeated)")
 
 	cmd.Flags().SetNormalizeFunc(func(f *pflag.FlagSet, name string) pflag.NormalizedName {
 		switch name {
@@ -244,6 +314,38 @@ func RunRollingUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer
 		return err
 	}
 
+	journalPath, err := instancegroups.RollingUpdateJournalPath(clientset.VFSContext(), cluster)
+	if err != nil {
+		return err
+	}
+	progress, err := instancegroups.ReadRollingUpdateProgress(ctx, journalPath)
+	if err != nil {
+		return err
+	}
+	if options.Resume {
+		if progress == nil || progress.Status == instancegroups.RollingUpdateStatusComplete {
+			return fmt.Errorf("there is no interrupted rolling update to resume for cluster %q", cluster.ObjectMeta.Name)
+		}
+		if len(options.InstanceGroups) != 0 || len(options.InstanceGroupRoles) != 0 {
+			return fmt.Errorf("cannot specify --instance-group or --instance-group-roles with --resume")
+		}
+		// Continue with the same instance groups and options as the interrupted rolling update
+		options.InstanceGroups = progress.InstanceGroupNames()
+		options.Force = progress.Force
+		if progress.Options != nil {
+			options.restore(progress.Options)
+		}
+	} else if progress != nil && progress.Status != instancegroups.RollingUpdateStatusComplete {
+		klog.Warningf("the rolling update started at %s did not complete; use --resume to continue it", progress.StartTime.Format(time.RFC3339))
+	}
+
+	if options.Canary < 0 {
+		return fmt.Errorf("--canary must not be negative")
+	}
+	if options.Canary == 0 && len(options.CanaryProbes) != 0 {
+		return fmt.Errorf("--canary-probe requires --canary")
+	}
+
 	var nodes []v1.Node
 	var k8sClient kubernetes.Interface
 	if !options.CloudOnly {
@@ -370,6 +472,9 @@ func RunRollingUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer
 		ValidationTimeout: options.ValidationTimeout,
 		ValidateCount:     int(options.ValidateCount),
 		DrainTimeout:      options.DrainTimeout,
+		Canary:            options.Canary,
+		CanarySoakPeriod:  options.CanarySoakPeriod,
+		CanaryProbes:      options.CanaryProbes,
 		// TODO should we expose this to the UI?
 		ValidateTickDuration:    30 * time.Second,
 		ValidateSuccessDuration: 10 * time.Second,
@@ -452,6 +557,12 @@ func RunRollingUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer
 		return nil
 	}
 
+	lock, err := statelock.Acquire(ctx, clientset.VFSContext(), cluster, "rolling-update cluster")
+	if err != nil {
+		return err
+	}
+	defer lock.Release(ctx)
+
 	var clusterValidator validation.ClusterValidator
 	if !options.CloudOnly {
 		restConfig, err := f.RESTConfig(cluster)
@@ -466,6 +577,13 @@ func RunRollingUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer
 	}
 	d.ClusterValidator = clusterValidator
 
+	if options.Resume {
+		d.Journal = instancegroups.ResumeJournal(journalPath, cluster, progress)
+	} else {
+		d.Journal = instancegroups.NewJournal(journalPath, cluster, options.Force, options.record())
+		d.Journal.RetainRollbacks(progress)
+	}
+
 	return d.RollingUpdate(ctx, groups, list)
 }
 
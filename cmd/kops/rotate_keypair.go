@@ -0,0 +1,700 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/acls"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/pkg/statelock"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/util/pkg/vfs"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	rotateKeypairLong = templates.LongDesc(i18n.T(`
+	Rotate the keypairs of one or more keysets.
+
+	The rotation is performed in phases: a new keypair is created in each
+	keyset and the cluster is updated to trust it, the new keypairs are
+	promoted and the cluster is updated to use them, then the previous
+	keypairs are distrusted and the cluster is updated to no longer trust
+	them. Each update is a reconcile of the cluster, including rolling
+	updates of the control plane and nodes.
+
+	Before promoting and before distrusting, the rotation checks that every
+	instance runs the updated configuration and that the kubeconfig of the
+	cluster trusts the new "kubernetes-ca" keypair. If a check fails, the
+	rotation stops, and can be continued by running the command again.
+
+	The last completed phase is recorded in the keystore, so an interrupted
+	rotation is continued from where it stopped. Only one rotation can be
+	in progress at a time.
+
+	If --all is specified, every rotatable keyset is rotated.
+	`))
+
+	rotateKeypairExample = templates.Examples(i18n.T(`
+	# Show the phases of a rotation of all rotatable keysets.
+	kops rotate keypair --all \
+		--name k8s-cluster.example.com --state s3://my-state-store
+
+	# Rotate all rotatable keysets.
+	kops rotate keypair --all --yes \
+		--name k8s-cluster.example.com --state s3://my-state-store
+
+	# Rotate the service-account keyset.
+	kops rotate keypair service-account --yes \
+		--name k8s-cluster.example.com --state s3://my-state-store
+
+	# Continue an interrupted rotation.
+	kops rotate keypair --yes \
+		--name k8s-cluster.example.com --state s3://my-state-store
+	`))
+
+	rotateKeypairShort = i18n.T(`Rotate the keypairs of keysets.`)
+)
+
+type RotateKeypairOptions struct {
+	ClusterName string
+	Keysets     []string
+	All         bool
+	Yes         bool
+}
+
+// NewCmdRotateKeypair returns a rotate keypair command.
+func NewCmdRotateKeypair(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &RotateKeypairOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "keypair {KEYSET... | --all}",
+		Short:   rotateKeypairShort,
+		Long:    rotateKeypairLong,
+		Example: rotateKeypairExample,
+		Args: func(cmd *cobra.Command, args []string) error {
+			options.ClusterName = rootCommand.ClusterName(true)
+
+			if options.ClusterName == "" {
+				return fmt.Errorf("--name is required")
+			}
+
+			if options.All && len(args) > 0 {
+				return fmt.Errorf("cannot specify keysets with --all")
+			}
+			options.Keysets = args
+
+			return nil
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			commandutils.ConfigureKlogForCompletion()
+
+			cluster, clientSet, completions, directive := GetClusterForCompletion(cmd.Context(), f, nil)
+			if cluster == nil {
+				return completions, directive
+			}
+
+			_, _, completions, directive = completeKeyset(cmd.Context(), cluster, clientSet, nil, func(name string, keyset *fi.Keyset) bool {
+				return name != "all" && rotatableKeysetFilter(name, keyset) && !slices.Contains(args, name)
+			})
+			return completions, directive
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunRotateKeypair(cmd.Context(), f, out, options)
+		},
+	}
+
+	cmd.Flags().BoolVar(&options.All, "all", options.All, "Rotate all rotatable keysets")
+	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Perform the rotation, without --yes the phases of the rotation are only displayed")
+
+	return cmd
+}
+
+// keypairRotationPhase is a phase of a keypair rotation.
+type keypairRotationPhase string
+
+const (
+	keypairRotationCreate        keypairRotationPhase = "CreateKeypairs"
+	keypairRotationTrust         keypairRotationPhase = "TrustKeypairs"
+	keypairRotationVerifyTrust   keypairRotationPhase = "VerifyTrust"
+	keypairRotationPromote       keypairRotationPhase = "PromoteKeypairs"
+	keypairRotationReissue       keypairRotationPhase = "ReissueCertificates"
+	keypairRotationVerifyReissue keypairRotationPhase = "VerifyCertificates"
+	keypairRotationDistrust      keypairRotationPhase = "DistrustKeypairs"
+	keypairRotationRemoveTrust   keypairRotationPhase = "RemoveTrust"
+)
+
+// keypairRotationPath is the path, relative to the keystore, at which the progress of a keypair rotation is recorded.
+const keypairRotationPath = "keypair-rotation.json"
+
+// keypairRotationPhases are the phases of a keypair rotation, in the order they are performed.
+var keypairRotationPhases = []struct {
+	Phase       keypairRotationPhase
+	Description string
+	run         func(r *keypairRotator, ctx context.Context) error
+}{
+	{keypairRotationCreate, "create a new keypair in each keyset", (*keypairRotator).createKeypairs},
+	{keypairRotationTrust, "reconcile the cluster, so that the new keypairs are trusted", (*keypairRotator).reconcile},
+	{keypairRotationVerifyTrust, "check that all instances and the kubeconfig trust the new keypairs", (*keypairRotator).verifyTrust},
+	{keypairRotationPromote, "promote the new keypairs to primary", (*keypairRotator).promoteKeypairs},
+	{keypairRotationReissue, "reconcile the cluster, so that certificates are issued by the new keypairs", (*keypairRotator).reconcile},
+	{keypairRotationVerifyReissue, "check that all instances and the kubeconfig use the new keypairs", (*keypairRotator).verifyReissue},
+	{keypairRotationDistrust, "distrust the previous keypairs", (*keypairRotator).distrustKeypairs},
+	{keypairRotationRemoveTrust, "reconcile the cluster, so that the previous keypairs are no longer trusted", (*keypairRotator).reconcile},
+}
+
+// keypairRotation is the progress of a keypair rotation, as recorded in the keystore.
+type keypairRotation struct {
+	// Keysets maps the name of each keyset being rotated to the ID of its new keypair,
+	// which is empty until the keypair has been created.
+	Keysets map[string]string `json:"keysets"`
+	// Phase is the last phase that completed, if any.
+	Phase keypairRotationPhase `json:"phase,omitempty"`
+	// StartTime is when the rotation was started.
+	StartTime time.Time `json:"startTime"`
+	// UpdateTime is when the progress was last recorded.
+	UpdateTime time.Time `json:"updateTime"`
+}
+
+// isComplete returns true if all the phases of the rotation have completed.
+func (r *keypairRotation) isComplete() bool {
+	return r.Phase == keypairRotationPhases[len(keypairRotationPhases)-1].Phase
+}
+
+// nextPhase returns the index of the first phase that has not completed.
+func (r *keypairRotation) nextPhase() int {
+	for i, phase := range keypairRotationPhases {
+		if phase.Phase == r.Phase {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// keysetNames returns the names of the keysets being rotated, sorted.
+func (r *keypairRotation) keysetNames() []string {
+	var names []string
+	for name := range r.Keysets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// readKeypairRotation reads the rotation recorded at the specified path; it returns (nil, nil) if there is none.
+func readKeypairRotation(ctx context.Context, p vfs.Path) (*keypairRotation, error) {
+	data, err := p.ReadFile(ctx)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading keypair rotation from %s: %w", p, err)
+	}
+	rotation := &keypairRotation{}
+	if err := json.Unmarshal(data, rotation); err != nil {
+		return nil, fmt.Errorf("parsing keypair rotation from %s: %w", p, err)
+	}
+	return rotation, nil
+}
+
+// writeKeypairRotation records the rotation at the specified path.
+func writeKeypairRotation(ctx context.Context, p vfs.Path, cluster *kops.Cluster, rotation *keypairRotation) error {
+	rotation.UpdateTime = time.Now().UTC()
+
+	data, err := json.MarshalIndent(rotation, "", "  ")
+	if err != nil {
+		return fmt.Errorf("serializing keypair rotation: %w", err)
+	}
+
+	acl, err := acls.GetACL(ctx, p, cluster)
+	if err != nil {
+		return err
+	}
+	if err := p.WriteFile(ctx, bytes.NewReader(data), acl); err != nil {
+		return fmt.Errorf("writing keypair rotation to %s: %w", p, err)
+	}
+	return nil
+}
+
+// RunRotateKeypair rotates the keypairs of keysets, continuing any rotation in progress.
+func RunRotateKeypair(ctx context.Context, f *util.Factory, out io.Writer, options *RotateKeypairOptions) error {
+	for _, name := range options.Keysets {
+		if name == "all" {
+			return fmt.Errorf("use --all to rotate all rotatable keysets")
+		}
+		if !rotatableKeysetFilter(name, nil) {
+			return fmt.Errorf("rotating keypairs for %q is not supported", name)
+		}
+	}
+
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return fmt.Errorf("getting cluster: %q: %v", options.ClusterName, err)
+	}
+
+	clientSet, err := f.KopsClient()
+	if err != nil {
+		return fmt.Errorf("getting clientset: %v", err)
+	}
+
+	lock, err := statelock.Acquire(ctx, clientSet.VFSContext(), cluster, "rotate keypair")
+	if err != nil {
+		return err
+	}
+	defer lock.Release(ctx)
+	// The rotation updates and rolling-updates the cluster while holding the lock
+	ctx = statelock.WithLock(ctx, lock)
+
+	keyStore, err := clientSet.KeyStore(cluster)
+	if err != nil {
+		return fmt.Errorf("getting keystore: %v", err)
+	}
+	hasVFSPath, ok := keyStore.(fi.HasVFSPath)
+	if !ok {
+		return fmt.Errorf("rotating keypairs is only supported with a VFS state store")
+	}
+	rotationPath := hasVFSPath.VFSPath().Join(keypairRotationPath)
+
+	rotation, err := readKeypairRotation(ctx, rotationPath)
+	if err != nil {
+		return err
+	}
+
+	keysets := options.Keysets
+	if options.All {
+		all, err := keyStore.ListKeysets()
+		if err != nil {
+			return fmt.Errorf("listing keysets: %v", err)
+		}
+		for name := range all {
+			if rotatableKeysetFilter(name, nil) {
+				keysets = append(keysets, name)
+			}
+		}
+	}
+
+	rotation, err = startKeypairRotation(out, rotation, keysets)
+	if err != nil {
+		return err
+	}
+
+	if !options.Yes {
+		fmt.Fprintf(out, "Phases of the rotation of %s:\n", strings.Join(rotation.keysetNames(), ", "))
+		for _, phase := range keypairRotationPhases[rotation.nextPhase():] {
+			fmt.Fprintf(out, "  %s: %s\n", phase.Phase, phase.Description)
+		}
+		fmt.Fprintf(out, "\nMust specify --yes to rotate keypairs\n")
+		return nil
+	}
+
+	r := &keypairRotator{
+		out:      out,
+		cluster:  cluster,
+		keyStore: keyStore,
+		rotation: rotation,
+		path:     rotationPath,
+		reconcileCluster: func(ctx context.Context) error {
+			opt := &CoreUpdateClusterOptions{}
+			opt.InitDefaults()
+			opt.ClusterName = cluster.ObjectMeta.Name
+			opt.Yes = true
+			return RunReconcileCluster(ctx, f, out, opt)
+		},
+		verifyInstances: func(ctx context.Context) error {
+			return verifyInstancesUpdated(ctx, f, cluster)
+		},
+		kubeconfig: clientcmd.NewDefaultPathOptions().GetStartingConfig,
+	}
+	return r.run(ctx)
+}
+
+// startKeypairRotation returns the rotation in progress, if any, or starts a rotation of the keysets.
+func startKeypairRotation(out io.Writer, rotation *keypairRotation, keysets []string) (*keypairRotation, error) {
+	keysets = slices.Clone(keysets)
+	sort.Strings(keysets)
+
+	if rotation != nil && !rotation.isComplete() {
+		if len(keysets) > 0 && !slices.Equal(keysets, rotation.keysetNames()) {
+			return nil, fmt.Errorf("a rotation of %s is in progress; run the command without keysets to continue it", strings.Join(rotation.keysetNames(), ", "))
+		}
+		fmt.Fprintf(out, "Continuing the rotation of %s started %s\n", strings.Join(rotation.keysetNames(), ", "), rotation.StartTime.Format(time.RFC3339))
+		return rotation, nil
+	}
+
+	if len(keysets) == 0 {
+		return nil, fmt.Errorf("must specify the keysets to rotate, or --all")
+	}
+	rotation = &keypairRotation{
+		Keysets:   make(map[string]string),
+		StartTime: time.Now().UTC(),
+	}
+	for _, name := range keysets {
+		rotation.Keysets[name] = ""
+	}
+	return rotation, nil
+}
+
+// keypairRotator performs the phases of a keypair rotation.
+type keypairRotator struct {
+	out      io.Writer
+	cluster  *kops.Cluster
+	keyStore fi.CAStore
+	rotation *keypairRotation
+	path     vfs.Path
+
+	// reconcileCluster updates the cluster, rolling the control plane and then the nodes.
+	reconcileCluster func(ctx context.Context) error
+	// verifyInstances checks that no instance of the cluster needs to be updated.
+	verifyInstances func(ctx context.Context) error
+	// kubeconfig reads the kubeconfig used to access the cluster.
+	kubeconfig func() (*clientcmdapi.Config, error)
+}
+
+// run performs the phases of the rotation that have not completed, recording each phase as it completes.
+func (r *keypairRotator) run(ctx context.Context) error {
+	for _, phase := range keypairRotationPhases[r.rotation.nextPhase():] {
+		fmt.Fprintf(r.out, "Phase %s: %s\n", phase.Phase, phase.Description)
+		if err := phase.run(r, ctx); err != nil {
+			return fmt.Errorf("phase %s: %w\nThe rotation can be continued by running the command again", phase.Phase, err)
+		}
+		r.rotation.Phase = phase.Phase
+		if err := r.save(ctx); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(r.out, "Rotation of %s is complete\n", strings.Join(r.rotation.keysetNames(), ", "))
+	if _, ok := r.rotation.Keysets[fi.CertificateIDCA]; ok {
+		fmt.Fprintf(r.out, "Export the kubeconfig with \"kops export kubeconfig\" to remove the previous keypair from its certificate-authority-data\n")
+	}
+	return nil
+}
+
+func (r *keypairRotator) save(ctx context.Context) error {
+	return writeKeypairRotation(ctx, r.path, r.cluster, r.rotation)
+}
+
+// findKeyset returns the keyset being rotated, and the new keypair if it has been created.
+func (r *keypairRotator) findKeyset(ctx context.Context, name string) (*fi.Keyset, *fi.KeysetItem, error) {
+	keyset, err := r.keyStore.FindKeyset(ctx, name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading keyset %s: %v", name, err)
+	} else if keyset == nil {
+		return nil, nil, fmt.Errorf("keyset %s not found", name)
+	}
+	id := r.rotation.Keysets[name]
+	if id == "" {
+		return keyset, nil, nil
+	}
+	item := keyset.Items[id]
+	if item == nil {
+		return nil, nil, fmt.Errorf("keypair %s %s not found", name, id)
+	}
+	if item.DistrustTimestamp != nil {
+		return nil, nil, fmt.Errorf("keypair %s %s has been distrusted", name, id)
+	}
+	return keyset, item, nil
+}
+
+// newerKeypair returns the newest trusted keypair with a private key that is newer than the primary, if any.
+func newerKeypair(keyset *fi.Keyset) *fi.KeysetItem {
+	newest := keyset.Primary
+	for _, item := range keyset.Items {
+		if item.DistrustTimestamp == nil && item.PrivateKey != nil && item.Certificate != nil && fi.KeysetItemIdOlder(newest.Id, item.Id) {
+			newest = item
+		}
+	}
+	if newest == keyset.Primary {
+		return nil
+	}
+	return newest
+}
+
+// createKeypairs creates a new keypair in each keyset, recording each one as it is created.
+// A keypair that was created by an interrupted run, or staged with "kops create keypair", is used instead of creating another.
+func (r *keypairRotator) createKeypairs(ctx context.Context) error {
+	for _, name := range r.rotation.keysetNames() {
+		keyset, item, err := r.findKeyset(ctx, name)
+		if err != nil {
+			return err
+		}
+		if item != nil {
+			continue
+		}
+
+		item = newerKeypair(keyset)
+		if item != nil {
+			fmt.Fprintf(r.out, "Using %s %s, which is newer than the primary\n", name, item.Id)
+		} else if pki.IsExternalSigner(keyset.Primary.PrivateKey) {
+			// We cannot create keys in the external signer; the user must add the new keypair
+			return fmt.Errorf("the private key of %s is held by an external signer; add the new keypair with \"kops create keypair %s --external-signer\" and rerun", name, name)
+		} else {
+			if err := createKeypair(ctx, r.out, &CreateKeypairOptions{}, name, r.keyStore); err != nil {
+				return fmt.Errorf("creating keypair for %s: %v", name, err)
+			}
+			if keyset, _, err = r.findKeyset(ctx, name); err != nil {
+				return err
+			}
+			if item = newerKeypair(keyset); item == nil {
+				return fmt.Errorf("created keypair for %s not found", name)
+			}
+		}
+
+		r.rotation.Keysets[name] = item.Id
+		if err := r.save(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// promoteKeypairs promotes the new keypair of each keyset.
+func (r *keypairRotator) promoteKeypairs(ctx context.Context) error {
+	for _, name := range r.rotation.keysetNames() {
+		keyset, item, err := r.findKeyset(ctx, name)
+		if err != nil {
+			return err
+		}
+		if keyset.Primary.Id == item.Id {
+			continue
+		}
+		if err := promoteKeypair(ctx, r.out, name, item.Id, r.keyStore); err != nil {
+			return fmt.Errorf("promoting keypair for %s: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// distrustKeypairs distrusts the keypairs of each keyset that are older than the new keypair.
+func (r *keypairRotator) distrustKeypairs(ctx context.Context) error {
+	for _, name := range r.rotation.keysetNames() {
+		keyset, item, err := r.findKeyset(ctx, name)
+		if err != nil {
+			return err
+		}
+		if keyset.Primary.Id != item.Id {
+			return fmt.Errorf("the primary keypair of %s is %s, not the new keypair %s", name, keyset.Primary.Id, item.Id)
+		}
+		if err := distrustKeypair(ctx, r.out, name, nil, r.keyStore); err != nil {
+			return fmt.Errorf("distrusting keypairs for %s: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// reconcile updates the cluster, so that it uses the current keypairs.
+func (r *keypairRotator) reconcile(ctx context.Context) error {
+	return r.reconcileCluster(ctx)
+}
+
+// verifyTrust checks that every instance runs the configuration trusting the new keypairs,
+// and that the kubeconfig trusts the new kubernetes-ca keypair, which will issue the API server certificate.
+func (r *keypairRotator) verifyTrust(ctx context.Context) error {
+	if err := r.verifyInstances(ctx); err != nil {
+		return err
+	}
+
+	if _, ok := r.rotation.Keysets[fi.CertificateIDCA]; !ok {
+		return nil
+	}
+	_, item, err := r.findKeyset(ctx, fi.CertificateIDCA)
+	if err != nil {
+		return err
+	}
+	config, err := r.kubeconfig()
+	if err != nil {
+		return fmt.Errorf("reading kubeconfig: %w", err)
+	}
+	if err := verifyKubeconfigTrusts(config, r.cluster.ObjectMeta.Name, item.Certificate); err != nil {
+		return fmt.Errorf("%w; run \"kops export kubeconfig\" and distribute the new certificate-authority-data to all clients of the cluster", err)
+	}
+	return nil
+}
+
+// verifyReissue checks that every instance runs the configuration using the new keypairs,
+// and that the kubeconfig does not use a credential issued by a kubernetes-ca keypair that is about to be distrusted.
+func (r *keypairRotator) verifyReissue(ctx context.Context) error {
+	if err := r.verifyInstances(ctx); err != nil {
+		return err
+	}
+
+	if _, ok := r.rotation.Keysets[fi.CertificateIDCA]; !ok {
+		return nil
+	}
+	keyset, item, err := r.findKeyset(ctx, fi.CertificateIDCA)
+	if err != nil {
+		return err
+	}
+	var previous []*pki.Certificate
+	for _, other := range keyset.Items {
+		if other.DistrustTimestamp == nil && other.Certificate != nil && fi.KeysetItemIdOlder(other.Id, item.Id) {
+			previous = append(previous, other.Certificate)
+		}
+	}
+	config, err := r.kubeconfig()
+	if err != nil {
+		return fmt.Errorf("reading kubeconfig: %w", err)
+	}
+	if err := verifyKubeconfigCredential(config, r.cluster.ObjectMeta.Name, previous); err != nil {
+		return fmt.Errorf("%w; run \"kops export kubeconfig --admin\" and distribute new credentials to all clients that use one", err)
+	}
+	return nil
+}
+
+// verifyInstancesUpdated checks that no instance of the cluster needs to be updated,
+// which would mean that it does not run the configuration with the current trust bundle.
+func verifyInstancesUpdated(ctx context.Context, f *util.Factory, cluster *kops.Cluster) error {
+	clientSet, err := f.KopsClient()
+	if err != nil {
+		return err
+	}
+
+	cloud, err := cloudup.BuildCloud(cluster)
+	if err != nil {
+		return err
+	}
+
+	restConfig, err := f.RESTConfig(cluster)
+	if err != nil {
+		return err
+	}
+	httpClient, err := f.HTTPClient(cluster)
+	if err != nil {
+		return err
+	}
+	k8sClient, err := kubernetes.NewForConfigAndClient(restConfig, httpClient)
+	if err != nil {
+		return fmt.Errorf("building kubernetes client: %w", err)
+	}
+	nodes, err := getNodes(ctx, k8sClient, false)
+	if err != nil {
+		return err
+	}
+
+	igList, err := clientSet.InstanceGroupsFor(cluster).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	var instanceGroups []*kops.InstanceGroup
+	for i := range igList.Items {
+		instanceGroups = append(instanceGroups, &igList.Items[i])
+	}
+
+	groups, err := cloud.GetCloudGroups(cluster, instanceGroups, false, nodes)
+	if err != nil {
+		return err
+	}
+
+	matched := make(map[string]bool)
+	var notUpdated []string
+	for _, group := range groups {
+		for _, instance := range group.Ready {
+			if instance.Node != nil {
+				matched[instance.Node.Name] = true
+			}
+		}
+		if len(group.NeedUpdate) > 0 {
+			notUpdated = append(notUpdated, fmt.Sprintf("%s (%d)", group.HumanName, len(group.NeedUpdate)))
+		}
+	}
+	for _, node := range nodes {
+		if !matched[node.Name] {
+			klog.Warningf("node %q does not belong to an instance group; check that it trusts the new keypairs", node.Name)
+		}
+	}
+	if len(notUpdated) > 0 {
+		sort.Strings(notUpdated)
+		return fmt.Errorf("instances have not been updated in: %s", strings.Join(notUpdated, ", "))
+	}
+	return nil
+}
+
+// verifyKubeconfigTrusts checks that the kubeconfig context of the cluster trusts the certificate of a CA keypair.
+// A cluster without certificate-authority-data, such as one whose API is served with a certificate
+// of its load balancer, is not checked.
+func verifyKubeconfigTrusts(config *clientcmdapi.Config, contextName string, ca *pki.Certificate) error {
+	kubeContext := config.Contexts[contextName]
+	if kubeContext == nil {
+		return fmt.Errorf("kubeconfig has no context %q", contextName)
+	}
+	cluster := config.Clusters[kubeContext.Cluster]
+	if cluster == nil {
+		return fmt.Errorf("kubeconfig has no cluster %q", kubeContext.Cluster)
+	}
+	if len(cluster.CertificateAuthorityData) == 0 {
+		klog.Infof("kubeconfig cluster %q has no certificate-authority-data; not checking it", kubeContext.Cluster)
+		return nil
+	}
+
+	rest := cluster.CertificateAuthorityData
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type == "CERTIFICATE" && bytes.Equal(block.Bytes, ca.Certificate.Raw) {
+			return nil
+		}
+	}
+	return fmt.Errorf("certificate-authority-data of kubeconfig cluster %q does not include the new keypair", kubeContext.Cluster)
+}
+
+// verifyKubeconfigCredential checks that the client certificate of the kubeconfig context of the cluster,
+// if any, was not issued by one of the previous CA keypairs.
+func verifyKubeconfigCredential(config *clientcmdapi.Config, contextName string, previous []*pki.Certificate) error {
+	kubeContext := config.Contexts[contextName]
+	if kubeContext == nil {
+		return fmt.Errorf("kubeconfig has no context %q", contextName)
+	}
+	authInfo := config.AuthInfos[kubeContext.AuthInfo]
+	if authInfo == nil || len(authInfo.ClientCertificateData) == 0 {
+		return nil
+	}
+	cert, err := pki.ParsePEMCertificate(authInfo.ClientCertificateData)
+	if err != nil {
+		return fmt.Errorf("parsing client-certificate-data of kubeconfig user %q: %w", kubeContext.AuthInfo, err)
+	}
+	for _, ca := range previous {
+		if cert.Certificate.CheckSignatureFrom(ca.Certificate) == nil {
+			return fmt.Errorf("client certificate of kubeconfig user %q was issued by a previous keypair", kubeContext.AuthInfo)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,444 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509/pkix"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/pkg/pki/fakesigner"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+const testRotationCluster = "rotation.example.com"
+
+// testRotationEnv is a keystore and fake cluster operations for driving a keypair rotation.
+type testRotationEnv struct {
+	cluster  *kops.Cluster
+	keyStore *fi.VFSCAStore
+	path     vfs.Path
+
+	reconciles int
+	// instancesNotUpdated makes the next check of the instances fail, as if a rolling update had been interrupted.
+	instancesNotUpdated bool
+	// exportKubeconfig regenerates the kubeconfig from the keystore each time it is read,
+	// as if "kops export kubeconfig --admin" had been run.
+	exportKubeconfig bool
+	kubeconfig       *clientcmdapi.Config
+}
+
+func issueTestCA(t *testing.T, name string, serial int64) (*pki.Certificate, *pki.PrivateKey) {
+	privateKey, err := pki.GeneratePrivateKey()
+	if err != nil {
+		t.Fatalf("error generating private key: %v", err)
+	}
+	s := pki.BuildPKISerial(serial)
+	cert, _, _, err := pki.IssueCert(context.TODO(), &pki.IssueCertRequest{
+		Type:       "ca",
+		Subject:    pkix.Name{CommonName: name, SerialNumber: s.String()},
+		Serial:     s,
+		PrivateKey: privateKey,
+	}, nil)
+	if err != nil {
+		t.Fatalf("error issuing certificate: %v", err)
+	}
+	return cert, privateKey
+}
+
+func newTestRotationEnv(t *testing.T, keysets ...string) *testRotationEnv {
+	ctx := context.TODO()
+
+	vfs.Context.ResetMemfsContext(true)
+	basePath, err := vfs.Context.BuildVfsPath("memfs://tests/" + testRotationCluster + "/pki")
+	if err != nil {
+		t.Fatalf("error building vfspath: %v", err)
+	}
+
+	env := &testRotationEnv{
+		cluster: &kops.Cluster{ObjectMeta: metav1.ObjectMeta{Name: testRotationCluster}},
+		path:    basePath.Join(keypairRotationPath),
+	}
+	env.keyStore = fi.NewVFSCAStore(env.cluster, basePath)
+
+	serial := time.Now().Add(-time.Hour).UnixNano()
+	for _, name := range keysets {
+		serial++
+		cert, privateKey := issueTestCA(t, name, serial)
+		keyset, err := fi.NewKeyset(cert, privateKey)
+		if err != nil {
+			t.Fatalf("error building keyset: %v", err)
+		}
+		if err := env.keyStore.StoreKeyset(ctx, name, keyset); err != nil {
+			t.Fatalf("error storing keyset: %v", err)
+		}
+	}
+
+	env.kubeconfig = env.buildKubeconfig(t)
+	return env
+}
+
+// buildKubeconfig builds a kubeconfig that trusts the trusted kubernetes-ca keypairs,
+// with a client certificate issued by the primary keypair.
+func (e *testRotationEnv) buildKubeconfig(t *testing.T) *clientcmdapi.Config {
+	ctx := context.TODO()
+
+	keyset, err := e.keyStore.FindKeyset(ctx, fi.CertificateIDCA)
+	if err != nil {
+		t.Fatalf("error reading keyset: %v", err)
+	}
+	if keyset == nil {
+		return &clientcmdapi.Config{}
+	}
+	var caData bytes.Buffer
+	for _, item := range keyset.Items {
+		if item.DistrustTimestamp == nil {
+			if _, err := item.Certificate.WriteTo(&caData); err != nil {
+				t.Fatalf("error writing certificate: %v", err)
+			}
+		}
+	}
+
+	clientCert, _, _, err := pki.IssueCert(ctx, &pki.IssueCertRequest{
+		Signer:  fi.CertificateIDCA,
+		Type:    "client",
+		Subject: pkix.Name{CommonName: "kubecfg", Organization: []string{"system:masters"}},
+	}, e.keyStore)
+	if err != nil {
+		t.Fatalf("error issuing client certificate: %v", err)
+	}
+	var clientData bytes.Buffer
+	if _, err := clientCert.WriteTo(&clientData); err != nil {
+		t.Fatalf("error writing certificate: %v", err)
+	}
+
+	return &clientcmdapi.Config{
+		Clusters:  map[string]*clientcmdapi.Cluster{testRotationCluster: {CertificateAuthorityData: caData.Bytes()}},
+		AuthInfos: map[string]*clientcmdapi.AuthInfo{testRotationCluster: {ClientCertificateData: clientData.Bytes()}},
+		Contexts:  map[string]*clientcmdapi.Context{testRotationCluster: {Cluster: testRotationCluster, AuthInfo: testRotationCluster}},
+	}
+}
+
+// rotator builds a rotator for the rotation recorded in the keystore, as a new run of the command would.
+func (e *testRotationEnv) rotator(t *testing.T, keysets ...string) *keypairRotator {
+	ctx := context.TODO()
+
+	recorded, err := readKeypairRotation(ctx, e.path)
+	if err != nil {
+		t.Fatalf("error reading rotation: %v", err)
+	}
+	rotation, err := startKeypairRotation(&bytes.Buffer{}, recorded, keysets)
+	if err != nil {
+		t.Fatalf("error starting rotation: %v", err)
+	}
+
+	return &keypairRotator{
+		out:      &bytes.Buffer{},
+		cluster:  e.cluster,
+		keyStore: e.keyStore,
+		rotation: rotation,
+		path:     e.path,
+		reconcileCluster: func(ctx context.Context) error {
+			e.reconciles++
+			return nil
+		},
+		verifyInstances: func(ctx context.Context) error {
+			if e.instancesNotUpdated {
+				e.instancesNotUpdated = false
+				return errors.New("instances have not been updated in: nodes (1)")
+			}
+			return nil
+		},
+		kubeconfig: func() (*clientcmdapi.Config, error) {
+			if e.exportKubeconfig {
+				e.kubeconfig = e.buildKubeconfig(t)
+			}
+			return e.kubeconfig, nil
+		},
+	}
+}
+
+func (e *testRotationEnv) findKeyset(t *testing.T, name string) *fi.Keyset {
+	keyset, err := e.keyStore.FindKeyset(context.TODO(), name)
+	if err != nil || keyset == nil {
+		t.Fatalf("error reading keyset %s: %v", name, err)
+	}
+	return keyset
+}
+
+func (e *testRotationEnv) recordedPhase(t *testing.T) keypairRotationPhase {
+	rotation, err := readKeypairRotation(context.TODO(), e.path)
+	if err != nil || rotation == nil {
+		t.Fatalf("error reading rotation: %v", err)
+	}
+	return rotation.Phase
+}
+
+func TestRotateKeypairResume(t *testing.T) {
+	ctx := context.TODO()
+	env := newTestRotationEnv(t, fi.CertificateIDCA, "service-account")
+	previousPrimary := env.findKeyset(t, fi.CertificateIDCA).Primary.Id
+
+	// The instances are not updated when the trust is first checked.
+	env.instancesNotUpdated = true
+	r := env.rotator(t, fi.CertificateIDCA, "service-account")
+	err := r.run(ctx)
+	if err == nil || !strings.Contains(err.Error(), "phase VerifyTrust") {
+		t.Fatalf("expected VerifyTrust to fail, got %v", err)
+	}
+	if phase := env.recordedPhase(t); phase != keypairRotationTrust {
+		t.Errorf("expected recorded phase %s, got %s", keypairRotationTrust, phase)
+	}
+	newID := r.rotation.Keysets[fi.CertificateIDCA]
+	if newID == "" || newID == previousPrimary {
+		t.Fatalf("expected a new keypair to be recorded, got %q", newID)
+	}
+	if keyset := env.findKeyset(t, fi.CertificateIDCA); keyset.Primary.Id != previousPrimary {
+		t.Errorf("keypair promoted before the trust was verified")
+	}
+
+	// The kubeconfig does not yet trust the new keypair.
+	r = env.rotator(t)
+	err = r.run(ctx)
+	if err == nil || !strings.Contains(err.Error(), "does not include the new keypair") {
+		t.Fatalf("expected the kubeconfig check to fail, got %v", err)
+	}
+	if phase := env.recordedPhase(t); phase != keypairRotationTrust {
+		t.Errorf("expected recorded phase %s, got %s", keypairRotationTrust, phase)
+	}
+
+	// The kubeconfig is exported after each failure; the rotation completes.
+	env.exportKubeconfig = true
+	r = env.rotator(t)
+	if err := r.run(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !r.rotation.isComplete() || env.recordedPhase(t) != keypairRotationRemoveTrust {
+		t.Errorf("expected the rotation to be complete, got phase %s", env.recordedPhase(t))
+	}
+	if env.reconciles != 3 {
+		t.Errorf("expected 3 reconciles, got %d", env.reconciles)
+	}
+
+	for _, name := range []string{fi.CertificateIDCA, "service-account"} {
+		keyset := env.findKeyset(t, name)
+		if len(keyset.Items) != 2 {
+			t.Errorf("expected 2 keypairs in %s, got %d", name, len(keyset.Items))
+		}
+		if keyset.Primary.Id != r.rotation.Keysets[name] {
+			t.Errorf("expected %s primary %s, got %s", name, r.rotation.Keysets[name], keyset.Primary.Id)
+		}
+		for id, item := range keyset.Items {
+			if distrusted := item.DistrustTimestamp != nil; distrusted != (id != keyset.Primary.Id) {
+				t.Errorf("unexpected trust of %s %s: distrusted=%v", name, id, distrusted)
+			}
+		}
+	}
+
+	// A completed rotation is not continued; a new one is started.
+	r = env.rotator(t, "service-account")
+	if r.rotation.Phase != "" || len(r.rotation.Keysets) != 1 {
+		t.Errorf("expected a new rotation, got %+v", r.rotation)
+	}
+}
+
+func TestRotateKeypairDistrustRequiresNewCredential(t *testing.T) {
+	ctx := context.TODO()
+	env := newTestRotationEnv(t, fi.CertificateIDCA)
+
+	r := env.rotator(t, fi.CertificateIDCA)
+	for _, phase := range []func(r *keypairRotator, ctx context.Context) error{(*keypairRotator).createKeypairs, (*keypairRotator).promoteKeypairs} {
+		if err := phase(r, ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	r.rotation.Phase = keypairRotationReissue
+	if err := r.save(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The client certificate was issued by the previous keypair.
+	env.exportKubeconfig = false
+	r = env.rotator(t)
+	err := r.run(ctx)
+	if err == nil || !strings.Contains(err.Error(), "phase VerifyCertificates") || !strings.Contains(err.Error(), "issued by a previous keypair") {
+		t.Fatalf("expected VerifyCertificates to fail, got %v", err)
+	}
+	for _, item := range env.findKeyset(t, fi.CertificateIDCA).Items {
+		if item.DistrustTimestamp != nil {
+			t.Errorf("keypair %s distrusted before the kubeconfig was updated", item.Id)
+		}
+	}
+
+	env.exportKubeconfig = true
+	if err := env.rotator(t).run(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestRotateKeypairCreateIsIdempotent(t *testing.T) {
+	ctx := context.TODO()
+	env := newTestRotationEnv(t, "service-account")
+
+	// A previous run created the keypair, but was interrupted before recording it.
+	if err := createKeypair(ctx, &bytes.Buffer{}, &CreateKeypairOptions{}, "service-account", env.keyStore); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	r := env.rotator(t, "service-account")
+	if err := r.createKeypairs(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := r.createKeypairs(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	keyset := env.findKeyset(t, "service-account")
+	if len(keyset.Items) != 2 {
+		t.Errorf("expected 2 keypairs, got %d", len(keyset.Items))
+	}
+	if item := newerKeypair(keyset); item == nil || item.Id != r.rotation.Keysets["service-account"] {
+		t.Errorf("expected the existing keypair to be recorded, got %q", r.rotation.Keysets["service-account"])
+	}
+}
+
+func TestRotateKeypairExternalSigner(t *testing.T) {
+	ctx := context.TODO()
+	env := newTestRotationEnv(t)
+
+	server := fakesigner.NewServer("secret")
+	defer server.Close()
+	t.Setenv(pki.ExternalSignerTokenEnvVar, "secret")
+
+	createExternal := func(key string, primary bool) {
+		if _, err := server.AddKeyVersion(key); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		configPath := filepath.Join(t.TempDir(), "signer.yaml")
+		config := "type: http\nurl: " + server.URL + "\nkey: " + key + "\n"
+		if err := os.WriteFile(configPath, []byte(config), 0o600); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		options := &CreateKeypairOptions{ExternalSignerPath: configPath, Primary: primary}
+		if err := createKeypair(ctx, &bytes.Buffer{}, options, "apiserver-aggregator-ca", env.keyStore); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	createExternal("aggregator-1", true)
+
+	keyset := env.findKeyset(t, "apiserver-aggregator-ca")
+	if !pki.IsExternalSigner(keyset.Primary.PrivateKey) {
+		t.Fatalf("expected the primary keypair to be held by the external signer")
+	}
+	if keyset.Primary.Certificate.Subject.CommonName != "apiserver-aggregator-ca" {
+		t.Errorf("unexpected subject %v", keyset.Primary.Certificate.Subject)
+	}
+
+	// The rotation cannot create a key in the external signer
+	r := env.rotator(t, "apiserver-aggregator-ca")
+	err := r.createKeypairs(ctx)
+	if err == nil || !strings.Contains(err.Error(), "external signer") {
+		t.Fatalf("expected an external signer error, got %v", err)
+	}
+
+	// Once the user has added the new keypair, the rotation uses it
+	createExternal("aggregator-2", false)
+	if err := r.createKeypairs(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	keyset = env.findKeyset(t, "apiserver-aggregator-ca")
+	item := keyset.Items[r.rotation.Keysets["apiserver-aggregator-ca"]]
+	if item == nil || !pki.IsExternalSigner(item.PrivateKey) || item.PrivateKey.Key.(*pki.ExternalSigner).Config().Key != "aggregator-2" {
+		t.Errorf("expected the rotation to use the added external keypair")
+	}
+}
+
+func TestStartKeypairRotation(t *testing.T) {
+	inProgress := &keypairRotation{
+		Keysets: map[string]string{"kubernetes-ca": "1", "service-account": "2"},
+		Phase:   keypairRotationPromote,
+	}
+
+	if _, err := startKeypairRotation(&bytes.Buffer{}, nil, nil); err == nil {
+		t.Errorf("expected an error without keysets")
+	}
+	if _, err := startKeypairRotation(&bytes.Buffer{}, inProgress, []string{"kubernetes-ca"}); err == nil {
+		t.Errorf("expected an error for different keysets")
+	}
+
+	rotation, err := startKeypairRotation(&bytes.Buffer{}, inProgress, []string{"service-account", "kubernetes-ca"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rotation != inProgress {
+		t.Errorf("expected the rotation in progress to be continued")
+	}
+	if next := keypairRotationPhases[rotation.nextPhase()].Phase; next != keypairRotationReissue {
+		t.Errorf("expected next phase %s, got %s", keypairRotationReissue, next)
+	}
+
+	rotation, err = startKeypairRotation(&bytes.Buffer{}, nil, []string{"kubernetes-ca"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rotation.nextPhase() != 0 || rotation.isComplete() {
+		t.Errorf("expected a new rotation to start with the first phase")
+	}
+}
+
+func TestVerifyKubeconfig(t *testing.T) {
+	env := newTestRotationEnv(t, fi.CertificateIDCA)
+	keyset := env.findKeyset(t, fi.CertificateIDCA)
+	other, _ := issueTestCA(t, fi.CertificateIDCA, time.Now().UnixNano())
+
+	if err := verifyKubeconfigTrusts(env.kubeconfig, testRotationCluster, keyset.Primary.Certificate); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := verifyKubeconfigTrusts(env.kubeconfig, testRotationCluster, other); err == nil {
+		t.Errorf("expected an error for an untrusted keypair")
+	}
+	if err := verifyKubeconfigTrusts(env.kubeconfig, "other.example.com", other); err == nil {
+		t.Errorf("expected an error for a missing context")
+	}
+
+	if err := verifyKubeconfigCredential(env.kubeconfig, testRotationCluster, []*pki.Certificate{other}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := verifyKubeconfigCredential(env.kubeconfig, testRotationCluster, []*pki.Certificate{keyset.Primary.Certificate}); err == nil {
+		t.Errorf("expected an error for a credential issued by a previous keypair")
+	}
+
+	env.kubeconfig.Clusters[testRotationCluster].CertificateAuthorityData = nil
+	env.kubeconfig.AuthInfos[testRotationCluster].ClientCertificateData = nil
+	if err := verifyKubeconfigTrusts(env.kubeconfig, testRotationCluster, other); err != nil {
+		t.Errorf("unexpected error without certificate-authority-data: %v", err)
+	}
+	if err := verifyKubeconfigCredential(env.kubeconfig, testRotationCluster, []*pki.Certificate{keyset.Primary.Certificate}); err != nil {
+		t.Errorf("unexpected error without a client certificate: %v", err)
+	}
+}
@@ -32,6 +32,7 @@ func NewCmdToolbox(f commandutils.Factory, out io.Writer) *cobra.Command {
 		Short: toolboxShort,
 	}
 
+	cmd.AddCommand(NewCmdToolboxBundle(f, out))
 	cmd.AddCommand(NewCmdToolboxDump(f, out))
 	cmd.AddCommand(NewCmdToolboxEnroll(f, out))
 	cmd.AddCommand(NewCmdToolboxTemplate(f, out))
@@ -0,0 +1,58 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/pkg/commands"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+func NewCmdToolboxBundle(f commandutils.Factory, out io.Writer) *cobra.Command {
+	options := &commands.ToolboxBundleOptions{}
+	options.InitDefaults()
+
+	cmd := &cobra.Command{
+		Use:   "bundle",
+		Short: i18n.T(`Build a bundle to bootstrap machines without network access to assets`),
+		Long: templates.LongDesc(i18n.T(`
+			Builds an archive holding nodeup, the file assets and the container images needed to
+			bootstrap a machine of an instance group, so that it can be enrolled without access to
+			the asset locations or mirrors.
+
+			The bundle holds the bootstrap configuration of the instance group, and for the control plane
+			its keys and secrets, so it is written readable only by its owner and should be kept private.`)),
+		Example: templates.Examples(i18n.T(`
+			kops toolbox bundle --cluster k8s-cluster.example.com --instance-group nodes --target nodes.tar.gz
+			kops toolbox enroll --cluster k8s-cluster.example.com --instance-group nodes --bundle nodes.tar.gz --host 10.0.0.10
+		`)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return commands.RunToolboxBundle(cmd.Context(), f, out, options)
+		},
+	}
+
+	cmd.Flags().StringVar(&options.ClusterName, "cluster", options.ClusterName, "Name of cluster")
+	cmd.Flags().StringVar(&options.InstanceGroup, "instance-group", options.InstanceGroup, "Name of instance-group to build the bundle for")
+	cmd.Flags().StringVar(&options.Architecture, "arch", options.Architecture, "CPU architecture of the machines: amd64 or arm64")
+	cmd.Flags().StringVar(&options.Target, "target", options.Target, "Path of the bundle to write")
+
+	return cmd
+}
@@ -50,5 +50,7 @@ func NewCmdToolboxEnroll(f commandutils.Factory, out io.Writer) *cobra.Command {
 	cmd.Flags().StringVar(&options.SSHUser, "ssh-user", options.SSHUser, "user for ssh")
 	cmd.Flags().IntVar(&options.SSHPort, "ssh-port", options.SSHPort, "port for ssh")
 
+	cmd.Flags().StringVar(&options.Bundle, "bundle", options.Bundle, "Bundle built with kops toolbox bundle, to install on the machine before bootstrapping it")
+
 	return cmd
 }
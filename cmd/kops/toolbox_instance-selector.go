@@ -30,6 +30,7 @@ import (
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/client/simple"
 	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/statelock"
 	"k8s.io/kops/upup/pkg/fi/cloudup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kubectl/pkg/util/i18n"
@@ -341,6 +342,12 @@ func RunToolboxInstanceSelector(ctx context.Context, f commandutils.Factory, out
 		return nil
 	}
 
+	lock, err := statelock.Acquire(ctx, clientset.VFSContext(), cluster, "toolbox instance-selector")
+	if err != nil {
+		return err
+	}
+	defer lock.Release(ctx)
+
 	for _, ig := range newInstanceGroups {
 		_, err = clientset.InstanceGroupsFor(cluster).Create(ctx, ig, metav1.CreateOptions{})
 		if err != nil {
@@ -19,6 +19,8 @@ package main
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -35,14 +37,19 @@ import (
 	"k8s.io/kops/pkg/apis/kops"
 	apisutil "k8s.io/kops/pkg/apis/kops/util"
 	"k8s.io/kops/pkg/assets"
+	"k8s.io/kops/pkg/client/simple"
 	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/instancegroups"
 	"k8s.io/kops/pkg/kubeconfig"
 	"k8s.io/kops/pkg/predicates"
+	"k8s.io/kops/pkg/statelock"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup"
 	"k8s.io/kops/upup/pkg/fi/utils"
+	"k8s.io/kops/util/pkg/vfs"
 	"k8s.io/kubectl/pkg/util/i18n"
 	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
 )
 
 var (
@@ -57,6 +64,13 @@ var (
 	updateClusterExample = templates.Examples(i18n.T(`
 	# After the cluster has been edited or upgraded, update the cloud resources with:
 	kops update cluster k8s-cluster.example.com --state=s3://my-state-store --yes
+
+	# Preview the changes as JSON, for consumption by other tools.
+	kops update cluster k8s-cluster.example.com --state=s3://my-state-store -o json
+
+	# Save the planned changes, review them, then apply exactly that plan.
+	kops update cluster k8s-cluster.example.com --state=s3://my-state-store --out-plan plan.kops
+	kops update cluster k8s-cluster.example.com --state=s3://my-state-store --plan plan.kops --yes
 	`))
 
 	updateClusterShort = i18n.T("Update a cluster.")
@@ -68,6 +82,16 @@ type UpdateClusterOptions struct {
 	// Reconcile is true if we should reconcile the cluster by rolling the control plane and nodes sequentially
 	Reconcile bool
 
+	// Output is the format in which the planned changes are printed during a dry-run.
+	// If empty, a human-readable report is printed.
+	Output string
+
+	// OutPlan is the path of a file to which the planned changes are saved during a dry-run.
+	OutPlan string
+
+	// Plan is the path of a saved plan; the update is refused if the state store or the planned changes differ from when it was saved.
+	Plan string
+
 	kubeconfig.CreateKubecfgOptions
 	CoreUpdateClusterOptions
 }
@@ -85,12 +109,20 @@ type CoreUpdateClusterOptions struct {
 	SSHPublicKey       string
 	RunTasksOptions    fi.RunTasksOptions
 	AllowKopsDowngrade bool
+	// AcknowledgeRollback allows the update to apply the specification of instance groups
+	// that were rolled back after their canary failed.
+	AcknowledgeRollback bool
 	// Bypasses kubelet vs control plane version skew checks,
 	// which by default prevent non-control plane instancegroups
 	// from being updated to a version greater than the control plane
 	IgnoreKubeletVersionSkew bool
 	// GetAssets is whether this is invoked from the CmdGetAssets.
 	GetAssets bool
+	// DetectDrift is whether this is invoked from the CmdGetDrift.
+	DetectDrift bool
+	// Cluster is evaluated instead of the cluster read from the state store;
+	// CmdGetDrift uses it to evaluate the last applied cluster spec.
+	Cluster *kops.Cluster
 
 	ClusterName string
 
@@ -160,6 +192,12 @@ func NewCmdUpdateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	}
 
 	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Create cloud resources, without --yes update is in dry run mode")
+	cmd.Flags().StringVarP(&options.Output, "output", "o", options.Output, "Output format for the planned changes in dry run mode. One of: json, yaml")
+	cmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{OutputJSON, OutputYaml}, cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.Flags().StringVar(&options.OutPlan, "out-plan", options.OutPlan, "Path of a file to which the planned changes are saved in dry run mode")
+	cmd.Flags().StringVar(&options.Plan, "plan", options.Plan, "Path of a plan saved with --out-plan; the update is refused if the state store or the planned changes differ from when it was saved")
 	cmd.Flags().Var(&options.Target, "target", fmt.Sprintf("Target - %q, %q", cloudup.TargetDirect, cloudup.TargetTerraform))
 	cmd.RegisterFlagCompletionFunc("target", completeUpdateClusterTarget(f, &options.CoreUpdateClusterOptions))
 	cmd.Flags().StringVar(&options.SSHPublicKey, "ssh-public-key", options.SSHPublicKey, "SSH public key to use (deprecated: use kops create secret instead)")
@@ -172,6 +210,7 @@ func NewCmdUpdateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	cmd.RegisterFlagCompletionFunc("user", completeKubecfgUser)
 	cmd.Flags().BoolVar(&options.Internal, "internal", options.Internal, "Use the cluster's internal DNS name. Implies --create-kube-config")
 	cmd.Flags().BoolVar(&options.AllowKopsDowngrade, "allow-kops-downgrade", options.AllowKopsDowngrade, "Allow an older version of kOps to update the cluster than last used")
+	cmd.Flags().BoolVar(&options.AcknowledgeRollback, "acknowledge-rollback", options.AcknowledgeRollback, "Update instance groups that were rolled back after their canary failed")
 	cmd.Flags().StringSliceVar(&options.InstanceGroups, "instance-group", options.InstanceGroups, "Instance groups to update (defaults to all if not specified)")
 	cmd.RegisterFlagCompletionFunc("instance-group", completeInstanceGroup(f, &options.InstanceGroups, &options.InstanceGroupRoles))
 	cmd.Flags().StringSliceVar(&options.InstanceGroupRoles, "instance-group-roles", options.InstanceGroupRoles, "Instance group roles to update ("+strings.Join(allRoles, ",")+")")
@@ -206,6 +245,8 @@ type UpdateClusterResults struct {
 	FileAssets []*assets.FileAsset
 	// Cluster is the cluster spec (output).
 	Cluster *kops.Cluster
+	// InstanceGroups are the instance groups of the cluster (output).
+	InstanceGroups []*kops.InstanceGroup
 }
 
 func RunCoreUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *CoreUpdateClusterOptions) (*UpdateClusterResults, error) {
@@ -253,6 +294,23 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Up
 		targetName = cloudup.TargetDryRun
 	}
 
+	switch c.Output {
+	case "":
+	case OutputJSON, OutputYaml:
+		if !isDryrun {
+			return nil, fmt.Errorf("--output is only supported in dry run mode")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported output format: %q", c.Output)
+	}
+
+	if c.OutPlan != "" && !isDryrun {
+		return nil, fmt.Errorf("--out-plan is only supported in dry run mode")
+	}
+	if c.OutPlan != "" && c.Plan != "" {
+		return nil, fmt.Errorf("cannot use both --out-plan and --plan")
+	}
+
 	if c.OutDir == "" {
 		if c.Target == cloudup.TargetTerraform {
 			c.OutDir = "out/terraform"
@@ -261,9 +319,13 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Up
 		}
 	}
 
-	cluster, err := GetCluster(ctx, f, c.ClusterName)
-	if err != nil {
-		return results, err
+	cluster := c.Cluster
+	if cluster == nil {
+		var err error
+		cluster, err = GetCluster(ctx, f, c.ClusterName)
+		if err != nil {
+			return results, err
+		}
 	}
 
 	clientset, err := f.KopsClient()
@@ -271,6 +333,22 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Up
 		return results, err
 	}
 
+	if !isDryrun {
+		lock, err := statelock.Acquire(ctx, clientset.VFSContext(), cluster, "update cluster")
+		if err != nil {
+			return results, err
+		}
+		defer lock.Release(ctx)
+	}
+
+	var acknowledgeRollbacks func(ctx context.Context) error
+	if !c.GetAssets && !c.DetectDrift {
+		acknowledgeRollbacks, err = checkCanaryRollbacks(ctx, clientset.VFSContext(), cluster, isDryrun, c.AcknowledgeRollback)
+		if err != nil {
+			return results, err
+		}
+	}
+
 	keyStore, err := clientset.KeyStore(cluster)
 	if err != nil {
 		return results, err
@@ -347,6 +425,32 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Up
 		instanceGroupFilters = append(instanceGroupFilters, matchInstanceGroupRoles(c.InstanceGroupRoles))
 	}
 
+	var stateStoreGeneration *cloudup.StateStoreGeneration
+	planOptions := &cloudup.SavedPlanOptions{
+		Phase:              string(phase),
+		InstanceGroups:     c.InstanceGroups,
+		InstanceGroupRoles: c.InstanceGroupRoles,
+		LifecycleOverrides: c.LifecycleOverrides,
+		Prune:              c.Prune,
+	}
+	if c.OutPlan != "" || c.Plan != "" {
+		// Computed before ApplyClusterCmd populates the cluster
+		stateStoreGeneration, err = buildStateStoreGeneration(ctx, clientset, cluster, keyStore, secretStore)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var savedPlan *cloudup.SavedPlan
+	if c.Plan != "" {
+		savedPlan, err = cloudup.ReadSavedPlan(c.Plan)
+		if err != nil {
+			return nil, err
+		}
+		if err := savedPlan.Verify(cluster.ObjectMeta.Name, planOptions, stateStoreGeneration); err != nil {
+			return nil, fmt.Errorf("cannot apply plan %q: %w", c.Plan, err)
+		}
+	}
+
 	cloud, err := cloudup.BuildCloud(cluster)
 	if err != nil {
 		return nil, err
@@ -361,21 +465,49 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Up
 			klog.V(2).Infof("successfully checked control plane running version: %v", minControlPlaneRunningVersion)
 		}
 	}
-	applyCmd := &cloudup.ApplyClusterCmd{
-		Cloud:                      cloud,
-		Clientset:                  clientset,
-		Cluster:                    cluster,
-		DryRun:                     isDryrun,
-		AllowKopsDowngrade:         c.AllowKopsDowngrade,
-		RunTasksOptions:            &c.RunTasksOptions,
-		OutDir:                     c.OutDir,
-		InstanceGroupFilter:        predicates.AllOf(instanceGroupFilters...),
-		Phase:                      phase,
-		TargetName:                 targetName,
-		LifecycleOverrides:         lifecycleOverrideMap,
-		GetAssets:                  c.GetAssets,
-		DeletionProcessing:         deletionProcessing,
-		ControlPlaneRunningVersion: minControlPlaneRunningVersion,
+	newApplyCmd := func(cluster *kops.Cluster, dryRun bool, targetName cloudup.Target) *cloudup.ApplyClusterCmd {
+		return &cloudup.ApplyClusterCmd{
+			Cloud:                      cloud,
+			Clientset:                  clientset,
+			Cluster:                    cluster,
+			DryRun:                     dryRun,
+			AllowKopsDowngrade:         c.AllowKopsDowngrade,
+			RunTasksOptions:            &c.RunTasksOptions,
+			OutDir:                     c.OutDir,
+			InstanceGroupFilter:        predicates.AllOf(instanceGroupFilters...),
+			Phase:                      phase,
+			TargetName:                 targetName,
+			LifecycleOverrides:         lifecycleOverrideMap,
+			GetAssets:                  c.GetAssets,
+			DeletionProcessing:         deletionProcessing,
+			ControlPlaneRunningVersion: minControlPlaneRunningVersion,
+		}
+	}
+
+	if savedPlan != nil && !isDryrun {
+		// The state store has not changed, but the cloud resources may have; compute the changes again
+		// and check that they are the ones in the plan. ApplyClusterCmd populates the cluster, so use a copy.
+		verifyCmd := newApplyCmd(cluster.DeepCopy(), true, cloudup.TargetDryRun)
+		verifyCmd.DryRunOutput = io.Discard
+		if _, err := verifyCmd.Run(ctx); err != nil {
+			return nil, fmt.Errorf("error computing changes to verify plan %q: %w", c.Plan, err)
+		}
+		plan, err := verifyCmd.Target.(*fi.CloudupDryRunTarget).BuildPlan(verifyCmd.TaskMap)
+		if err != nil {
+			return nil, fmt.Errorf("error building plan: %w", err)
+		}
+		if err := savedPlan.VerifyChanges(plan); err != nil {
+			return nil, fmt.Errorf("cannot apply plan %q: %w", c.Plan, err)
+		}
+	}
+	if savedPlan != nil {
+		klog.Infof("Verified that plan %q is current", c.Plan)
+	}
+
+	applyCmd := newApplyCmd(cluster, isDryrun, targetName)
+	if c.Output != "" || c.DetectDrift {
+		// The plan is printed in the requested format below, or by the caller
+		applyCmd.DryRunOutput = io.Discard
 	}
 
 	applyResults, err := applyCmd.Run(ctx)
@@ -383,14 +515,40 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Up
 		return results, err
 	}
 
+	// The rollbacks are only acknowledged once the fixed specification has been applied
+	if acknowledgeRollbacks != nil {
+		if err := acknowledgeRollbacks(ctx); err != nil {
+			return results, err
+		}
+	}
+
 	results.Target = applyCmd.Target
 	results.TaskMap = applyCmd.TaskMap
 	results.ImageAssets = applyResults.AssetBuilder.ImageAssets
 	results.FileAssets = applyResults.AssetBuilder.FileAssets
 	results.Cluster = cluster
+	results.InstanceGroups = applyCmd.InstanceGroups
 
-	if isDryrun && !c.GetAssets {
+	if isDryrun && !c.GetAssets && !c.DetectDrift {
 		target := applyCmd.Target.(*fi.CloudupDryRunTarget)
+		if c.OutPlan != "" {
+			plan, err := target.BuildPlan(applyCmd.TaskMap)
+			if err != nil {
+				return results, fmt.Errorf("error building plan: %w", err)
+			}
+			savedPlan := cloudup.NewSavedPlan(cluster.ObjectMeta.Name, planOptions, stateStoreGeneration, plan)
+			if err := cloudup.WriteSavedPlan(c.OutPlan, savedPlan); err != nil {
+				return results, err
+			}
+			klog.Infof("Plan saved to %q; apply it with --plan %s --yes", c.OutPlan, c.OutPlan)
+		}
+		if c.Output != "" {
+			plan, err := target.BuildPlan(applyCmd.TaskMap)
+			if err != nil {
+				return results, fmt.Errorf("error building plan: %w", err)
+			}
+			return results, writePlan(out, c.Output, plan)
+		}
 		if target.HasChanges() {
 			fmt.Fprintf(out, "Must specify --yes to apply changes\n")
 		} else {
@@ -494,6 +652,48 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Up
 	return results, nil
 }
 
+// buildStateStoreGeneration identifies the current version of the cluster, instance groups, additional objects, keystore and secrets.
+func buildStateStoreGeneration(ctx context.Context, clientset simple.Clientset, cluster *kops.Cluster, keyStore fi.CAStore, secretStore fi.SecretStore) (*cloudup.StateStoreGeneration, error) {
+	list, err := clientset.InstanceGroupsFor(cluster).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var instanceGroups []*kops.InstanceGroup
+	for i := range list.Items {
+		instanceGroups = append(instanceGroups, &list.Items[i])
+	}
+	additionalObjects, err := clientset.AddonsFor(cluster).List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return cloudup.BuildStateStoreGeneration(cluster, instanceGroups, additionalObjects, keyStore, secretStore)
+}
+
+// writePlan prints the planned changes in the requested output format.
+func writePlan(out io.Writer, output string, plan *fi.Plan) error {
+	switch output {
+	case OutputYaml:
+		y, err := yaml.Marshal(plan)
+		if err != nil {
+			return fmt.Errorf("unable to marshal YAML: %v", err)
+		}
+		if _, err := out.Write(y); err != nil {
+			return fmt.Errorf("error writing to output: %v", err)
+		}
+	case OutputJSON:
+		j, err := json.MarshalIndent(plan, "", "  ")
+		if err != nil {
+			return fmt.Errorf("unable to marshal JSON: %v", err)
+		}
+		if _, err := out.Write(append(j, '\n')); err != nil {
+			return fmt.Errorf("error writing to output: %v", err)
+		}
+	default:
+		return fmt.Errorf("unsupported output format: %q", output)
+	}
+	return nil
+}
+
 func parseLifecycle(lifecycle string) (fi.Lifecycle, error) {
 	if v, ok := fi.LifecycleNameMap[lifecycle]; ok {
 		return v, nil
@@ -657,3 +857,42 @@ func checkControlPlaneRunningVersion(ctx context.Context, clusterName string, ve
 	}
 	return strings.TrimPrefix(version, "v"), nil
 }
+
+// checkCanaryRollbacks refuses to update instance groups that were rolled back after their canary failed,
+// as updating them would apply the specification that failed again, unless the rollback is acknowledged.
+// If the rollbacks are acknowledged, it returns a function recording the acknowledgement, so that later updates
+// are not refused; it should only be called once the cluster has been updated.
+func checkCanaryRollbacks(ctx context.Context, vfsContext *vfs.VFSContext, cluster *kops.Cluster, isDryrun bool, acknowledge bool) (func(ctx context.Context) error, error) {
+	journalPath, err := instancegroups.RollingUpdateJournalPath(vfsContext, cluster)
+	if err != nil {
+		return nil, err
+	}
+	progress, err := instancegroups.ReadRollingUpdateProgress(ctx, journalPath)
+	if err != nil {
+		return nil, err
+	}
+	rolledBack := progress.UnacknowledgedRollbacks()
+	if len(rolledBack) == 0 {
+		return nil, nil
+	}
+
+	var names []string
+	for _, ig := range rolledBack {
+		klog.Warningf("Instance group %q was rolled back after its canary failed: %s", ig.Name, ig.Error)
+		names = append(names, ig.Name)
+	}
+	if !acknowledge {
+		message := fmt.Sprintf("instance groups %s were rolled back after their canary failed, and updating them would apply the specification that failed again; fix their specification, then update the cluster with --acknowledge-rollback", strings.Join(names, ", "))
+		if isDryrun {
+			klog.Warning(message)
+			return nil, nil
+		}
+		return nil, errors.New(message)
+	}
+	if isDryrun {
+		return nil, nil
+	}
+	return func(ctx context.Context) error {
+		return instancegroups.AcknowledgeRollbacks(ctx, journalPath, cluster, progress)
+	}, nil
+}
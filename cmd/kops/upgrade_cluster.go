@@ -30,9 +30,12 @@ import (
 	"k8s.io/kops/cmd/kops/util"
 	kopsapi "k8s.io/kops/pkg/apis/kops"
 	kopsutil "k8s.io/kops/pkg/apis/kops/util"
+	"k8s.io/kops/pkg/client/simple"
 	"k8s.io/kops/pkg/commands"
 	"k8s.io/kops/pkg/commands/commandutils"
 	"k8s.io/kops/pkg/pretty"
+	"k8s.io/kops/pkg/statelock"
+	"k8s.io/kops/pkg/upgradeadvisor"
 	"k8s.io/kops/upup/pkg/fi/cloudup"
 	"k8s.io/kops/util/pkg/tables"
 	"k8s.io/kubectl/pkg/util/i18n"
@@ -49,6 +52,9 @@ var (
 	upgradeClusterExample = templates.Examples(i18n.T(`
 	# Upgrade a cluster's Kubernetes version.
 	kops upgrade cluster k8s-cluster.example.com --yes --state=s3://my-state-store
+
+	# Check what would break when upgrading a cluster, without changing it.
+	kops upgrade cluster k8s-cluster.example.com --kubernetes-version 1.33.0 --analyze
 	`))
 
 	upgradeClusterShort = i18n.T("Upgrade a kubernetes cluster.")
@@ -60,6 +66,8 @@ type UpgradeClusterOptions struct {
 	Channel     string
 	// KubernetesVersion is the k8s version to use for upgrade.
 	KubernetesVersion string
+	// Analyze checks the cluster for issues with the upgrade, without changing it.
+	Analyze bool
 }
 
 func NewCmdUpgradeCluster(f *util.Factory, out io.Writer) *cobra.Command {
@@ -84,6 +92,7 @@ func NewCmdUpgradeCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	cmd.RegisterFlagCompletionFunc("channel", completeChannel)
 	cmd.Flags().StringVar(&options.KubernetesVersion, "kubernetes-version", "", "Kubernetes version to use for upgrade")
 	cmd.RegisterFlagCompletionFunc("kubernetes-version", completeKubernetesVersion)
+	cmd.Flags().BoolVar(&options.Analyze, "analyze", false, "Check the cluster spec and addons for issues with the upgrade, without changing anything")
 
 	return cmd
 }
@@ -157,6 +166,9 @@ func RunUpgradeCluster(ctx context.Context, f *util.Factory, out io.Writer, opti
 	if options.KubernetesVersion != "" {
 		proposedKubernetesVersion, err = kopsutil.ParseKubernetesVersion(options.KubernetesVersion)
 		if err != nil {
+			if options.Analyze {
+				return fmt.Errorf("cannot analyze an upgrade to Kubernetes version %q: %w", options.KubernetesVersion, err)
+			}
 			klog.Warningf("error parsing KubernetesVersion %q", cluster.Spec.KubernetesVersion)
 		}
 	}
@@ -241,6 +253,22 @@ func RunUpgradeCluster(ctx context.Context, f *util.Factory, out io.Writer, opti
 		}
 	}
 
+	if options.Analyze {
+		// Unless a version is requested, there is no Kubernetes upgrade to analyze when the cluster already runs the version recommended by the channel
+		if options.KubernetesVersion == "" && currentKubernetesVersion != nil && proposedKubernetesVersion != nil && currentKubernetesVersion.EQ(*proposedKubernetesVersion) {
+			if len(actions) != 0 {
+				if err := renderUpgradeActions(out, actions); err != nil {
+					return err
+				}
+				fmt.Fprintf(out, "\n")
+			}
+			fmt.Fprintf(out, "No Kubernetes upgrade required: the cluster already runs Kubernetes %s, as recommended by channel %q\n", currentKubernetesVersion, channelLocation)
+			fmt.Fprintf(out, "Use --kubernetes-version to analyze an upgrade to another version\n")
+			return nil
+		}
+		return analyzeUpgrade(ctx, clientset, out, cluster, instanceGroups, actions, proposedKubernetesVersion)
+	}
+
 	if len(actions) == 0 {
 		// TODO: Allow --force option to force even if not needed?
 		// Note stderr - we try not to print to stdout if no update is needed
@@ -248,31 +276,21 @@ func RunUpgradeCluster(ctx context.Context, f *util.Factory, out io.Writer, opti
 		return nil
 	}
 
-	{
-		t := &tables.Table{}
-		t.AddColumn("ITEM", func(a *upgradeAction) string {
-			return a.Item
-		})
-		t.AddColumn("PROPERTY", func(a *upgradeAction) string {
-			return a.Property
-		})
-		t.AddColumn("OLD", func(a *upgradeAction) string {
-			return a.Old
-		})
-		t.AddColumn("NEW", func(a *upgradeAction) string {
-			return a.New
-		})
-
-		err := t.Render(actions, out, "ITEM", "PROPERTY", "OLD", "NEW")
-		if err != nil {
-			return err
-		}
+	if err := renderUpgradeActions(out, actions); err != nil {
+		return err
 	}
 
 	if !options.Yes {
 		fmt.Printf("\nMust specify --yes to perform upgrade\n")
 		return nil
 	}
+
+	lock, err := statelock.Acquire(ctx, clientset.VFSContext(), cluster, "upgrade cluster")
+	if err != nil {
+		return err
+	}
+	defer lock.Release(ctx)
+
 	for _, action := range actions {
 		action.apply()
 	}
@@ -296,6 +314,77 @@ func RunUpgradeCluster(ctx context.Context, f *util.Factory, out io.Writer, opti
 	return nil
 }
 
+// analyzeUpgrade prints the proposed upgrade actions, and the issues that the upgrade would cause.
+func analyzeUpgrade(ctx context.Context, clientset simple.Clientset, out io.Writer, cluster *kopsapi.Cluster, instanceGroups []*kopsapi.InstanceGroup, actions []*upgradeAction, kubernetesVersion *semver.Version) error {
+	if kubernetesVersion == nil {
+		return fmt.Errorf("unable to determine the kubernetes version to upgrade to")
+	}
+
+	additionalObjects, err := clientset.AddonsFor(cluster).List(ctx)
+	if err != nil {
+		return fmt.Errorf("error reading additional objects: %w", err)
+	}
+
+	if len(actions) != 0 {
+		if err := renderUpgradeActions(out, actions); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "\n")
+	}
+
+	advisor := &upgradeadvisor.Advisor{
+		VFSContext: clientset.VFSContext(),
+		To:         *kubernetesVersion,
+	}
+	issues := advisor.Analyze(cluster, instanceGroups, additionalObjects)
+	if len(issues) == 0 {
+		fmt.Fprintf(out, "No issues found upgrading to Kubernetes %s\n", kubernetesVersion)
+		return nil
+	}
+
+	t := &tables.Table{}
+	t.AddColumn("SEVERITY", func(i *upgradeadvisor.Issue) string {
+		return string(i.Severity)
+	})
+	t.AddColumn("ITEM", func(i *upgradeadvisor.Issue) string {
+		return i.Item
+	})
+	t.AddColumn("ISSUE", func(i *upgradeadvisor.Issue) string {
+		return i.Message
+	})
+	if err := t.Render(issues, out, "SEVERITY", "ITEM", "ISSUE"); err != nil {
+		return err
+	}
+
+	blocking := 0
+	for _, issue := range issues {
+		if issue.Severity == upgradeadvisor.SeverityBlocking {
+			blocking++
+		}
+	}
+	if blocking != 0 {
+		return fmt.Errorf("found %d blocking issue(s) upgrading to Kubernetes %s", blocking, kubernetesVersion)
+	}
+	return nil
+}
+
+func renderUpgradeActions(out io.Writer, actions []*upgradeAction) error {
+	t := &tables.Table{}
+	t.AddColumn("ITEM", func(a *upgradeAction) string {
+		return a.Item
+	})
+	t.AddColumn("PROPERTY", func(a *upgradeAction) string {
+		return a.Property
+	})
+	t.AddColumn("OLD", func(a *upgradeAction) string {
+		return a.Old
+	})
+	t.AddColumn("NEW", func(a *upgradeAction) string {
+		return a.New
+	})
+	return t.Render(actions, out, "ITEM", "PROPERTY", "OLD", "NEW")
+}
+
 func completeChannel(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 	// TODO implement completion against VFS
 	return []string{"alpha", "stable"}, cobra.ShellCompDirectiveNoFileComp
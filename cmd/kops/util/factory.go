@@ -148,9 +148,6 @@ func (f *Factory) KopsClient() (simple.Clientset, error) {
 
 			f.clientset = vfsclientset.NewVFSClientset(f.VFSContext(), basePath)
 		}
-		if strings.HasPrefix(registryPath, "file://") {
-			klog.Warning("The local filesystem state store is not functional for running clusters")
-		}
 	}
 
 	return f.clientset, nil
@@ -0,0 +1,69 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"os"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/upup/pkg/fi/nodeup"
+)
+
+// runAudit implements "nodeup audit", which reports as JSON how the node has drifted from its configuration, without changing it.
+// It returns the exit status: 0 if the node has not drifted, 2 if it has, and 1 on error.
+func runAudit(args []string) int {
+	flags := flag.NewFlagSet("audit", flag.ExitOnError)
+	// Accept the klog flags, e.g. -v
+	flag.CommandLine.VisitAll(func(f *flag.Flag) {
+		flags.Var(f.Value, f.Name, f.Usage)
+	})
+
+	var flagConf, flagCacheDir string
+	flags.StringVar(&flagConf, "conf", "node.yaml", "configuration location")
+	flags.StringVar(&flagCacheDir, "cache", "/var/cache/nodeup", "the location for the local asset cache")
+
+	flag.Set("logtostderr", "true")
+	flags.Parse(args)
+
+	if flagConf == "" {
+		klog.Exitf("--conf is required")
+	}
+
+	// The report is written to stdout; everything else is logged to stderr.
+	cmd := &nodeup.NodeUpCommand{
+		ConfigLocation: flagConf,
+		Target:         "audit",
+		CacheDir:       flagCacheDir,
+	}
+	err := cmd.Run(os.Stdout)
+	if errors.Is(err, nodeup.ErrDrift) {
+		return 2
+	}
+	if err != nil {
+		klog.Errorf("error auditing node: %v", err)
+		// Errors running the tasks are already in the report
+		if !errors.Is(err, nodeup.ErrAuditFailed) {
+			if err := nodeup.WriteAuditErrorReport(os.Stdout, err); err != nil {
+				klog.Errorf("error writing report: %v", err)
+			}
+		}
+		return 1
+	}
+	return 0
+}
@@ -36,6 +36,19 @@ const (
 func main() {
 	klog.InitFlags(nil)
 
+	if len(os.Args) > 1 && os.Args[1] == "audit" {
+		os.Exit(runAudit(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "reconcile" {
+		os.Exit(runReconcile(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "tpm-identity" {
+		os.Exit(runTPMIdentity(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "renew-certificates" {
+		os.Exit(runRenewCertificates(os.Args[2:]))
+	}
+
 	var flagConf, flagCacheDir, gitVersion string
 	var flagRetries int
 	var dryrun, installSystemdUnit bool
@@ -0,0 +1,57 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"flag"
+	"os"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/upup/pkg/fi/nodeup"
+)
+
+// runReconcile implements "nodeup reconcile", which periodically applies the latest configuration to the running node.
+// It runs until node reconciliation is disabled for the cluster, and returns the exit status.
+func runReconcile(args []string) int {
+	flags := flag.NewFlagSet("reconcile", flag.ExitOnError)
+	// Accept the klog flags, e.g. -v
+	flag.CommandLine.VisitAll(func(f *flag.Flag) {
+		flags.Var(f.Value, f.Name, f.Usage)
+	})
+
+	var flagConf, flagCacheDir string
+	flags.StringVar(&flagConf, "conf", "node.yaml", "configuration location")
+	flags.StringVar(&flagCacheDir, "cache", "/var/cache/nodeup", "the location for the local asset cache")
+
+	flag.Set("logtostderr", "true")
+	flags.Parse(args)
+
+	if flagConf == "" {
+		klog.Exitf("--conf is required")
+	}
+
+	cmd := &nodeup.NodeUpCommand{
+		ConfigLocation: flagConf,
+		Target:         "reconcile",
+		CacheDir:       flagCacheDir,
+	}
+	if err := cmd.RunReconciler(os.Stdout); err != nil {
+		klog.Errorf("error reconciling node: %v", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+	"time"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/upup/pkg/fi/nodeup"
+)
+
+// runRenewCertificates implements "nodeup renew-certificates", which renews the certificates the node got from kops-controller
+// when they are close to expiry. It is run periodically by a systemd timer, and returns the exit status.
+func runRenewCertificates(args []string) int {
+	flags := flag.NewFlagSet("renew-certificates", flag.ExitOnError)
+	// Accept the klog flags, e.g. -v
+	flag.CommandLine.VisitAll(func(f *flag.Flag) {
+		flags.Var(f.Value, f.Name, f.Usage)
+	})
+
+	options := &nodeup.RenewCertificatesOptions{}
+	flags.StringVar(&options.ClusterName, "cluster-name", "", "the name of the cluster")
+	flags.StringVar(&options.SrvKubernetesDir, "srv-kubernetes", "/srv/kubernetes", "the directory holding the kubelet serving certificate")
+	flags.DurationVar(&options.RenewBefore, "renew-before", 60*24*time.Hour, "renew the certificates when one of them expires within this duration")
+	flags.BoolVar(&options.Force, "force", false, "renew the certificates even if they do not expire soon")
+
+	flag.Set("logtostderr", "true")
+	flags.Parse(args)
+
+	if options.ClusterName == "" {
+		klog.Exitf("--cluster-name is required")
+	}
+
+	if err := nodeup.RenewCertificates(context.Background(), options); err != nil {
+		klog.Errorf("error renewing certificates: %v", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/google/go-tpm/tpm2/transport/linuxtpm"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/kops/v1alpha2"
+	"k8s.io/kops/pkg/bootstrap/tpmbootstrap"
+	"sigs.k8s.io/yaml"
+)
+
+// runTPMIdentity implements "nodeup tpm-identity", which prints the Host that allows the machine
+// to join the cluster with TPM attestation, for an administrator to apply to the cluster.
+// It returns the exit status.
+func runTPMIdentity(args []string) int {
+	flags := flag.NewFlagSet("tpm-identity", flag.ExitOnError)
+	// Accept the klog flags, e.g. -v
+	flag.CommandLine.VisitAll(func(f *flag.Flag) {
+		flags.Var(f.Value, f.Name, f.Usage)
+	})
+
+	var flagInstanceGroup, flagDevice string
+	flags.StringVar(&flagInstanceGroup, "instance-group", "", "the instance group the machine joins")
+	flags.StringVar(&flagDevice, "device", tpmbootstrap.DevicePath, "the TPM device")
+
+	flag.Set("logtostderr", "true")
+	flags.Parse(args)
+
+	if flagInstanceGroup == "" {
+		klog.Exitf("--instance-group is required")
+	}
+
+	// The node joins with its hostname, so the host must have the same name
+	hostname, err := os.Hostname()
+	if err != nil {
+		klog.Errorf("couldn't determine hostname: %v", err)
+		return 1
+	}
+
+	tpm, err := linuxtpm.Open(flagDevice)
+	if err != nil {
+		klog.Errorf("opening TPM %q: %v", flagDevice, err)
+		return 1
+	}
+	defer tpm.Close()
+
+	identity, err := tpmbootstrap.ReadIdentity(tpm)
+	if err != nil {
+		klog.Errorf("reading TPM identity: %v", err)
+		return 1
+	}
+
+	host := &v1alpha2.Host{}
+	host.APIVersion = v1alpha2.SchemeGroupVersion.String()
+	host.Kind = "Host"
+	host.Namespace = "kops-system"
+	host.Name = hostname
+	host.Spec.InstanceGroup = flagInstanceGroup
+	host.Spec.TPM = &v1alpha2.HostTPMSpec{
+		AttestationKey: identity.AttestationKey,
+	}
+
+	b, err := yaml.Marshal(host)
+	if err != nil {
+		klog.Errorf("serializing host: %v", err)
+		return 1
+	}
+	if _, err := os.Stdout.Write(b); err != nil {
+		klog.Errorf("writing host: %v", err)
+		return 1
+	}
+	return 0
+}
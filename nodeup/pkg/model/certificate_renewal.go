@@ -0,0 +1,86 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"strings"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/systemd"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+// CertificateRenewalServiceName is the name of the systemd unit that renews the certificates the node got from kops-controller.
+const CertificateRenewalServiceName = "kops-renew-certificates.service"
+
+// CertificateRenewalBuilder installs a daily timer that renews the certificates the node got from kops-controller
+// before they expire, so that long-lived nodes do not have to be replaced to refresh them.
+type CertificateRenewalBuilder struct {
+	*NodeupModelContext
+
+	// NodeupPath is the location of the nodeup binary.
+	NodeupPath string
+}
+
+var _ fi.NodeupModelBuilder = &CertificateRenewalBuilder{}
+
+// Build is responsible for installing the certificate renewal service and timer.
+func (b *CertificateRenewalBuilder) Build(c *fi.NodeupModelBuilderContext) error {
+	// The control plane and apiserver nodes issue their own certificates
+	if b.IsMaster || b.HasAPIServer {
+		return nil
+	}
+
+	command := []string{
+		b.NodeupPath,
+		"renew-certificates",
+		"--cluster-name=" + b.NodeupConfig.ClusterName,
+		"--srv-kubernetes=" + b.PathSrvKubernetes(),
+	}
+
+	manifest := &systemd.Manifest{}
+	manifest.Set("Unit", "Description", "Renew the certificates issued by kops-controller (nodeup)")
+	manifest.Set("Unit", "Documentation", "https://github.com/kubernetes/kops")
+	manifest.Set("Service", "Type", "oneshot")
+	manifest.Set("Service", "ExecStart", strings.Join(command, " "))
+
+	manifestString := manifest.Render()
+	klog.V(8).Infof("Built service manifest %q\n%s", CertificateRenewalServiceName, manifestString)
+
+	// The service is started by the timer
+	c.AddTask(&nodetasks.Service{
+		Name:        CertificateRenewalServiceName,
+		Definition:  s(manifestString),
+		ManageState: fi.PtrTo(false),
+	})
+
+	timer := &systemd.Manifest{}
+	timer.Set("Unit", "Description", "Daily renewal of the certificates issued by kops-controller")
+	timer.Set("Timer", "OnCalendar", "daily")
+	timer.Set("Timer", "RandomizedDelaySec", "1h")
+	timer.Set("Timer", "Persistent", "true")
+
+	service := &nodetasks.Service{
+		Name:       strings.TrimSuffix(CertificateRenewalServiceName, ".service") + ".timer",
+		Definition: s(timer.Render()),
+	}
+	service.InitDefaults()
+	c.AddTask(service)
+
+	return nil
+}
@@ -99,7 +99,7 @@ func (b *ContainerdBuilder) installContainerd(c *fi.NodeupModelBuilderContext) e
 	// Add Apache2 license
 	{
 		t := &nodetasks.File{
-			Path:     "/usr/share/doc/containerd/apache.txt",
+			Path:     b.InstallPath("/usr/share/doc/containerd/apache.txt"),
 			Contents: fi.NewStringResource(resources.ContainerdApache2License),
 			Type:     nodetasks.FileType_File,
 		}
@@ -122,7 +122,7 @@ func (b *ContainerdBuilder) installContainerd(c *fi.NodeupModelBuilderContext) e
 	}
 	for k, v := range f {
 		fileTask := &nodetasks.File{
-			Path:     filepath.Join("/usr/bin", k),
+			Path:     b.InstallPath(filepath.Join("/usr/bin", k)),
 			Contents: v,
 			Type:     nodetasks.FileType_File,
 			Mode:     fi.PtrTo("0755"),
@@ -147,7 +147,7 @@ func (b *ContainerdBuilder) installContainerd(c *fi.NodeupModelBuilderContext) e
 	}
 	for _, v := range f {
 		fileTask := &nodetasks.File{
-			Path:     "/usr/sbin/runc",
+			Path:     b.InstallPath("/usr/sbin/runc"),
 			Contents: v,
 			Type:     nodetasks.FileType_File,
 			Mode:     fi.PtrTo("0755"),
@@ -40,6 +40,10 @@ func TestContainerdBuilder_Flatcar(t *testing.T) {
 	runContainerdBuilderTest(t, "flatcar", distributions.DistributionFlatcar)
 }
 
+func TestContainerdBuilder_FedoraCoreOS(t *testing.T) {
+	runContainerdBuilderTest(t, "fedoracoreos", distributions.DistributionFedoraCoreOS)
+}
+
 func TestContainerdBuilder_SkipInstall(t *testing.T) {
 	runContainerdBuilderTest(t, "skipinstall", distributions.DistributionUbuntu2004)
 }
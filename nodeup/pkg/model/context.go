@@ -32,6 +32,7 @@ import (
 	"k8s.io/kops/pkg/apis/kops"
 	kopsmodel "k8s.io/kops/pkg/apis/kops/model"
 	"k8s.io/kops/pkg/apis/nodeup"
+	"k8s.io/kops/pkg/pki"
 	"k8s.io/kops/pkg/systemd"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
@@ -355,6 +356,15 @@ func (c *NodeupModelContext) KubectlPath() string {
 	return kubeletCommand
 }
 
+// InstallPath returns the path to which nodeup writes a file that belongs under /usr.
+// On distros with a read-only /usr, the file is written into the kOps systemd-sysext extension, which is merged into /usr.
+func (c *NodeupModelContext) InstallPath(path string) string {
+	if c.Distribution.UsesSysext() {
+		return filepath.Join(nodetasks.SysextExtensionsDir, SysextExtensionName, path)
+	}
+	return path
+}
+
 // BuildCertificatePairTask creates the tasks to create the certificate and private key files.
 func (c *NodeupModelContext) BuildCertificatePairTask(ctx *fi.NodeupModelBuilderContext, name, path, filename string, owner *string, beforeServices []string) error {
 	return c.buildCertificatePairTask(ctx, name, path, filename, owner, beforeServices, true)
@@ -435,6 +445,22 @@ func (c *NodeupModelContext) buildCertificatePairTask(ctx *fi.NodeupModelBuilder
 	return nil
 }
 
+// UsesExternalSigner returns true if the private key of the keypair for the named keyset is held by an external signer.
+func (c *NodeupModelContext) UsesExternalSigner(ctx *fi.NodeupModelBuilderContext, name string) (bool, error) {
+	keyset, err := c.KeyStore.FindKeyset(ctx.Context(), name)
+	if err != nil {
+		return false, err
+	}
+	if keyset == nil {
+		return false, fmt.Errorf("keyset %q not found", name)
+	}
+	item := keyset.Items[c.NodeupConfig.KeypairIDs[name]]
+	if item == nil {
+		return false, fmt.Errorf("did not find keypair %s for %s", c.NodeupConfig.KeypairIDs[name], name)
+	}
+	return pki.IsExternalSigner(item.PrivateKey), nil
+}
+
 // BuildCertificateTask builds a task to create a certificate file.
 func (c *NodeupModelContext) BuildCertificateTask(ctx *fi.NodeupModelBuilderContext, name, filename string, owner *string) error {
 	keyset, err := c.KeyStore.FindKeyset(ctx.Context(), name)
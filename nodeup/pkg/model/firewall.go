@@ -18,12 +18,13 @@ package model
 
 import (
 	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/systemd"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
 )
 
-// FirewallBuilder configures the firewall (iptables)
+// FirewallBuilder configures the firewall (iptables or nftables)
 type FirewallBuilder struct {
 	*NodeupModelContext
 }
@@ -32,6 +33,12 @@ var _ fi.NodeupModelBuilder = &FirewallBuilder{}
 
 // Build is responsible for generating any node firewall rules
 func (b *FirewallBuilder) Build(c *fi.NodeupModelBuilderContext) error {
+	if b.NodeupConfig.FirewallBackend == kops.FirewallBackendNFTables {
+		c.AddTask(b.buildNFTablesScript())
+		c.AddTask(b.buildNFTablesSystemdService())
+		return nil
+	}
+
 	// We need forwarding enabled (https://github.com/kubernetes/kubernetes/issues/40182)
 	c.AddTask(b.buildFirewallScript())
 	c.AddTask(b.buildSystemdService())
@@ -93,3 +100,69 @@ fi
 		Mode:     s("0755"),
 	}
 }
+
+// nftablesScriptPath is the location of the script that configures nftables for the node firewall
+const nftablesScriptPath = "/opt/kops/bin/nftables-setup"
+
+// nftablesScript accepts all TCP/UDP/ICMP packets in the host's own input and forward chains that drop by default.
+// An accept verdict only ends the evaluation of the chain it is in, and every base chain on a hook still sees the packet,
+// so accept rules in a separate table would not override the drop policy of the host's chains.
+// The rules are tagged with a comment, so that running the script again does not add them twice.
+const nftablesScript = `#!/bin/bash
+# Built by kops - do not edit
+
+set -o errexit
+set -o nounset
+set -o pipefail
+
+# Hosts with an nftables firewall may drop most inbound/forwarded packets.
+# We need to add rules to accept all TCP/UDP/ICMP packets to their input and forward chains.
+nft list chains | awk '
+$1 == "table" { family = $2; table = $3 }
+$1 == "chain" { chain = $2 }
+/type filter hook (input|forward) / && /policy drop;/ { print family, table, chain }
+' | while read -r family table chain; do
+  case "${family}" in
+    ip|ip6|inet) ;;
+    *) continue ;;
+  esac
+  if nft list chain "${family}" "${table}" "${chain}" | grep -q 'comment "kops-firewall"'; then
+    continue
+  fi
+  echo "Add rules to accept all TCP/UDP/ICMP packets to ${family} ${table} ${chain}"
+  nft insert rule "${family}" "${table}" "${chain}" meta l4proto '{ tcp, udp, icmp, ipv6-icmp }' accept comment '"kops-firewall"'
+done
+`
+
+func (b *FirewallBuilder) buildNFTablesSystemdService() *nodetasks.Service {
+	manifest := &systemd.Manifest{}
+	manifest.Set("Unit", "Description", "Configure nftables for kubernetes")
+	manifest.Set("Unit", "Documentation", "https://github.com/kubernetes/kops")
+	manifest.Set("Unit", "After", "nftables.service")
+	manifest.Set("Unit", "Before", "network.target")
+	manifest.Set("Service", "Type", "oneshot")
+	manifest.Set("Service", "RemainAfterExit", "yes")
+	manifest.Set("Service", "ExecStart", nftablesScriptPath)
+	manifest.Set("Install", "WantedBy", "basic.target")
+
+	manifestString := manifest.Render()
+	klog.V(8).Infof("Built service manifest %q\n%s", "kubernetes-nftables-setup", manifestString)
+
+	service := &nodetasks.Service{
+		Name:       "kubernetes-nftables-setup.service",
+		Definition: s(manifestString),
+	}
+
+	service.InitDefaults()
+
+	return service
+}
+
+func (b *FirewallBuilder) buildNFTablesScript() *nodetasks.File {
+	return &nodetasks.File{
+		Path:     nftablesScriptPath,
+		Contents: fi.NewStringResource(nftablesScript),
+		Type:     nodetasks.FileType_File,
+		Mode:     s("0755"),
+	}
+}
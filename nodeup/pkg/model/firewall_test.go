@@ -0,0 +1,125 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func TestFirewallBuilderIPTables(t *testing.T) {
+	RunGoldenTest(t, "tests/firewallbuilder/iptables", "firewall", func(nodeupModelContext *NodeupModelContext, target *fi.NodeupModelBuilderContext) error {
+		builder := FirewallBuilder{NodeupModelContext: nodeupModelContext}
+		return builder.Build(target)
+	})
+}
+
+func TestFirewallBuilderNFTables(t *testing.T) {
+	RunGoldenTest(t, "tests/firewallbuilder/nftables", "firewall", func(nodeupModelContext *NodeupModelContext, target *fi.NodeupModelBuilderContext) error {
+		builder := FirewallBuilder{NodeupModelContext: nodeupModelContext}
+		return builder.Build(target)
+	})
+}
+
+// fakeNFT prints the chains of a host whose firewall drops inbound and forwarded packets,
+// and records the rules inserted into them.
+const fakeNFT = `#!/bin/bash
+log="$(dirname "$0")/nft.log"
+touch "${log}"
+case "$1 $2" in
+"list chains")
+  cat <<'EOT'
+table inet filter {
+	chain input {
+		type filter hook input priority filter; policy drop;
+	}
+	chain forward {
+		type filter hook forward priority filter; policy drop;
+	}
+	chain output {
+		type filter hook output priority filter; policy accept;
+	}
+}
+table ip nat {
+	chain postrouting {
+		type nat hook postrouting priority srcnat; policy accept;
+	}
+}
+table bridge filter {
+	chain forward {
+		type filter hook forward priority filter; policy drop;
+	}
+}
+EOT
+  ;;
+"list chain")
+  if grep -q "^insert rule $3 $4 $5 " "${log}"; then
+    echo 'meta l4proto { tcp, udp, icmp, ipv6-icmp } accept comment "kops-firewall"'
+  fi
+  ;;
+"insert rule")
+  echo "$*" >> "${log}"
+  ;;
+*)
+  exit 1
+  ;;
+esac
+`
+
+func TestNFTablesScriptDropPolicyHost(t *testing.T) {
+	for _, tool := range []string{"bash", "awk", "grep"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			t.Skipf("%s not found: %v", tool, err)
+		}
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "nft"), []byte(fakeNFT), 0o755); err != nil {
+		t.Fatalf("writing fake nft: %v", err)
+	}
+	script := filepath.Join(dir, "nftables-setup")
+	if err := os.WriteFile(script, []byte(nftablesScript), 0o755); err != nil {
+		t.Fatalf("writing script: %v", err)
+	}
+
+	// The second run must not add the rules again
+	for i := 0; i < 2; i++ {
+		cmd := exec.Command("bash", script)
+		cmd.Env = append(os.Environ(), "PATH="+dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("running script: %v\n%s", err, output)
+		}
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, "nft.log"))
+	if err != nil {
+		t.Fatalf("reading nft log: %v", err)
+	}
+	actual := strings.Split(strings.TrimSpace(string(b)), "\n")
+	expected := []string{
+		`insert rule inet filter input meta l4proto { tcp, udp, icmp, ipv6-icmp } accept comment "kops-firewall"`,
+		`insert rule inet filter forward meta l4proto { tcp, udp, icmp, ipv6-icmp } accept comment "kops-firewall"`,
+	}
+	if strings.Join(actual, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("unexpected rules inserted:\n%s\nexpected:\n%s", strings.Join(actual, "\n"), strings.Join(expected, "\n"))
+	}
+}
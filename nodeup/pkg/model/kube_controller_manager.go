@@ -21,6 +21,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/flagbuilder"
 	"k8s.io/kops/pkg/k8scodecs"
@@ -40,6 +41,10 @@ import (
 // KubeControllerManagerBuilder install kube-controller-manager (just the manifest at the moment)
 type KubeControllerManagerBuilder struct {
 	*NodeupModelContext
+
+	// externalSignerCA is set when the private key of the cluster CA is held by an external signer,
+	// in which case kube-controller-manager cannot sign certificate signing requests.
+	externalSignerCA bool
 }
 
 var _ fi.NodeupModelBuilder = &KubeControllerManagerBuilder{}
@@ -60,6 +65,14 @@ func (b *KubeControllerManagerBuilder) Build(c *fi.NodeupModelBuilderContext) er
 	if err := b.BuildCertificatePairTask(c, fi.CertificateIDCA, pathSrvKCM, "ca", nil, nil); err != nil {
 		return err
 	}
+	externalSignerCA, err := b.UsesExternalSigner(c, fi.CertificateIDCA)
+	if err != nil {
+		return err
+	}
+	if externalSignerCA {
+		klog.Warningf("the private key of %q is held by an external signer; kube-controller-manager will not sign certificate signing requests", fi.CertificateIDCA)
+		b.externalSignerCA = true
+	}
 
 	if err := b.BuildPrivateKeyTask(c, "service-account", pathSrvKCM, "service-account", nil, nil); err != nil {
 		return err
@@ -159,10 +172,13 @@ func (b *KubeControllerManagerBuilder) buildPod(kcm *kops.KubeControllerManagerC
 	}
 
 	// Configure CA certificate to be used to sign keys
-	flags = append(flags, []string{
-		"--cluster-signing-cert-file=" + filepath.Join(pathSrvKCM, "ca.crt"),
-		"--cluster-signing-key-file=" + filepath.Join(pathSrvKCM, "ca.key"),
-	}...)
+	// kube-controller-manager can only sign with a key file, not with an external signer
+	if !b.externalSignerCA {
+		flags = append(flags, []string{
+			"--cluster-signing-cert-file=" + filepath.Join(pathSrvKCM, "ca.crt"),
+			"--cluster-signing-key-file=" + filepath.Join(pathSrvKCM, "ca.key"),
+		}...)
+	}
 
 	pod := &v1.Pod{
 		TypeMeta: metav1.TypeMeta{
@@ -190,8 +206,8 @@ func (b *KubeControllerManagerBuilder) buildPod(kcm *kops.KubeControllerManagerC
 			// Default is different on ContainerOS, see https://github.com/kubernetes/kubernetes/pull/58171
 			volumePluginDir = "/home/kubernetes/flexvolume/"
 
-		case distributions.DistributionFlatcar:
-			// The /usr directory is read-only for Flatcar
+		case distributions.DistributionFlatcar, distributions.DistributionFedoraCoreOS:
+			// The /usr directory is read-only for Flatcar and Fedora CoreOS
 			volumePluginDir = "/var/lib/kubelet/volumeplugins/"
 
 		default:
@@ -576,6 +576,8 @@ func (b *KubeletBuilder) addContainerizedMounter(c *fi.NodeupModelBuilderContext
 	})
 
 	// TODO: leverage assets for this tar file (but we want to avoid expansion of the archive)
+	// It is not an asset, so it cannot be mirrored or signed, and assets.signaturePublicKeys does not apply to it;
+	// the pinned hash is what verifies it.
 	c.AddTask(&nodetasks.Archive{
 		Name:      "containerized_mounter",
 		Source:    "https://storage.googleapis.com/kubernetes-release/gci-mounter/mounter.tar",
@@ -693,8 +695,8 @@ func (b *KubeletBuilder) buildKubeletConfigSpec(ctx context.Context) (*kops.Kube
 			// Default is different on ContainerOS, see https://github.com/kubernetes/kubernetes/pull/58171
 			c.VolumePluginDirectory = "/home/kubernetes/flexvolume/"
 
-		case distributions.DistributionFlatcar:
-			// The /usr directory is read-only for Flatcar
+		case distributions.DistributionFlatcar, distributions.DistributionFedoraCoreOS:
+			// The /usr directory is read-only for Flatcar and Fedora CoreOS
 			c.VolumePluginDirectory = "/var/lib/kubelet/volumeplugins/"
 
 		default:
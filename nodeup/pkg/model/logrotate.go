@@ -43,7 +43,11 @@ func (b *LogrotateBuilder) Build(c *fi.NodeupModelBuilderContext) error {
 	case distributions.DistributionFlatcar:
 		klog.Infof("Detected Flatcar; won't install logrotate")
 	default:
-		c.AddTask(&nodetasks.Package{Name: "logrotate"})
+		if b.Distribution.IsImmutable() {
+			klog.Infof("Detected immutable distribution %v; won't install logrotate", b.Distribution)
+		} else {
+			c.AddTask(&nodetasks.Package{Name: "logrotate"})
+		}
 	}
 
 	b.addLogRotate(c, "docker", "/var/log/docker.log", logRotateOptions{})
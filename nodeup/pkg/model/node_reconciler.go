@@ -0,0 +1,71 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"strings"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/systemd"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+// NodeReconcilerServiceName is the name of the systemd unit that periodically applies the latest configuration to the node.
+const NodeReconcilerServiceName = "kops-reconcile.service"
+
+// NodeReconcilerBuilder installs the node reconciler, when node reconciliation is enabled.
+type NodeReconcilerBuilder struct {
+	*NodeupModelContext
+
+	// Command is the nodeup command that runs the reconciler.
+	Command []string
+}
+
+var _ fi.NodeupModelBuilder = &NodeReconcilerBuilder{}
+
+// Build is responsible for installing the node reconciler service.
+func (b *NodeReconcilerBuilder) Build(c *fi.NodeupModelBuilderContext) error {
+	if b.NodeupConfig.ReconcileInterval == nil {
+		return nil
+	}
+
+	manifest := &systemd.Manifest{}
+	manifest.Set("Unit", "Description", "Apply the latest kOps configuration to the node (nodeup)")
+	manifest.Set("Unit", "Documentation", "https://github.com/kubernetes/kops")
+
+	manifest.Set("Service", "EnvironmentFile", "/etc/sysconfig/kops-configuration")
+	manifest.Set("Service", "EnvironmentFile", "/etc/environment")
+	manifest.Set("Service", "ExecStart", strings.Join(b.Command, " "))
+	// The reconciler exits successfully when node reconciliation is disabled
+	manifest.Set("Service", "Restart", "on-failure")
+	manifest.Set("Service", "RestartSec", "60s")
+
+	manifest.Set("Install", "WantedBy", "multi-user.target")
+
+	manifestString := manifest.Render()
+	klog.V(8).Infof("Built service manifest %q\n%s", NodeReconcilerServiceName, manifestString)
+
+	service := &nodetasks.Service{
+		Name:       NodeReconcilerServiceName,
+		Definition: s(manifestString),
+	}
+	service.InitDefaults()
+	c.AddTask(service)
+
+	return nil
+}
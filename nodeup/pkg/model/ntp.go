@@ -48,6 +48,11 @@ func (b *NTPBuilder) Build(c *fi.NodeupModelBuilderContext) error {
 		klog.Infof("Detected Flatcar; won't install ntp")
 		return nil
 	}
+	if b.Distribution.IsImmutable() {
+		// Image-based distros ship with a time sync service
+		klog.Infof("Detected immutable distribution %v; won't install ntp", b.Distribution)
+		return nil
+	}
 
 	var ntpHost string
 	switch b.CloudProvider() {
@@ -17,6 +17,7 @@ limitations under the License.
 package model
 
 import (
+	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
 	"k8s.io/kops/util/pkg/distributions"
@@ -51,6 +52,9 @@ func (b *PackagesBuilder) Build(c *fi.NodeupModelBuilderContext) error {
 		c.AddTask(&nodetasks.Package{Name: "pigz"})
 		c.AddTask(&nodetasks.Package{Name: "socat"})
 		c.AddTask(&nodetasks.Package{Name: "util-linux"})
+		if b.NodeupConfig.FirewallBackend == kops.FirewallBackendNFTables {
+			c.AddTask(&nodetasks.Package{Name: "nftables"})
+		}
 		// Additional packages
 		for _, additionalPackage := range b.NodeupConfig.Packages {
 			c.EnsureTask(&nodetasks.Package{Name: additionalPackage})
@@ -70,6 +74,9 @@ func (b *PackagesBuilder) Build(c *fi.NodeupModelBuilderContext) error {
 		c.AddTask(&nodetasks.Package{Name: "libtool-ltdl"})
 		c.AddTask(&nodetasks.Package{Name: "socat"})
 		c.AddTask(&nodetasks.Package{Name: "util-linux"})
+		if b.NodeupConfig.FirewallBackend == kops.FirewallBackendNFTables {
+			c.AddTask(&nodetasks.Package{Name: "nftables"})
+		}
 		// Handle some packages differently for each distro
 		// Amazon Linux 2 doesn't have SELinux enabled by default
 		if b.Distribution != distributions.DistributionAmazonLinux2 {
@@ -84,6 +91,12 @@ func (b *PackagesBuilder) Build(c *fi.NodeupModelBuilderContext) error {
 		for _, additionalPackage := range b.NodeupConfig.Packages {
 			c.EnsureTask(&nodetasks.Package{Name: additionalPackage})
 		}
+	} else if b.Distribution.IsImmutable() {
+		// Immutable distros have no package manager; the required tools must be part of the image
+		klog.Infof("Detected immutable distribution %v; won't install packages", b.Distribution)
+		if len(b.NodeupConfig.Packages) != 0 {
+			klog.Warningf("cannot install additional packages %v on immutable distribution %v", b.NodeupConfig.Packages, b.Distribution)
+		}
 	} else {
 		// Hopefully they are already installed
 		klog.Warningf("unknown distribution, skipping required packages install: %v", b.Distribution)
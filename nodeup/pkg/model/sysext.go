@@ -0,0 +1,46 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+// SysextExtensionName is the name of the systemd-sysext extension holding the files that kOps installs under /usr.
+const SysextExtensionName = "kops"
+
+// SysextBuilder merges the files written to InstallPath into /usr, on distros with a read-only /usr.
+type SysextBuilder struct {
+	*NodeupModelContext
+}
+
+var _ fi.NodeupModelBuilder = &SysextBuilder{}
+
+func (b *SysextBuilder) Build(c *fi.NodeupModelBuilderContext) error {
+	if !b.Distribution.UsesSysext() {
+		return nil
+	}
+
+	// The extension is built on the node, for the OS release it is running, so it can match any OS
+	c.AddTask(&nodetasks.Sysext{
+		Name:    SysextExtensionName,
+		Release: "ID=_any\n",
+	})
+
+	return nil
+}
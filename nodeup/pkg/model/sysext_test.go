@@ -0,0 +1,32 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"testing"
+
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/distributions"
+)
+
+func TestSysextBuilder(t *testing.T) {
+	RunGoldenTest(t, "tests/sysextbuilder", "sysext", func(nodeupModelContext *NodeupModelContext, target *fi.NodeupModelBuilderContext) error {
+		nodeupModelContext.Distribution = distributions.DistributionFedoraCoreOS
+		builder := SysextBuilder{NodeupModelContext: nodeupModelContext}
+		return builder.Build(target)
+	})
+}
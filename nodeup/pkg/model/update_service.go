@@ -41,7 +41,9 @@ var _ fi.NodeupModelBuilder = &UpdateServiceBuilder{}
 // Build is responsible for configuring automatic updates based on the OS.
 func (b *UpdateServiceBuilder) Build(c *fi.NodeupModelBuilderContext) error {
 	if b.Distribution == distributions.DistributionFlatcar {
-		b.buildFlatcarSystemdService(c)
+		b.buildFlatcarSystemdService(c, "locksmithd.service")
+	} else if b.Distribution == distributions.DistributionFedoraCoreOS {
+		b.buildFlatcarSystemdService(c, "zincati.service")
 	} else if b.Distribution.IsDebianFamily() {
 		b.buildDebianPackage(c)
 	}
@@ -49,7 +51,8 @@ func (b *UpdateServiceBuilder) Build(c *fi.NodeupModelBuilderContext) error {
 	return nil
 }
 
-func (b *UpdateServiceBuilder) buildFlatcarSystemdService(c *fi.NodeupModelBuilderContext) {
+// buildFlatcarSystemdService builds a service that masks the update scheduler of an immutable OS, such as locksmithd on Flatcar.
+func (b *UpdateServiceBuilder) buildFlatcarSystemdService(c *fi.NodeupModelBuilderContext, updateScheduler string) {
 	for _, spec := range b.NodeupConfig.Hooks {
 		for _, hook := range spec {
 			if hook.Name == flatcarServiceName || hook.Name == flatcarServiceName+".service" {
@@ -64,9 +67,9 @@ func (b *UpdateServiceBuilder) buildFlatcarSystemdService(c *fi.NodeupModelBuild
 	manifest := &systemd.Manifest{}
 	manifest.Set("Unit", "Description", "Disable OS Update Scheduler")
 
-	manifest.Set("Unit", "Before", "locksmithd.service")
+	manifest.Set("Unit", "Before", updateScheduler)
 	manifest.Set("Service", "Type", "oneshot")
-	manifest.Set("Service", "ExecStart", "/usr/bin/systemctl mask --now locksmithd.service")
+	manifest.Set("Service", "ExecStart", "/usr/bin/systemctl mask --now "+updateScheduler)
 
 	manifestString := manifest.Render()
 	klog.V(8).Infof("Built service manifest %q\n%s", flatcarServiceName, manifestString)
@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/distributions"
 )
 
 func TestUpdateServiceBuilderAutomaticUpgrade(t *testing.T) {
@@ -35,3 +36,11 @@ func TestUpdateServiceBuilderExternal(t *testing.T) {
 		return builder.Build(target)
 	})
 }
+
+func TestUpdateServiceBuilderFedoraCoreOS(t *testing.T) {
+	RunGoldenTest(t, "tests/updateservicebuilder/fedoracoreos", "updateservice", func(nodeupModelContext *NodeupModelContext, target *fi.NodeupModelBuilderContext) error {
+		nodeupModelContext.Distribution = distributions.DistributionFedoraCoreOS
+		builder := UpdateServiceBuilder{NodeupModelContext: nodeupModelContext}
+		return builder.Build(target)
+	})
+}
@@ -31,6 +31,10 @@ const (
 	PathClusterCompleted = "cluster-completed.spec"
 	// PathKopsVersionUpdated is the path for the version of kops last used to apply the cluster.
 	PathKopsVersionUpdated = "kops-version.txt"
+	// PathRollingUpdateJournal is the path for the progress journal of the last rolling update.
+	PathRollingUpdateJournal = "rolling-update/journal.json"
+	// PathStateStoreLock is the path for the lease held by a command that is changing the cluster.
+	PathStateStoreLock = "lock.json"
 )
 
 func ConfigBase(vfsContext *vfs.VFSContext, c *api.Cluster) (vfs.Path, error) {
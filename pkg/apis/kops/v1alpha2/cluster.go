@@ -159,6 +159,8 @@ type ClusterSpec struct {
 	//   'automatic' (default): apply updates automatically (apply OS security upgrades, avoiding rebooting when possible)
 	//   'external': do not apply updates automatically; they are applied manually or by an external system
 	UpdatePolicy *string `json:"updatePolicy,omitempty"`
+	// NodeReconciliation configures nodeup to periodically apply the latest configuration to running nodes.
+	NodeReconciliation *NodeReconciliationSpec `json:"nodeReconciliation,omitempty"`
 	// ExternalPolicies allows the insertion of pre-existing managed policies on IG Roles
 	ExternalPolicies map[string][]string `json:"externalPolicies,omitempty"`
 	// Additional policies to add for roles
@@ -238,6 +240,8 @@ type ClusterSpec struct {
 	// specified, each parameter must follow the form variable=value, the way
 	// it would appear in sysctl.conf.
 	SysctlParameters []string `json:"sysctlParameters,omitempty"`
+	// Firewall configures the host firewall of the nodes.
+	Firewall *FirewallConfig `json:"firewall,omitempty"`
 	// RollingUpdate defines the default rolling-update settings for instance groups
 	RollingUpdate *RollingUpdate `json:"rollingUpdate,omitempty"`
 	// ClusterAutoscaler defines the cluster autoscaler configuration.
@@ -356,6 +360,13 @@ type AssetsSpec struct {
 	FileRepository *string `json:"fileRepository,omitempty"`
 	// ContainerProxy is a url for a pull-through proxy of a docker registry
 	ContainerProxy *string `json:"containerProxy,omitempty"`
+	// PinImageDigests resolves the images of kOps-managed components and addons to their digests when the cluster is updated,
+	// so that the manifests and nodes reference them by digest instead of by tag.
+	PinImageDigests bool `json:"pinImageDigests,omitempty"`
+	// SignaturePublicKeys are PEM-encoded public keys. If set, every image and file asset must be signed with one of the keys,
+	// which is verified when assets are copied to the local repositories, when the cluster is updated, in which case images
+	// are pinned to the verified digests, and when nodes download them.
+	SignaturePublicKeys []string `json:"signaturePublicKeys,omitempty"`
 }
 
 // IAMSpec adds control over the IAM security policies applied to resources
@@ -841,6 +852,15 @@ type DNSControllerGossipConfigSecondary struct {
 	Seed     *string `json:"seed,omitempty"`
 }
 
+// NodeReconciliationSpec configures nodeup to run as a service that keeps running nodes in sync with the cluster configuration.
+type NodeReconciliationSpec struct {
+	// Enabled runs nodeup periodically on every node, applying the configuration changes that do not require replacing the node.
+	// Changes that do require replacing the node are reported in the node's conditions.
+	Enabled *bool `json:"enabled,omitempty"`
+	// Interval is how often nodes fetch and apply the latest configuration. Defaults to 10m.
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
 type RollingUpdate struct {
 	// DrainAndTerminate enables draining and terminating nodes during rolling updates.
 	// Defaults to true.
@@ -870,6 +890,90 @@ type RollingUpdate struct {
 	// nodes.
 	// +optional
 	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
+	// Hooks are steps that run during the replacement of each instance, before the node is drained,
+	// after it is drained and after the cluster validates with its replacement.
+	// Hooks set on an instance group replace those set on the cluster.
+	// +optional
+	Hooks []RollingUpdateHook `json:"hooks,omitempty"`
+}
+
+// RollingUpdateHookStage is the point in the replacement of an instance at which a hook runs.
+type RollingUpdateHookStage string
+
+const (
+	// RollingUpdateHookStageBeforeDrain runs before the node is cordoned and drained.
+	RollingUpdateHookStageBeforeDrain RollingUpdateHookStage = "BeforeDrain"
+	// RollingUpdateHookStageAfterDrain runs after the node is drained, before the instance is terminated.
+	RollingUpdateHookStageAfterDrain RollingUpdateHookStage = "AfterDrain"
+	// RollingUpdateHookStageAfterValidate runs after the instance is terminated and the cluster validates.
+	RollingUpdateHookStageAfterValidate RollingUpdateHookStage = "AfterValidate"
+)
+
+// RollingUpdateHookFailurePolicy is the action taken when a hook fails.
+type RollingUpdateHookFailurePolicy string
+
+const (
+	// RollingUpdateHookFailurePolicyFail stops the rolling update when the hook fails.
+	RollingUpdateHookFailurePolicyFail RollingUpdateHookFailurePolicy = "Fail"
+	// RollingUpdateHookFailurePolicyIgnore logs the failure and continues the rolling update.
+	RollingUpdateHookFailurePolicyIgnore RollingUpdateHookFailurePolicy = "Ignore"
+)
+
+// RollingUpdateHook is a step that runs during the replacement of an instance.
+// Exactly one of Exec, HTTP or Job must be set.
+type RollingUpdateHook struct {
+	// Name identifies the hook in logs.
+	Name string `json:"name"`
+	// Stage is the point in the replacement of an instance at which the hook runs.
+	Stage RollingUpdateHookStage `json:"stage"`
+	// FailurePolicy is the action taken when the hook fails or times out. Defaults to Fail.
+	// +optional
+	FailurePolicy RollingUpdateHookFailurePolicy `json:"failurePolicy,omitempty"`
+	// Timeout is the maximum time to wait for the hook to complete. Defaults to 5m.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// Exec runs a command on the machine running the rolling update.
+	// +optional
+	Exec *ExecRollingUpdateHook `json:"exec,omitempty"`
+	// HTTP sends a request to a webhook.
+	// +optional
+	HTTP *HTTPRollingUpdateHook `json:"http,omitempty"`
+	// Job runs a Kubernetes Job in the cluster.
+	// +optional
+	Job *JobRollingUpdateHook `json:"job,omitempty"`
+}
+
+// ExecRollingUpdateHook runs a command on the machine running the rolling update.
+// The command fails the hook if it exits non-zero.
+type ExecRollingUpdateHook struct {
+	// Command is the command to run, with its arguments.
+	Command []string `json:"command"`
+}
+
+// HTTPRollingUpdateHook sends a POST request describing the instance to a webhook.
+// A response status other than 2xx fails the hook.
+type HTTPRollingUpdateHook struct {
+	// URL is the URL of the webhook.
+	URL string `json:"url"`
+	// Headers are additional headers to send with the request.
+	// +optional
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// JobRollingUpdateHook runs a Kubernetes Job in the cluster, and waits for it to complete.
+// The Job fails the hook if its pod fails.
+type JobRollingUpdateHook struct {
+	// Namespace is the namespace in which to create the Job. Defaults to kube-system.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Image is the container image to run.
+	Image string `json:"image"`
+	// Command is the entrypoint of the container, with its arguments.
+	// +optional
+	Command []string `json:"command,omitempty"`
+	// ServiceAccountName is the name of the service account to run the Job as.
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
 }
 
 type PackagesConfig struct {
@@ -0,0 +1,24 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+// FirewallConfig is the configuration for the host firewall of the nodes.
+type FirewallConfig struct {
+	// Backend is the tool used to configure the firewall: iptables (default) or nftables.
+	// With nftables, kube-proxy also defaults to the nftables proxy mode.
+	Backend string `json:"backend,omitempty"`
+}
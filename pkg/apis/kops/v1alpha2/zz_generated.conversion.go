@@ -444,6 +444,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ExecRollingUpdateHook)(nil), (*kops.ExecRollingUpdateHook)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_ExecRollingUpdateHook_To_kops_ExecRollingUpdateHook(a.(*ExecRollingUpdateHook), b.(*kops.ExecRollingUpdateHook), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.ExecRollingUpdateHook)(nil), (*ExecRollingUpdateHook)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_ExecRollingUpdateHook_To_v1alpha2_ExecRollingUpdateHook(a.(*kops.ExecRollingUpdateHook), b.(*ExecRollingUpdateHook), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*ExternalNetworkingSpec)(nil), (*kops.ExternalNetworkingSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_ExternalNetworkingSpec_To_kops_ExternalNetworkingSpec(a.(*ExternalNetworkingSpec), b.(*kops.ExternalNetworkingSpec), scope)
 	}); err != nil {
@@ -464,6 +474,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*FirewallConfig)(nil), (*kops.FirewallConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_FirewallConfig_To_kops_FirewallConfig(a.(*FirewallConfig), b.(*kops.FirewallConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.FirewallConfig)(nil), (*FirewallConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_FirewallConfig_To_v1alpha2_FirewallConfig(a.(*kops.FirewallConfig), b.(*FirewallConfig), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*FlannelNetworkingSpec)(nil), (*kops.FlannelNetworkingSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_FlannelNetworkingSpec_To_kops_FlannelNetworkingSpec(a.(*FlannelNetworkingSpec), b.(*kops.FlannelNetworkingSpec), scope)
 	}); err != nil {
@@ -514,6 +534,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*HTTPRollingUpdateHook)(nil), (*kops.HTTPRollingUpdateHook)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_HTTPRollingUpdateHook_To_kops_HTTPRollingUpdateHook(a.(*HTTPRollingUpdateHook), b.(*kops.HTTPRollingUpdateHook), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.HTTPRollingUpdateHook)(nil), (*HTTPRollingUpdateHook)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_HTTPRollingUpdateHook_To_v1alpha2_HTTPRollingUpdateHook(a.(*kops.HTTPRollingUpdateHook), b.(*HTTPRollingUpdateHook), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*HubbleSpec)(nil), (*kops.HubbleSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_HubbleSpec_To_kops_HubbleSpec(a.(*HubbleSpec), b.(*kops.HubbleSpec), scope)
 	}); err != nil {
@@ -584,6 +614,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*JobRollingUpdateHook)(nil), (*kops.JobRollingUpdateHook)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_JobRollingUpdateHook_To_kops_JobRollingUpdateHook(a.(*JobRollingUpdateHook), b.(*kops.JobRollingUpdateHook), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.JobRollingUpdateHook)(nil), (*JobRollingUpdateHook)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_JobRollingUpdateHook_To_v1alpha2_JobRollingUpdateHook(a.(*kops.JobRollingUpdateHook), b.(*JobRollingUpdateHook), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*KarpenterConfig)(nil), (*kops.KarpenterConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_KarpenterConfig_To_kops_KarpenterConfig(a.(*KarpenterConfig), b.(*kops.KarpenterConfig), scope)
 	}); err != nil {
@@ -914,6 +954,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*NodeReconciliationSpec)(nil), (*kops.NodeReconciliationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_NodeReconciliationSpec_To_kops_NodeReconciliationSpec(a.(*NodeReconciliationSpec), b.(*kops.NodeReconciliationSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.NodeReconciliationSpec)(nil), (*NodeReconciliationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_NodeReconciliationSpec_To_v1alpha2_NodeReconciliationSpec(a.(*kops.NodeReconciliationSpec), b.(*NodeReconciliationSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*NodeTerminationHandlerSpec)(nil), (*kops.NodeTerminationHandlerSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_NodeTerminationHandlerSpec_To_kops_NodeTerminationHandlerSpec(a.(*NodeTerminationHandlerSpec), b.(*kops.NodeTerminationHandlerSpec), scope)
 	}); err != nil {
@@ -1054,6 +1104,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*RollingUpdateHook)(nil), (*kops.RollingUpdateHook)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_RollingUpdateHook_To_kops_RollingUpdateHook(a.(*RollingUpdateHook), b.(*kops.RollingUpdateHook), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.RollingUpdateHook)(nil), (*RollingUpdateHook)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_RollingUpdateHook_To_v1alpha2_RollingUpdateHook(a.(*kops.RollingUpdateHook), b.(*RollingUpdateHook), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*RomanaNetworkingSpec)(nil), (*kops.RomanaNetworkingSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_RomanaNetworkingSpec_To_kops_RomanaNetworkingSpec(a.(*RomanaNetworkingSpec), b.(*kops.RomanaNetworkingSpec), scope)
 	}); err != nil {
@@ -1581,6 +1641,8 @@ func autoConvert_v1alpha2_AssetsSpec_To_kops_AssetsSpec(in *AssetsSpec, out *kop
 	out.ContainerRegistry = in.ContainerRegistry
 	out.FileRepository = in.FileRepository
 	out.ContainerProxy = in.ContainerProxy
+	out.PinImageDigests = in.PinImageDigests
+	out.SignaturePublicKeys = in.SignaturePublicKeys
 	return nil
 }
 
@@ -1593,6 +1655,8 @@ func autoConvert_kops_AssetsSpec_To_v1alpha2_AssetsSpec(in *kops.AssetsSpec, out
 	out.ContainerRegistry = in.ContainerRegistry
 	out.FileRepository = in.FileRepository
 	out.ContainerProxy = in.ContainerProxy
+	out.PinImageDigests = in.PinImageDigests
+	out.SignaturePublicKeys = in.SignaturePublicKeys
 	return nil
 }
 
@@ -2549,6 +2613,15 @@ func autoConvert_v1alpha2_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 	// INFO: in.KubernetesAPIAccess opted out of conversion generation
 	// INFO: in.IsolateMasters opted out of conversion generation
 	out.UpdatePolicy = in.UpdatePolicy
+	if in.NodeReconciliation != nil {
+		in, out := &in.NodeReconciliation, &out.NodeReconciliation
+		*out = new(kops.NodeReconciliationSpec)
+		if err := Convert_v1alpha2_NodeReconciliationSpec_To_kops_NodeReconciliationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NodeReconciliation = nil
+	}
 	out.ExternalPolicies = in.ExternalPolicies
 	out.AdditionalPolicies = in.AdditionalPolicies
 	if in.FileAssets != nil {
@@ -2798,6 +2871,15 @@ func autoConvert_v1alpha2_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 	}
 	out.UseHostCertificates = in.UseHostCertificates
 	out.SysctlParameters = in.SysctlParameters
+	if in.Firewall != nil {
+		in, out := &in.Firewall, &out.Firewall
+		*out = new(kops.FirewallConfig)
+		if err := Convert_v1alpha2_FirewallConfig_To_kops_FirewallConfig(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Firewall = nil
+	}
 	if in.RollingUpdate != nil {
 		in, out := &in.RollingUpdate, &out.RollingUpdate
 		*out = new(kops.RollingUpdate)
@@ -2889,6 +2971,15 @@ func autoConvert_kops_ClusterSpec_To_v1alpha2_ClusterSpec(in *kops.ClusterSpec,
 	out.NodePortAccess = in.NodePortAccess
 	out.SSHKeyName = in.SSHKeyName
 	out.UpdatePolicy = in.UpdatePolicy
+	if in.NodeReconciliation != nil {
+		in, out := &in.NodeReconciliation, &out.NodeReconciliation
+		*out = new(NodeReconciliationSpec)
+		if err := Convert_kops_NodeReconciliationSpec_To_v1alpha2_NodeReconciliationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NodeReconciliation = nil
+	}
 	out.ExternalPolicies = in.ExternalPolicies
 	out.AdditionalPolicies = in.AdditionalPolicies
 	if in.FileAssets != nil {
@@ -3133,6 +3224,15 @@ func autoConvert_kops_ClusterSpec_To_v1alpha2_ClusterSpec(in *kops.ClusterSpec,
 	}
 	out.UseHostCertificates = in.UseHostCertificates
 	out.SysctlParameters = in.SysctlParameters
+	if in.Firewall != nil {
+		in, out := &in.Firewall, &out.Firewall
+		*out = new(FirewallConfig)
+		if err := Convert_kops_FirewallConfig_To_v1alpha2_FirewallConfig(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Firewall = nil
+	}
 	if in.RollingUpdate != nil {
 		in, out := &in.RollingUpdate, &out.RollingUpdate
 		*out = new(RollingUpdate)
@@ -3867,6 +3967,26 @@ func Convert_kops_ExecContainerAction_To_v1alpha2_ExecContainerAction(in *kops.E
 	return autoConvert_kops_ExecContainerAction_To_v1alpha2_ExecContainerAction(in, out, s)
 }
 
+func autoConvert_v1alpha2_ExecRollingUpdateHook_To_kops_ExecRollingUpdateHook(in *ExecRollingUpdateHook, out *kops.ExecRollingUpdateHook, s conversion.Scope) error {
+	out.Command = in.Command
+	return nil
+}
+
+// Convert_v1alpha2_ExecRollingUpdateHook_To_kops_ExecRollingUpdateHook is an autogenerated conversion function.
+func Convert_v1alpha2_ExecRollingUpdateHook_To_kops_ExecRollingUpdateHook(in *ExecRollingUpdateHook, out *kops.ExecRollingUpdateHook, s conversion.Scope) error {
+	return autoConvert_v1alpha2_ExecRollingUpdateHook_To_kops_ExecRollingUpdateHook(in, out, s)
+}
+
+func autoConvert_kops_ExecRollingUpdateHook_To_v1alpha2_ExecRollingUpdateHook(in *kops.ExecRollingUpdateHook, out *ExecRollingUpdateHook, s conversion.Scope) error {
+	out.Command = in.Command
+	return nil
+}
+
+// Convert_kops_ExecRollingUpdateHook_To_v1alpha2_ExecRollingUpdateHook is an autogenerated conversion function.
+func Convert_kops_ExecRollingUpdateHook_To_v1alpha2_ExecRollingUpdateHook(in *kops.ExecRollingUpdateHook, out *ExecRollingUpdateHook, s conversion.Scope) error {
+	return autoConvert_kops_ExecRollingUpdateHook_To_v1alpha2_ExecRollingUpdateHook(in, out, s)
+}
+
 func autoConvert_v1alpha2_ExternalDNSConfig_To_kops_ExternalDNSConfig(in *ExternalDNSConfig, out *kops.ExternalDNSConfig, s conversion.Scope) error {
 	// INFO: in.Disable opted out of conversion generation
 	out.WatchIngress = in.WatchIngress
@@ -3946,6 +4066,26 @@ func Convert_kops_FileAssetSpec_To_v1alpha2_FileAssetSpec(in *kops.FileAssetSpec
 	return autoConvert_kops_FileAssetSpec_To_v1alpha2_FileAssetSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_FirewallConfig_To_kops_FirewallConfig(in *FirewallConfig, out *kops.FirewallConfig, s conversion.Scope) error {
+	out.Backend = in.Backend
+	return nil
+}
+
+// Convert_v1alpha2_FirewallConfig_To_kops_FirewallConfig is an autogenerated conversion function.
+func Convert_v1alpha2_FirewallConfig_To_kops_FirewallConfig(in *FirewallConfig, out *kops.FirewallConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha2_FirewallConfig_To_kops_FirewallConfig(in, out, s)
+}
+
+func autoConvert_kops_FirewallConfig_To_v1alpha2_FirewallConfig(in *kops.FirewallConfig, out *FirewallConfig, s conversion.Scope) error {
+	out.Backend = in.Backend
+	return nil
+}
+
+// Convert_kops_FirewallConfig_To_v1alpha2_FirewallConfig is an autogenerated conversion function.
+func Convert_kops_FirewallConfig_To_v1alpha2_FirewallConfig(in *kops.FirewallConfig, out *FirewallConfig, s conversion.Scope) error {
+	return autoConvert_kops_FirewallConfig_To_v1alpha2_FirewallConfig(in, out, s)
+}
+
 func autoConvert_v1alpha2_FlannelNetworkingSpec_To_kops_FlannelNetworkingSpec(in *FlannelNetworkingSpec, out *kops.FlannelNetworkingSpec, s conversion.Scope) error {
 	out.Backend = in.Backend
 	// INFO: in.DisableTxChecksumOffloading opted out of conversion generation
@@ -4075,6 +4215,28 @@ func Convert_kops_HTTPProxy_To_v1alpha2_HTTPProxy(in *kops.HTTPProxy, out *HTTPP
 	return autoConvert_kops_HTTPProxy_To_v1alpha2_HTTPProxy(in, out, s)
 }
 
+func autoConvert_v1alpha2_HTTPRollingUpdateHook_To_kops_HTTPRollingUpdateHook(in *HTTPRollingUpdateHook, out *kops.HTTPRollingUpdateHook, s conversion.Scope) error {
+	out.URL = in.URL
+	out.Headers = in.Headers
+	return nil
+}
+
+// Convert_v1alpha2_HTTPRollingUpdateHook_To_kops_HTTPRollingUpdateHook is an autogenerated conversion function.
+func Convert_v1alpha2_HTTPRollingUpdateHook_To_kops_HTTPRollingUpdateHook(in *HTTPRollingUpdateHook, out *kops.HTTPRollingUpdateHook, s conversion.Scope) error {
+	return autoConvert_v1alpha2_HTTPRollingUpdateHook_To_kops_HTTPRollingUpdateHook(in, out, s)
+}
+
+func autoConvert_kops_HTTPRollingUpdateHook_To_v1alpha2_HTTPRollingUpdateHook(in *kops.HTTPRollingUpdateHook, out *HTTPRollingUpdateHook, s conversion.Scope) error {
+	out.URL = in.URL
+	out.Headers = in.Headers
+	return nil
+}
+
+// Convert_kops_HTTPRollingUpdateHook_To_v1alpha2_HTTPRollingUpdateHook is an autogenerated conversion function.
+func Convert_kops_HTTPRollingUpdateHook_To_v1alpha2_HTTPRollingUpdateHook(in *kops.HTTPRollingUpdateHook, out *HTTPRollingUpdateHook, s conversion.Scope) error {
+	return autoConvert_kops_HTTPRollingUpdateHook_To_v1alpha2_HTTPRollingUpdateHook(in, out, s)
+}
+
 func autoConvert_v1alpha2_HookSpec_To_kops_HookSpec(in *HookSpec, out *kops.HookSpec, s conversion.Scope) error {
 	out.Name = in.Name
 	out.Enabled = in.Enabled
@@ -4725,6 +4887,32 @@ func Convert_kops_InstanceRequirementsSpec_To_v1alpha2_InstanceRequirementsSpec(
 	return autoConvert_kops_InstanceRequirementsSpec_To_v1alpha2_InstanceRequirementsSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_JobRollingUpdateHook_To_kops_JobRollingUpdateHook(in *JobRollingUpdateHook, out *kops.JobRollingUpdateHook, s conversion.Scope) error {
+	out.Namespace = in.Namespace
+	out.Image = in.Image
+	out.Command = in.Command
+	out.ServiceAccountName = in.ServiceAccountName
+	return nil
+}
+
+// Convert_v1alpha2_JobRollingUpdateHook_To_kops_JobRollingUpdateHook is an autogenerated conversion function.
+func Convert_v1alpha2_JobRollingUpdateHook_To_kops_JobRollingUpdateHook(in *JobRollingUpdateHook, out *kops.JobRollingUpdateHook, s conversion.Scope) error {
+	return autoConvert_v1alpha2_JobRollingUpdateHook_To_kops_JobRollingUpdateHook(in, out, s)
+}
+
+func autoConvert_kops_JobRollingUpdateHook_To_v1alpha2_JobRollingUpdateHook(in *kops.JobRollingUpdateHook, out *JobRollingUpdateHook, s conversion.Scope) error {
+	out.Namespace = in.Namespace
+	out.Image = in.Image
+	out.Command = in.Command
+	out.ServiceAccountName = in.ServiceAccountName
+	return nil
+}
+
+// Convert_kops_JobRollingUpdateHook_To_v1alpha2_JobRollingUpdateHook is an autogenerated conversion function.
+func Convert_kops_JobRollingUpdateHook_To_v1alpha2_JobRollingUpdateHook(in *kops.JobRollingUpdateHook, out *JobRollingUpdateHook, s conversion.Scope) error {
+	return autoConvert_kops_JobRollingUpdateHook_To_v1alpha2_JobRollingUpdateHook(in, out, s)
+}
+
 func autoConvert_v1alpha2_KarpenterConfig_To_kops_KarpenterConfig(in *KarpenterConfig, out *kops.KarpenterConfig, s conversion.Scope) error {
 	out.Enabled = in.Enabled
 	out.LogEncoding = in.LogEncoding
@@ -6715,6 +6903,28 @@ func Convert_kops_NodeProblemDetectorConfig_To_v1alpha2_NodeProblemDetectorConfi
 	return autoConvert_kops_NodeProblemDetectorConfig_To_v1alpha2_NodeProblemDetectorConfig(in, out, s)
 }
 
+func autoConvert_v1alpha2_NodeReconciliationSpec_To_kops_NodeReconciliationSpec(in *NodeReconciliationSpec, out *kops.NodeReconciliationSpec, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.Interval = in.Interval
+	return nil
+}
+
+// Convert_v1alpha2_NodeReconciliationSpec_To_kops_NodeReconciliationSpec is an autogenerated conversion function.
+func Convert_v1alpha2_NodeReconciliationSpec_To_kops_NodeReconciliationSpec(in *NodeReconciliationSpec, out *kops.NodeReconciliationSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_NodeReconciliationSpec_To_kops_NodeReconciliationSpec(in, out, s)
+}
+
+func autoConvert_kops_NodeReconciliationSpec_To_v1alpha2_NodeReconciliationSpec(in *kops.NodeReconciliationSpec, out *NodeReconciliationSpec, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.Interval = in.Interval
+	return nil
+}
+
+// Convert_kops_NodeReconciliationSpec_To_v1alpha2_NodeReconciliationSpec is an autogenerated conversion function.
+func Convert_kops_NodeReconciliationSpec_To_v1alpha2_NodeReconciliationSpec(in *kops.NodeReconciliationSpec, out *NodeReconciliationSpec, s conversion.Scope) error {
+	return autoConvert_kops_NodeReconciliationSpec_To_v1alpha2_NodeReconciliationSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_NodeTerminationHandlerSpec_To_kops_NodeTerminationHandlerSpec(in *NodeTerminationHandlerSpec, out *kops.NodeTerminationHandlerSpec, s conversion.Scope) error {
 	out.DeleteSQSMsgIfNodeNotFound = in.DeleteSQSMsgIfNodeNotFound
 	out.Enabled = in.Enabled
@@ -7201,6 +7411,17 @@ func autoConvert_v1alpha2_RollingUpdate_To_kops_RollingUpdate(in *RollingUpdate,
 	out.DrainAndTerminate = in.DrainAndTerminate
 	out.MaxUnavailable = in.MaxUnavailable
 	out.MaxSurge = in.MaxSurge
+	if in.Hooks != nil {
+		in, out := &in.Hooks, &out.Hooks
+		*out = make([]kops.RollingUpdateHook, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_RollingUpdateHook_To_kops_RollingUpdateHook(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Hooks = nil
+	}
 	return nil
 }
 
@@ -7213,6 +7434,17 @@ func autoConvert_kops_RollingUpdate_To_v1alpha2_RollingUpdate(in *kops.RollingUp
 	out.DrainAndTerminate = in.DrainAndTerminate
 	out.MaxUnavailable = in.MaxUnavailable
 	out.MaxSurge = in.MaxSurge
+	if in.Hooks != nil {
+		in, out := &in.Hooks, &out.Hooks
+		*out = make([]RollingUpdateHook, len(*in))
+		for i := range *in {
+			if err := Convert_kops_RollingUpdateHook_To_v1alpha2_RollingUpdateHook(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Hooks = nil
+	}
 	return nil
 }
 
@@ -7221,6 +7453,86 @@ func Convert_kops_RollingUpdate_To_v1alpha2_RollingUpdate(in *kops.RollingUpdate
 	return autoConvert_kops_RollingUpdate_To_v1alpha2_RollingUpdate(in, out, s)
 }
 
+func autoConvert_v1alpha2_RollingUpdateHook_To_kops_RollingUpdateHook(in *RollingUpdateHook, out *kops.RollingUpdateHook, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Stage = kops.RollingUpdateHookStage(in.Stage)
+	out.FailurePolicy = kops.RollingUpdateHookFailurePolicy(in.FailurePolicy)
+	out.Timeout = in.Timeout
+	if in.Exec != nil {
+		in, out := &in.Exec, &out.Exec
+		*out = new(kops.ExecRollingUpdateHook)
+		if err := Convert_v1alpha2_ExecRollingUpdateHook_To_kops_ExecRollingUpdateHook(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Exec = nil
+	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(kops.HTTPRollingUpdateHook)
+		if err := Convert_v1alpha2_HTTPRollingUpdateHook_To_kops_HTTPRollingUpdateHook(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.HTTP = nil
+	}
+	if in.Job != nil {
+		in, out := &in.Job, &out.Job
+		*out = new(kops.JobRollingUpdateHook)
+		if err := Convert_v1alpha2_JobRollingUpdateHook_To_kops_JobRollingUpdateHook(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Job = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha2_RollingUpdateHook_To_kops_RollingUpdateHook is an autogenerated conversion function.
+func Convert_v1alpha2_RollingUpdateHook_To_kops_RollingUpdateHook(in *RollingUpdateHook, out *kops.RollingUpdateHook, s conversion.Scope) error {
+	return autoConvert_v1alpha2_RollingUpdateHook_To_kops_RollingUpdateHook(in, out, s)
+}
+
+func autoConvert_kops_RollingUpdateHook_To_v1alpha2_RollingUpdateHook(in *kops.RollingUpdateHook, out *RollingUpdateHook, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Stage = RollingUpdateHookStage(in.Stage)
+	out.FailurePolicy = RollingUpdateHookFailurePolicy(in.FailurePolicy)
+	out.Timeout = in.Timeout
+	if in.Exec != nil {
+		in, out := &in.Exec, &out.Exec
+		*out = new(ExecRollingUpdateHook)
+		if err := Convert_kops_ExecRollingUpdateHook_To_v1alpha2_ExecRollingUpdateHook(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Exec = nil
+	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(HTTPRollingUpdateHook)
+		if err := Convert_kops_HTTPRollingUpdateHook_To_v1alpha2_HTTPRollingUpdateHook(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.HTTP = nil
+	}
+	if in.Job != nil {
+		in, out := &in.Job, &out.Job
+		*out = new(JobRollingUpdateHook)
+		if err := Convert_kops_JobRollingUpdateHook_To_v1alpha2_JobRollingUpdateHook(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Job = nil
+	}
+	return nil
+}
+
+// Convert_kops_RollingUpdateHook_To_v1alpha2_RollingUpdateHook is an autogenerated conversion function.
+func Convert_kops_RollingUpdateHook_To_v1alpha2_RollingUpdateHook(in *kops.RollingUpdateHook, out *RollingUpdateHook, s conversion.Scope) error {
+	return autoConvert_kops_RollingUpdateHook_To_v1alpha2_RollingUpdateHook(in, out, s)
+}
+
 func autoConvert_v1alpha2_RomanaNetworkingSpec_To_kops_RomanaNetworkingSpec(in *RomanaNetworkingSpec, out *kops.RomanaNetworkingSpec, s conversion.Scope) error {
 	out.DaemonServiceIP = in.DaemonServiceIP
 	out.EtcdServiceIP = in.EtcdServiceIP
@@ -263,6 +263,11 @@ func (in *AssetsSpec) DeepCopyInto(out *AssetsSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.SignaturePublicKeys != nil {
+		in, out := &in.SignaturePublicKeys, &out.SignaturePublicKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -1206,6 +1211,11 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.NodeReconciliation != nil {
+		in, out := &in.NodeReconciliation, &out.NodeReconciliation
+		*out = new(NodeReconciliationSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ExternalPolicies != nil {
 		in, out := &in.ExternalPolicies, &out.ExternalPolicies
 		*out = make(map[string][]string, len(*in))
@@ -1413,6 +1423,11 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Firewall != nil {
+		in, out := &in.Firewall, &out.Firewall
+		*out = new(FirewallConfig)
+		**out = **in
+	}
 	if in.RollingUpdate != nil {
 		in, out := &in.RollingUpdate, &out.RollingUpdate
 		*out = new(RollingUpdate)
@@ -2146,6 +2161,27 @@ func (in *ExecContainerAction) DeepCopy() *ExecContainerAction {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExecRollingUpdateHook) DeepCopyInto(out *ExecRollingUpdateHook) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecRollingUpdateHook.
+func (in *ExecRollingUpdateHook) DeepCopy() *ExecRollingUpdateHook {
+	if in == nil {
+		return nil
+	}
+	out := new(ExecRollingUpdateHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalDNSConfig) DeepCopyInto(out *ExternalDNSConfig) {
 	*out = *in
@@ -2204,6 +2240,22 @@ func (in *FileAssetSpec) DeepCopy() *FileAssetSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FirewallConfig) DeepCopyInto(out *FirewallConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirewallConfig.
+func (in *FirewallConfig) DeepCopy() *FirewallConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(FirewallConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FlannelNetworkingSpec) DeepCopyInto(out *FlannelNetworkingSpec) {
 	*out = *in
@@ -2324,6 +2376,29 @@ func (in *HTTPProxy) DeepCopy() *HTTPProxy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPRollingUpdateHook) DeepCopyInto(out *HTTPRollingUpdateHook) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRollingUpdateHook.
+func (in *HTTPRollingUpdateHook) DeepCopy() *HTTPRollingUpdateHook {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPRollingUpdateHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HookSpec) DeepCopyInto(out *HookSpec) {
 	*out = *in
@@ -2370,7 +2445,7 @@ func (in *Host) DeepCopyInto(out *Host) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	return
 }
 
@@ -2428,6 +2503,11 @@ func (in *HostList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HostSpec) DeepCopyInto(out *HostSpec) {
 	*out = *in
+	if in.TPM != nil {
+		in, out := &in.TPM, &out.TPM
+		*out = new(HostTPMSpec)
+		**out = **in
+	}
 	return
 }
 
@@ -2441,6 +2521,22 @@ func (in *HostSpec) DeepCopy() *HostSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostTPMSpec) DeepCopyInto(out *HostTPMSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostTPMSpec.
+func (in *HostTPMSpec) DeepCopy() *HostTPMSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HostTPMSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HubbleSpec) DeepCopyInto(out *HubbleSpec) {
 	*out = *in
@@ -2903,6 +2999,27 @@ func (in *InstanceRequirementsSpec) DeepCopy() *InstanceRequirementsSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JobRollingUpdateHook) DeepCopyInto(out *JobRollingUpdateHook) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobRollingUpdateHook.
+func (in *JobRollingUpdateHook) DeepCopy() *JobRollingUpdateHook {
+	if in == nil {
+		return nil
+	}
+	out := new(JobRollingUpdateHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KarpenterConfig) DeepCopyInto(out *KarpenterConfig) {
 	*out = *in
@@ -5070,6 +5187,32 @@ func (in *NodeProblemDetectorConfig) DeepCopy() *NodeProblemDetectorConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeReconciliationSpec) DeepCopyInto(out *NodeReconciliationSpec) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeReconciliationSpec.
+func (in *NodeReconciliationSpec) DeepCopy() *NodeReconciliationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeReconciliationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeTerminationHandlerSpec) DeepCopyInto(out *NodeTerminationHandlerSpec) {
 	*out = *in
@@ -5635,6 +5778,13 @@ func (in *RollingUpdate) DeepCopyInto(out *RollingUpdate) {
 		*out = new(intstr.IntOrString)
 		**out = **in
 	}
+	if in.Hooks != nil {
+		in, out := &in.Hooks, &out.Hooks
+		*out = make([]RollingUpdateHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -5648,6 +5798,42 @@ func (in *RollingUpdate) DeepCopy() *RollingUpdate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RollingUpdateHook) DeepCopyInto(out *RollingUpdateHook) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Exec != nil {
+		in, out := &in.Exec, &out.Exec
+		*out = new(ExecRollingUpdateHook)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(HTTPRollingUpdateHook)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Job != nil {
+		in, out := &in.Job, &out.Job
+		*out = new(JobRollingUpdateHook)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollingUpdateHook.
+func (in *RollingUpdateHook) DeepCopy() *RollingUpdateHook {
+	if in == nil {
+		return nil
+	}
+	out := new(RollingUpdateHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RomanaNetworkingSpec) DeepCopyInto(out *RomanaNetworkingSpec) {
 	*out = *in
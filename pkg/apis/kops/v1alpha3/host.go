@@ -36,6 +36,14 @@ type Host struct {
 type HostSpec struct {
 	PublicKey     string `json:"publicKey,omitempty"`
 	InstanceGroup string `json:"instanceGroup,omitempty"`
+	// TPM identifies the TPM of the machine, for machines that authenticate with TPM attestation.
+	TPM *HostTPMSpec `json:"tpm,omitempty"`
+}
+
+// HostTPMSpec holds the public key of the TPM of a machine.
+type HostTPMSpec struct {
+	// AttestationKey is the PEM-encoded public key of the attestation key that the machine creates in the TPM.
+	AttestationKey string `json:"attestationKey,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -504,6 +504,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ExecRollingUpdateHook)(nil), (*kops.ExecRollingUpdateHook)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_ExecRollingUpdateHook_To_kops_ExecRollingUpdateHook(a.(*ExecRollingUpdateHook), b.(*kops.ExecRollingUpdateHook), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.ExecRollingUpdateHook)(nil), (*ExecRollingUpdateHook)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_ExecRollingUpdateHook_To_v1alpha3_ExecRollingUpdateHook(a.(*kops.ExecRollingUpdateHook), b.(*ExecRollingUpdateHook), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*ExternalDNSConfig)(nil), (*kops.ExternalDNSConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_ExternalDNSConfig_To_kops_ExternalDNSConfig(a.(*ExternalDNSConfig), b.(*kops.ExternalDNSConfig), scope)
 	}); err != nil {
@@ -534,6 +544,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*FirewallConfig)(nil), (*kops.FirewallConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_FirewallConfig_To_kops_FirewallConfig(a.(*FirewallConfig), b.(*kops.FirewallConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.FirewallConfig)(nil), (*FirewallConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_FirewallConfig_To_v1alpha3_FirewallConfig(a.(*kops.FirewallConfig), b.(*FirewallConfig), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*FlannelNetworkingSpec)(nil), (*kops.FlannelNetworkingSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_FlannelNetworkingSpec_To_kops_FlannelNetworkingSpec(a.(*FlannelNetworkingSpec), b.(*kops.FlannelNetworkingSpec), scope)
 	}); err != nil {
@@ -594,6 +614,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*HTTPRollingUpdateHook)(nil), (*kops.HTTPRollingUpdateHook)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_HTTPRollingUpdateHook_To_kops_HTTPRollingUpdateHook(a.(*HTTPRollingUpdateHook), b.(*kops.HTTPRollingUpdateHook), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.HTTPRollingUpdateHook)(nil), (*HTTPRollingUpdateHook)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_HTTPRollingUpdateHook_To_v1alpha3_HTTPRollingUpdateHook(a.(*kops.HTTPRollingUpdateHook), b.(*HTTPRollingUpdateHook), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*HetznerSpec)(nil), (*kops.HetznerSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_HetznerSpec_To_kops_HetznerSpec(a.(*HetznerSpec), b.(*kops.HetznerSpec), scope)
 	}); err != nil {
@@ -704,6 +734,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*JobRollingUpdateHook)(nil), (*kops.JobRollingUpdateHook)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_JobRollingUpdateHook_To_kops_JobRollingUpdateHook(a.(*JobRollingUpdateHook), b.(*kops.JobRollingUpdateHook), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.JobRollingUpdateHook)(nil), (*JobRollingUpdateHook)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_JobRollingUpdateHook_To_v1alpha3_JobRollingUpdateHook(a.(*kops.JobRollingUpdateHook), b.(*JobRollingUpdateHook), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*KarpenterConfig)(nil), (*kops.KarpenterConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_KarpenterConfig_To_kops_KarpenterConfig(a.(*KarpenterConfig), b.(*kops.KarpenterConfig), scope)
 	}); err != nil {
@@ -1004,6 +1044,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*NodeReconciliationSpec)(nil), (*kops.NodeReconciliationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_NodeReconciliationSpec_To_kops_NodeReconciliationSpec(a.(*NodeReconciliationSpec), b.(*kops.NodeReconciliationSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.NodeReconciliationSpec)(nil), (*NodeReconciliationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_NodeReconciliationSpec_To_v1alpha3_NodeReconciliationSpec(a.(*kops.NodeReconciliationSpec), b.(*NodeReconciliationSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*NodeTerminationHandlerSpec)(nil), (*kops.NodeTerminationHandlerSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_NodeTerminationHandlerSpec_To_kops_NodeTerminationHandlerSpec(a.(*NodeTerminationHandlerSpec), b.(*kops.NodeTerminationHandlerSpec), scope)
 	}); err != nil {
@@ -1154,6 +1204,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*RollingUpdateHook)(nil), (*kops.RollingUpdateHook)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_RollingUpdateHook_To_kops_RollingUpdateHook(a.(*RollingUpdateHook), b.(*kops.RollingUpdateHook), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.RollingUpdateHook)(nil), (*RollingUpdateHook)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_RollingUpdateHook_To_v1alpha3_RollingUpdateHook(a.(*kops.RollingUpdateHook), b.(*RollingUpdateHook), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*RouteSpec)(nil), (*kops.RouteSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_RouteSpec_To_kops_RouteSpec(a.(*RouteSpec), b.(*kops.RouteSpec), scope)
 	}); err != nil {
@@ -1741,6 +1801,8 @@ func autoConvert_v1alpha3_AssetsSpec_To_kops_AssetsSpec(in *AssetsSpec, out *kop
 	out.ContainerRegistry = in.ContainerRegistry
 	out.FileRepository = in.FileRepository
 	out.ContainerProxy = in.ContainerProxy
+	out.PinImageDigests = in.PinImageDigests
+	out.SignaturePublicKeys = in.SignaturePublicKeys
 	return nil
 }
 
@@ -1753,6 +1815,8 @@ func autoConvert_kops_AssetsSpec_To_v1alpha3_AssetsSpec(in *kops.AssetsSpec, out
 	out.ContainerRegistry = in.ContainerRegistry
 	out.FileRepository = in.FileRepository
 	out.ContainerProxy = in.ContainerProxy
+	out.PinImageDigests = in.PinImageDigests
+	out.SignaturePublicKeys = in.SignaturePublicKeys
 	return nil
 }
 
@@ -2778,6 +2842,15 @@ func autoConvert_v1alpha3_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 	out.NodePortAccess = in.NodePortAccess
 	out.SSHKeyName = in.SSHKeyName
 	out.UpdatePolicy = in.UpdatePolicy
+	if in.NodeReconciliation != nil {
+		in, out := &in.NodeReconciliation, &out.NodeReconciliation
+		*out = new(kops.NodeReconciliationSpec)
+		if err := Convert_v1alpha3_NodeReconciliationSpec_To_kops_NodeReconciliationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NodeReconciliation = nil
+	}
 	out.ExternalPolicies = in.ExternalPolicies
 	out.AdditionalPolicies = in.AdditionalPolicies
 	if in.FileAssets != nil {
@@ -3014,6 +3087,15 @@ func autoConvert_v1alpha3_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 	}
 	out.UseHostCertificates = in.UseHostCertificates
 	out.SysctlParameters = in.SysctlParameters
+	if in.Firewall != nil {
+		in, out := &in.Firewall, &out.Firewall
+		*out = new(kops.FirewallConfig)
+		if err := Convert_v1alpha3_FirewallConfig_To_kops_FirewallConfig(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Firewall = nil
+	}
 	if in.RollingUpdate != nil {
 		in, out := &in.RollingUpdate, &out.RollingUpdate
 		*out = new(kops.RollingUpdate)
@@ -3112,6 +3194,15 @@ func autoConvert_kops_ClusterSpec_To_v1alpha3_ClusterSpec(in *kops.ClusterSpec,
 	out.NodePortAccess = in.NodePortAccess
 	out.SSHKeyName = in.SSHKeyName
 	out.UpdatePolicy = in.UpdatePolicy
+	if in.NodeReconciliation != nil {
+		in, out := &in.NodeReconciliation, &out.NodeReconciliation
+		*out = new(NodeReconciliationSpec)
+		if err := Convert_kops_NodeReconciliationSpec_To_v1alpha3_NodeReconciliationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NodeReconciliation = nil
+	}
 	out.ExternalPolicies = in.ExternalPolicies
 	out.AdditionalPolicies = in.AdditionalPolicies
 	if in.FileAssets != nil {
@@ -3348,6 +3439,15 @@ func autoConvert_kops_ClusterSpec_To_v1alpha3_ClusterSpec(in *kops.ClusterSpec,
 	}
 	out.UseHostCertificates = in.UseHostCertificates
 	out.SysctlParameters = in.SysctlParameters
+	if in.Firewall != nil {
+		in, out := &in.Firewall, &out.Firewall
+		*out = new(FirewallConfig)
+		if err := Convert_kops_FirewallConfig_To_v1alpha3_FirewallConfig(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Firewall = nil
+	}
 	if in.RollingUpdate != nil {
 		in, out := &in.RollingUpdate, &out.RollingUpdate
 		*out = new(RollingUpdate)
@@ -4127,6 +4227,26 @@ func Convert_kops_ExecContainerAction_To_v1alpha3_ExecContainerAction(in *kops.E
 	return autoConvert_kops_ExecContainerAction_To_v1alpha3_ExecContainerAction(in, out, s)
 }
 
+func autoConvert_v1alpha3_ExecRollingUpdateHook_To_kops_ExecRollingUpdateHook(in *ExecRollingUpdateHook, out *kops.ExecRollingUpdateHook, s conversion.Scope) error {
+	out.Command = in.Command
+	return nil
+}
+
+// Convert_v1alpha3_ExecRollingUpdateHook_To_kops_ExecRollingUpdateHook is an autogenerated conversion function.
+func Convert_v1alpha3_ExecRollingUpdateHook_To_kops_ExecRollingUpdateHook(in *ExecRollingUpdateHook, out *kops.ExecRollingUpdateHook, s conversion.Scope) error {
+	return autoConvert_v1alpha3_ExecRollingUpdateHook_To_kops_ExecRollingUpdateHook(in, out, s)
+}
+
+func autoConvert_kops_ExecRollingUpdateHook_To_v1alpha3_ExecRollingUpdateHook(in *kops.ExecRollingUpdateHook, out *ExecRollingUpdateHook, s conversion.Scope) error {
+	out.Command = in.Command
+	return nil
+}
+
+// Convert_kops_ExecRollingUpdateHook_To_v1alpha3_ExecRollingUpdateHook is an autogenerated conversion function.
+func Convert_kops_ExecRollingUpdateHook_To_v1alpha3_ExecRollingUpdateHook(in *kops.ExecRollingUpdateHook, out *ExecRollingUpdateHook, s conversion.Scope) error {
+	return autoConvert_kops_ExecRollingUpdateHook_To_v1alpha3_ExecRollingUpdateHook(in, out, s)
+}
+
 func autoConvert_v1alpha3_ExternalDNSConfig_To_kops_ExternalDNSConfig(in *ExternalDNSConfig, out *kops.ExternalDNSConfig, s conversion.Scope) error {
 	out.WatchIngress = in.WatchIngress
 	out.WatchNamespace = in.WatchNamespace
@@ -4215,6 +4335,26 @@ func Convert_kops_FileAssetSpec_To_v1alpha3_FileAssetSpec(in *kops.FileAssetSpec
 	return autoConvert_kops_FileAssetSpec_To_v1alpha3_FileAssetSpec(in, out, s)
 }
 
+func autoConvert_v1alpha3_FirewallConfig_To_kops_FirewallConfig(in *FirewallConfig, out *kops.FirewallConfig, s conversion.Scope) error {
+	out.Backend = in.Backend
+	return nil
+}
+
+// Convert_v1alpha3_FirewallConfig_To_kops_FirewallConfig is an autogenerated conversion function.
+func Convert_v1alpha3_FirewallConfig_To_kops_FirewallConfig(in *FirewallConfig, out *kops.FirewallConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha3_FirewallConfig_To_kops_FirewallConfig(in, out, s)
+}
+
+func autoConvert_kops_FirewallConfig_To_v1alpha3_FirewallConfig(in *kops.FirewallConfig, out *FirewallConfig, s conversion.Scope) error {
+	out.Backend = in.Backend
+	return nil
+}
+
+// Convert_kops_FirewallConfig_To_v1alpha3_FirewallConfig is an autogenerated conversion function.
+func Convert_kops_FirewallConfig_To_v1alpha3_FirewallConfig(in *kops.FirewallConfig, out *FirewallConfig, s conversion.Scope) error {
+	return autoConvert_kops_FirewallConfig_To_v1alpha3_FirewallConfig(in, out, s)
+}
+
 func autoConvert_v1alpha3_FlannelNetworkingSpec_To_kops_FlannelNetworkingSpec(in *FlannelNetworkingSpec, out *kops.FlannelNetworkingSpec, s conversion.Scope) error {
 	out.Backend = in.Backend
 	out.IptablesResyncSeconds = in.IptablesResyncSeconds
@@ -4393,6 +4533,28 @@ func Convert_kops_HTTPProxy_To_v1alpha3_HTTPProxy(in *kops.HTTPProxy, out *HTTPP
 	return autoConvert_kops_HTTPProxy_To_v1alpha3_HTTPProxy(in, out, s)
 }
 
+func autoConvert_v1alpha3_HTTPRollingUpdateHook_To_kops_HTTPRollingUpdateHook(in *HTTPRollingUpdateHook, out *kops.HTTPRollingUpdateHook, s conversion.Scope) error {
+	out.URL = in.URL
+	out.Headers = in.Headers
+	return nil
+}
+
+// Convert_v1alpha3_HTTPRollingUpdateHook_To_kops_HTTPRollingUpdateHook is an autogenerated conversion function.
+func Convert_v1alpha3_HTTPRollingUpdateHook_To_kops_HTTPRollingUpdateHook(in *HTTPRollingUpdateHook, out *kops.HTTPRollingUpdateHook, s conversion.Scope) error {
+	return autoConvert_v1alpha3_HTTPRollingUpdateHook_To_kops_HTTPRollingUpdateHook(in, out, s)
+}
+
+func autoConvert_kops_HTTPRollingUpdateHook_To_v1alpha3_HTTPRollingUpdateHook(in *kops.HTTPRollingUpdateHook, out *HTTPRollingUpdateHook, s conversion.Scope) error {
+	out.URL = in.URL
+	out.Headers = in.Headers
+	return nil
+}
+
+// Convert_kops_HTTPRollingUpdateHook_To_v1alpha3_HTTPRollingUpdateHook is an autogenerated conversion function.
+func Convert_kops_HTTPRollingUpdateHook_To_v1alpha3_HTTPRollingUpdateHook(in *kops.HTTPRollingUpdateHook, out *HTTPRollingUpdateHook, s conversion.Scope) error {
+	return autoConvert_kops_HTTPRollingUpdateHook_To_v1alpha3_HTTPRollingUpdateHook(in, out, s)
+}
+
 func autoConvert_v1alpha3_HetznerSpec_To_kops_HetznerSpec(in *HetznerSpec, out *kops.HetznerSpec, s conversion.Scope) error {
 	return nil
 }
@@ -5121,6 +5283,32 @@ func Convert_kops_InstanceRootVolumeSpec_To_v1alpha3_InstanceRootVolumeSpec(in *
 	return autoConvert_kops_InstanceRootVolumeSpec_To_v1alpha3_InstanceRootVolumeSpec(in, out, s)
 }
 
+func autoConvert_v1alpha3_JobRollingUpdateHook_To_kops_JobRollingUpdateHook(in *JobRollingUpdateHook, out *kops.JobRollingUpdateHook, s conversion.Scope) error {
+	out.Namespace = in.Namespace
+	out.Image = in.Image
+	out.Command = in.Command
+	out.ServiceAccountName = in.ServiceAccountName
+	return nil
+}
+
+// Convert_v1alpha3_JobRollingUpdateHook_To_kops_JobRollingUpdateHook is an autogenerated conversion function.
+func Convert_v1alpha3_JobRollingUpdateHook_To_kops_JobRollingUpdateHook(in *JobRollingUpdateHook, out *kops.JobRollingUpdateHook, s conversion.Scope) error {
+	return autoConvert_v1alpha3_JobRollingUpdateHook_To_kops_JobRollingUpdateHook(in, out, s)
+}
+
+func autoConvert_kops_JobRollingUpdateHook_To_v1alpha3_JobRollingUpdateHook(in *kops.JobRollingUpdateHook, out *JobRollingUpdateHook, s conversion.Scope) error {
+	out.Namespace = in.Namespace
+	out.Image = in.Image
+	out.Command = in.Command
+	out.ServiceAccountName = in.ServiceAccountName
+	return nil
+}
+
+// Convert_kops_JobRollingUpdateHook_To_v1alpha3_JobRollingUpdateHook is an autogenerated conversion function.
+func Convert_kops_JobRollingUpdateHook_To_v1alpha3_JobRollingUpdateHook(in *kops.JobRollingUpdateHook, out *JobRollingUpdateHook, s conversion.Scope) error {
+	return autoConvert_kops_JobRollingUpdateHook_To_v1alpha3_JobRollingUpdateHook(in, out, s)
+}
+
 func autoConvert_v1alpha3_KarpenterConfig_To_kops_KarpenterConfig(in *KarpenterConfig, out *kops.KarpenterConfig, s conversion.Scope) error {
 	out.Enabled = in.Enabled
 	out.LogEncoding = in.LogEncoding
@@ -6971,6 +7159,28 @@ func Convert_kops_NodeProblemDetectorConfig_To_v1alpha3_NodeProblemDetectorConfi
 	return autoConvert_kops_NodeProblemDetectorConfig_To_v1alpha3_NodeProblemDetectorConfig(in, out, s)
 }
 
+func autoConvert_v1alpha3_NodeReconciliationSpec_To_kops_NodeReconciliationSpec(in *NodeReconciliationSpec, out *kops.NodeReconciliationSpec, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.Interval = in.Interval
+	return nil
+}
+
+// Convert_v1alpha3_NodeReconciliationSpec_To_kops_NodeReconciliationSpec is an autogenerated conversion function.
+func Convert_v1alpha3_NodeReconciliationSpec_To_kops_NodeReconciliationSpec(in *NodeReconciliationSpec, out *kops.NodeReconciliationSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha3_NodeReconciliationSpec_To_kops_NodeReconciliationSpec(in, out, s)
+}
+
+func autoConvert_kops_NodeReconciliationSpec_To_v1alpha3_NodeReconciliationSpec(in *kops.NodeReconciliationSpec, out *NodeReconciliationSpec, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.Interval = in.Interval
+	return nil
+}
+
+// Convert_kops_NodeReconciliationSpec_To_v1alpha3_NodeReconciliationSpec is an autogenerated conversion function.
+func Convert_kops_NodeReconciliationSpec_To_v1alpha3_NodeReconciliationSpec(in *kops.NodeReconciliationSpec, out *NodeReconciliationSpec, s conversion.Scope) error {
+	return autoConvert_kops_NodeReconciliationSpec_To_v1alpha3_NodeReconciliationSpec(in, out, s)
+}
+
 func autoConvert_v1alpha3_NodeTerminationHandlerSpec_To_kops_NodeTerminationHandlerSpec(in *NodeTerminationHandlerSpec, out *kops.NodeTerminationHandlerSpec, s conversion.Scope) error {
 	out.DeleteSQSMsgIfNodeNotFound = in.DeleteSQSMsgIfNodeNotFound
 	out.Enabled = in.Enabled
@@ -7489,6 +7699,17 @@ func autoConvert_v1alpha3_RollingUpdate_To_kops_RollingUpdate(in *RollingUpdate,
 	out.DrainAndTerminate = in.DrainAndTerminate
 	out.MaxUnavailable = in.MaxUnavailable
 	out.MaxSurge = in.MaxSurge
+	if in.Hooks != nil {
+		in, out := &in.Hooks, &out.Hooks
+		*out = make([]kops.RollingUpdateHook, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha3_RollingUpdateHook_To_kops_RollingUpdateHook(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Hooks = nil
+	}
 	return nil
 }
 
@@ -7501,6 +7722,17 @@ func autoConvert_kops_RollingUpdate_To_v1alpha3_RollingUpdate(in *kops.RollingUp
 	out.DrainAndTerminate = in.DrainAndTerminate
 	out.MaxUnavailable = in.MaxUnavailable
 	out.MaxSurge = in.MaxSurge
+	if in.Hooks != nil {
+		in, out := &in.Hooks, &out.Hooks
+		*out = make([]RollingUpdateHook, len(*in))
+		for i := range *in {
+			if err := Convert_kops_RollingUpdateHook_To_v1alpha3_RollingUpdateHook(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Hooks = nil
+	}
 	return nil
 }
 
@@ -7509,6 +7741,86 @@ func Convert_kops_RollingUpdate_To_v1alpha3_RollingUpdate(in *kops.RollingUpdate
 	return autoConvert_kops_RollingUpdate_To_v1alpha3_RollingUpdate(in, out, s)
 }
 
+func autoConvert_v1alpha3_RollingUpdateHook_To_kops_RollingUpdateHook(in *RollingUpdateHook, out *kops.RollingUpdateHook, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Stage = kops.RollingUpdateHookStage(in.Stage)
+	out.FailurePolicy = kops.RollingUpdateHookFailurePolicy(in.FailurePolicy)
+	out.Timeout = in.Timeout
+	if in.Exec != nil {
+		in, out := &in.Exec, &out.Exec
+		*out = new(kops.ExecRollingUpdateHook)
+		if err := Convert_v1alpha3_ExecRollingUpdateHook_To_kops_ExecRollingUpdateHook(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Exec = nil
+	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(kops.HTTPRollingUpdateHook)
+		if err := Convert_v1alpha3_HTTPRollingUpdateHook_To_kops_HTTPRollingUpdateHook(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.HTTP = nil
+	}
+	if in.Job != nil {
+		in, out := &in.Job, &out.Job
+		*out = new(kops.JobRollingUpdateHook)
+		if err := Convert_v1alpha3_JobRollingUpdateHook_To_kops_JobRollingUpdateHook(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Job = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha3_RollingUpdateHook_To_kops_RollingUpdateHook is an autogenerated conversion function.
+func Convert_v1alpha3_RollingUpdateHook_To_kops_RollingUpdateHook(in *RollingUpdateHook, out *kops.RollingUpdateHook, s conversion.Scope) error {
+	return autoConvert_v1alpha3_RollingUpdateHook_To_kops_RollingUpdateHook(in, out, s)
+}
+
+func autoConvert_kops_RollingUpdateHook_To_v1alpha3_RollingUpdateHook(in *kops.RollingUpdateHook, out *RollingUpdateHook, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Stage = RollingUpdateHookStage(in.Stage)
+	out.FailurePolicy = RollingUpdateHookFailurePolicy(in.FailurePolicy)
+	out.Timeout = in.Timeout
+	if in.Exec != nil {
+		in, out := &in.Exec, &out.Exec
+		*out = new(ExecRollingUpdateHook)
+		if err := Convert_kops_ExecRollingUpdateHook_To_v1alpha3_ExecRollingUpdateHook(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Exec = nil
+	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(HTTPRollingUpdateHook)
+		if err := Convert_kops_HTTPRollingUpdateHook_To_v1alpha3_HTTPRollingUpdateHook(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.HTTP = nil
+	}
+	if in.Job != nil {
+		in, out := &in.Job, &out.Job
+		*out = new(JobRollingUpdateHook)
+		if err := Convert_kops_JobRollingUpdateHook_To_v1alpha3_JobRollingUpdateHook(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Job = nil
+	}
+	return nil
+}
+
+// Convert_kops_RollingUpdateHook_To_v1alpha3_RollingUpdateHook is an autogenerated conversion function.
+func Convert_kops_RollingUpdateHook_To_v1alpha3_RollingUpdateHook(in *kops.RollingUpdateHook, out *RollingUpdateHook, s conversion.Scope) error {
+	return autoConvert_kops_RollingUpdateHook_To_v1alpha3_RollingUpdateHook(in, out, s)
+}
+
 func autoConvert_v1alpha3_RouteSpec_To_kops_RouteSpec(in *RouteSpec, out *kops.RouteSpec, s conversion.Scope) error {
 	out.CIDR = in.CIDR
 	out.Target = in.Target
@@ -23,6 +23,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/kops/pkg/policy"
 )
 
 func ValidateAdditionalObject(ctx context.Context, fieldPath *field.Path, u *unstructured.Unstructured) field.ErrorList {
@@ -38,6 +39,7 @@ func ValidateAdditionalObject(ctx context.Context, fieldPath *field.Path, u *uns
 	// It would be nice to be able to consume validation code e.g. via a container,
 	// so we could be more extensible.
 	errors = append(errors, validateAdditionalObjectKubescheduler(ctx, fieldPath, gvk, u)...)
+	errors = append(errors, validateAdditionalObjectClusterPolicy(fieldPath, gvk, u)...)
 	return errors
 }
 
@@ -56,3 +58,15 @@ func validateAdditionalObjectKubescheduler(ctx context.Context, fieldPath *field
 
 	return errors
 }
+
+func validateAdditionalObjectClusterPolicy(fieldPath *field.Path, gvk schema.GroupVersionKind, u *unstructured.Unstructured) field.ErrorList {
+	if !policy.IsClusterPolicy(gvk) {
+		return nil
+	}
+
+	p, err := policy.Parse(u)
+	if err != nil {
+		return field.ErrorList{field.Invalid(fieldPath, u.GetName(), err.Error())}
+	}
+	return p.Validate(fieldPath)
+}
@@ -25,6 +25,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws/arn"
 	"github.com/blang/semver/v4"
@@ -43,6 +44,7 @@ import (
 	"k8s.io/kops/pkg/model/iam"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/utils"
+	"k8s.io/kops/util/pkg/signature"
 )
 
 func newValidateCluster(cluster *kops.Cluster, strict bool) field.ErrorList {
@@ -116,6 +118,10 @@ func validateClusterSpec(spec *kops.ClusterSpec, c *kops.Cluster, fieldPath *fie
 	// UpdatePolicy
 	allErrs = append(allErrs, IsValidValue(fieldPath.Child("updatePolicy"), spec.UpdatePolicy, []string{kops.UpdatePolicyAutomatic, kops.UpdatePolicyExternal})...)
 
+	if spec.NodeReconciliation != nil {
+		allErrs = append(allErrs, validateNodeReconciliation(spec.NodeReconciliation, fieldPath.Child("nodeReconciliation"))...)
+	}
+
 	// Hooks
 	for i := range spec.Hooks {
 		allErrs = append(allErrs, validateHookSpec(&spec.Hooks[i], fieldPath.Child("hooks").Index(i))...)
@@ -140,7 +146,11 @@ func validateClusterSpec(spec *kops.ClusterSpec, c *kops.Cluster, fieldPath *fie
 	}
 
 	if spec.KubeProxy != nil {
-		allErrs = append(allErrs, validateKubeProxy(spec.KubeProxy, fieldPath.Child("kubeProxy"))...)
+		allErrs = append(allErrs, validateKubeProxy(spec.KubeProxy, c, fieldPath.Child("kubeProxy"))...)
+	}
+
+	if spec.Firewall != nil {
+		allErrs = append(allErrs, validateFirewall(spec.Firewall, fieldPath.Child("firewall"))...)
 	}
 
 	if spec.Kubelet != nil {
@@ -210,9 +220,7 @@ func validateClusterSpec(spec *kops.ClusterSpec, c *kops.Cluster, fieldPath *fie
 	}
 
 	if spec.Assets != nil {
-		if spec.Assets.ContainerProxy != nil && spec.Assets.ContainerRegistry != nil {
-			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("assets", "containerProxy"), "containerProxy cannot be used in conjunction with containerRegistry"))
-		}
+		allErrs = append(allErrs, validateAssets(spec.Assets, fieldPath.Child("assets"))...)
 	}
 
 	for i, sysctlParameter := range spec.SysctlParameters {
@@ -870,7 +878,7 @@ func validateKubeScheduler(v *kops.KubeSchedulerConfig, c *kops.Cluster, fldPath
 	return allErrs
 }
 
-func validateKubeProxy(k *kops.KubeProxyConfig, fldPath *field.Path) field.ErrorList {
+func validateKubeProxy(k *kops.KubeProxyConfig, c *kops.Cluster, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
 	master := k.Master
@@ -885,6 +893,10 @@ func validateKubeProxy(k *kops.KubeProxyConfig, fldPath *field.Path) field.Error
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("master"), master, "Not a valid APIServer URL"))
 	}
 
+	if k.ProxyMode == "nftables" && c.IsKubernetesLT("1.31") {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("proxyMode"), "nftables proxy mode requires Kubernetes 1.31 or later"))
+	}
+
 	return allErrs
 }
 
@@ -1815,6 +1827,93 @@ func validateRollingUpdate(rollingUpdate *kops.RollingUpdate, fldpath *field.Pat
 			allErrs = append(allErrs, field.Forbidden(fldpath.Child("maxSurge"), "Cannot be zero if maxUnavailable is zero"))
 		}
 	}
+	names := sets.NewString()
+	for i := range rollingUpdate.Hooks {
+		hook := &rollingUpdate.Hooks[i]
+		if names.Has(hook.Name) {
+			allErrs = append(allErrs, field.Duplicate(fldpath.Child("hooks").Index(i).Child("name"), hook.Name))
+		}
+		names.Insert(hook.Name)
+		allErrs = append(allErrs, validateRollingUpdateHook(hook, fldpath.Child("hooks").Index(i))...)
+	}
+	return allErrs
+}
+
+func validateRollingUpdateHook(hook *kops.RollingUpdateHook, fldpath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if hook.Name == "" {
+		allErrs = append(allErrs, field.Required(fldpath.Child("name"), ""))
+	}
+	allErrs = append(allErrs, IsValidValue(fldpath.Child("stage"), &hook.Stage, kops.SupportedRollingUpdateHookStages)...)
+	if hook.FailurePolicy != "" {
+		allErrs = append(allErrs, IsValidValue(fldpath.Child("failurePolicy"), &hook.FailurePolicy, kops.SupportedRollingUpdateHookFailurePolicies)...)
+	}
+	if hook.Timeout != nil && hook.Timeout.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldpath.Child("timeout"), hook.Timeout.Duration.String(), "must be positive"))
+	}
+
+	actions := 0
+	if hook.Exec != nil {
+		actions++
+		if len(hook.Exec.Command) == 0 {
+			allErrs = append(allErrs, field.Required(fldpath.Child("exec", "command"), ""))
+		}
+	}
+	if hook.HTTP != nil {
+		actions++
+		if u, err := url.Parse(hook.HTTP.URL); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldpath.Child("http", "url"), hook.HTTP.URL, fmt.Sprintf("cannot parse URL: %v", err)))
+		} else if u.Scheme != "http" && u.Scheme != "https" {
+			allErrs = append(allErrs, field.Invalid(fldpath.Child("http", "url"), hook.HTTP.URL, "must be an http or https URL"))
+		}
+	}
+	if hook.Job != nil {
+		actions++
+		if hook.Job.Image == "" {
+			allErrs = append(allErrs, field.Required(fldpath.Child("job", "image"), ""))
+		}
+	}
+	if actions != 1 {
+		allErrs = append(allErrs, field.Invalid(fldpath, hook.Name, "exactly one of exec, http or job must be specified"))
+	}
+
+	return allErrs
+}
+
+func validateNodeReconciliation(spec *kops.NodeReconciliationSpec, fldpath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if spec.Interval != nil && spec.Interval.Duration < time.Minute {
+		allErrs = append(allErrs, field.Invalid(fldpath.Child("interval"), spec.Interval.Duration.String(), "must be at least 1m"))
+	}
+
+	return allErrs
+}
+
+func validateAssets(spec *kops.AssetsSpec, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if spec.ContainerProxy != nil && spec.ContainerRegistry != nil {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("containerProxy"), "containerProxy cannot be used in conjunction with containerRegistry"))
+	}
+
+	for i, publicKey := range spec.SignaturePublicKeys {
+		if _, err := signature.ParsePublicKey(publicKey); err != nil {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("signaturePublicKeys").Index(i), publicKey, err.Error()))
+		}
+	}
+
+	return allErrs
+}
+
+func validateFirewall(spec *kops.FirewallConfig, fldpath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if spec.Backend != "" {
+		allErrs = append(allErrs, IsValidValue(fldpath.Child("backend"), &spec.Backend, []string{kops.FirewallBackendIPTables, kops.FirewallBackendNFTables})...)
+	}
+
 	return allErrs
 }
 
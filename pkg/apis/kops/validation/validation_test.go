@@ -1386,6 +1386,63 @@ func Test_Validate_RollingUpdate(t *testing.T) {
 			},
 			ExpectedErrors: []string{"Forbidden::testField.maxSurge"},
 		},
+		{
+			Input: kops.RollingUpdate{
+				Hooks: []kops.RollingUpdateHook{
+					{
+						Name:  "deregister",
+						Stage: kops.RollingUpdateHookStageBeforeDrain,
+						Exec:  &kops.ExecRollingUpdateHook{Command: []string{"deregister.sh"}},
+					},
+					{
+						Name:          "snapshot",
+						Stage:         kops.RollingUpdateHookStageAfterDrain,
+						FailurePolicy: kops.RollingUpdateHookFailurePolicyIgnore,
+						Timeout:       &metav1.Duration{Duration: time.Minute},
+						HTTP:          &kops.HTTPRollingUpdateHook{URL: "https://example.com/snapshot"},
+					},
+					{
+						Name:  "verify",
+						Stage: kops.RollingUpdateHookStageAfterValidate,
+						Job:   &kops.JobRollingUpdateHook{Image: "example.com/verify:1.0"},
+					},
+				},
+			},
+		},
+		{
+			Input: kops.RollingUpdate{
+				Hooks: []kops.RollingUpdateHook{
+					{
+						Name:  "deregister",
+						Stage: "Sometime",
+						Exec:  &kops.ExecRollingUpdateHook{Command: []string{"deregister.sh"}},
+					},
+				},
+			},
+			ExpectedErrors: []string{"Unsupported value::testField.hooks[0].stage"},
+		},
+		{
+			Input: kops.RollingUpdate{
+				Hooks: []kops.RollingUpdateHook{
+					{
+						Name:  "deregister",
+						Stage: kops.RollingUpdateHookStageBeforeDrain,
+						Exec:  &kops.ExecRollingUpdateHook{Command: []string{"deregister.sh"}},
+						HTTP:  &kops.HTTPRollingUpdateHook{URL: "https://example.com/deregister"},
+					},
+					{
+						Name:  "deregister",
+						Stage: kops.RollingUpdateHookStageBeforeDrain,
+						HTTP:  &kops.HTTPRollingUpdateHook{URL: "ftp://example.com/deregister"},
+					},
+				},
+			},
+			ExpectedErrors: []string{
+				"Invalid value::testField.hooks[0]",
+				"Duplicate value::testField.hooks[1].name",
+				"Invalid value::testField.hooks[1].http.url",
+			},
+		},
 	}
 	for _, g := range grid {
 		errs := validateRollingUpdate(&g.Input, field.NewPath("testField"), g.OnMasterIG)
@@ -1851,3 +1908,97 @@ func Test_Validate_NriConfig(t *testing.T) {
 		testErrors(t, g.Input.Containerd, errs, g.ExpectedErrors)
 	}
 }
+
+func Test_Validate_NodeReconciliation(t *testing.T) {
+	grid := []struct {
+		Input          kops.NodeReconciliationSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.NodeReconciliationSpec{
+				Enabled: fi.PtrTo(true),
+			},
+		},
+		{
+			Input: kops.NodeReconciliationSpec{
+				Enabled:  fi.PtrTo(true),
+				Interval: &metav1.Duration{Duration: 5 * time.Minute},
+			},
+		},
+		{
+			Input: kops.NodeReconciliationSpec{
+				Enabled:  fi.PtrTo(true),
+				Interval: &metav1.Duration{Duration: 10 * time.Second},
+			},
+			ExpectedErrors: []string{"Invalid value::nodeReconciliation.interval"},
+		},
+	}
+	for _, g := range grid {
+		errs := validateNodeReconciliation(&g.Input, field.NewPath("nodeReconciliation"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_Firewall(t *testing.T) {
+	grid := []struct {
+		Input          kops.FirewallConfig
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.FirewallConfig{},
+		},
+		{
+			Input: kops.FirewallConfig{
+				Backend: "nftables",
+			},
+		},
+		{
+			Input: kops.FirewallConfig{
+				Backend: "firewalld",
+			},
+			ExpectedErrors: []string{"Unsupported value::firewall.backend"},
+		},
+	}
+	for _, g := range grid {
+		errs := validateFirewall(&g.Input, field.NewPath("firewall"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_Assets(t *testing.T) {
+	publicKey := `-----BEGIN PUBLIC KEY-----
+MFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAEuYgW9N0I9d6NmjTXTjim0VHIhCOW
+8SdAsVZ1YBZX/7omPLQ5+JQuWUnPqJ8kKjOskkabTMaYIIFva1PUECWtXQ==
+-----END PUBLIC KEY-----
+`
+	grid := []struct {
+		Input          kops.AssetsSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.AssetsSpec{},
+		},
+		{
+			Input: kops.AssetsSpec{
+				ContainerProxy:    fi.PtrTo("proxy.example.com"),
+				ContainerRegistry: fi.PtrTo("registry.example.com"),
+			},
+			ExpectedErrors: []string{"Forbidden::assets.containerProxy"},
+		},
+		{
+			Input: kops.AssetsSpec{
+				SignaturePublicKeys: []string{publicKey},
+			},
+		},
+		{
+			Input: kops.AssetsSpec{
+				SignaturePublicKeys: []string{publicKey, "not a key"},
+			},
+			ExpectedErrors: []string{"Invalid value::assets.signaturePublicKeys[1]"},
+		},
+	}
+	for _, g := range grid {
+		errs := validateAssets(&g.Input, field.NewPath("assets"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
@@ -52,6 +52,22 @@ type BootstrapResponse struct {
 	NodeConfig *NodeConfig `json:"nodeConfig,omitempty"`
 }
 
+// RenewRequest is a request from a node to kops-controller to renew the certificates it was issued when it bootstrapped.
+// The node authenticates with its kubelet client certificate, so the request is not signed.
+// The response is a BootstrapResponse.
+type RenewRequest struct {
+	// APIVersion defines the versioned schema of this representation of a request.
+	APIVersion string `json:"apiVersion"`
+	// Certs are the certificates to renew and the public keys of their replacements.
+	Certs map[string]string `json:"certs"`
+	// KeypairIDs are the keypair IDs of the CAs to use for issuing certificates.
+	KeypairIDs map[string]string `json:"keypairIDs"`
+
+	// KubeletServerCertificate is the current kubelet serving certificate,
+	// whose names are kept when the "kubelet-server" certificate is renewed.
+	KubeletServerCertificate string `json:"kubeletServerCertificate,omitempty"`
+}
+
 // NodeConfig holds configuration needed to boot a node (without the kops state store)
 type NodeConfig struct {
 	// NodeupConfig holds the nodeup.Config for the node's instance group.
@@ -18,14 +18,19 @@ package nodeup
 
 import (
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/apis/kops/model"
 	"k8s.io/kops/util/pkg/architectures"
 	"k8s.io/kops/util/pkg/reflectutils"
 )
 
+// defaultReconcileInterval is how often nodeup applies the latest configuration, if node reconciliation is enabled without an interval.
+const defaultReconcileInterval = 10 * time.Minute
+
 // Config is the configuration for the nodeup binary
 type Config struct {
 	// Assets are locations where we can find files to be installed
@@ -33,6 +38,8 @@ type Config struct {
 	Assets map[architectures.Architecture][]string `json:",omitempty"`
 	// Images are a list of images we should preload
 	Images map[architectures.Architecture][]*Image `json:"images,omitempty"`
+	// AssetSignaturePublicKeys are PEM-encoded public keys, one of which must have signed the assets and images nodeup downloads.
+	AssetSignaturePublicKeys []string `json:"assetSignaturePublicKeys,omitempty"`
 	// ClusterName is the name of the cluster
 	ClusterName string `json:",omitempty"`
 	// Channels is a list of channels that we should apply
@@ -81,8 +88,13 @@ type Config struct {
 	ServiceNodePortRange string `json:",omitempty"`
 	// SysctlParameters will configure kernel parameters using sysctl(8).
 	SysctlParameters []string `json:",omitempty"`
+	// FirewallBackend is the tool used to configure the node firewall; iptables if empty.
+	FirewallBackend string `json:",omitempty"`
 	// UpdatePolicy determines the policy for applying upgrades automatically.
 	UpdatePolicy string
+	// ReconcileInterval is how often nodeup applies the latest configuration to the running node.
+	// It is nil if nodeup only runs when the node boots.
+	ReconcileInterval *metav1.Duration `json:"reconcileInterval,omitempty"`
 	// VolumeMounts are a collection of volume mounts.
 	VolumeMounts []kops.VolumeMountSpec `json:",omitempty"`
 
@@ -299,6 +311,13 @@ func NewConfig(cluster *kops.Cluster, instanceGroup *kops.InstanceGroup) (*Confi
 		config.UpdatePolicy = kops.UpdatePolicyAutomatic
 	}
 
+	if r := cluster.Spec.NodeReconciliation; r != nil && r.Enabled != nil && *r.Enabled {
+		config.ReconcileInterval = &metav1.Duration{Duration: defaultReconcileInterval}
+		if r.Interval != nil {
+			config.ReconcileInterval = r.Interval
+		}
+	}
+
 	if cluster.InstallCNIAssets() {
 		config.InstallCNIAssets = true
 	}
@@ -406,6 +425,10 @@ func NewConfig(cluster *kops.Cluster, instanceGroup *kops.InstanceGroup) (*Confi
 		config.SysctlParameters = append(config.SysctlParameters, cluster.Spec.SysctlParameters...)
 	}
 
+	if cluster.Spec.Firewall != nil {
+		config.FirewallBackend = cluster.Spec.Firewall.Backend
+	}
+
 	return &config, &bootConfig
 }
 
@@ -25,8 +25,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/google/go-containerregistry/pkg/authn"
-	"github.com/google/go-containerregistry/pkg/crane"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/apis/kops"
@@ -35,6 +33,7 @@ import (
 	"k8s.io/kops/pkg/kubemanifest"
 	"k8s.io/kops/pkg/values"
 	"k8s.io/kops/util/pkg/hashing"
+	"k8s.io/kops/util/pkg/signature"
 	"k8s.io/kops/util/pkg/vfs"
 )
 
@@ -58,6 +57,13 @@ type AssetBuilder struct {
 	// StaticFiles records static files:
 	// * Configuration files supporting static pods
 	StaticFiles []*StaticFile
+
+	// ResolveImageDigest returns the digest of an image; if nil, the image is looked up in its registry.
+	ResolveImageDigest func(image string) (string, error)
+
+	// VerifyImageSignature verifies the signature of an image and returns its verified digest;
+	// if nil, the signature is looked up in the image's registry.
+	VerifyImageSignature func(image string) (string, error)
 }
 
 type StaticFile struct {
@@ -101,6 +107,8 @@ type ImageAsset struct {
 	DownloadLocation string
 	// CanonicalLocation will be the source location of the image.
 	CanonicalLocation string
+	// Digest is the digest the image was pinned to, if any.
+	Digest string
 }
 
 // FileAsset models a file's location.
@@ -144,6 +152,7 @@ func (a *AssetBuilder) RemapManifest(data []byte) ([]byte, error) {
 }
 
 // RemapImage normalizes a containers location if a user sets the AssetsLocation ContainerRegistry location.
+// If signatures are required, it fails unless the image is signed, and pins the image to the digest that was verified.
 func (a *AssetBuilder) RemapImage(image string) (string, error) {
 	asset := &ImageAsset{
 		DownloadLocation:  image,
@@ -224,7 +233,32 @@ func (a *AssetBuilder) RemapImage(image string) (string, error) {
 
 	a.ImageAssets = append(a.ImageAssets, asset)
 
-	if !featureflag.ImageDigest.Enabled() || os.Getenv("KOPS_BASE_URL") != "" {
+	// When getting assets, the image may not have been copied to the local repository yet;
+	// the copy has the same digest as the canonical image.
+	digestImage := image
+	if a.GetAssets && asset.DownloadLocation != asset.CanonicalLocation {
+		digestImage = asset.CanonicalLocation
+	}
+
+	// When signatures are required, the image is pinned to the digest that was verified
+	if a.AssetsLocation != nil && len(a.AssetsLocation.SignaturePublicKeys) != 0 {
+		digest, err := a.verifyImageSignature(digestImage)
+		if err != nil {
+			return "", fmt.Errorf("unable to verify signature of image %q: %w", digestImage, err)
+		}
+		asset.Digest = digest
+
+		if _, pinned, found := strings.Cut(image, "@"); found {
+			if pinned != digest {
+				return "", fmt.Errorf("image %q is pinned to a different digest than the verified digest %q", image, digest)
+			}
+			return image, nil
+		}
+		return image + "@" + digest, nil
+	}
+
+	pinImageDigests := a.AssetsLocation != nil && a.AssetsLocation.PinImageDigests
+	if !pinImageDigests && (!featureflag.ImageDigest.Enabled() || os.Getenv("KOPS_BASE_URL") != "") {
 		return image, nil
 	}
 
@@ -232,15 +266,36 @@ func (a *AssetBuilder) RemapImage(image string) (string, error) {
 		return image, nil
 	}
 
-	digest, err := crane.Digest(image, crane.WithAuthFromKeychain(authn.DefaultKeychain))
+	resolveImageDigest := a.ResolveImageDigest
+	if resolveImageDigest == nil {
+		resolveImageDigest = ImageDigest
+	}
+	digest, err := resolveImageDigest(digestImage)
 	if err != nil {
-		klog.Warningf("failed to digest image %q: %s", image, err)
+		if pinImageDigests {
+			return "", fmt.Errorf("unable to pin digest of image %q: %w", digestImage, err)
+		}
+		klog.Warningf("failed to digest image %q: %s", digestImage, err)
 		return image, nil
 	}
+	asset.Digest = digest
 
 	return image + "@" + digest, nil
 }
 
+// verifyImageSignature checks that the image is signed with one of the public keys, and returns the verified digest.
+func (a *AssetBuilder) verifyImageSignature(image string) (string, error) {
+	if a.VerifyImageSignature != nil {
+		return a.VerifyImageSignature(image)
+	}
+
+	verifier, err := signature.NewVerifier(a.AssetsLocation.SignaturePublicKeys)
+	if err != nil {
+		return "", err
+	}
+	return verifier.VerifyImage(image)
+}
+
 // RemapFile returns a remapped URL for the file, if AssetsLocation is defined.
 // It is returns in a FileAsset, alongside the SHA hash of the file.
 // The SHA hash is is knownHash is provided, and otherwise will be found first by
@@ -17,10 +17,16 @@ limitations under the License.
 package assets
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/testutils/golden"
 )
@@ -173,3 +179,234 @@ func TestRemapEmptySection(t *testing.T) {
 
 	golden.AssertMatchesFile(t, string(actual), expectedPath)
 }
+
+// annotationRefName is the annotation holding the name of an image in an OCI image layout.
+const annotationRefName = "org.opencontainers.image.ref.name"
+
+// ociLayoutImages writes an image for each name to an OCI image layout, which stands in for a registry,
+// and returns a digest resolver that looks images up in the layout.
+func ociLayoutImages(t *testing.T, names ...string) func(image string) (string, error) {
+	p, err := layout.Write(t.TempDir(), empty.Index)
+	if err != nil {
+		t.Fatalf("error writing OCI layout: %v", err)
+	}
+	for _, name := range names {
+		img, err := mutate.Config(empty.Image, v1.Config{Labels: map[string]string{"name": name}})
+		if err != nil {
+			t.Fatalf("error building image %q: %v", name, err)
+		}
+		if err := p.AppendImage(img, layout.WithAnnotations(map[string]string{annotationRefName: name})); err != nil {
+			t.Fatalf("error writing image %q: %v", name, err)
+		}
+	}
+
+	return func(image string) (string, error) {
+		index, err := p.ImageIndex()
+		if err != nil {
+			return "", err
+		}
+		manifest, err := index.IndexManifest()
+		if err != nil {
+			return "", err
+		}
+		for _, desc := range manifest.Manifests {
+			if desc.Annotations[annotationRefName] == image {
+				return desc.Digest.String(), nil
+			}
+		}
+		return "", fmt.Errorf("image %q not found", image)
+	}
+}
+
+func TestRemapImage_PinImageDigests(t *testing.T) {
+	resolver := ociLayoutImages(t, "registry.example.com/kube-proxy:v1.32.0", "registry.k8s.io/kube-proxy:v1.32.0")
+	digest, err := resolver("registry.example.com/kube-proxy:v1.32.0")
+	if err != nil {
+		t.Fatalf("error resolving digest: %v", err)
+	}
+
+	builder := buildAssetBuilder(t)
+	registry := "registry.example.com"
+	builder.AssetsLocation.ContainerRegistry = &registry
+	builder.AssetsLocation.PinImageDigests = true
+	builder.ResolveImageDigest = resolver
+
+	expected := "registry.example.com/kube-proxy:v1.32.0@" + digest
+	remapped, err := builder.RemapImage("registry.k8s.io/kube-proxy:v1.32.0")
+	if err != nil {
+		t.Fatalf("error remapping image: %v", err)
+	}
+	if remapped != expected {
+		t.Errorf("unexpected image; expected %q, got %q", expected, remapped)
+	}
+	if builder.ImageAssets[0].Digest != digest {
+		t.Errorf("unexpected asset digest; expected %q, got %q", digest, builder.ImageAssets[0].Digest)
+	}
+
+	// Remapping the pinned image again converges
+	remapped, err = builder.RemapImage(remapped)
+	if err != nil {
+		t.Fatalf("error remapping image: %v", err)
+	}
+	if remapped != expected {
+		t.Errorf("unexpected image; expected %q, got %q", expected, remapped)
+	}
+}
+
+func TestRemapImage_PinImageDigests_GetAssets(t *testing.T) {
+	// The image has not been copied to the local repository yet
+	resolver := ociLayoutImages(t, "registry.k8s.io/kube-proxy:v1.32.0")
+	digest, err := resolver("registry.k8s.io/kube-proxy:v1.32.0")
+	if err != nil {
+		t.Fatalf("error resolving digest: %v", err)
+	}
+
+	builder := buildAssetBuilder(t)
+	builder.GetAssets = true
+	registry := "registry.example.com"
+	builder.AssetsLocation.ContainerRegistry = &registry
+	builder.AssetsLocation.PinImageDigests = true
+	builder.ResolveImageDigest = resolver
+
+	expected := "registry.example.com/kube-proxy:v1.32.0@" + digest
+	remapped, err := builder.RemapImage("registry.k8s.io/kube-proxy:v1.32.0")
+	if err != nil {
+		t.Fatalf("error remapping image: %v", err)
+	}
+	if remapped != expected {
+		t.Errorf("unexpected image; expected %q, got %q", expected, remapped)
+	}
+}
+
+func TestRemapImage_PinImageDigests_Unresolved(t *testing.T) {
+	builder := buildAssetBuilder(t)
+	builder.AssetsLocation.PinImageDigests = true
+	builder.ResolveImageDigest = ociLayoutImages(t)
+
+	if _, err := builder.RemapImage("registry.k8s.io/kube-proxy:v1.32.0"); err == nil {
+		t.Errorf("expected an error remapping an image whose digest cannot be resolved")
+	}
+}
+
+func TestRemapManifest_PinImageDigests(t *testing.T) {
+	resolver := ociLayoutImages(t, "registry.k8s.io/coredns/coredns:v1.11.3")
+	digest, err := resolver("registry.k8s.io/coredns/coredns:v1.11.3")
+	if err != nil {
+		t.Fatalf("error resolving digest: %v", err)
+	}
+
+	builder := buildAssetBuilder(t)
+	builder.AssetsLocation.PinImageDigests = true
+	builder.ResolveImageDigest = resolver
+
+	manifest := `apiVersion: v1
+kind: Pod
+metadata:
+  name: coredns
+spec:
+  containers:
+  - image: registry.k8s.io/coredns/coredns:v1.11.3
+    name: coredns
+`
+	actual, err := builder.RemapManifest([]byte(manifest))
+	if err != nil {
+		t.Fatalf("error remapping manifest: %v", err)
+	}
+
+	expected := "image: registry.k8s.io/coredns/coredns:v1.11.3@" + digest + "\n"
+	if !strings.Contains(string(actual), expected) {
+		t.Errorf("expected manifest to contain %q, got:\n%s", expected, actual)
+	}
+}
+
+func TestRemapImage_SignaturePublicKeys(t *testing.T) {
+	digest := "sha256:4a5b0a2ce2d1f2fbbb7a0a5cbd1b80e3ca4b3b05c2a4e0b2e7e8a0fd1b5f8fe1"
+
+	builder := buildAssetBuilder(t)
+	registry := "registry.example.com"
+	builder.AssetsLocation.ContainerRegistry = &registry
+	builder.AssetsLocation.SignaturePublicKeys = []string{"unused"}
+	var verified []string
+	builder.VerifyImageSignature = func(image string) (string, error) {
+		verified = append(verified, image)
+		return digest, nil
+	}
+
+	// The image is pinned to the verified digest, even though pinImageDigests is not set
+	expected := "registry.example.com/kube-proxy:v1.32.0@" + digest
+	remapped, err := builder.RemapImage("registry.k8s.io/kube-proxy:v1.32.0")
+	if err != nil {
+		t.Fatalf("error remapping image: %v", err)
+	}
+	if remapped != expected {
+		t.Errorf("unexpected image; expected %q, got %q", expected, remapped)
+	}
+	if builder.ImageAssets[0].Digest != digest {
+		t.Errorf("unexpected asset digest; expected %q, got %q", digest, builder.ImageAssets[0].Digest)
+	}
+
+	// Remapping the pinned image again verifies it again, and converges
+	remapped, err = builder.RemapImage(remapped)
+	if err != nil {
+		t.Fatalf("error remapping image: %v", err)
+	}
+	if remapped != expected {
+		t.Errorf("unexpected image; expected %q, got %q", expected, remapped)
+	}
+
+	expectedVerified := []string{"registry.example.com/kube-proxy:v1.32.0", expected}
+	if strings.Join(verified, ",") != strings.Join(expectedVerified, ",") {
+		t.Errorf("unexpected images verified; expected %q, got %q", expectedVerified, verified)
+	}
+}
+
+func TestRemapImage_SignaturePublicKeys_GetAssets(t *testing.T) {
+	// The image has not been copied to the local repository yet, so its signature is verified at the canonical location
+	digest := "sha256:4a5b0a2ce2d1f2fbbb7a0a5cbd1b80e3ca4b3b05c2a4e0b2e7e8a0fd1b5f8fe1"
+
+	builder := buildAssetBuilder(t)
+	builder.GetAssets = true
+	registry := "registry.example.com"
+	builder.AssetsLocation.ContainerRegistry = &registry
+	builder.AssetsLocation.SignaturePublicKeys = []string{"unused"}
+	builder.VerifyImageSignature = func(image string) (string, error) {
+		if image != "registry.k8s.io/kube-proxy:v1.32.0" {
+			return "", fmt.Errorf("image %q not found", image)
+		}
+		return digest, nil
+	}
+
+	expected := "registry.example.com/kube-proxy:v1.32.0@" + digest
+	remapped, err := builder.RemapImage("registry.k8s.io/kube-proxy:v1.32.0")
+	if err != nil {
+		t.Fatalf("error remapping image: %v", err)
+	}
+	if remapped != expected {
+		t.Errorf("unexpected image; expected %q, got %q", expected, remapped)
+	}
+}
+
+func TestRemapImage_SignaturePublicKeys_Unverified(t *testing.T) {
+	builder := buildAssetBuilder(t)
+	builder.AssetsLocation.SignaturePublicKeys = []string{"unused"}
+	builder.VerifyImageSignature = func(image string) (string, error) {
+		return "", fmt.Errorf("no signature of %q was made with the public keys", image)
+	}
+
+	if _, err := builder.RemapImage("registry.k8s.io/kube-proxy:v1.32.0"); err == nil {
+		t.Errorf("expected an error remapping an image whose signature cannot be verified")
+	}
+}
+
+func TestRemapImage_SignaturePublicKeys_DifferentDigest(t *testing.T) {
+	builder := buildAssetBuilder(t)
+	builder.AssetsLocation.SignaturePublicKeys = []string{"unused"}
+	builder.VerifyImageSignature = func(image string) (string, error) {
+		return "sha256:4a5b0a2ce2d1f2fbbb7a0a5cbd1b80e3ca4b3b05c2a4e0b2e7e8a0fd1b5f8fe1", nil
+	}
+
+	image := "registry.k8s.io/kube-proxy:v1.32.0@sha256:0000000000000000000000000000000000000000000000000000000000000000"
+	if _, err := builder.RemapImage(image); err == nil {
+		t.Errorf("expected an error remapping an image pinned to a digest other than the verified one")
+	}
+}
@@ -22,6 +22,7 @@ import (
 
 	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/util/pkg/signature"
 	"k8s.io/kops/util/pkg/vfs"
 )
 
@@ -32,12 +33,23 @@ type assetTask interface {
 func Copy(imageAssets []*ImageAsset, fileAssets []*FileAsset, vfsContext *vfs.VFSContext, cluster *kops.Cluster) error {
 	tasks := map[string]assetTask{}
 
+	// Assets are only copied if they are signed, when signatures are required
+	var verifier *signature.Verifier
+	if cluster != nil && cluster.Spec.Assets != nil && len(cluster.Spec.Assets.SignaturePublicKeys) != 0 {
+		v, err := signature.NewVerifier(cluster.Spec.Assets.SignaturePublicKeys)
+		if err != nil {
+			return err
+		}
+		verifier = v
+	}
+
 	for _, imageAsset := range imageAssets {
 		if imageAsset.DownloadLocation != imageAsset.CanonicalLocation {
 			copyImageTask := &CopyImage{
 				Name:        imageAsset.DownloadLocation,
 				SourceImage: imageAsset.CanonicalLocation,
 				TargetImage: imageAsset.DownloadLocation,
+				Verifier:    verifier,
 			}
 
 			if existing, ok := tasks[copyImageTask.Name]; ok {
@@ -59,6 +71,7 @@ func Copy(imageAssets []*ImageAsset, fileAssets []*FileAsset, vfsContext *vfs.VF
 				SHA:        fileAsset.SHAValue.Hex(),
 				VFSContext: vfsContext,
 				Cluster:    cluster,
+				Verifier:   verifier,
 			}
 
 			if existing, ok := tasks[copyFileTask.Name]; ok {
@@ -28,6 +28,7 @@ import (
 	"k8s.io/kops/pkg/acls"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/util/pkg/hashing"
+	"k8s.io/kops/util/pkg/signature"
 	"k8s.io/kops/util/pkg/vfs"
 )
 
@@ -40,6 +41,9 @@ type CopyFile struct {
 	SHA        string
 	VFSContext *vfs.VFSContext
 	Cluster    *kops.Cluster
+
+	// Verifier, if set, verifies the detached signature of the source file, which is copied along with it.
+	Verifier *signature.Verifier
 }
 
 // fileExtensionForSHA returns the expected extension for the given hash
@@ -65,6 +69,12 @@ func (e *CopyFile) Run() error {
 		return err
 	}
 
+	if e.Verifier != nil {
+		if err := copyFileSignature(ctx, e.VFSContext, e.Cluster, e.Verifier, e.SourceFile, e.TargetFile, expectedSHA); err != nil {
+			return err
+		}
+	}
+
 	targetSHAFile := e.TargetFile + shaExtension
 
 	targetSHABytes, err := e.VFSContext.ReadFile(targetSHAFile)
@@ -163,6 +173,33 @@ func transferFile(ctx context.Context, vfsContext *vfs.VFSContext, cluster *kops
 	return nil
 }
 
+// copyFileSignature verifies the detached signature of the source file, and copies it next to the target file.
+func copyFileSignature(ctx context.Context, vfsContext *vfs.VFSContext, cluster *kops.Cluster, verifier *signature.Verifier, source string, target string, sha string) error {
+	hash, err := hashing.FromString(sha)
+	if err != nil {
+		return fmt.Errorf("unable to parse sha: %q, %v", sha, err)
+	}
+
+	sourceSig := source + signature.FileSignatureSuffix
+	sig, err := vfsContext.ReadFile(sourceSig)
+	if err != nil {
+		return fmt.Errorf("error downloading signature %q: %v", sourceSig, err)
+	}
+	if err := verifier.VerifyHash(hash, sig); err != nil {
+		return fmt.Errorf("verifying signature of %q: %w", source, err)
+	}
+
+	objectStore, err := buildVFSPath(target)
+	if err != nil {
+		return err
+	}
+	sigVFS, err := vfsContext.BuildVfsPath(objectStore + signature.FileSignatureSuffix)
+	if err != nil {
+		return fmt.Errorf("error building path %q: %v", objectStore+signature.FileSignatureSuffix, err)
+	}
+	return writeFile(ctx, cluster, sigVFS, sig)
+}
+
 func writeFile(ctx context.Context, cluster *kops.Cluster, p vfs.Path, data []byte) error {
 	acl, err := acls.GetACL(ctx, p, cluster)
 	if err != nil {
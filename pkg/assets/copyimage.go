@@ -24,6 +24,7 @@ import (
 	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/google/go-containerregistry/pkg/v1/types"
 	"k8s.io/klog/v2"
+	"k8s.io/kops/util/pkg/signature"
 )
 
 // CopyImage copies a docker image from a source registry, to a target registry,
@@ -32,6 +33,9 @@ type CopyImage struct {
 	Name        string
 	SourceImage string
 	TargetImage string
+
+	// Verifier, if set, verifies the signatures of the source image, which are copied along with it.
+	Verifier *signature.Verifier
 }
 
 func (e *CopyImage) Run() error {
@@ -50,6 +54,15 @@ func (e *CopyImage) Run() error {
 
 	options := []remote.Option{remote.WithAuthFromKeychain(authn.DefaultKeychain)}
 
+	if e.Verifier != nil {
+		digest, err := e.Verifier.VerifyImage(source)
+		if err != nil {
+			return err
+		}
+		// Copy the image that was verified, even if the tag is pushed again
+		sourceRef = sourceRef.Context().Digest(digest)
+	}
+
 	desc, err := remote.Get(sourceRef, options...)
 	if err != nil {
 		return fmt.Errorf("fetching %q: %v", source, err)
@@ -58,25 +71,43 @@ func (e *CopyImage) Run() error {
 	targetDesc, err := remote.Get(targetRef, options...)
 	if err == nil && desc.Digest.String() == targetDesc.Digest.String() {
 		klog.Infof("no need to copy image from %v to %v", sourceRef, targetRef)
-		return nil
+	} else {
+		switch desc.MediaType {
+		case types.OCIImageIndex, types.DockerManifestList:
+			// Handle indexes separately.
+			if err := copyIndex(desc, sourceRef, targetRef, options...); err != nil {
+				return fmt.Errorf("failed to copy index: %v", err)
+			}
+		default:
+			// Assume anything else is an image, since some registries don't set mediaTypes properly.
+			if err := copyImage(desc, sourceRef, targetRef, options...); err != nil {
+				return fmt.Errorf("failed to copy image: %v", err)
+			}
+		}
 	}
 
-	switch desc.MediaType {
-	case types.OCIImageIndex, types.DockerManifestList:
-		// Handle indexes separately.
-		if err := copyIndex(desc, sourceRef, targetRef, options...); err != nil {
-			return fmt.Errorf("failed to copy index: %v", err)
-		}
-	default:
-		// Assume anything else is an image, since some registries don't set mediaTypes properly.
-		if err := copyImage(desc, sourceRef, targetRef, options...); err != nil {
-			return fmt.Errorf("failed to copy image: %v", err)
+	if e.Verifier != nil {
+		if err := copySignatures(desc.Digest.String(), sourceRef, targetRef, options...); err != nil {
+			return fmt.Errorf("failed to copy signatures: %v", err)
 		}
 	}
 
 	return nil
 }
 
+// copySignatures copies the cosign signatures of the image with the digest, so that nodes can verify the copied image.
+func copySignatures(digest string, sourceRef name.Reference, targetRef name.Reference, options ...remote.Option) error {
+	sourceSigRef := signature.SignatureTag(sourceRef.Context(), digest)
+	targetSigRef := signature.SignatureTag(targetRef.Context(), digest)
+	klog.Infof("copying signatures from %v to %v", sourceSigRef, targetSigRef)
+
+	img, err := remote.Image(sourceSigRef, options...)
+	if err != nil {
+		return err
+	}
+	return remote.Write(targetSigRef, img, options...)
+}
+
 func copyImage(desc *remote.Descriptor, sourceRef name.Reference, targetRef name.Reference, options ...remote.Option) error {
 	klog.Infof("copying image from %v to %v", sourceRef, targetRef)
 
@@ -0,0 +1,385 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/uuid"
+	"k8s.io/kops/util/pkg/hashing"
+)
+
+const (
+	// SBOMFormatSPDX is the SPDX 2.3 JSON format.
+	SBOMFormatSPDX = "spdx"
+	// SBOMFormatCycloneDX is the CycloneDX 1.5 JSON format.
+	SBOMFormatCycloneDX = "cyclonedx"
+)
+
+// SBOM is a software bill of materials, listing the files and images used by a cluster.
+type SBOM struct {
+	// ClusterName is the name of the cluster the assets are used by.
+	ClusterName string
+	// ToolVersion is the version of kOps that built the SBOM.
+	ToolVersion string
+	// Timestamp is when the SBOM was built.
+	Timestamp time.Time
+	// Components are the files and images used by the cluster.
+	Components []*SBOMComponent
+}
+
+// SBOMComponent is a file or image used by a cluster.
+type SBOMComponent struct {
+	// Kind is "file" or "image".
+	Kind string
+	// Name is the name of the file, or the repository of the image.
+	Name string
+	// Version is the version found in the location of the file, or the tag of the image, if any.
+	Version string
+	// Canonical is the canonical location, from which the asset originates.
+	Canonical string
+	// Download is the location from which the cluster downloads the asset.
+	Download string
+	// Hash is the hash of the file; it is nil for images.
+	Hash *hashing.Hash
+	// Digest is the digest of the image, if it is known.
+	Digest string
+}
+
+// versionRegex matches the version in the location of a file, for example v1.31.0 or 1.7.20.
+var versionRegex = regexp.MustCompile(`v?[0-9]+\.[0-9]+\.[0-9]+(-(alpha|beta|rc)\.?[0-9]+)?`)
+
+// BuildSBOMComponents lists the components of the assets; imageDigests holds the known digests of the images, by download location.
+func BuildSBOMComponents(imageAssets []*ImageAsset, fileAssets []*FileAsset, imageDigests map[string]string) []*SBOMComponent {
+	var components []*SBOMComponent
+
+	seen := make(map[string]bool)
+	for _, imageAsset := range imageAssets {
+		if seen[imageAsset.CanonicalLocation] {
+			continue
+		}
+		seen[imageAsset.CanonicalLocation] = true
+
+		component := &SBOMComponent{
+			Kind:      "image",
+			Name:      imageAsset.CanonicalLocation,
+			Canonical: imageAsset.CanonicalLocation,
+			Download:  imageAsset.DownloadLocation,
+			Digest:    imageDigests[imageAsset.DownloadLocation],
+		}
+		if ref, err := name.ParseReference(imageAsset.CanonicalLocation); err == nil {
+			component.Name = ref.Context().Name()
+			if tag, ok := ref.(name.Tag); ok {
+				component.Version = tag.TagStr()
+			} else if component.Digest == "" {
+				component.Digest = ref.Identifier()
+			}
+		}
+		components = append(components, component)
+	}
+
+	seen = make(map[string]bool)
+	for _, fileAsset := range fileAssets {
+		canonical := fileAsset.CanonicalURL.String()
+		if seen[canonical] {
+			continue
+		}
+		seen[canonical] = true
+
+		component := &SBOMComponent{
+			Kind:      "file",
+			Name:      path.Base(fileAsset.CanonicalURL.Path),
+			Canonical: canonical,
+			Download:  fileAsset.DownloadURL.String(),
+			Hash:      fileAsset.SHAValue,
+		}
+		// Prefer the version in the name of the file, then the closest directory
+		segments := strings.Split(fileAsset.CanonicalURL.Path, "/")
+		for i := len(segments) - 1; i >= 0 && component.Version == ""; i-- {
+			component.Version = versionRegex.FindString(segments[i])
+		}
+		components = append(components, component)
+	}
+
+	return components
+}
+
+// purl returns the package URL of the component.
+func (c *SBOMComponent) purl() string {
+	if c.Kind == "image" {
+		if c.Digest == "" {
+			return ""
+		}
+		repository := c.Name
+		if i := strings.LastIndex(repository, "/"); i != -1 {
+			repository = repository[i+1:]
+		}
+		q := url.Values{}
+		q.Set("repository_url", c.Name)
+		if c.Version != "" {
+			q.Set("tag", c.Version)
+		}
+		return "pkg:oci/" + purlEscape(repository) + "@" + purlEscape(c.Digest) + "?" + q.Encode()
+	}
+
+	s := "pkg:generic/" + purlEscape(c.Name)
+	if c.Version != "" {
+		s += "@" + purlEscape(c.Version)
+	}
+	q := url.Values{}
+	q.Set("download_url", c.Canonical)
+	if c.Hash != nil {
+		q.Set("checksum", string(c.Hash.Algorithm)+":"+c.Hash.Hex())
+	}
+	return s + "?" + q.Encode()
+}
+
+// purlEscape percent-encodes a name or version in a package URL, where ":" must be encoded.
+func purlEscape(s string) string {
+	return strings.ReplaceAll(url.PathEscape(s), ":", "%3A")
+}
+
+// digestHex splits an image digest into its algorithm and hex value.
+func digestHex(digest string) (string, string) {
+	algorithm, hex, found := strings.Cut(digest, ":")
+	if !found {
+		return "", ""
+	}
+	return algorithm, hex
+}
+
+type spdxDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	Name             string            `json:"name"`
+	SPDXID           string            `json:"SPDXID"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	PrimaryPurpose   string            `json:"primaryPackagePurpose"`
+	DownloadLocation string            `json:"downloadLocation"`
+	SourceInfo       string            `json:"sourceInfo,omitempty"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	Checksums        []spdxChecksum    `json:"checksums,omitempty"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs,omitempty"`
+}
+
+type spdxChecksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+// spdxChecksumAlgorithm returns the SPDX name of a hash algorithm, such as SHA256 for sha256.
+func spdxChecksumAlgorithm(algorithm string) string {
+	return strings.ToUpper(strings.ReplaceAll(algorithm, "-", ""))
+}
+
+// WriteSPDX writes the SBOM as an SPDX 2.3 JSON document.
+func (s *SBOM) WriteSPDX(w io.Writer) error {
+	doc := spdxDocument{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              "kops-assets-" + s.ClusterName,
+		DocumentNamespace: "https://kops.sigs.k8s.io/spdx/" + url.PathEscape(s.ClusterName) + "-" + uuid.NewString(),
+		CreationInfo: spdxCreationInfo{
+			Created:  s.Timestamp.UTC().Format(time.RFC3339),
+			Creators: []string{"Tool: kops-" + s.ToolVersion},
+		},
+		Packages:      []spdxPackage{},
+		Relationships: []spdxRelationship{},
+	}
+
+	for i, c := range s.Components {
+		p := spdxPackage{
+			Name:             c.Name,
+			SPDXID:           fmt.Sprintf("SPDXRef-Package-%s-%d", c.Kind, i),
+			VersionInfo:      c.Version,
+			DownloadLocation: c.Canonical,
+			FilesAnalyzed:    false,
+		}
+		if c.Kind == "image" {
+			p.PrimaryPurpose = "CONTAINER"
+			// Images are not downloaded from URLs, so they have no SPDX download location
+			p.DownloadLocation = "NOASSERTION"
+			if algorithm, hex := digestHex(c.Digest); hex != "" {
+				p.Checksums = append(p.Checksums, spdxChecksum{Algorithm: spdxChecksumAlgorithm(algorithm), ChecksumValue: hex})
+			}
+		} else {
+			p.PrimaryPurpose = "FILE"
+			if c.Hash != nil {
+				p.Checksums = append(p.Checksums, spdxChecksum{Algorithm: spdxChecksumAlgorithm(string(c.Hash.Algorithm)), ChecksumValue: c.Hash.Hex()})
+			}
+		}
+		if c.Download != c.Canonical {
+			p.SourceInfo = "mirrored at " + c.Download
+		}
+		if purl := c.purl(); purl != "" {
+			p.ExternalRefs = append(p.ExternalRefs, spdxExternalRef{
+				ReferenceCategory: "PACKAGE-MANAGER",
+				ReferenceType:     "purl",
+				ReferenceLocator:  purl,
+			})
+		}
+		doc.Packages = append(doc.Packages, p)
+		doc.Relationships = append(doc.Relationships, spdxRelationship{
+			SPDXElementID:      doc.SPDXID,
+			RelationshipType:   "DESCRIBES",
+			RelatedSPDXElement: p.SPDXID,
+		})
+	}
+
+	return writeSBOMJSON(w, doc)
+}
+
+type cycloneDXDocument struct {
+	BOMFormat    string               `json:"bomFormat"`
+	SpecVersion  string               `json:"specVersion"`
+	SerialNumber string               `json:"serialNumber"`
+	Version      int                  `json:"version"`
+	Metadata     cycloneDXMetadata    `json:"metadata"`
+	Components   []cycloneDXComponent `json:"components"`
+}
+
+type cycloneDXMetadata struct {
+	Timestamp string             `json:"timestamp"`
+	Tools     cycloneDXTools     `json:"tools"`
+	Component cycloneDXComponent `json:"component"`
+}
+
+type cycloneDXTools struct {
+	Components []cycloneDXComponent `json:"components"`
+}
+
+type cycloneDXComponent struct {
+	Type               string                       `json:"type"`
+	BOMRef             string                       `json:"bom-ref,omitempty"`
+	Name               string                       `json:"name"`
+	Version            string                       `json:"version,omitempty"`
+	Hashes             []cycloneDXHash              `json:"hashes,omitempty"`
+	PURL               string                       `json:"purl,omitempty"`
+	ExternalReferences []cycloneDXExternalReference `json:"externalReferences,omitempty"`
+}
+
+type cycloneDXHash struct {
+	Algorithm string `json:"alg"`
+	Content   string `json:"content"`
+}
+
+type cycloneDXExternalReference struct {
+	Type    string `json:"type"`
+	URL     string `json:"url"`
+	Comment string `json:"comment,omitempty"`
+}
+
+// cycloneDXHashAlgorithm returns the CycloneDX name of a hash algorithm, such as SHA-256 for sha256.
+func cycloneDXHashAlgorithm(algorithm string) string {
+	algorithm = strings.ToUpper(strings.ReplaceAll(algorithm, "-", ""))
+	if strings.HasPrefix(algorithm, "SHA") {
+		return "SHA-" + strings.TrimPrefix(algorithm, "SHA")
+	}
+	return algorithm
+}
+
+// WriteCycloneDX writes the SBOM as a CycloneDX 1.5 JSON document.
+func (s *SBOM) WriteCycloneDX(w io.Writer) error {
+	doc := cycloneDXDocument{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: "urn:uuid:" + uuid.NewString(),
+		Version:      1,
+		Metadata: cycloneDXMetadata{
+			Timestamp: s.Timestamp.UTC().Format(time.RFC3339),
+			Tools: cycloneDXTools{
+				Components: []cycloneDXComponent{{Type: "application", Name: "kops", Version: s.ToolVersion}},
+			},
+			Component: cycloneDXComponent{Type: "platform", Name: s.ClusterName},
+		},
+		Components: []cycloneDXComponent{},
+	}
+
+	for i, c := range s.Components {
+		component := cycloneDXComponent{
+			BOMRef:  fmt.Sprintf("%s-%d", c.Kind, i),
+			Name:    c.Name,
+			Version: c.Version,
+			PURL:    c.purl(),
+		}
+		if c.Kind == "image" {
+			component.Type = "container"
+			if algorithm, hex := digestHex(c.Digest); hex != "" {
+				component.Hashes = append(component.Hashes, cycloneDXHash{Algorithm: cycloneDXHashAlgorithm(algorithm), Content: hex})
+			}
+		} else {
+			component.Type = "file"
+			if c.Hash != nil {
+				component.Hashes = append(component.Hashes, cycloneDXHash{Algorithm: cycloneDXHashAlgorithm(string(c.Hash.Algorithm)), Content: c.Hash.Hex()})
+			}
+			component.ExternalReferences = append(component.ExternalReferences, cycloneDXExternalReference{Type: "distribution", URL: c.Canonical})
+		}
+		if c.Download != c.Canonical {
+			component.ExternalReferences = append(component.ExternalReferences, cycloneDXExternalReference{Type: "distribution", URL: c.Download, Comment: "mirror"})
+		}
+		doc.Components = append(doc.Components, component)
+	}
+
+	return writeSBOMJSON(w, doc)
+}
+
+func writeSBOMJSON(w io.Writer, doc interface{}) error {
+	b, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error serializing SBOM: %w", err)
+	}
+	b = append(b, '\n')
+	if _, err := w.Write(b); err != nil {
+		return fmt.Errorf("error writing SBOM: %w", err)
+	}
+	return nil
+}
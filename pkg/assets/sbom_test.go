@@ -0,0 +1,159 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/kops/util/pkg/hashing"
+)
+
+const testSHA256 = "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+func buildTestSBOM(t *testing.T) *SBOM {
+	hash, err := hashing.FromString(testSHA256)
+	if err != nil {
+		t.Fatalf("error parsing hash: %v", err)
+	}
+	mustParse := func(s string) *url.URL {
+		u, err := url.Parse(s)
+		if err != nil {
+			t.Fatalf("error parsing url %q: %v", s, err)
+		}
+		return u
+	}
+
+	imageAssets := []*ImageAsset{
+		{CanonicalLocation: "registry.k8s.io/kube-proxy:v1.31.0", DownloadLocation: "mirror.example.com/kube-proxy:v1.31.0"},
+		{CanonicalLocation: "registry.k8s.io/kube-proxy:v1.31.0", DownloadLocation: "mirror.example.com/kube-proxy:v1.31.0"},
+	}
+	fileAssets := []*FileAsset{
+		{
+			CanonicalURL: mustParse("https://dl.k8s.io/release/v1.31.0/bin/linux/amd64/kubelet"),
+			DownloadURL:  mustParse("https://mirror.example.com/release/v1.31.0/bin/linux/amd64/kubelet"),
+			SHAValue:     hash,
+		},
+		{
+			CanonicalURL: mustParse("https://github.com/containerd/containerd/releases/download/v1.7.20/containerd-1.7.20-linux-amd64.tar.gz"),
+			DownloadURL:  mustParse("https://github.com/containerd/containerd/releases/download/v1.7.20/containerd-1.7.20-linux-amd64.tar.gz"),
+			SHAValue:     hash,
+		},
+	}
+	imageDigests := map[string]string{
+		"mirror.example.com/kube-proxy:v1.31.0": "sha256:" + testSHA256,
+	}
+
+	return &SBOM{
+		ClusterName: "minimal.example.com",
+		ToolVersion: "1.33.0",
+		Timestamp:   time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		Components:  BuildSBOMComponents(imageAssets, fileAssets, imageDigests),
+	}
+}
+
+func TestBuildSBOMComponents(t *testing.T) {
+	sbom := buildTestSBOM(t)
+
+	expected := []struct {
+		Kind    string
+		Name    string
+		Version string
+		Digest  string
+	}{
+		{Kind: "image", Name: "registry.k8s.io/kube-proxy", Version: "v1.31.0", Digest: "sha256:" + testSHA256},
+		{Kind: "file", Name: "kubelet", Version: "v1.31.0"},
+		{Kind: "file", Name: "containerd-1.7.20-linux-amd64.tar.gz", Version: "1.7.20"},
+	}
+	if len(sbom.Components) != len(expected) {
+		t.Fatalf("expected %d components, got %d", len(expected), len(sbom.Components))
+	}
+	for i, e := range expected {
+		c := sbom.Components[i]
+		if c.Kind != e.Kind || c.Name != e.Name || c.Version != e.Version || c.Digest != e.Digest {
+			t.Errorf("unexpected component %d: %+v, expected %+v", i, c, e)
+		}
+	}
+}
+
+func TestWriteSPDX(t *testing.T) {
+	var b bytes.Buffer
+	if err := buildTestSBOM(t).WriteSPDX(&b); err != nil {
+		t.Fatalf("error writing SPDX: %v", err)
+	}
+
+	var doc spdxDocument
+	if err := json.Unmarshal(b.Bytes(), &doc); err != nil {
+		t.Fatalf("error parsing SPDX: %v", err)
+	}
+	if doc.SPDXVersion != "SPDX-2.3" || doc.CreationInfo.Created != "2025-01-02T03:04:05Z" {
+		t.Errorf("unexpected document header: %+v", doc)
+	}
+	if len(doc.Packages) != 3 || len(doc.Relationships) != 3 {
+		t.Fatalf("expected 3 packages and relationships, got %d and %d", len(doc.Packages), len(doc.Relationships))
+	}
+
+	kubelet := doc.Packages[1]
+	if kubelet.DownloadLocation != "https://dl.k8s.io/release/v1.31.0/bin/linux/amd64/kubelet" {
+		t.Errorf("unexpected download location %q", kubelet.DownloadLocation)
+	}
+	if len(kubelet.Checksums) != 1 || kubelet.Checksums[0].Algorithm != "SHA256" || kubelet.Checksums[0].ChecksumValue != testSHA256 {
+		t.Errorf("unexpected checksums %+v", kubelet.Checksums)
+	}
+	if kubelet.SourceInfo != "mirrored at https://mirror.example.com/release/v1.31.0/bin/linux/amd64/kubelet" {
+		t.Errorf("unexpected source info %q", kubelet.SourceInfo)
+	}
+
+	image := doc.Packages[0]
+	if image.PrimaryPurpose != "CONTAINER" || len(image.ExternalRefs) != 1 || !strings.HasPrefix(image.ExternalRefs[0].ReferenceLocator, "pkg:oci/kube-proxy@sha256%3A"+testSHA256) {
+		t.Errorf("unexpected image package %+v", image)
+	}
+}
+
+func TestWriteCycloneDX(t *testing.T) {
+	var b bytes.Buffer
+	if err := buildTestSBOM(t).WriteCycloneDX(&b); err != nil {
+		t.Fatalf("error writing CycloneDX: %v", err)
+	}
+
+	var doc cycloneDXDocument
+	if err := json.Unmarshal(b.Bytes(), &doc); err != nil {
+		t.Fatalf("error parsing CycloneDX: %v", err)
+	}
+	if doc.BOMFormat != "CycloneDX" || doc.SpecVersion != "1.5" || doc.Metadata.Component.Name != "minimal.example.com" {
+		t.Errorf("unexpected document header: %+v", doc)
+	}
+	if len(doc.Components) != 3 {
+		t.Fatalf("expected 3 components, got %d", len(doc.Components))
+	}
+
+	containerd := doc.Components[2]
+	if containerd.Type != "file" || containerd.Version != "1.7.20" {
+		t.Errorf("unexpected component %+v", containerd)
+	}
+	if len(containerd.Hashes) != 1 || containerd.Hashes[0].Algorithm != "SHA-256" || containerd.Hashes[0].Content != testSHA256 {
+		t.Errorf("unexpected hashes %+v", containerd.Hashes)
+	}
+	// The file is not mirrored, so only its canonical location is listed
+	if len(containerd.ExternalReferences) != 1 {
+		t.Errorf("unexpected external references %+v", containerd.ExternalReferences)
+	}
+}
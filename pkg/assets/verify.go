@@ -0,0 +1,170 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// VerifyResult is the result of checking that a mirrored asset matches its canonical source.
+type VerifyResult struct {
+	// Kind is "file" or "image".
+	Kind string `json:"kind"`
+	// Canonical is the canonical location of the asset.
+	Canonical string `json:"canonical"`
+	// Download is the mirrored location of the asset, which was verified.
+	Download string `json:"download"`
+	// Expected is the hash of the file, or the digest of the canonical image.
+	Expected string `json:"expected,omitempty"`
+	// Actual is the hash or digest of the mirrored asset.
+	Actual string `json:"actual,omitempty"`
+	// Error is set if the asset could not be verified.
+	Error string `json:"error,omitempty"`
+}
+
+// Verified returns true if the mirrored asset matches its canonical source.
+func (r *VerifyResult) Verified() bool {
+	return r.Error == "" && r.Expected != "" && r.Actual == r.Expected
+}
+
+// Verify downloads every mirrored asset, and checks that the hash of files and the digest of images
+// match those of their canonical source. Assets that are not mirrored are not verified.
+func Verify(imageAssets []*ImageAsset, fileAssets []*FileAsset, vfsContext *vfs.VFSContext) []*VerifyResult {
+	var results []*VerifyResult
+	var checks []func()
+
+	seen := make(map[string]bool)
+	for _, imageAsset := range imageAssets {
+		if imageAsset.DownloadLocation == imageAsset.CanonicalLocation || seen[imageAsset.DownloadLocation] {
+			continue
+		}
+		seen[imageAsset.DownloadLocation] = true
+
+		result := &VerifyResult{
+			Kind:      "image",
+			Canonical: imageAsset.CanonicalLocation,
+			Download:  imageAsset.DownloadLocation,
+		}
+		results = append(results, result)
+		checks = append(checks, func() { verifyImage(result) })
+	}
+
+	seen = make(map[string]bool)
+	for _, fileAsset := range fileAssets {
+		download := fileAsset.DownloadURL.String()
+		if download == fileAsset.CanonicalURL.String() || seen[download] {
+			continue
+		}
+		seen[download] = true
+
+		result := &VerifyResult{
+			Kind:      "file",
+			Canonical: fileAsset.CanonicalURL.String(),
+			Download:  download,
+		}
+		results = append(results, result)
+		checks = append(checks, func() { verifyFile(result, fileAsset, vfsContext) })
+	}
+
+	// Limit the number of concurrent downloads, as Copy does
+	sem := make(chan struct{}, 5)
+	var wg sync.WaitGroup
+	for _, check := range checks {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(check func()) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			check()
+		}(check)
+	}
+	wg.Wait()
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Kind != results[j].Kind {
+			return results[i].Kind < results[j].Kind
+		}
+		return results[i].Download < results[j].Download
+	})
+	return results
+}
+
+func verifyFile(result *VerifyResult, fileAsset *FileAsset, vfsContext *vfs.VFSContext) {
+	if fileAsset.SHAValue == nil {
+		result.Error = "no hash is known for the canonical file"
+		return
+	}
+	result.Expected = fileAsset.SHAValue.Hex()
+
+	klog.V(2).Infof("verifying %q", result.Download)
+	data, err := vfsContext.ReadFile(result.Download)
+	if err != nil {
+		result.Error = fmt.Sprintf("error downloading file: %v", err)
+		return
+	}
+	actual, err := fileAsset.SHAValue.Algorithm.Hash(bytes.NewReader(data))
+	if err != nil {
+		result.Error = fmt.Sprintf("error hashing file: %v", err)
+		return
+	}
+	result.Actual = actual.Hex()
+	if result.Actual != result.Expected {
+		result.Error = "hash does not match the canonical file"
+	}
+}
+
+func verifyImage(result *VerifyResult) {
+	expected, err := ImageDigest(result.Canonical)
+	if err != nil {
+		result.Error = err.Error()
+		return
+	}
+	result.Expected = expected
+
+	klog.V(2).Infof("verifying %q", result.Download)
+	actual, err := ImageDigest(result.Download)
+	if err != nil {
+		result.Error = err.Error()
+		return
+	}
+	result.Actual = actual
+	if result.Actual != result.Expected {
+		result.Error = "digest does not match the canonical image"
+	}
+}
+
+// ImageDigest returns the digest of the manifest, or of the index for multi-architecture images, of an image in a registry.
+func ImageDigest(image string) (string, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return "", fmt.Errorf("parsing reference %q: %w", image, err)
+	}
+	desc, err := remote.Get(ref, remote.WithAuthFromKeychain(authn.DefaultKeychain))
+	if err != nil {
+		return "", fmt.Errorf("fetching %q: %w", image, err)
+	}
+	return desc.Digest.String(), nil
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metalbootstrap
+
+import (
+	"fmt"
+	"os"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/bootstrap"
+	"k8s.io/kops/pkg/bootstrap/pkibootstrap"
+	"k8s.io/kops/pkg/bootstrap/tokenbootstrap"
+	"k8s.io/kops/pkg/bootstrap/tpmbootstrap"
+)
+
+const (
+	// PrivateKeyPath is the private key created when a machine is enrolled over SSH.
+	PrivateKeyPath = "/etc/kubernetes/kops/pki/machine/private.pem"
+	// JoinTokenPath is the pre-shared join token of the machine.
+	JoinTokenPath = "/etc/kubernetes/kops/pki/machine/join-token"
+)
+
+// NewAuthenticator returns the authenticator for a bare-metal machine, based on how the machine was provisioned:
+// the private key from an SSH enrollment, a pre-shared join token, or else the TPM of the machine.
+func NewAuthenticator() (bootstrap.Authenticator, error) {
+	if _, err := os.Stat(PrivateKeyPath); err == nil {
+		return pkibootstrap.NewAuthenticatorFromFile(PrivateKeyPath)
+	}
+	if _, err := os.Stat(JoinTokenPath); err == nil {
+		klog.Infof("authenticating with join token from %s", JoinTokenPath)
+		return tokenbootstrap.NewAuthenticatorFromFile(JoinTokenPath)
+	}
+	if _, err := os.Stat(tpmbootstrap.DevicePath); err == nil {
+		klog.Infof("authenticating with TPM attestation using %s", tpmbootstrap.DevicePath)
+		return tpmbootstrap.NewAuthenticatorFromDevice(tpmbootstrap.DevicePath)
+	}
+	return nil, fmt.Errorf("no machine identity found: expected %s, %s or a TPM at %s", PrivateKeyPath, JoinTokenPath, tpmbootstrap.DevicePath)
+}
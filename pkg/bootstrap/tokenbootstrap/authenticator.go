@@ -0,0 +1,99 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tokenbootstrap
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"k8s.io/kops/pkg/bootstrap"
+)
+
+type tokenAuthenticator struct {
+	hostname    string
+	tokenID     string
+	tokenSecret string
+}
+
+var _ bootstrap.Authenticator = &tokenAuthenticator{}
+
+// NewAuthenticator returns an authenticator that signs requests with the join token.
+func NewAuthenticator(hostname string, joinToken string) (bootstrap.Authenticator, error) {
+	tokenID, tokenSecret, err := ParseJoinToken(joinToken)
+	if err != nil {
+		return nil, err
+	}
+	return &tokenAuthenticator{hostname: hostname, tokenID: tokenID, tokenSecret: tokenSecret}, nil
+}
+
+// NewAuthenticatorFromFile returns an authenticator that signs requests with the join token in the file p.
+func NewAuthenticatorFromFile(p string) (bootstrap.Authenticator, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, fmt.Errorf("couldn't determine hostname: %w", err)
+	}
+
+	b, err := os.ReadFile(p)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %q: %w", p, err)
+	}
+	a, err := NewAuthenticator(hostname, string(b))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing join token from %q: %w", p, err)
+	}
+	return a, nil
+}
+
+func (a *tokenAuthenticator) CreateToken(body []byte) (string, error) {
+	requestHash := sha256.Sum256(body)
+
+	data := AuthTokenData{
+		Timestamp:   time.Now().Unix(),
+		Audience:    AudienceNodeAuthentication,
+		RequestHash: requestHash[:],
+
+		TokenID:  a.tokenID,
+		Instance: a.hostname,
+	}
+
+	payload, err := json.Marshal(&data)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal token data: %w", err)
+	}
+
+	token := &AuthToken{
+		Data:      payload,
+		Signature: sign(a.tokenSecret, payload),
+	}
+
+	b, err := json.Marshal(token)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal token: %w", err)
+	}
+	return AuthenticationTokenPrefix + base64.StdEncoding.EncodeToString(b), nil
+}
+
+func sign(tokenSecret string, payload []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(tokenSecret))
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tokenbootstrap
+
+// Options describes how we authenticate instances with pre-shared join tokens.
+type Options struct {
+	// MaxTimeSkew is the maximum time skew to allow (in seconds)
+	MaxTimeSkew int64 `json:"MaxTimeSkew,omitempty"`
+}
+
+// AuthenticationTokenPrefix is the prefix used for authentication using join tokens
+const AuthenticationTokenPrefix = "x-join-token "
+
+const (
+	// SecretNamespace is the namespace of the secrets holding the join tokens.
+	SecretNamespace = "kops-system"
+	// SecretNamePrefix is the prefix of the name of a join token secret, followed by the token id.
+	SecretNamePrefix = "join-token-"
+	// SecretType is the type of join token secrets.
+	SecretType = "kops.k8s.io/join-token"
+
+	// SecretTokenSecretKey is the key of the secret part of the token.
+	SecretTokenSecretKey = "token-secret"
+	// SecretInstanceGroupKey is the key of the instance group that machines joining with the token are members of.
+	SecretInstanceGroupKey = "instance-group"
+	// SecretExpirationKey is the key of the optional RFC3339 time after which the token is no longer valid.
+	SecretExpirationKey = "expiration"
+)
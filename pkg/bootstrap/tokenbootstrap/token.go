@@ -0,0 +1,70 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tokenbootstrap
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// AuthToken describes the authentication header data when using join tokens.
+type AuthToken struct {
+	// Signature is the HMAC-SHA256 of Data, keyed with the secret part of the join token.
+	// The secret itself is never sent.
+	Signature []byte `json:"signature,omitempty"`
+
+	// Data is the data we are signing.
+	// It is a JSON encoded form of AuthTokenData.
+	Data []byte `json:"data,omitempty"`
+}
+
+// AuthTokenData is the data that is signed as part of the header.
+type AuthTokenData struct {
+	// Instance is the name of the node we are claiming
+	Instance string `json:"instance,omitempty"`
+
+	// TokenID is the id of the join token we are signing with.
+	TokenID string `json:"tokenID,omitempty"`
+
+	// RequestHash is the hash of the request
+	RequestHash []byte `json:"requestHash,omitempty"`
+
+	// Timestamp is the time of this request (to help prevent replay attacks)
+	Timestamp int64 `json:"timestamp,omitempty"`
+
+	// Audience is the audience for this request (to help prevent replay attacks)
+	Audience string `json:"audience,omitempty"`
+}
+
+// AudienceNodeAuthentication is used in case we have multiple audiences using join tokens in future
+const AudienceNodeAuthentication = "kops.k8s.io/node-bootstrap"
+
+// joinTokenRegexp matches a join token, in the same format as a Kubernetes bootstrap token.
+var joinTokenRegexp = regexp.MustCompile(`^([a-z0-9]{6})\.([a-z0-9]{16})$`)
+
+// tokenIDRegexp matches the id of a join token.
+var tokenIDRegexp = regexp.MustCompile(`^[a-z0-9]{6}$`)
+
+// ParseJoinToken splits a join token of the form "<id>.<secret>" into its id and secret.
+func ParseJoinToken(s string) (string, string, error) {
+	match := joinTokenRegexp.FindStringSubmatch(strings.TrimSpace(s))
+	if match == nil {
+		return "", "", fmt.Errorf("join token must be of the form [a-z0-9]{6}.[a-z0-9]{16}")
+	}
+	return match[1], match[2], nil
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tokenbootstrap
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	kops "k8s.io/kops/pkg/apis/kops/v1alpha2"
+	"k8s.io/kops/pkg/bootstrap"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func buildSecret(tokenID, tokenSecret string, expiration string) *corev1.Secret {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: SecretNamespace, Name: SecretNamePrefix + tokenID},
+		Type:       SecretType,
+		Data: map[string][]byte{
+			SecretTokenSecretKey:   []byte(tokenSecret),
+			SecretInstanceGroupKey: []byte("nodes"),
+		},
+	}
+	if expiration != "" {
+		secret.Data[SecretExpirationKey] = []byte(expiration)
+	}
+	return secret
+}
+
+func buildVerifier(t *testing.T, objects ...client.Object) bootstrap.Verifier {
+	s := runtime.NewScheme()
+	if err := scheme.AddToScheme(s); err != nil {
+		t.Fatalf("building scheme: %v", err)
+	}
+	if err := kops.AddToScheme(s); err != nil {
+		t.Fatalf("building scheme: %v", err)
+	}
+	verifier, err := NewVerifier(&Options{}, fake.NewClientBuilder().WithScheme(s).WithObjects(objects...).Build())
+	if err != nil {
+		t.Fatalf("building verifier: %v", err)
+	}
+	return verifier
+}
+
+func TestVerifyToken(t *testing.T) {
+	ctx := context.Background()
+	body := []byte(`{"certs":{}}`)
+
+	authenticator, err := NewAuthenticator("machine1", "abcdef.0123456789abcdef\n")
+	if err != nil {
+		t.Fatalf("building authenticator: %v", err)
+	}
+	token, err := authenticator.CreateToken(body)
+	if err != nil {
+		t.Fatalf("creating token: %v", err)
+	}
+	if strings.Contains(token, "0123456789abcdef") {
+		t.Fatalf("token contains the join token secret")
+	}
+
+	t.Run("allowed", func(t *testing.T) {
+		verifier := buildVerifier(t, buildSecret("abcdef", "0123456789abcdef", time.Now().Add(time.Hour).Format(time.RFC3339)))
+		result, err := verifier.VerifyToken(ctx, nil, token, body)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.NodeName != "machine1" || result.InstanceGroupName != "nodes" {
+			t.Errorf("unexpected result %+v", result)
+		}
+	})
+
+	t.Run("node exists", func(t *testing.T) {
+		verifier := buildVerifier(t,
+			buildSecret("abcdef", "0123456789abcdef", ""),
+			&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "machine1"}})
+		if _, err := verifier.VerifyToken(ctx, nil, token, body); err != bootstrap.ErrAlreadyExists {
+			t.Errorf("expected ErrAlreadyExists, got %v", err)
+		}
+	})
+
+	opaque := buildSecret("abcdef", "0123456789abcdef", "")
+	opaque.Type = corev1.SecretTypeOpaque
+
+	grid := []struct {
+		name     string
+		objects  []client.Object
+		token    string
+		body     []byte
+		expected string
+	}{
+		{
+			name:     "no secret",
+			expected: "join token not found",
+		},
+		{
+			name:     "wrong secret",
+			objects:  []client.Object{buildSecret("abcdef", "fedcba9876543210", "")},
+			expected: "failed to verify signature",
+		},
+		{
+			name:     "expired",
+			objects:  []client.Object{buildSecret("abcdef", "0123456789abcdef", time.Now().Add(-time.Hour).Format(time.RFC3339))},
+			expected: "expired",
+		},
+		{
+			name:     "wrong secret type",
+			objects:  []client.Object{opaque},
+			expected: "is not of type",
+		},
+		{
+			name: "enrolled host",
+			objects: []client.Object{
+				buildSecret("abcdef", "0123456789abcdef", ""),
+				&kops.Host{ObjectMeta: metav1.ObjectMeta{Namespace: SecretNamespace, Name: "machine1"}},
+			},
+			expected: "belongs to an enrolled host",
+		},
+		{
+			name:     "different body",
+			objects:  []client.Object{buildSecret("abcdef", "0123456789abcdef", "")},
+			body:     []byte(`{"certs":{"kubelet":""}}`),
+			expected: "incorrect RequestHash",
+		},
+		{
+			name:     "other verifier",
+			token:    "x-pki-tpm abc",
+			expected: bootstrap.ErrNotThisVerifier.Error(),
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			verifier := buildVerifier(t, g.objects...)
+			requestToken := token
+			if g.token != "" {
+				requestToken = g.token
+			}
+			requestBody := body
+			if g.body != nil {
+				requestBody = g.body
+			}
+			_, err := verifier.VerifyToken(ctx, nil, requestToken, requestBody)
+			if err == nil {
+				t.Fatalf("expected error %q", g.expected)
+			}
+			if !strings.Contains(err.Error(), g.expected) {
+				t.Errorf("expected error %q, got %q", g.expected, err)
+			}
+		})
+	}
+}
+
+func TestParseJoinToken(t *testing.T) {
+	for _, s := range []string{"", "abcdef", "abcdef.short", "ABCDEF.0123456789abcdef", "abcdef.0123456789abcdef.x"} {
+		if _, _, err := ParseJoinToken(s); err == nil {
+			t.Errorf("expected error parsing %q", s)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tokenbootstrap
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	kops "k8s.io/kops/pkg/apis/kops/v1alpha2"
+	"k8s.io/kops/pkg/bootstrap"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type verifier struct {
+	opt    Options
+	client client.Client
+}
+
+// NewVerifier constructs a new verifier.
+// Join tokens are read from secrets in the kops-system namespace, so the client should not be cached,
+// to avoid watching every secret.
+func NewVerifier(options *Options, client client.Client) (bootstrap.Verifier, error) {
+	opt := *options
+	if opt.MaxTimeSkew == 0 {
+		opt.MaxTimeSkew = 300
+	}
+	return &verifier{
+		opt:    opt,
+		client: client,
+	}, nil
+}
+
+var _ bootstrap.Verifier = &verifier{}
+
+func (v *verifier) VerifyToken(ctx context.Context, rawRequest *http.Request, authToken string, body []byte) (*bootstrap.VerifyResult, error) {
+	if !strings.HasPrefix(authToken, AuthenticationTokenPrefix) {
+		return nil, bootstrap.ErrNotThisVerifier
+	}
+
+	token, tokenData, err := v.parseTokenData(strings.TrimPrefix(authToken, AuthenticationTokenPrefix), body)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := v.getSecret(ctx, tokenData.TokenID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !hmac.Equal(sign(string(secret.Data[SecretTokenSecretKey]), token.Data), token.Signature) {
+		return nil, fmt.Errorf("failed to verify signature with join token %q", tokenData.TokenID)
+	}
+
+	if s := string(secret.Data[SecretExpirationKey]); s != "" {
+		expiration, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return nil, fmt.Errorf("parsing expiration of join token %q: %w", tokenData.TokenID, err)
+		}
+		if time.Now().After(expiration) {
+			return nil, fmt.Errorf("join token %q expired at %v", tokenData.TokenID, expiration)
+		}
+	}
+
+	instanceGroup := string(secret.Data[SecretInstanceGroupKey])
+	if instanceGroup == "" {
+		return nil, fmt.Errorf("join token %q did not have %s", tokenData.TokenID, SecretInstanceGroupKey)
+	}
+
+	// A join token is shared by many machines, so it must not be usable to claim the name of an existing node,
+	// or of a machine that was enrolled with its own key.
+	nodeName := tokenData.Instance
+	if errs := validation.IsDNS1123Subdomain(nodeName); len(errs) != 0 {
+		return nil, fmt.Errorf("invalid node name %q: %s", nodeName, strings.Join(errs, ", "))
+	}
+	if err := v.client.Get(ctx, types.NamespacedName{Name: nodeName}, &corev1.Node{}); err == nil {
+		return nil, bootstrap.ErrAlreadyExists
+	} else if !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("error getting node %q: %w", nodeName, err)
+	}
+	if err := v.client.Get(ctx, types.NamespacedName{Namespace: SecretNamespace, Name: nodeName}, &kops.Host{}); err == nil {
+		return nil, fmt.Errorf("node name %q belongs to an enrolled host", nodeName)
+	} else if !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("error getting host %q: %w", nodeName, err)
+	}
+
+	result := &bootstrap.VerifyResult{
+		NodeName:          nodeName,
+		InstanceGroupName: instanceGroup,
+	}
+
+	return result, nil
+}
+
+func (v *verifier) parseTokenData(authToken string, body []byte) (*AuthToken, *AuthTokenData, error) {
+	tokenBytes, err := base64.StdEncoding.DecodeString(authToken)
+	if err != nil {
+		return nil, nil, fmt.Errorf("decoding authorization token: %w", err)
+	}
+
+	token := &AuthToken{}
+	if err = json.Unmarshal(tokenBytes, token); err != nil {
+		return nil, nil, fmt.Errorf("unmarshalling authorization token: %w", err)
+	}
+
+	tokenData := &AuthTokenData{}
+	if err := json.Unmarshal(token.Data, tokenData); err != nil {
+		return nil, nil, fmt.Errorf("unmarshalling authorization token data: %w", err)
+	}
+
+	// Guard against replay attacks
+	if tokenData.Audience != AudienceNodeAuthentication {
+		return nil, nil, fmt.Errorf("incorrect Audience")
+	}
+	timeSkew := math.Abs(time.Since(time.Unix(tokenData.Timestamp, 0)).Seconds())
+	if timeSkew > float64(v.opt.MaxTimeSkew) {
+		return nil, nil, fmt.Errorf("incorrect Timestamp %v", tokenData.Timestamp)
+	}
+
+	// Verify the token has signed the body content.
+	requestHash := sha256.Sum256(body)
+	if !bytes.Equal(requestHash[:], tokenData.RequestHash) {
+		return nil, nil, fmt.Errorf("incorrect RequestHash")
+	}
+
+	return token, tokenData, nil
+}
+
+func (v *verifier) getSecret(ctx context.Context, tokenID string) (*corev1.Secret, error) {
+	if !tokenIDRegexp.MatchString(tokenID) {
+		return nil, fmt.Errorf("invalid join token id %q", tokenID)
+	}
+
+	id := types.NamespacedName{
+		Namespace: SecretNamespace,
+		Name:      SecretNamePrefix + tokenID,
+	}
+	secret := &corev1.Secret{}
+	if err := v.client.Get(ctx, id, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("join token not found for %v", id)
+		}
+		return nil, fmt.Errorf("error getting join token %v: %w", id, err)
+	}
+	if secret.Type != SecretType {
+		return nil, fmt.Errorf("secret %v is not of type %s", id, SecretType)
+	}
+	if len(secret.Data[SecretTokenSecretKey]) == 0 {
+		return nil, fmt.Errorf("secret %v did not have %s", id, SecretTokenSecretKey)
+	}
+	return secret, nil
+}
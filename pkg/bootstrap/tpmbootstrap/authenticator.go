@@ -0,0 +1,137 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tpmbootstrap
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/google/go-tpm/tpm2"
+	"github.com/google/go-tpm/tpm2/transport"
+	"github.com/google/go-tpm/tpm2/transport/linuxtpm"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/bootstrap"
+)
+
+// DevicePath is the path of the TPM device, through the kernel resource manager.
+const DevicePath = "/dev/tpmrm0"
+
+type tpmAuthenticator struct {
+	hostname string
+	openTPM  func() (transport.TPMCloser, error)
+}
+
+var _ bootstrap.Authenticator = &tpmAuthenticator{}
+
+// NewAuthenticator returns an authenticator that attests requests with the TPM returned by openTPM.
+func NewAuthenticator(hostname string, openTPM func() (transport.TPMCloser, error)) (bootstrap.Authenticator, error) {
+	return &tpmAuthenticator{hostname: hostname, openTPM: openTPM}, nil
+}
+
+// NewAuthenticatorFromDevice returns an authenticator that attests requests with the TPM device at path p.
+func NewAuthenticatorFromDevice(p string) (bootstrap.Authenticator, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, fmt.Errorf("couldn't determine hostname: %w", err)
+	}
+
+	return NewAuthenticator(hostname, func() (transport.TPMCloser, error) {
+		tpm, err := linuxtpm.Open(p)
+		if err != nil {
+			return nil, fmt.Errorf("opening TPM %q: %w", p, err)
+		}
+		return tpm, nil
+	})
+}
+
+func (a *tpmAuthenticator) CreateToken(body []byte) (string, error) {
+	requestHash := sha256.Sum256(body)
+
+	tpmStart := time.Now()
+
+	tpm, err := a.openTPM()
+	if err != nil {
+		return "", fmt.Errorf("failed to open TPM: %w", err)
+	}
+	defer tpm.Close()
+
+	ak, err := createAttestationKey(tpm)
+	if err != nil {
+		return "", err
+	}
+	defer ak.flush(tpm)
+
+	klog.Infof("TPM initialization took %v", time.Since(tpmStart))
+
+	data := AuthTokenData{
+		Timestamp:   time.Now().Unix(),
+		Audience:    AudienceNodeAuthentication,
+		RequestHash: requestHash[:],
+
+		Instance:       a.hostname,
+		AttestationKey: tpm2.Marshal(ak.public),
+	}
+
+	payload, err := json.Marshal(&data)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal token data: %w", err)
+	}
+
+	token, err := quote(tpm, ak, payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to attest token data: %w", err)
+	}
+
+	b, err := json.Marshal(token)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal token: %w", err)
+	}
+	return AuthenticationTokenPrefix + base64.StdEncoding.EncodeToString(b), nil
+}
+
+// quote has the TPM attest the hash of the payload, signed with the attestation key.
+// A restricted key only signs data that the TPM generated, so the signature is over a TPMS_ATTEST structure.
+func quote(tpm transport.TPM, ak *attestationKey, payload []byte) (*AuthToken, error) {
+	beforeSign := time.Now()
+
+	digest := sha256.Sum256(payload)
+
+	rsp, err := tpm2.Quote{
+		SignHandle: tpm2.AuthHandle{
+			Handle: ak.handle.Handle,
+			Name:   ak.handle.Name,
+			Auth:   tpm2.PasswordAuth(nil),
+		},
+		QualifyingData: tpm2.TPM2BData{Buffer: digest[:]},
+		InScheme:       tpm2.TPMTSigScheme{Scheme: tpm2.TPMAlgNull},
+	}.Execute(tpm)
+	if err != nil {
+		return nil, fmt.Errorf("TPM2_Quote failed: %w", err)
+	}
+
+	klog.Infof("TPM signing took %v", time.Since(beforeSign))
+
+	return &AuthToken{
+		Data:        payload,
+		Attestation: rsp.Quoted.Bytes(),
+		Signature:   tpm2.Marshal(rsp.Signature),
+	}, nil
+}
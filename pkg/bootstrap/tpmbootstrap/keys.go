@@ -0,0 +1,142 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tpmbootstrap
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/google/go-tpm/tpm2"
+	"github.com/google/go-tpm/tpm2/transport"
+	"k8s.io/klog/v2"
+)
+
+// attestationKeyTemplate is the template of the attestation key.
+// The attestation key is a primary key of the endorsement hierarchy, so the TPM recreates the same key from the same template
+// until the TPM is cleared. It is a restricted signing key that cannot leave the TPM.
+// The key is not certified by the endorsement key: it is trusted because it was recorded from the machine when it was enrolled.
+var attestationKeyTemplate = tpm2.TPMTPublic{
+	Type:    tpm2.TPMAlgECC,
+	NameAlg: tpm2.TPMAlgSHA256,
+	ObjectAttributes: tpm2.TPMAObject{
+		FixedTPM:            true,
+		FixedParent:         true,
+		SensitiveDataOrigin: true,
+		UserWithAuth:        true,
+		NoDA:                true,
+		Restricted:          true,
+		SignEncrypt:         true,
+	},
+	Parameters: tpm2.NewTPMUPublicParms(
+		tpm2.TPMAlgECC,
+		&tpm2.TPMSECCParms{
+			Scheme: tpm2.TPMTECCScheme{
+				Scheme: tpm2.TPMAlgECDSA,
+				Details: tpm2.NewTPMUAsymScheme(
+					tpm2.TPMAlgECDSA,
+					&tpm2.TPMSSigSchemeECDSA{HashAlg: tpm2.TPMAlgSHA256},
+				),
+			},
+			CurveID: tpm2.TPMECCNistP256,
+		},
+	),
+	Unique: tpm2.NewTPMUPublicID(
+		tpm2.TPMAlgECC,
+		&tpm2.TPMSECCPoint{
+			X: tpm2.TPM2BECCParameter{Buffer: make([]byte, 32)},
+			Y: tpm2.TPM2BECCParameter{Buffer: make([]byte, 32)},
+		},
+	),
+}
+
+// Identity is the public key that identifies the TPM of a machine.
+type Identity struct {
+	// AttestationKey is the PEM-encoded public key of the attestation key.
+	AttestationKey string
+}
+
+// ReadIdentity returns the public key that identifies the TPM, to be recorded in the Host of the machine.
+func ReadIdentity(tpm transport.TPM) (*Identity, error) {
+	ak, err := createAttestationKey(tpm)
+	if err != nil {
+		return nil, err
+	}
+	defer ak.flush(tpm)
+
+	akPublicKey, err := tpm2.Pub(ak.public)
+	if err != nil {
+		return nil, fmt.Errorf("decoding attestation key: %w", err)
+	}
+
+	identity := &Identity{}
+	if identity.AttestationKey, err = encodePublicKey(akPublicKey); err != nil {
+		return nil, err
+	}
+	return identity, nil
+}
+
+// attestationKey is an attestation key loaded in the TPM.
+type attestationKey struct {
+	handle tpm2.NamedHandle
+	public tpm2.TPMTPublic
+}
+
+func createAttestationKey(tpm transport.TPM) (*attestationKey, error) {
+	rsp, err := tpm2.CreatePrimary{
+		PrimaryHandle: tpm2.TPMRHEndorsement,
+		InPublic:      tpm2.New2B(attestationKeyTemplate),
+	}.Execute(tpm)
+	if err != nil {
+		return nil, fmt.Errorf("creating attestation key: %w", err)
+	}
+	ak := &attestationKey{
+		handle: tpm2.NamedHandle{Handle: rsp.ObjectHandle, Name: rsp.Name},
+	}
+	public, err := rsp.OutPublic.Contents()
+	if err != nil {
+		ak.flush(tpm)
+		return nil, fmt.Errorf("decoding attestation key: %w", err)
+	}
+	ak.public = *public
+	return ak, nil
+}
+
+func (k *attestationKey) flush(tpm transport.TPM) {
+	flushContext(tpm, k.handle.Handle)
+}
+
+func flushContext(tpm transport.TPM, handle tpm2.TPMHandle) {
+	if _, err := (tpm2.FlushContext{FlushHandle: handle}).Execute(tpm); err != nil {
+		klog.Warningf("failed to flush TPM handle %v: %v", handle, err)
+	}
+}
+
+func encodePublicKey(publicKey crypto.PublicKey) (string, error) {
+	pkData, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return "", fmt.Errorf("error converting public key to x509: %w", err)
+	}
+
+	var b bytes.Buffer
+	if err := pem.Encode(&b, &pem.Block{Type: "PUBLIC KEY", Bytes: pkData}); err != nil {
+		return "", fmt.Errorf("error encoding public key: %w", err)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,90 @@
+//go:build tpm_simulator
+
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tpmbootstrap
+
+// These tests run the authenticator against the reference TPM simulator, which is built with cgo
+// from C sources that are not vendored, so they must be run in module mode:
+//
+//	go test -mod=mod -tags tpm_simulator ./pkg/bootstrap/tpmbootstrap/
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-tpm-tools/simulator"
+	"github.com/google/go-tpm/tpm2/transport"
+)
+
+// openSimulator starts a software TPM with a fixed seed, so that it has the same keys as other simulators with that seed.
+// Only one simulator can run at a time.
+func openSimulator(t *testing.T, seed int64) transport.TPMCloser {
+	sim, err := simulator.GetWithFixedSeedInsecure(seed)
+	if err != nil {
+		t.Fatalf("opening TPM simulator: %v", err)
+	}
+	return transport.FromReadWriteCloser(sim)
+}
+
+func readIdentity(t *testing.T, seed int64) *Identity {
+	tpm := openSimulator(t, seed)
+	defer tpm.Close()
+
+	identity, err := ReadIdentity(tpm)
+	if err != nil {
+		t.Fatalf("reading TPM identity: %v", err)
+	}
+	return identity
+}
+
+func TestSimulatedTPM(t *testing.T) {
+	ctx := context.Background()
+
+	identity := readIdentity(t, 1)
+	otherIdentity := readIdentity(t, 2)
+
+	body := []byte(`{"certs":{}}`)
+
+	authenticator, err := NewAuthenticator("machine1", func() (transport.TPMCloser, error) {
+		return openSimulator(t, 1), nil
+	})
+	if err != nil {
+		t.Fatalf("building authenticator: %v", err)
+	}
+	token, err := authenticator.CreateToken(body)
+	if err != nil {
+		t.Fatalf("creating token: %v", err)
+	}
+
+	// The identity is stable, so it can be recorded before the machine joins
+	if again := readIdentity(t, 1); *again != *identity {
+		t.Fatalf("TPM identity changed: %+v != %+v", again, identity)
+	}
+
+	result, err := buildVerifier(t, buildHost("machine1", identity)).VerifyToken(ctx, nil, token, body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.NodeName != "machine1" || result.InstanceGroupName != "nodes" {
+		t.Errorf("unexpected result %+v", result)
+	}
+
+	if _, err := buildVerifier(t, buildHost("machine1", otherIdentity)).VerifyToken(ctx, nil, token, body); err == nil {
+		t.Errorf("expected token to be rejected for a different TPM")
+	}
+}
@@ -0,0 +1,311 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tpmbootstrap
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-tpm/tpm2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kops "k8s.io/kops/pkg/apis/kops/v1alpha2"
+	"k8s.io/kops/pkg/bootstrap"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// softwareTPM produces the attestations a TPM would, with keys held in memory.
+// It lets us test the verifier without a TPM; tpm_simulator_test.go runs the authenticator against a simulated TPM.
+type softwareTPM struct {
+	attestationKey *ecdsa.PrivateKey
+}
+
+func newSoftwareTPM(t *testing.T) *softwareTPM {
+	ak, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generating attestation key: %v", err)
+	}
+	return &softwareTPM{attestationKey: ak}
+}
+
+func (s *softwareTPM) identity(t *testing.T) *Identity {
+	identity := &Identity{}
+	var err error
+	if identity.AttestationKey, err = encodePublicKey(&s.attestationKey.PublicKey); err != nil {
+		t.Fatal(err)
+	}
+	return identity
+}
+
+// attestationKeyPublic returns the TPMT_PUBLIC the TPM would report for the attestation key.
+func (s *softwareTPM) attestationKeyPublic(t *testing.T) tpm2.TPMTPublic {
+	ecdhKey, err := s.attestationKey.PublicKey.ECDH()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The uncompressed point is 0x04 || X || Y
+	point := ecdhKey.Bytes()
+
+	public := attestationKeyTemplate
+	public.Unique = tpm2.NewTPMUPublicID(
+		tpm2.TPMAlgECC,
+		&tpm2.TPMSECCPoint{
+			X: tpm2.TPM2BECCParameter{Buffer: point[1:33]},
+			Y: tpm2.TPM2BECCParameter{Buffer: point[33:]},
+		},
+	)
+	return public
+}
+
+// createToken builds a token like the authenticator does; modify can change the token data and the attestation before signing.
+func (s *softwareTPM) createToken(t *testing.T, hostname string, body []byte, modify func(data *AuthTokenData, attest *tpm2.TPMSAttest)) string {
+	requestHash := sha256.Sum256(body)
+	data := AuthTokenData{
+		Timestamp:   time.Now().Unix(),
+		Audience:    AudienceNodeAuthentication,
+		RequestHash: requestHash[:],
+
+		Instance:       hostname,
+		AttestationKey: tpm2.Marshal(s.attestationKeyPublic(t)),
+	}
+	attest := tpm2.TPMSAttest{
+		Magic:    tpm2.TPMGeneratedValue,
+		Type:     tpm2.TPMSTAttestQuote,
+		Attested: tpm2.NewTPMUAttest(tpm2.TPMSTAttestQuote, &tpm2.TPMSQuoteInfo{}),
+	}
+	if modify != nil {
+		modify(&data, &attest)
+	}
+
+	payload, err := json.Marshal(&data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attest.ExtraData.Buffer == nil {
+		digest := sha256.Sum256(payload)
+		attest.ExtraData = tpm2.TPM2BData{Buffer: digest[:]}
+	}
+	attestation := tpm2.Marshal(attest)
+
+	attestationHash := sha256.Sum256(attestation)
+	r, sig, err := ecdsa.Sign(rand.Reader, s.attestationKey, attestationHash[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature := tpm2.TPMTSignature{
+		SigAlg: tpm2.TPMAlgECDSA,
+		Signature: tpm2.NewTPMUSignature(
+			tpm2.TPMAlgECDSA,
+			&tpm2.TPMSSignatureECC{
+				Hash:       tpm2.TPMAlgSHA256,
+				SignatureR: tpm2.TPM2BECCParameter{Buffer: r.Bytes()},
+				SignatureS: tpm2.TPM2BECCParameter{Buffer: sig.Bytes()},
+			},
+		),
+	}
+
+	b, err := json.Marshal(&AuthToken{
+		Data:        payload,
+		Attestation: attestation,
+		Signature:   tpm2.Marshal(signature),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return AuthenticationTokenPrefix + base64.StdEncoding.EncodeToString(b)
+}
+
+func buildVerifier(t *testing.T, hosts ...*kops.Host) bootstrap.Verifier {
+	scheme := runtime.NewScheme()
+	if err := kops.AddToScheme(scheme); err != nil {
+		t.Fatalf("building scheme: %v", err)
+	}
+	builder := fake.NewClientBuilder().WithScheme(scheme)
+	for _, host := range hosts {
+		builder = builder.WithObjects(host)
+	}
+	verifier, err := NewVerifier(&Options{}, builder.Build())
+	if err != nil {
+		t.Fatalf("building verifier: %v", err)
+	}
+	return verifier
+}
+
+func buildHost(name string, identity *Identity) *kops.Host {
+	return &kops.Host{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kops-system", Name: name},
+		Spec: kops.HostSpec{
+			InstanceGroup: "nodes",
+			TPM: &kops.HostTPMSpec{
+				AttestationKey: identity.AttestationKey,
+			},
+		},
+	}
+}
+
+func TestVerifyToken(t *testing.T) {
+	ctx := context.Background()
+
+	tpm := newSoftwareTPM(t)
+	identity := tpm.identity(t)
+	otherIdentity := newSoftwareTPM(t).identity(t)
+
+	body := []byte(`{"certs":{}}`)
+	token := tpm.createToken(t, "machine1", body, nil)
+
+	t.Run("allowed", func(t *testing.T) {
+		verifier := buildVerifier(t, buildHost("machine1", identity))
+		result, err := verifier.VerifyToken(ctx, nil, token, body)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.NodeName != "machine1" || result.InstanceGroupName != "nodes" {
+			t.Errorf("unexpected result %+v", result)
+		}
+	})
+
+	grid := []struct {
+		name     string
+		hosts    []*kops.Host
+		token    string
+		body     []byte
+		expected string
+	}{
+		{
+			name:     "no host",
+			expected: "host not found",
+		},
+		{
+			name:     "host without TPM",
+			hosts:    []*kops.Host{{ObjectMeta: metav1.ObjectMeta{Namespace: "kops-system", Name: "machine1"}, Spec: kops.HostSpec{InstanceGroup: "nodes"}}},
+			expected: "did not have spec.tpm",
+		},
+		{
+			name:     "different TPM",
+			hosts:    []*kops.Host{buildHost("machine1", otherIdentity)},
+			expected: "attestation key of host \"machine1\": public key does not match",
+		},
+		{
+			name:     "different body",
+			hosts:    []*kops.Host{buildHost("machine1", identity)},
+			body:     []byte(`{"certs":{"kubelet":""}}`),
+			expected: "incorrect RequestHash",
+		},
+		{
+			name:     "other verifier",
+			hosts:    []*kops.Host{buildHost("machine1", identity)},
+			token:    "x-pki-tpm abc",
+			expected: bootstrap.ErrNotThisVerifier.Error(),
+		},
+		{
+			name:     "tampered data",
+			hosts:    []*kops.Host{buildHost("machine1", identity)},
+			token:    tamper(t, token),
+			expected: "attestation is not for the token data",
+		},
+		{
+			name:  "not generated by a TPM",
+			hosts: []*kops.Host{buildHost("machine1", identity)},
+			token: tpm.createToken(t, "machine1", body, func(data *AuthTokenData, attest *tpm2.TPMSAttest) {
+				attest.Magic = 0
+			}),
+			expected: "attestation was not generated by a TPM",
+		},
+		{
+			name:  "not a quote",
+			hosts: []*kops.Host{buildHost("machine1", identity)},
+			token: tpm.createToken(t, "machine1", body, func(data *AuthTokenData, attest *tpm2.TPMSAttest) {
+				attest.Type = tpm2.TPMSTAttestCertify
+				attest.Attested = tpm2.NewTPMUAttest(tpm2.TPMSTAttestCertify, &tpm2.TPMSCertifyInfo{})
+			}),
+			expected: "attestation is not a quote",
+		},
+		{
+			name:  "unrestricted attestation key",
+			hosts: []*kops.Host{buildHost("machine1", identity)},
+			token: tpm.createToken(t, "machine1", body, func(data *AuthTokenData, attest *tpm2.TPMSAttest) {
+				public := tpm.attestationKeyPublic(t)
+				public.ObjectAttributes.Restricted = false
+				data.AttestationKey = tpm2.Marshal(public)
+			}),
+			expected: "attestation key is not a restricted signing key",
+		},
+		{
+			name:  "signed by another key",
+			hosts: []*kops.Host{buildHost("machine1", identity)},
+			token: func() string {
+				// Claim our attestation key, but sign with the key of another TPM
+				return newSoftwareTPM(t).createToken(t, "machine1", body, func(data *AuthTokenData, attest *tpm2.TPMSAttest) {
+					data.AttestationKey = tpm2.Marshal(tpm.attestationKeyPublic(t))
+				})
+			}(),
+			expected: "invalid signature",
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			verifier := buildVerifier(t, g.hosts...)
+			requestToken := token
+			if g.token != "" {
+				requestToken = g.token
+			}
+			requestBody := body
+			if g.body != nil {
+				requestBody = g.body
+			}
+			_, err := verifier.VerifyToken(ctx, nil, requestToken, requestBody)
+			if err == nil {
+				t.Fatalf("expected error %q", g.expected)
+			}
+			if !strings.Contains(err.Error(), g.expected) {
+				t.Errorf("expected error %q, got %q", g.expected, err)
+			}
+		})
+	}
+}
+
+// tamper changes the timestamp of the token data, keeping the attestation.
+func tamper(t *testing.T, token string) string {
+	b, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(token, AuthenticationTokenPrefix))
+	if err != nil {
+		t.Fatal(err)
+	}
+	authToken := &AuthToken{}
+	if err := json.Unmarshal(b, authToken); err != nil {
+		t.Fatal(err)
+	}
+	data := &AuthTokenData{}
+	if err := json.Unmarshal(authToken.Data, data); err != nil {
+		t.Fatal(err)
+	}
+	data.Timestamp++
+	if authToken.Data, err = json.Marshal(data); err != nil {
+		t.Fatal(err)
+	}
+	if b, err = json.Marshal(authToken); err != nil {
+		t.Fatal(err)
+	}
+	return AuthenticationTokenPrefix + base64.StdEncoding.EncodeToString(b)
+}
@@ -0,0 +1,235 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tpmbootstrap
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/go-tpm/tpm2"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	kops "k8s.io/kops/pkg/apis/kops/v1alpha2"
+	"k8s.io/kops/pkg/bootstrap"
+	"k8s.io/kops/pkg/pki"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type verifier struct {
+	opt    Options
+	client client.Client
+}
+
+// NewVerifier constructs a new verifier.
+// Machines are allowed by the Host with their name in the kops-system namespace,
+// which records the attestation key of their TPM.
+// The attestation key is pinned: it is not bound to an endorsement key or checked against the certificate of the TPM manufacturer,
+// so the verifier trusts that the recorded key was read from the TPM of the machine when it was enrolled.
+func NewVerifier(options *Options, client client.Client) (bootstrap.Verifier, error) {
+	opt := *options
+	if opt.MaxTimeSkew == 0 {
+		opt.MaxTimeSkew = 300
+	}
+	return &verifier{
+		opt:    opt,
+		client: client,
+	}, nil
+}
+
+var _ bootstrap.Verifier = &verifier{}
+
+func (v *verifier) VerifyToken(ctx context.Context, rawRequest *http.Request, authToken string, body []byte) (*bootstrap.VerifyResult, error) {
+	// Reminder: we shouldn't trust any data we get from the client until we've checked the signature (and even then...)
+	if !strings.HasPrefix(authToken, AuthenticationTokenPrefix) {
+		return nil, bootstrap.ErrNotThisVerifier
+	}
+
+	token, tokenData, err := v.parseTokenData(strings.TrimPrefix(authToken, AuthenticationTokenPrefix), body)
+	if err != nil {
+		return nil, err
+	}
+
+	host, err := v.getHost(ctx, tokenData.Instance)
+	if err != nil {
+		return nil, err
+	}
+
+	// The attestation key must be the key we recorded from the TPM of the machine
+	akPublicKey, err := parseAttestationKey(tokenData.AttestationKey)
+	if err != nil {
+		return nil, err
+	}
+	if err := matchPublicKey(host.Spec.TPM.AttestationKey, akPublicKey); err != nil {
+		return nil, fmt.Errorf("attestation key of host %q: %w", host.Name, err)
+	}
+
+	if err := verifyQuote(akPublicKey, token); err != nil {
+		return nil, fmt.Errorf("failed to verify attestation for host %q: %w", host.Name, err)
+	}
+
+	result := &bootstrap.VerifyResult{
+		NodeName:          host.Name,
+		InstanceGroupName: host.Spec.InstanceGroup,
+	}
+
+	return result, nil
+}
+
+func (v *verifier) parseTokenData(authToken string, body []byte) (*AuthToken, *AuthTokenData, error) {
+	tokenBytes, err := base64.StdEncoding.DecodeString(authToken)
+	if err != nil {
+		return nil, nil, fmt.Errorf("decoding authorization token: %w", err)
+	}
+
+	token := &AuthToken{}
+	if err = json.Unmarshal(tokenBytes, token); err != nil {
+		return nil, nil, fmt.Errorf("unmarshalling authorization token: %w", err)
+	}
+
+	tokenData := &AuthTokenData{}
+	if err := json.Unmarshal(token.Data, tokenData); err != nil {
+		return nil, nil, fmt.Errorf("unmarshalling authorization token data: %w", err)
+	}
+
+	// Guard against replay attacks
+	if tokenData.Audience != AudienceNodeAuthentication {
+		return nil, nil, fmt.Errorf("incorrect Audience")
+	}
+	timeSkew := math.Abs(time.Since(time.Unix(tokenData.Timestamp, 0)).Seconds())
+	if timeSkew > float64(v.opt.MaxTimeSkew) {
+		return nil, nil, fmt.Errorf("incorrect Timestamp %v", tokenData.Timestamp)
+	}
+
+	// Verify the token has signed the body content.
+	requestHash := sha256.Sum256(body)
+	if !bytes.Equal(requestHash[:], tokenData.RequestHash) {
+		return nil, nil, fmt.Errorf("incorrect RequestHash")
+	}
+
+	return token, tokenData, nil
+}
+
+func (v *verifier) getHost(ctx context.Context, nodeName string) (*kops.Host, error) {
+	id := types.NamespacedName{
+		Namespace: "kops-system",
+		Name:      nodeName,
+	}
+	var host kops.Host
+	if err := v.client.Get(ctx, id, &host); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("host not found for %v", id)
+		}
+		return nil, fmt.Errorf("error getting host %v: %w", id, err)
+	}
+
+	if host.Spec.TPM == nil || host.Spec.TPM.AttestationKey == "" {
+		return nil, fmt.Errorf("host %v did not have spec.tpm.attestationKey", id)
+	}
+	if host.Spec.InstanceGroup == "" {
+		return nil, fmt.Errorf("host %v did not have spec.instanceGroup", id)
+	}
+	return &host, nil
+}
+
+// parseAttestationKey decodes the TPMT_PUBLIC of the attestation key,
+// checking that it was created as a restricted signing key that cannot leave the TPM.
+func parseAttestationKey(b []byte) (*ecdsa.PublicKey, error) {
+	public, err := tpm2.Unmarshal[tpm2.TPMTPublic](b)
+	if err != nil {
+		return nil, fmt.Errorf("decoding attestation key: %w", err)
+	}
+
+	attributes := public.ObjectAttributes
+	if !attributes.FixedTPM || !attributes.FixedParent || !attributes.SensitiveDataOrigin || !attributes.Restricted || !attributes.SignEncrypt || attributes.Decrypt {
+		return nil, fmt.Errorf("attestation key is not a restricted signing key generated in the TPM")
+	}
+
+	publicKey, err := tpm2.Pub(*public)
+	if err != nil {
+		return nil, fmt.Errorf("decoding attestation key: %w", err)
+	}
+	ecdsaPublicKey, ok := publicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("attestation key type %T not supported", publicKey)
+	}
+	return ecdsaPublicKey, nil
+}
+
+// matchPublicKey checks that the public key is the PEM-encoded public key recorded in the host.
+func matchPublicKey(expectedPEM string, publicKey crypto.PublicKey) error {
+	expected, err := pki.ParsePEMPublicKey([]byte(expectedPEM))
+	if err != nil {
+		return fmt.Errorf("failed to parse public key: %w", err)
+	}
+	k, ok := publicKey.(interface{ Equal(crypto.PublicKey) bool })
+	if !ok || !k.Equal(expected.Key) {
+		return fmt.Errorf("public key does not match")
+	}
+	return nil
+}
+
+// verifyQuote checks that the attestation is a TPM2_Quote of the hash of the token data, signed by the attestation key.
+func verifyQuote(akPublicKey *ecdsa.PublicKey, token *AuthToken) error {
+	attest, err := tpm2.Unmarshal[tpm2.TPMSAttest](token.Attestation)
+	if err != nil {
+		return fmt.Errorf("decoding attestation: %w", err)
+	}
+	if attest.Magic != tpm2.TPMGeneratedValue {
+		return fmt.Errorf("attestation was not generated by a TPM")
+	}
+	if attest.Type != tpm2.TPMSTAttestQuote {
+		return fmt.Errorf("attestation is not a quote")
+	}
+	dataHash := sha256.Sum256(token.Data)
+	if !bytes.Equal(attest.ExtraData.Buffer, dataHash[:]) {
+		return fmt.Errorf("attestation is not for the token data")
+	}
+
+	signature, err := tpm2.Unmarshal[tpm2.TPMTSignature](token.Signature)
+	if err != nil {
+		return fmt.Errorf("decoding signature: %w", err)
+	}
+	if signature.SigAlg != tpm2.TPMAlgECDSA {
+		return fmt.Errorf("signature algorithm %v not supported", signature.SigAlg)
+	}
+	ecdsaSignature, err := signature.Signature.ECDSA()
+	if err != nil {
+		return fmt.Errorf("decoding signature: %w", err)
+	}
+	if ecdsaSignature.Hash != tpm2.TPMAlgSHA256 {
+		return fmt.Errorf("signature hash %v not supported", ecdsaSignature.Hash)
+	}
+
+	attestHash := sha256.Sum256(token.Attestation)
+	r := new(big.Int).SetBytes(ecdsaSignature.SignatureR.Buffer)
+	s := new(big.Int).SetBytes(ecdsaSignature.SignatureS.Buffer)
+	if !ecdsa.Verify(akPublicKey, attestHash[:], r, s) {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package certinventory records the leaf certificates that nodeup issues on a node,
+// so that their expiry can be reported by kops-controller and kops get certificates.
+package certinventory
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// Dir is the directory on the host in which nodeup records the certificates it issues.
+	// It is inside the directory that is mounted into kops-controller.
+	Dir = "/etc/kubernetes/kops-controller/certificates"
+
+	// NodeAnnotation is the node annotation on which kops-controller publishes the certificates recorded on the node.
+	NodeAnnotation = "kops.k8s.io/certificates"
+)
+
+// Certificate describes a leaf certificate that was issued on a node.
+type Certificate struct {
+	// Name is the name of the nodeup task that issued the certificate.
+	Name string `json:"name"`
+	// Node is the name of the node on which the certificate was issued.
+	Node string `json:"node,omitempty"`
+	// Keyset is the name of the keyset that signed the certificate.
+	Keyset string `json:"keyset"`
+	// KeypairID is the id of the keypair in the keyset that signed the certificate.
+	KeypairID      string    `json:"keypairID,omitempty"`
+	Subject        string    `json:"subject"`
+	Issuer         string    `json:"issuer"`
+	AlternateNames []string  `json:"alternateNames,omitempty"`
+	NotBefore      time.Time `json:"notBefore"`
+	NotAfter       time.Time `json:"notAfter"`
+}
+
+// NewCertificate builds the record of a certificate issued from the given keyset.
+func NewCertificate(name string, node string, keyset string, keypairID string, cert *x509.Certificate) *Certificate {
+	return &Certificate{
+		Name:           name,
+		Node:           node,
+		Keyset:         keyset,
+		KeypairID:      keypairID,
+		Subject:        cert.Subject.String(),
+		Issuer:         cert.Issuer.String(),
+		AlternateNames: AlternateNames(cert),
+		NotBefore:      cert.NotBefore.UTC(),
+		NotAfter:       cert.NotAfter.UTC(),
+	}
+}
+
+// AlternateNames returns the sorted subject alternative names of a certificate.
+func AlternateNames(cert *x509.Certificate) []string {
+	var alternateNames []string
+	alternateNames = append(alternateNames, cert.DNSNames...)
+	alternateNames = append(alternateNames, cert.EmailAddresses...)
+	for _, ip := range cert.IPAddresses {
+		alternateNames = append(alternateNames, ip.String())
+	}
+	sort.Strings(alternateNames)
+	return alternateNames
+}
+
+// Write records a certificate in dir, replacing any earlier record with the same name.
+func Write(dir string, cert *Certificate) error {
+	if cert.Name == "" || strings.ContainsAny(cert.Name, "/\\") {
+		return fmt.Errorf("invalid certificate name %q", cert.Name)
+	}
+
+	data, err := json.Marshal(cert)
+	if err != nil {
+		return fmt.Errorf("serializing certificate record: %w", err)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating directory %q: %w", dir, err)
+	}
+
+	// Write to a temporary file and rename, so readers never see a partial record
+	p := filepath.Join(dir, cert.Name+".json")
+	tmp := p + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("writing %q: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, p); err != nil {
+		return fmt.Errorf("renaming %q to %q: %w", tmp, p, err)
+	}
+	return nil
+}
+
+// Read returns the certificates recorded in dir, sorted by name.
+// A missing directory is not an error; nothing has been recorded yet.
+func Read(dir string) ([]*Certificate, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading directory %q: %w", dir, err)
+	}
+
+	var certs []*Certificate
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		p := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return nil, fmt.Errorf("reading %q: %w", p, err)
+		}
+		cert := &Certificate{}
+		if err := json.Unmarshal(data, cert); err != nil {
+			return nil, fmt.Errorf("parsing %q: %w", p, err)
+		}
+		certs = append(certs, cert)
+	}
+	sort.Slice(certs, func(i, j int) bool {
+		return certs[i].Name < certs[j].Name
+	})
+	return certs, nil
+}
+
+// EncodeAnnotation serializes certificates for the NodeAnnotation.
+func EncodeAnnotation(certs []*Certificate) (string, error) {
+	data, err := json.Marshal(certs)
+	if err != nil {
+		return "", fmt.Errorf("serializing certificates: %w", err)
+	}
+	return string(data), nil
+}
+
+// DecodeAnnotation parses the value of the NodeAnnotation.
+func DecodeAnnotation(value string) ([]*Certificate, error) {
+	var certs []*Certificate
+	if err := json.Unmarshal([]byte(value), &certs); err != nil {
+		return nil, fmt.Errorf("parsing %s annotation: %w", NodeAnnotation, err)
+	}
+	return certs, nil
+}
+
+// Status classifies how close a certificate is to its expiry.
+type Status string
+
+const (
+	StatusOK       Status = "OK"
+	StatusWarning  Status = "Warning"
+	StatusCritical Status = "Critical"
+	StatusExpired  Status = "Expired"
+)
+
+// Thresholds are the remaining lifetimes at which a certificate is reported as
+// a warning or as critical.
+type Thresholds struct {
+	Warning  time.Duration
+	Critical time.Duration
+}
+
+// DefaultThresholds warns a month ahead and escalates a week ahead of expiry.
+var DefaultThresholds = Thresholds{
+	Warning:  30 * 24 * time.Hour,
+	Critical: 7 * 24 * time.Hour,
+}
+
+// Status returns the status of a certificate expiring at notAfter, as seen at now.
+func (t Thresholds) Status(notAfter time.Time, now time.Time) Status {
+	remaining := notAfter.Sub(now)
+	switch {
+	case remaining <= 0:
+		return StatusExpired
+	case remaining <= t.Critical:
+		return StatusCritical
+	case remaining <= t.Warning:
+		return StatusWarning
+	default:
+		return StatusOK
+	}
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certinventory
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWriteRead(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "certificates")
+
+	certs, err := Read(dir)
+	if err != nil {
+		t.Fatalf("reading missing directory: %v", err)
+	}
+	if len(certs) != 0 {
+		t.Fatalf("expected no certificates, got %v", certs)
+	}
+
+	notAfter := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	x509Cert := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "kube-apiserver"},
+		Issuer:      pkix.Name{CommonName: "kubernetes-ca"},
+		DNSNames:    []string{"kubernetes", "api.internal.example.com"},
+		IPAddresses: []net.IP{net.ParseIP("10.0.0.1")},
+		NotBefore:   notAfter.Add(-time.Hour),
+		NotAfter:    notAfter,
+	}
+
+	for _, name := range []string{"kubelet-server", "kube-apiserver"} {
+		if err := Write(dir, NewCertificate(name, "node-a", "kubernetes-ca", "1", x509Cert)); err != nil {
+			t.Fatalf("writing %s: %v", name, err)
+		}
+	}
+	// Rewriting a record replaces it
+	if err := Write(dir, NewCertificate("kube-apiserver", "node-a", "kubernetes-ca", "2", x509Cert)); err != nil {
+		t.Fatalf("rewriting: %v", err)
+	}
+
+	certs, err = Read(dir)
+	if err != nil {
+		t.Fatalf("reading: %v", err)
+	}
+	if len(certs) != 2 {
+		t.Fatalf("expected 2 certificates, got %d", len(certs))
+	}
+	got := certs[0]
+	want := &Certificate{
+		Name:           "kube-apiserver",
+		Node:           "node-a",
+		Keyset:         "kubernetes-ca",
+		KeypairID:      "2",
+		Subject:        "CN=kube-apiserver",
+		Issuer:         "CN=kubernetes-ca",
+		AlternateNames: []string{"10.0.0.1", "api.internal.example.com", "kubernetes"},
+		NotBefore:      notAfter.Add(-time.Hour),
+		NotAfter:       notAfter,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected record\n got: %+v\nwant: %+v", got, want)
+	}
+
+	annotation, err := EncodeAnnotation(certs)
+	if err != nil {
+		t.Fatalf("encoding annotation: %v", err)
+	}
+	decoded, err := DecodeAnnotation(annotation)
+	if err != nil {
+		t.Fatalf("decoding annotation: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, certs) {
+		t.Errorf("annotation did not round-trip\n got: %+v\nwant: %+v", decoded, certs)
+	}
+}
+
+func TestWriteRejectsPath(t *testing.T) {
+	err := Write(t.TempDir(), &Certificate{Name: "../kubelet"})
+	if err == nil {
+		t.Fatalf("expected error for name containing a path separator")
+	}
+}
+
+func TestThresholdsStatus(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	grid := []struct {
+		notAfter time.Time
+		expected Status
+	}{
+		{notAfter: now.Add(90 * day), expected: StatusOK},
+		{notAfter: now.Add(30 * day), expected: StatusWarning},
+		{notAfter: now.Add(8 * day), expected: StatusWarning},
+		{notAfter: now.Add(7 * day), expected: StatusCritical},
+		{notAfter: now.Add(time.Minute), expected: StatusCritical},
+		{notAfter: now, expected: StatusExpired},
+		{notAfter: now.Add(-day), expected: StatusExpired},
+	}
+	for _, g := range grid {
+		if actual := DefaultThresholds.Status(g.notAfter, now); actual != g.expected {
+			t.Errorf("expiry in %v: expected %s, got %s", g.notAfter.Sub(now), g.expected, actual)
+		}
+	}
+}
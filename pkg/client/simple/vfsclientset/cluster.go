@@ -139,6 +139,10 @@ func (r *ClusterVFS) Update(c *api.Cluster, status *api.ClusterStatus) (*api.Clu
 		return nil, errors.NewNotFound(schema.GroupResource{Group: api.GroupName, Resource: "Cluster"}, clusterName)
 	}
 
+	if err := checkResourceVersion("Cluster", c, old); err != nil {
+		return nil, err
+	}
+
 	if err := validation.ValidateClusterUpdate(c, status, old, r.vfsContext).ToAggregate(); err != nil {
 		return nil, err
 	}
@@ -148,7 +152,7 @@ func (r *ClusterVFS) Update(c *api.Cluster, status *api.ClusterStatus) (*api.Clu
 	}
 
 	if err := r.writeConfig(ctx, c, r.basePath.Join(clusterName, registry.PathCluster), c, vfs.WriteOptionOnlyIfExists); err != nil {
-		if os.IsNotExist(err) {
+		if os.IsNotExist(err) || errors.IsConflict(err) {
 			return nil, err
 		}
 		return nil, fmt.Errorf("error writing Cluster: %v", err)
@@ -19,15 +19,20 @@ package vfsclientset
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	stderrors "errors"
 	"fmt"
 	"os"
 	"reflect"
 	"sort"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/acls"
 	"k8s.io/kops/pkg/apis/kops"
@@ -128,11 +133,72 @@ func (c *VFSClientBase) readConfig(ctx context.Context, configPath vfs.Path) (ru
 	if err != nil {
 		return nil, fmt.Errorf("error parsing %s: %v", configPath, err)
 	}
+
+	objectMeta, err := meta.Accessor(object)
+	if err != nil {
+		return nil, err
+	}
+	objectMeta.SetResourceVersion(resourceVersion(data))
+
 	return object, nil
 }
 
+// resourceVersion returns the resource version of an object stored with the specified content.
+func resourceVersion(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:16])
+}
+
+// checkResourceVersion returns a Conflict error if the object being updated was read from a different version of the stored object.
+// As in Kubernetes, an object without a resource version is updated unconditionally.
+func checkResourceVersion(resource string, updated metav1.Object, stored metav1.Object) error {
+	rv := updated.GetResourceVersion()
+	if rv == "" || rv == stored.GetResourceVersion() {
+		return nil
+	}
+	return newConflict(resource, updated.GetName())
+}
+
+func newConflict(resource string, name string) error {
+	return errors.NewConflict(schema.GroupResource{Group: kops.GroupName, Resource: resource}, name, fmt.Errorf("the object has been modified; please apply your changes to the latest version and try again"))
+}
+
+// replaceConfig writes the configuration file only if it still holds the content with the specified resource version.
+// The write is conditional on the version of the file in state stores that support it, so that an update
+// racing with another one fails with a Conflict error instead of overwriting it.
+func (c *VFSClientBase) replaceConfig(ctx context.Context, configPath vfs.Path, name string, data []byte, acl vfs.ACL, rv string) error {
+	conditionalPath, ok := configPath.(vfs.ConditionalPath)
+	if !ok {
+		return configPath.WriteFile(ctx, bytes.NewReader(data), acl)
+	}
+
+	stored, version, err := conditionalPath.ReadFileVersion(ctx)
+	if err != nil {
+		return err
+	}
+	if resourceVersion(stored) != rv {
+		return newConflict(c.kind, name)
+	}
+	if err := conditionalPath.ReplaceFile(ctx, bytes.NewReader(data), acl, version); err != nil {
+		if stderrors.Is(err, vfs.ErrVersionMismatch) {
+			return newConflict(c.kind, name)
+		}
+		return err
+	}
+	return nil
+}
+
 func (c *VFSClientBase) writeConfig(ctx context.Context, cluster *kops.Cluster, configPath vfs.Path, o runtime.Object, writeOptions ...vfs.WriteOption) error {
+	objectMeta, err := meta.Accessor(o)
+	if err != nil {
+		return err
+	}
+
+	// The resource version identifies the stored content, so it is not stored itself
+	rv := objectMeta.GetResourceVersion()
+	objectMeta.SetResourceVersion("")
 	data, err := c.serialize(o)
+	objectMeta.SetResourceVersion(rv)
 	if err != nil {
 		return fmt.Errorf("error marshaling object: %v", err)
 	}
@@ -163,6 +229,8 @@ func (c *VFSClientBase) writeConfig(ctx context.Context, cluster *kops.Cluster,
 	rs := bytes.NewReader(data)
 	if create {
 		err = configPath.CreateFile(ctx, rs, acl)
+	} else if rv != "" {
+		err = c.replaceConfig(ctx, configPath, objectMeta.GetName(), data, acl, rv)
 	} else {
 		err = configPath.WriteFile(ctx, rs, acl)
 	}
@@ -171,8 +239,13 @@ func (c *VFSClientBase) writeConfig(ctx context.Context, cluster *kops.Cluster,
 			klog.Warningf("failed to create file as already exists: %v", configPath)
 			return err
 		}
+		if errors.IsConflict(err) {
+			return err
+		}
 		return fmt.Errorf("error writing configuration file %s: %v", configPath, err)
 	}
+
+	objectMeta.SetResourceVersion(resourceVersion(data))
 	return nil
 }
 
@@ -196,6 +269,9 @@ func (c *VFSClientBase) update(ctx context.Context, cluster *kops.Cluster, i run
 
 	err = c.writeConfig(ctx, cluster, c.basePath.Join(objectMeta.GetName()), i, vfs.WriteOptionOnlyIfExists)
 	if err != nil {
+		if errors.IsConflict(err) {
+			return err
+		}
 		return fmt.Errorf("error writing %s: %v", c.kind, err)
 	}
 
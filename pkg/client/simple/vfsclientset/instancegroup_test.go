@@ -0,0 +1,140 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfsclientset
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func TestInstanceGroupUpdateConflict(t *testing.T) {
+	ctx := context.TODO()
+	vfsContext := vfs.NewVFSContext()
+	vfsContext.ResetMemfsContext(true)
+	basePath, err := vfsContext.BuildVfsPath("memfs://state")
+	if err != nil {
+		t.Fatalf("error building path: %v", err)
+	}
+	clientset := NewVFSClientset(vfsContext, basePath)
+
+	cluster := &kops.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test.k8s.local"}}
+	igs := clientset.InstanceGroupsFor(cluster)
+
+	created, err := igs.Create(ctx, &kops.InstanceGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "nodes"},
+		Spec: kops.InstanceGroupSpec{
+			Role:    kops.InstanceGroupRoleNode,
+			MinSize: fi.PtrTo(int32(1)),
+			MaxSize: fi.PtrTo(int32(1)),
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("error creating instance group: %v", err)
+	}
+	if created.ResourceVersion == "" {
+		t.Errorf("expected resource version to be set on create")
+	}
+
+	first, err := igs.Get(ctx, "nodes", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("error getting instance group: %v", err)
+	}
+	second, err := igs.Get(ctx, "nodes", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("error getting instance group: %v", err)
+	}
+	if first.ResourceVersion != created.ResourceVersion {
+		t.Errorf("expected resource version %q, got %q", created.ResourceVersion, first.ResourceVersion)
+	}
+
+	first.Spec.MaxSize = fi.PtrTo(int32(2))
+	updated, err := igs.Update(ctx, first, metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatalf("error updating instance group: %v", err)
+	}
+	if updated.ResourceVersion == second.ResourceVersion {
+		t.Errorf("expected resource version to change on update")
+	}
+
+	second.Spec.MinSize = fi.PtrTo(int32(0))
+	_, err = igs.Update(ctx, second, metav1.UpdateOptions{})
+	if !errors.IsConflict(err) {
+		t.Errorf("expected Conflict error when updating a stale instance group, got %v", err)
+	}
+
+	// The updated object can be updated again
+	updated.Spec.MaxSize = fi.PtrTo(int32(3))
+	if _, err := igs.Update(ctx, updated, metav1.UpdateOptions{}); err != nil {
+		t.Errorf("error updating instance group again: %v", err)
+	}
+
+	// Without a resource version, the update is unconditional
+	second.ResourceVersion = ""
+	if _, err := igs.Update(ctx, second, metav1.UpdateOptions{}); err != nil {
+		t.Errorf("error updating instance group without resource version: %v", err)
+	}
+}
+
+// racingPath is a path whose content is replaced by another writer right after it is read.
+type racingPath struct {
+	*vfs.MemFSPath
+}
+
+func (p *racingPath) ReadFileVersion(ctx context.Context) ([]byte, string, error) {
+	data, version, err := p.MemFSPath.ReadFileVersion(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := p.MemFSPath.WriteFile(ctx, bytes.NewReader([]byte("concurrent update")), nil); err != nil {
+		return nil, "", err
+	}
+	return data, version, nil
+}
+
+func TestReplaceConfigConflict(t *testing.T) {
+	ctx := context.TODO()
+	p := vfs.NewMemFSPath(vfs.NewMemFSContext(), "config")
+	if err := p.WriteFile(ctx, bytes.NewReader([]byte("stored")), nil); err != nil {
+		t.Fatalf("error writing config: %v", err)
+	}
+
+	c := &VFSClientBase{kind: "InstanceGroup"}
+	err := c.replaceConfig(ctx, &racingPath{MemFSPath: p}, "nodes", []byte("updated"), nil, resourceVersion([]byte("stored")))
+	if !errors.IsConflict(err) {
+		t.Errorf("expected Conflict error when the config changes before it is replaced, got %v", err)
+	}
+
+	data, err := p.ReadFile(ctx)
+	if err != nil {
+		t.Fatalf("error reading config: %v", err)
+	}
+	if string(data) != "concurrent update" {
+		t.Errorf("expected the concurrent update to be kept, got %q", data)
+	}
+
+	err = c.replaceConfig(ctx, p, "nodes", []byte("updated"), nil, resourceVersion([]byte("stored")))
+	if !errors.IsConflict(err) {
+		t.Errorf("expected Conflict error when the config was read from a different version, got %v", err)
+	}
+}
@@ -0,0 +1,301 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"k8s.io/klog/v2"
+
+	"k8s.io/kops/pkg/apis/nodeup"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/featureflag"
+	"k8s.io/kops/pkg/nodemodel"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/architectures"
+	"k8s.io/kops/util/pkg/hashing"
+)
+
+const (
+	// bundleNodeupPath is where the nodeup script looks for nodeup before downloading it.
+	bundleNodeupPath = "opt/kops/bin/nodeup"
+	// bundleScriptPath is where the bundle holds the script that bootstraps the node.
+	bundleScriptPath = "opt/kops/bootstrap.sh"
+	// bundleCacheDir is the directory in which nodeup looks for assets and images before downloading them.
+	bundleCacheDir = "var/cache/nodeup"
+)
+
+type ToolboxBundleOptions struct {
+	ClusterName   string
+	InstanceGroup string
+
+	// Architecture is the CPU architecture of the machines the bundle is built for.
+	Architecture string
+
+	// Target is the path of the bundle to write.
+	Target string
+}
+
+func (o *ToolboxBundleOptions) InitDefaults() {
+	o.Architecture = string(architectures.ArchitectureAmd64)
+}
+
+// bundleFile is a file in the bundle, either held in memory or read from a local file.
+type bundleFile struct {
+	// Path is the path of the file relative to the root of the machine.
+	Path string
+	Mode int64
+
+	Contents  []byte
+	LocalPath string
+}
+
+// RunToolboxBundle writes an archive holding nodeup and everything it downloads to bootstrap a machine of an instance group,
+// so that the machine can be enrolled without access to the asset and image locations.
+func RunToolboxBundle(ctx context.Context, f commandutils.Factory, out io.Writer, options *ToolboxBundleOptions) error {
+	if !featureflag.Metal.Enabled() {
+		return fmt.Errorf("bare-metal support requires the Metal feature flag to be enabled")
+	}
+	if options.ClusterName == "" {
+		return fmt.Errorf("cluster is required")
+	}
+	if options.InstanceGroup == "" {
+		return fmt.Errorf("instance-group is required")
+	}
+	if options.Target == "" {
+		return fmt.Errorf("target is required")
+	}
+	arch := architectures.Architecture(options.Architecture)
+	if arch != architectures.ArchitectureAmd64 && arch != architectures.ArchitectureArm64 {
+		return fmt.Errorf("unsupported architecture %q", options.Architecture)
+	}
+
+	clientset, err := f.KopsClient()
+	if err != nil {
+		return err
+	}
+
+	configBuilder := &ConfigBuilder{
+		Clientset:         clientset,
+		ClusterName:       options.ClusterName,
+		InstanceGroupName: options.InstanceGroup,
+	}
+
+	bootstrapData, err := configBuilder.GetBootstrapData(ctx)
+	if err != nil {
+		return err
+	}
+	assetBuilder, err := configBuilder.GetAssetBuilder(ctx)
+	if err != nil {
+		return err
+	}
+	nodeUpAssets, err := nodemodel.BuildNodeUpAssets(ctx, assetBuilder)
+	if err != nil {
+		return err
+	}
+	nodeUpAsset := nodeUpAssets.NodeUpAssets[arch]
+	if nodeUpAsset == nil {
+		return fmt.Errorf("no nodeup asset for architecture %q", arch)
+	}
+
+	downloadDir, err := os.MkdirTemp("", "kops-bundle")
+	if err != nil {
+		return fmt.Errorf("creating download directory: %w", err)
+	}
+	defer func() {
+		if err := os.RemoveAll(downloadDir); err != nil {
+			klog.Warningf("error removing download directory %q: %v", downloadDir, err)
+		}
+	}()
+
+	files, err := buildBundleFiles(downloadDir, bootstrapData, arch, nodeUpAsset.Locations, nodeUpAsset.Hash)
+	if err != nil {
+		return err
+	}
+
+	if err := writeBundleFile(options.Target, files); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Wrote bundle for instance group %q (%s) to %s\n", options.InstanceGroup, arch, options.Target)
+	return nil
+}
+
+// buildBundleFiles downloads nodeup, and the assets and images in the nodeup configuration, and lists the files of the bundle.
+func buildBundleFiles(downloadDir string, bootstrapData *BootstrapData, arch architectures.Architecture, nodeupLocations []string, nodeupHash *hashing.Hash) ([]*bundleFile, error) {
+	var files []*bundleFile
+
+	nodeupFile, err := downloadBundleAsset(downloadDir, nodeupLocations, nodeupHash)
+	if err != nil {
+		return nil, fmt.Errorf("downloading nodeup: %w", err)
+	}
+	files = append(files, &bundleFile{Path: bundleNodeupPath, Mode: 0o755, LocalPath: nodeupFile})
+
+	if len(bootstrapData.NodeupScript) != 0 {
+		files = append(files, &bundleFile{Path: bundleScriptPath, Mode: 0o755, Contents: bootstrapData.NodeupScript})
+	}
+
+	// The control plane reads its configuration from local files, which hold secrets
+	for p, contents := range bootstrapData.NodeupScriptAdditionalFiles {
+		files = append(files, &bundleFile{Path: strings.TrimPrefix(p, "/"), Mode: 0o600, Contents: contents})
+	}
+
+	if config := bootstrapData.NodeupConfig; config != nil {
+		for _, asset := range config.Assets[arch] {
+			urls, hash, err := fi.ParseAsset(asset)
+			if err != nil {
+				return nil, err
+			}
+			if err := addBundleCacheFile(&files, downloadDir, urls, hash); err != nil {
+				return nil, err
+			}
+		}
+		for _, image := range config.Images[arch] {
+			if err := addBundleImage(&files, downloadDir, image); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+	return files, nil
+}
+
+// addBundleImage adds an image to the nodeup cache in the bundle.
+func addBundleImage(files *[]*bundleFile, downloadDir string, image *nodeup.Image) error {
+	hash, err := hashing.FromString(image.Hash)
+	if err != nil {
+		return fmt.Errorf("parsing hash of image %q: %w", image.Name, err)
+	}
+	return addBundleCacheFile(files, downloadDir, image.Sources, hash)
+}
+
+// addBundleCacheFile downloads an asset and adds it to the nodeup cache in the bundle, at the path where nodeup looks for it.
+func addBundleCacheFile(files *[]*bundleFile, downloadDir string, urls []string, hash *hashing.Hash) error {
+	if len(urls) == 0 {
+		return fmt.Errorf("no locations specified for asset")
+	}
+	if hash == nil {
+		return fmt.Errorf("no hash specified for asset %q", urls[0])
+	}
+	localPath, err := downloadBundleAsset(downloadDir, urls, hash)
+	if err != nil {
+		return err
+	}
+	*files = append(*files, &bundleFile{
+		Path:      fi.AssetCachePath(bundleCacheDir, urls[0], hash),
+		Mode:      0o644,
+		LocalPath: localPath,
+	})
+	return nil
+}
+
+// downloadBundleAsset downloads an asset from the first of its locations that is available, and verifies its hash.
+func downloadBundleAsset(downloadDir string, urls []string, hash *hashing.Hash) (string, error) {
+	localPath := fi.AssetCachePath(downloadDir, urls[0], hash)
+
+	var err error
+	for _, url := range urls {
+		_, err = fi.DownloadURL(url, localPath, hash)
+		if err != nil {
+			klog.Warningf("error downloading url %q: %v", url, err)
+			continue
+		}
+		return localPath, nil
+	}
+	return "", fmt.Errorf("downloading %q: %w", urls[0], err)
+}
+
+// writeBundleFile writes the bundle to a gzipped tar file at target.
+// The bundle holds the bootstrap configuration, and the keys of the control plane, so only the owner can read it.
+func writeBundleFile(target string, files []*bundleFile) error {
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("creating %q: %w", target, err)
+	}
+	// The mode is only applied when the file is created
+	if err := f.Chmod(0o600); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("changing mode of %q: %w", target, err)
+	}
+	if err := writeBundle(f, files); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("writing %q: %w", target, err)
+	}
+	return f.Close()
+}
+
+// writeBundle writes the files as a gzipped tar archive, to be extracted at the root of the machine.
+func writeBundle(w io.Writer, files []*bundleFile) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	// Parent directories are not included, so that extracting the bundle does not change existing directories
+	for _, file := range files {
+		if err := writeBundleEntry(tw, file); err != nil {
+			return fmt.Errorf("adding %q: %w", file.Path, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+func writeBundleEntry(tw *tar.Writer, file *bundleFile) error {
+	header := &tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     file.Path,
+		Mode:     file.Mode,
+		Size:     int64(len(file.Contents)),
+	}
+
+	if file.LocalPath == "" {
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		_, err := tw.Write(file.Contents)
+		return err
+	}
+
+	f, err := os.Open(file.LocalPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	header.Size = stat.Size()
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"k8s.io/kops/pkg/apis/nodeup"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/hashing"
+)
+
+func TestWriteBundle(t *testing.T) {
+	localPath := filepath.Join(t.TempDir(), "nodeup")
+	if err := os.WriteFile(localPath, []byte("nodeup binary"), 0o600); err != nil {
+		t.Fatalf("error writing file: %v", err)
+	}
+
+	files := []*bundleFile{
+		{Path: "etc/kubernetes/kops/config/igconfig/control-plane/nodeupconfig.yaml", Mode: 0o600, Contents: []byte("config")},
+		{Path: bundleNodeupPath, Mode: 0o755, LocalPath: localPath},
+	}
+
+	var b bytes.Buffer
+	if err := writeBundle(&b, files); err != nil {
+		t.Fatalf("error writing bundle: %v", err)
+	}
+
+	gr, err := gzip.NewReader(&b)
+	if err != nil {
+		t.Fatalf("error reading bundle: %v", err)
+	}
+	tr := tar.NewReader(gr)
+
+	type entry struct {
+		Name     string
+		Mode     int64
+		Contents string
+	}
+	var actual []entry
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("error reading bundle: %v", err)
+		}
+		contents, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatalf("error reading %q: %v", header.Name, err)
+		}
+		actual = append(actual, entry{Name: header.Name, Mode: header.Mode, Contents: string(contents)})
+	}
+
+	expected := []entry{
+		{Name: "etc/kubernetes/kops/config/igconfig/control-plane/nodeupconfig.yaml", Mode: 0o600, Contents: "config"},
+		{Name: "opt/kops/bin/nodeup", Mode: 0o755, Contents: "nodeup binary"},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected bundle contents\nactual:   %v\nexpected: %v", actual, expected)
+	}
+}
+
+func TestWriteBundleFileIsPrivate(t *testing.T) {
+	target := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	// An existing bundle is replaced, and its mode is changed
+	if err := os.WriteFile(target, []byte("previous bundle, which is longer than the new one"), 0o644); err != nil {
+		t.Fatalf("error writing file: %v", err)
+	}
+
+	files := []*bundleFile{
+		{Path: "etc/kubernetes/kops/config/igconfig/control-plane/nodeupconfig.yaml", Mode: 0o600, Contents: []byte("config")},
+	}
+	if err := writeBundleFile(target, files); err != nil {
+		t.Fatalf("error writing bundle: %v", err)
+	}
+
+	stat, err := os.Stat(target)
+	if err != nil {
+		t.Fatalf("error reading bundle: %v", err)
+	}
+	if mode := stat.Mode().Perm(); mode != 0o600 {
+		t.Errorf("unexpected bundle mode %v, expected %v", mode, os.FileMode(0o600))
+	}
+
+	f, err := os.Open(target)
+	if err != nil {
+		t.Fatalf("error reading bundle: %v", err)
+	}
+	defer f.Close()
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("error reading bundle: %v", err)
+	}
+	if _, err := io.ReadAll(gr); err != nil {
+		t.Errorf("error reading bundle: %v", err)
+	}
+}
+
+func TestAddBundleImageUsesNodeupCachePath(t *testing.T) {
+	contents := []byte("image")
+	hash, err := hashing.HashAlgorithmSHA256.Hash(bytes.NewReader(contents))
+	if err != nil {
+		t.Fatalf("error hashing: %v", err)
+	}
+
+	// Pre-populate the download directory, so that the image is not downloaded
+	downloadDir := t.TempDir()
+	url := "https://example.com/images/kube-proxy-amd64.tar.gz"
+	if err := os.WriteFile(fi.AssetCachePath(downloadDir, url, hash), contents, 0o600); err != nil {
+		t.Fatalf("error writing file: %v", err)
+	}
+
+	var files []*bundleFile
+	image := &nodeup.Image{Name: "kube-proxy", Sources: []string{url}, Hash: hash.Hex()}
+	if err := addBundleImage(&files, downloadDir, image); err != nil {
+		t.Fatalf("error adding image: %v", err)
+	}
+
+	if len(files) != 1 {
+		t.Fatalf("expected 1 file, got %d", len(files))
+	}
+	expected := "var/cache/nodeup/sha256:" + hash.Hex() + "_kube-proxy-amd64_tar_gz"
+	if files[0].Path != expected {
+		t.Errorf("unexpected path %q, expected %q", files[0].Path, expected)
+	}
+}
@@ -65,6 +65,9 @@ type ToolboxEnrollOptions struct {
 
 	SSHUser string
 	SSHPort int
+
+	// Bundle is the path of a bundle built by kops toolbox bundle, which is extracted on the machine before it is bootstrapped.
+	Bundle string
 }
 
 func (o *ToolboxEnrollOptions) InitDefaults() {
@@ -182,9 +185,16 @@ func enrollHost(ctx context.Context, ig *kops.InstanceGroup, options *ToolboxEnr
 		}
 	}
 
-	for k, v := range bootstrapData.NodeupScriptAdditionalFiles {
-		if err := sshTarget.writeFile(ctx, k, bytes.NewReader(v)); err != nil {
-			return fmt.Errorf("writing file %q over SSH: %w", k, err)
+	if options.Bundle != "" {
+		// The bundle holds the additional files of the nodeup script
+		if err := installBundle(ctx, sshTarget, options.Bundle, sudo); err != nil {
+			return err
+		}
+	} else {
+		for k, v := range bootstrapData.NodeupScriptAdditionalFiles {
+			if err := sshTarget.writeFile(ctx, k, bytes.NewReader(v)); err != nil {
+				return fmt.Errorf("writing file %q over SSH: %w", k, err)
+			}
 		}
 	}
 
@@ -196,6 +206,30 @@ func enrollHost(ctx context.Context, ig *kops.InstanceGroup, options *ToolboxEnr
 	return nil
 }
 
+// installBundle copies a bundle to the host and extracts it, so that nodeup finds its assets and images without downloading them.
+func installBundle(ctx context.Context, sshTarget *SSHHost, bundlePath string, sudo bool) error {
+	f, err := os.Open(bundlePath)
+	if err != nil {
+		return fmt.Errorf("opening bundle %q: %w", bundlePath, err)
+	}
+	defer f.Close()
+
+	remotePath := "/tmp/kops-bundle.tar.gz"
+	if err := sshTarget.writeFile(ctx, remotePath, f); err != nil {
+		return fmt.Errorf("writing bundle over SSH: %w", err)
+	}
+	defer func() {
+		if _, err := sshTarget.runCommand(ctx, "rm -f "+remotePath, ExecOptions{Sudo: sudo, Echo: false}); err != nil {
+			klog.Warningf("error removing bundle %q: %v", remotePath, err)
+		}
+	}()
+
+	if _, err := sshTarget.runCommand(ctx, "tar -xzf "+remotePath+" -C /", ExecOptions{Sudo: sudo, Echo: true}); err != nil {
+		return fmt.Errorf("extracting bundle: %w", err)
+	}
+	return nil
+}
+
 func createHostResourceInAPIServer(ctx context.Context, options *ToolboxEnrollOptions, nodeName string, publicKey []byte, client client.Client) error {
 	host := &v1alpha2.Host{}
 	host.Namespace = "kops-system"
@@ -0,0 +1,184 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	api "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+)
+
+// updateCanaries replaces the canary instances of a group, then waits for the canary soak period,
+// validating the cluster and running the canary probes.
+// If any of that fails, the group is rolled back to its previous specification.
+func (c *RollingUpdateCluster) updateCanaries(ctx context.Context, group *cloudinstances.CloudInstanceGroup, canaries []*cloudinstances.CloudInstance, sleepAfterTerminate time.Duration) error {
+	igName := group.InstanceGroup.ObjectMeta.Name
+	klog.Infof("Replacing %d canary instance(s) in instance group %q", len(canaries), igName)
+
+	err := c.replaceCanaries(ctx, group, canaries, sleepAfterTerminate)
+	if err == nil {
+		err = c.soakCanaries(ctx, group)
+	}
+	if err != nil {
+		return c.rollbackCanaries(ctx, group, sleepAfterTerminate, err)
+	}
+
+	klog.Infof("Canary instance(s) in instance group %q are healthy; updating the remaining instances", igName)
+	return nil
+}
+
+// replaceCanaries replaces the canary instances one at a time, validating the cluster after each.
+func (c *RollingUpdateCluster) replaceCanaries(ctx context.Context, group *cloudinstances.CloudInstanceGroup, canaries []*cloudinstances.CloudInstance, sleepAfterTerminate time.Duration) error {
+	for _, u := range canaries {
+		if err := c.drainTerminateAndWait(ctx, u, sleepAfterTerminate); err != nil {
+			return err
+		}
+
+		// Unlike the rest of the rolling update, a canary that does not validate always fails
+		if !c.CloudOnly {
+			klog.Info("Validating the cluster.")
+			err := c.validateClusterWithTimeout(c.ValidateCount, group)
+			c.Journal.recordValidation(group.InstanceGroup.ObjectMeta.Name, "after terminating canary instance", err)
+			if err != nil {
+				return fmt.Errorf("cluster did not validate after terminating canary instance %q: %w", u.ID, err)
+			}
+		}
+
+		if err := c.runHooks(ctx, api.RollingUpdateHookStageAfterValidate, u); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// soakCanaries validates the cluster and runs the canary probes until the soak period has passed.
+func (c *RollingUpdateCluster) soakCanaries(ctx context.Context, group *cloudinstances.CloudInstanceGroup) error {
+	klog.Infof("Soaking canary instance(s) in instance group %q for %s", group.InstanceGroup.ObjectMeta.Name, c.CanarySoakPeriod)
+
+	deadline := time.Now().Add(c.CanarySoakPeriod)
+	for {
+		if !c.CloudOnly {
+			result, err := c.ClusterValidator.Validate(ctx)
+			if err != nil {
+				return fmt.Errorf("error validating cluster during canary soak period: %w", err)
+			}
+			if hasFailureRelevantToGroup(result.Failures, group) {
+				var messages []string
+				for _, failure := range result.Failures {
+					messages = append(messages, failure.Message)
+				}
+				return fmt.Errorf("cluster did not pass validation during canary soak period: %s", strings.Join(messages, ", "))
+			}
+		}
+
+		for _, probe := range c.CanaryProbes {
+			if err := c.runCanaryProbe(ctx, group, probe); err != nil {
+				return err
+			}
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("canary soak period interrupted: %w", ctx.Err())
+		case <-time.After(min(remaining, c.ValidateTickDuration)):
+		}
+	}
+}
+
+// runCanaryProbe runs a canary probe with sh, on the machine running the rolling update.
+func (c *RollingUpdateCluster) runCanaryProbe(ctx context.Context, group *cloudinstances.CloudInstanceGroup, probe string) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultHookTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", probe)
+	cmd.Env = append(os.Environ(),
+		"KOPS_CLUSTER_NAME="+c.Cluster.ObjectMeta.Name,
+		"KOPS_INSTANCE_GROUP="+group.InstanceGroup.ObjectMeta.Name,
+	)
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("canary probe %q failed: %w: %s", probe, err, strings.TrimSpace(string(output)))
+	}
+	klog.V(2).Infof("canary probe %q succeeded", probe)
+	return nil
+}
+
+// rollbackCanaries reverts the group to the specification of its instances that needed update,
+// then replaces the instances that were created from the new specification.
+func (c *RollingUpdateCluster) rollbackCanaries(ctx context.Context, group *cloudinstances.CloudInstanceGroup, sleepAfterTerminate time.Duration, cause error) error {
+	igName := group.InstanceGroup.ObjectMeta.Name
+	klog.Warningf("Canary for instance group %q failed, rolling back: %v", igName, cause)
+
+	if err := c.Cloud.RollbackGroup(group); err != nil {
+		return fmt.Errorf("canary for instance group %q failed: %w; error rolling back: %v", igName, cause, err)
+	}
+	// The next "kops update cluster" would apply the specification that failed again, so it refuses to until the rollback is acknowledged
+	c.Journal.recordRollback(igName, cause)
+
+	rolledBack, err := c.refreshCloudGroup(ctx, group)
+	if err != nil {
+		return fmt.Errorf("canary for instance group %q failed: %w; error finding canary instances: %v", igName, cause, err)
+	}
+
+	// Instances created from the new specification now need update
+	for _, u := range rolledBack.NeedUpdate {
+		klog.Infof("Replacing canary instance %q", u.ID)
+		if err := c.drainTerminateAndWait(ctx, u, sleepAfterTerminate); err != nil {
+			return fmt.Errorf("canary for instance group %q failed: %w; error replacing canary instance %q: %v", igName, cause, u.ID, err)
+		}
+	}
+
+	return fmt.Errorf("canary for instance group %q failed and the instance group was rolled back: %w", igName, cause)
+}
+
+// refreshCloudGroup fetches the current state of a cloud group.
+func (c *RollingUpdateCluster) refreshCloudGroup(ctx context.Context, group *cloudinstances.CloudInstanceGroup) (*cloudinstances.CloudInstanceGroup, error) {
+	var nodes []corev1.Node
+	if !c.CloudOnly {
+		nodeList, err := c.K8sClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("error listing nodes in cluster: %w", err)
+		}
+		nodes = nodeList.Items
+	}
+
+	groups, err := c.Cloud.GetCloudGroups(c.Cluster, []*api.InstanceGroup{group.InstanceGroup}, false, nodes)
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range groups {
+		if g.HumanName == group.HumanName {
+			return g, nil
+		}
+	}
+	return nil, fmt.Errorf("cloud group %q not found", group.HumanName)
+}
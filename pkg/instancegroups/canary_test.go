@@ -0,0 +1,136 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/autoscaling"
+	autoscalingtypes "github.com/aws/aws-sdk-go-v2/service/autoscaling/types"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+)
+
+// canaryTestCloud returns fixed cloud groups, simulating the state of the cloud after a rollback.
+type canaryTestCloud struct {
+	*awsup.MockAWSCloud
+	cloudGroups map[string]*cloudinstances.CloudInstanceGroup
+}
+
+func (c *canaryTestCloud) GetCloudGroups(cluster *kopsapi.Cluster, instancegroups []*kopsapi.InstanceGroup, warnUnmatched bool, nodes []v1.Node) (map[string]*cloudinstances.CloudInstanceGroup, error) {
+	return c.cloudGroups, nil
+}
+
+func getCanaryTestSetup() (*RollingUpdateCluster, *canaryTestCloud, map[string]*cloudinstances.CloudInstanceGroup) {
+	c, mockcloud := getTestSetup()
+	cloud := &canaryTestCloud{MockAWSCloud: mockcloud}
+	c.Cloud = cloud
+	c.Canary = 1
+	c.CanarySoakPeriod = 5 * time.Millisecond
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, mockcloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 3)
+
+	// The instances needing update were created from version 1 of the launch template
+	asg := groups["node-1"].Raw.(*autoscalingtypes.AutoScalingGroup)
+	for _, id := range []string{"node-1a", "node-1b", "node-1c"} {
+		asg.Instances = append(asg.Instances, autoscalingtypes.Instance{
+			InstanceId: aws.String(id),
+			LaunchTemplate: &autoscalingtypes.LaunchTemplateSpecification{
+				LaunchTemplateId: aws.String("lt-node-1"),
+				Version:          aws.String("1"),
+			},
+		})
+	}
+
+	return c, cloud, groups
+}
+
+func TestRollingUpdateCanarySucceeds(t *testing.T) {
+	ctx := context.TODO()
+	c, cloud, groups := getCanaryTestSetup()
+	c.CanaryProbes = []string{"test \"$KOPS_INSTANCE_GROUP\" = node-1"}
+
+	err := c.RollingUpdate(ctx, groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	assertGroupInstanceCount(t, cloud, "node-1", 0)
+}
+
+func TestRollingUpdateCanaryFailsProbe(t *testing.T) {
+	ctx := context.TODO()
+	c, cloud, groups := getCanaryTestSetup()
+	c.CanaryProbes = []string{"exit 1"}
+
+	// After the rollback, the replacement for the canary instance is the only one not using version 1
+	_, err := cloud.Autoscaling().AttachInstances(ctx, &autoscaling.AttachInstancesInput{
+		AutoScalingGroupName: aws.String("node-1"),
+		InstanceIds:          []string{"node-1d"},
+	})
+	assert.NoError(t, err, "attaching canary replacement")
+	rolledBack := &cloudinstances.CloudInstanceGroup{
+		HumanName:     "node-1",
+		InstanceGroup: groups["node-1"].InstanceGroup,
+		Raw:           groups["node-1"].Raw,
+	}
+	_, _ = rolledBack.NewCloudInstance("node-1b", cloudinstances.CloudInstanceStatusUpToDate, nil)
+	_, _ = rolledBack.NewCloudInstance("node-1c", cloudinstances.CloudInstanceStatusUpToDate, nil)
+	_, _ = rolledBack.NewCloudInstance("node-1d", cloudinstances.CloudInstanceStatusNeedsUpdate, nil)
+	cloud.cloudGroups = map[string]*cloudinstances.CloudInstanceGroup{"node-1": rolledBack}
+
+	err = c.RollingUpdate(ctx, groups, &kopsapi.InstanceGroupList{})
+	assert.ErrorContains(t, err, `canary for instance group "node-1" failed and the instance group was rolled back`)
+	assert.ErrorContains(t, err, `canary probe "exit 1" failed`)
+
+	asgGroups, _ := cloud.Autoscaling().DescribeAutoScalingGroups(ctx, &autoscaling.DescribeAutoScalingGroupsInput{
+		AutoScalingGroupNames: []string{"node-1"},
+	})
+	if assert.Len(t, asgGroups.AutoScalingGroups, 1) {
+		asg := asgGroups.AutoScalingGroups[0]
+		if assert.NotNil(t, asg.LaunchTemplate, "launch template") {
+			assert.Equal(t, "lt-node-1", aws.ToString(asg.LaunchTemplate.LaunchTemplateId))
+			assert.Equal(t, "1", aws.ToString(asg.LaunchTemplate.Version))
+		}
+
+		var remaining []string
+		for _, instance := range asg.Instances {
+			remaining = append(remaining, aws.ToString(instance.InstanceId))
+		}
+		assert.ElementsMatch(t, []string{"node-1b", "node-1c"}, remaining, "the canary and its replacement should be terminated")
+	}
+}
+
+func TestRollingUpdateCanaryFailsValidation(t *testing.T) {
+	ctx := context.TODO()
+	c, cloud, groups := getCanaryTestSetup()
+	c.ClusterValidator = &failingClusterValidator{}
+	c.FailOnValidate = false
+	c.ValidationTimeout = 10 * time.Millisecond
+	cloud.cloudGroups = map[string]*cloudinstances.CloudInstanceGroup{}
+
+	err := c.RollingUpdate(ctx, groups, &kopsapi.InstanceGroupList{})
+	assert.ErrorContains(t, err, "cluster did not validate after terminating canary instance")
+
+	assertGroupInstanceCount(t, cloud, "node-1", 2)
+}
@@ -0,0 +1,241 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	api "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+const (
+	// defaultHookTimeout is the maximum time to wait for a hook that does not set a timeout.
+	defaultHookTimeout = 5 * time.Minute
+	// defaultHookJobNamespace is the namespace in which Job hooks run if they do not set one.
+	defaultHookJobNamespace = "kube-system"
+)
+
+// hookJobPollInterval is the interval at which we check whether a Job hook has completed.
+var hookJobPollInterval = 5 * time.Second
+
+// HookEvent describes the instance a rolling update hook is running for.
+// It is the body of the request sent to HTTP hooks, and is passed to exec and Job hooks as environment variables.
+type HookEvent struct {
+	ClusterName   string                     `json:"clusterName"`
+	InstanceGroup string                     `json:"instanceGroup"`
+	InstanceID    string                     `json:"instanceID"`
+	NodeName      string                     `json:"nodeName,omitempty"`
+	Hook          string                     `json:"hook"`
+	Stage         api.RollingUpdateHookStage `json:"stage"`
+}
+
+// environment returns the event as environment variables.
+func (e *HookEvent) environment() []corev1.EnvVar {
+	return []corev1.EnvVar{
+		{Name: "KOPS_CLUSTER_NAME", Value: e.ClusterName},
+		{Name: "KOPS_INSTANCE_GROUP", Value: e.InstanceGroup},
+		{Name: "KOPS_INSTANCE_ID", Value: e.InstanceID},
+		{Name: "KOPS_NODE_NAME", Value: e.NodeName},
+		{Name: "KOPS_HOOK", Value: e.Hook},
+		{Name: "KOPS_HOOK_STAGE", Value: string(e.Stage)},
+	}
+}
+
+// runHooks runs the hooks of the instance's group for the specified stage, in the order they are specified.
+// A hook that fails stops the rolling update, unless its failure policy is Ignore.
+func (c *RollingUpdateCluster) runHooks(ctx context.Context, stage api.RollingUpdateHookStage, u *cloudinstances.CloudInstance) error {
+	settings := resolveSettings(c.Cluster, u.CloudInstanceGroup.InstanceGroup, 0)
+
+	for i := range settings.Hooks {
+		hook := &settings.Hooks[i]
+		if hook.Stage != stage {
+			continue
+		}
+
+		event := &HookEvent{
+			ClusterName:   c.Cluster.ObjectMeta.Name,
+			InstanceGroup: u.CloudInstanceGroup.InstanceGroup.ObjectMeta.Name,
+			InstanceID:    u.ID,
+			Hook:          hook.Name,
+			Stage:         stage,
+		}
+		if u.Node != nil {
+			event.NodeName = u.Node.Name
+		}
+
+		klog.Infof("Running %s hook %q for instance %q", stage, hook.Name, u.ID)
+		if err := c.runHook(ctx, hook, event); err != nil {
+			if hook.FailurePolicy == api.RollingUpdateHookFailurePolicyIgnore {
+				klog.Warningf("Ignoring failure of %s hook %q for instance %q: %v", stage, hook.Name, u.ID, err)
+				continue
+			}
+			return fmt.Errorf("%s hook %q failed for instance %q: %w", stage, hook.Name, u.ID, err)
+		}
+	}
+
+	return nil
+}
+
+func (c *RollingUpdateCluster) runHook(ctx context.Context, hook *api.RollingUpdateHook, event *HookEvent) error {
+	timeout := defaultHookTimeout
+	if hook.Timeout != nil {
+		timeout = hook.Timeout.Duration
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	switch {
+	case hook.Exec != nil:
+		return runExecHook(ctx, hook.Exec, event)
+	case hook.HTTP != nil:
+		return runHTTPHook(ctx, hook.HTTP, event)
+	case hook.Job != nil:
+		return c.runJobHook(ctx, hook.Job, event)
+	default:
+		return fmt.Errorf("hook %q does not specify exec, http or job", hook.Name)
+	}
+}
+
+// runExecHook runs a command on the machine running the rolling update.
+func runExecHook(ctx context.Context, hook *api.ExecRollingUpdateHook, event *HookEvent) error {
+	if len(hook.Command) == 0 {
+		return fmt.Errorf("command not specified")
+	}
+
+	cmd := exec.CommandContext(ctx, hook.Command[0], hook.Command[1:]...)
+	cmd.Env = os.Environ()
+	for _, env := range event.environment() {
+		cmd.Env = append(cmd.Env, env.Name+"="+env.Value)
+	}
+
+	output, err := cmd.CombinedOutput()
+	if len(output) != 0 {
+		klog.Infof("Output from hook %q:\n%s", event.Hook, output)
+	}
+	if err != nil {
+		return fmt.Errorf("error running %v: %w", hook.Command, err)
+	}
+	return nil
+}
+
+// runHTTPHook sends the event to a webhook.
+func runHTTPHook(ctx context.Context, hook *api.HTTPRollingUpdateHook, event *HookEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("error serializing hook event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error building request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range hook.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling %s: %w", hook.URL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("unexpected response from %s: %s: %s", hook.URL, resp.Status, string(b))
+	}
+	return nil
+}
+
+// runJobHook runs a Job in the cluster and waits for it to complete.
+func (c *RollingUpdateCluster) runJobHook(ctx context.Context, hook *api.JobRollingUpdateHook, event *HookEvent) error {
+	if c.K8sClient == nil {
+		return fmt.Errorf("job hooks require access to the kubernetes API")
+	}
+
+	namespace := hook.Namespace
+	if namespace == "" {
+		namespace = defaultHookJobNamespace
+	}
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "kops-rolling-update-hook-",
+			Namespace:    namespace,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            fi.PtrTo(int32(0)),
+			TTLSecondsAfterFinished: fi.PtrTo(int32(3600)),
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy:      corev1.RestartPolicyNever,
+					ServiceAccountName: hook.ServiceAccountName,
+					Containers: []corev1.Container{
+						{
+							Name:    "hook",
+							Image:   hook.Image,
+							Command: hook.Command,
+							Env:     event.environment(),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	created, err := c.K8sClient.BatchV1().Jobs(namespace).Create(ctx, job, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("error creating job: %w", err)
+	}
+	klog.Infof("Waiting for job %s/%s", namespace, created.Name)
+
+	return wait.PollUntilContextCancel(ctx, hookJobPollInterval, true, func(ctx context.Context) (bool, error) {
+		job, err := c.K8sClient.BatchV1().Jobs(namespace).Get(ctx, created.Name, metav1.GetOptions{})
+		if err != nil {
+			klog.Warningf("error getting job %s/%s: %v", namespace, created.Name, err)
+			return false, nil
+		}
+		for _, condition := range job.Status.Conditions {
+			if condition.Status != corev1.ConditionTrue {
+				continue
+			}
+			switch condition.Type {
+			case batchv1.JobComplete:
+				return true, nil
+			case batchv1.JobFailed:
+				return false, fmt.Errorf("job %s/%s failed: %s", namespace, created.Name, condition.Message)
+			}
+		}
+		return false, nil
+	})
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+)
+
+// hookRecorder is a webhook that records the events it receives.
+type hookRecorder struct {
+	mutex  sync.Mutex
+	events []HookEvent
+	// failStage is a stage for which the webhook returns an error
+	failStage kopsapi.RollingUpdateHookStage
+}
+
+func (h *hookRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	event := HookEvent{}
+	if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.events = append(h.events, event)
+
+	if event.Stage == h.failStage {
+		http.Error(w, "failed", http.StatusInternalServerError)
+	}
+}
+
+func (h *hookRecorder) stages(instanceID string) []kopsapi.RollingUpdateHookStage {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	var stages []kopsapi.RollingUpdateHookStage
+	for _, event := range h.events {
+		if event.InstanceID == instanceID {
+			stages = append(stages, event.Stage)
+		}
+	}
+	return stages
+}
+
+func buildHookTestGroups(c *RollingUpdateCluster, recorder *httptest.Server, failurePolicy kopsapi.RollingUpdateHookFailurePolicy, count int) map[string]*cloudinstances.CloudInstanceGroup {
+	var hooks []kopsapi.RollingUpdateHook
+	for _, stage := range kopsapi.SupportedRollingUpdateHookStages {
+		hooks = append(hooks, kopsapi.RollingUpdateHook{
+			Name:          string(stage),
+			Stage:         stage,
+			FailurePolicy: failurePolicy,
+			HTTP:          &kopsapi.HTTPRollingUpdateHook{URL: recorder.URL},
+		})
+	}
+	c.Cluster.Spec.RollingUpdate = &kopsapi.RollingUpdate{Hooks: hooks}
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, c.Cloud.(awsup.AWSCloud), "node-1", kopsapi.InstanceGroupRoleNode, count, count)
+	return groups
+}
+
+func TestRollingUpdateRunsHooks(t *testing.T) {
+	ctx := context.TODO()
+	c, _ := getTestSetup()
+
+	recorder := &hookRecorder{}
+	server := httptest.NewServer(recorder)
+	defer server.Close()
+
+	groups := buildHookTestGroups(c, server, "", 2)
+	err := c.RollingUpdate(ctx, groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	expected := []kopsapi.RollingUpdateHookStage{
+		kopsapi.RollingUpdateHookStageBeforeDrain,
+		kopsapi.RollingUpdateHookStageAfterDrain,
+		kopsapi.RollingUpdateHookStageAfterValidate,
+	}
+	assert.Equal(t, expected, recorder.stages("node-1a"))
+	assert.Equal(t, expected, recorder.stages("node-1b"))
+	for _, event := range recorder.events {
+		assert.Equal(t, "test.k8s.local", event.ClusterName)
+		assert.Equal(t, "node-1", event.InstanceGroup)
+		assert.Equal(t, event.InstanceID+".local", event.NodeName)
+	}
+}
+
+func TestRollingUpdateRunsHooksConcurrently(t *testing.T) {
+	ctx := context.TODO()
+	c, _ := getTestSetup()
+
+	recorder := &hookRecorder{}
+	server := httptest.NewServer(recorder)
+	defer server.Close()
+
+	groups := buildHookTestGroups(c, server, "", 5)
+	three := intstr.FromInt(3)
+	c.Cluster.Spec.RollingUpdate.MaxUnavailable = &three
+
+	err := c.RollingUpdate(ctx, groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	// Every instance is validated, including those whose drains completed after the last validation in the loop
+	expected := []kopsapi.RollingUpdateHookStage{
+		kopsapi.RollingUpdateHookStageBeforeDrain,
+		kopsapi.RollingUpdateHookStageAfterDrain,
+		kopsapi.RollingUpdateHookStageAfterValidate,
+	}
+	for _, id := range []string{"node-1a", "node-1b", "node-1c", "node-1d", "node-1e"} {
+		assert.Equal(t, expected, recorder.stages(id), "hooks of %s", id)
+	}
+}
+
+func TestRollingUpdateFailingHookStopsUpdate(t *testing.T) {
+	ctx := context.TODO()
+	c, _ := getTestSetup()
+
+	recorder := &hookRecorder{failStage: kopsapi.RollingUpdateHookStageAfterDrain}
+	server := httptest.NewServer(recorder)
+	defer server.Close()
+
+	groups := buildHookTestGroups(c, server, "", 2)
+	err := c.RollingUpdate(ctx, groups, &kopsapi.InstanceGroupList{})
+	assert.ErrorContains(t, err, `AfterDrain hook "AfterDrain" failed`)
+
+	assert.Equal(t, []kopsapi.RollingUpdateHookStage{
+		kopsapi.RollingUpdateHookStageBeforeDrain,
+		kopsapi.RollingUpdateHookStageAfterDrain,
+	}, recorder.stages("node-1a"))
+	assert.Empty(t, recorder.stages("node-1b"), "second instance should not be updated")
+}
+
+func TestRollingUpdateIgnoresFailingHook(t *testing.T) {
+	ctx := context.TODO()
+	c, _ := getTestSetup()
+
+	recorder := &hookRecorder{failStage: kopsapi.RollingUpdateHookStageAfterDrain}
+	server := httptest.NewServer(recorder)
+	defer server.Close()
+
+	groups := buildHookTestGroups(c, server, kopsapi.RollingUpdateHookFailurePolicyIgnore, 2)
+	err := c.RollingUpdate(ctx, groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	assert.Len(t, recorder.stages("node-1a"), 3)
+	assert.Len(t, recorder.stages("node-1b"), 3)
+}
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"k8s.io/kops/upup/pkg/fi"
@@ -105,12 +106,23 @@ func (c *RollingUpdateCluster) rollingUpdateInstanceGroup(ctx context.Context, g
 		return fmt.Errorf("rollingUpdate is missing a k8s client")
 	}
 
+	igName := group.InstanceGroup.ObjectMeta.Name
+	if c.Journal.isGroupComplete(igName) {
+		klog.Infof("Skipping instance group %q, which was completed by the rolling update being resumed", igName)
+		return nil
+	}
+	c.Journal.startGroup(igName)
+	defer func() {
+		c.Journal.finishGroup(igName, err)
+	}()
+
 	noneReady := len(group.Ready) == 0
 	numInstances := len(group.Ready) + len(group.NeedUpdate)
 	update := group.NeedUpdate
 	if c.Force {
 		update = append(update, group.Ready...)
 	}
+	update = c.Journal.filterTerminated(igName, update)
 
 	if len(update) == 0 {
 		return nil
@@ -179,6 +191,25 @@ func (c *RollingUpdateCluster) rollingUpdateInstanceGroup(ctx context.Context, g
 	}
 
 	update = prioritizeUpdate(update)
+	update = c.Journal.orderInstances(igName, update)
+
+	if c.Canary > 0 && !isBastion && *settings.DrainAndTerminate {
+		numCanaries := min(c.Canary, len(update))
+		for _, u := range update[:numCanaries] {
+			c.Journal.recordScheduled(igName, u)
+		}
+		if err := c.updateCanaries(ctx, group, update[:numCanaries], sleepAfterTerminate); err != nil {
+			return err
+		}
+		update = update[numCanaries:]
+		if len(update) == 0 {
+			return nil
+		}
+		noneReady = false
+		if maxSurge > len(update) {
+			maxSurge = len(update)
+		}
+	}
 
 	if maxSurge > 0 && !c.CloudOnly {
 		skippedNodes := 0
@@ -219,9 +250,50 @@ func (c *RollingUpdateCluster) rollingUpdateInstanceGroup(ctx context.Context, g
 
 	terminateChan := make(chan error, maxConcurrency)
 
-	for uIdx, u := range update {
+	// terminated holds the instances that have been replaced since the cluster last validated,
+	// so that we can run their AfterValidate hooks.
+	var terminatedMutex sync.Mutex
+	var terminated []*cloudinstances.CloudInstance
+	runAfterValidateHooks := func() error {
+		terminatedMutex.Lock()
+		validated := terminated
+		terminated = nil
+		terminatedMutex.Unlock()
+
+		for _, m := range validated {
+			if err := c.runHooks(ctx, api.RollingUpdateHookStageAfterValidate, m); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	scheduler := c.scheduler
+	if group.InstanceGroup.Spec.Role == api.InstanceGroupRoleControlPlane || isBastion {
+		scheduler = nil
+	}
+
+	pending := update
+	for uIdx := 0; len(pending) > 0; uIdx++ {
+		var u *cloudinstances.CloudInstance
+		u, pending, err = scheduler.next(ctx, pending)
+		if err != nil {
+			return waitForPendingBeforeReturningError(runningDrains, terminateChan, err)
+		}
+		c.Journal.recordScheduled(igName, u)
+		if err := scheduler.acquire(ctx, u); err != nil {
+			return waitForPendingBeforeReturningError(runningDrains, terminateChan, err)
+		}
+
 		go func(m *cloudinstances.CloudInstance) {
-			terminateChan <- c.drainTerminateAndWait(ctx, m, sleepAfterTerminate)
+			err := c.drainTerminateAndWait(ctx, m, sleepAfterTerminate)
+			scheduler.release(m)
+			if err == nil {
+				terminatedMutex.Lock()
+				terminated = append(terminated, m)
+				terminatedMutex.Unlock()
+			}
+			terminateChan <- err
 		}(u)
 		runningDrains++
 
@@ -242,6 +314,11 @@ func (c *RollingUpdateCluster) rollingUpdateInstanceGroup(ctx context.Context, g
 			return waitForPendingBeforeReturningError(runningDrains, terminateChan, err)
 		}
 
+		err = runAfterValidateHooks()
+		if err != nil {
+			return waitForPendingBeforeReturningError(runningDrains, terminateChan, err)
+		}
+
 		if c.Interactive {
 			nodeName := ""
 			if u.Node != nil {
@@ -274,19 +351,29 @@ func (c *RollingUpdateCluster) rollingUpdateInstanceGroup(ctx context.Context, g
 		}
 	}
 
-	if runningDrains > 0 {
-		for runningDrains > 0 {
-			err = <-terminateChan
-			runningDrains--
-			if err != nil {
-				return waitForPendingBeforeReturningError(runningDrains, terminateChan, err)
-			}
+	for runningDrains > 0 {
+		err = <-terminateChan
+		runningDrains--
+		if err != nil {
+			return waitForPendingBeforeReturningError(runningDrains, terminateChan, err)
 		}
+	}
 
+	// Instances swept up after the last validation, or still draining when the loop ended,
+	// have not been validated yet.
+	terminatedMutex.Lock()
+	unvalidated := len(terminated)
+	terminatedMutex.Unlock()
+	if unvalidated > 0 {
 		err = c.maybeValidate(" after terminating instance", c.ValidateCount, group)
 		if err != nil {
 			return err
 		}
+
+		err = runAfterValidateHooks()
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -422,6 +509,10 @@ func (c *RollingUpdateCluster) drainTerminateAndWait(ctx context.Context, u *clo
 
 	isBastion := u.CloudInstanceGroup.InstanceGroup.IsBastion()
 
+	if err := c.runHooks(ctx, api.RollingUpdateHookStageBeforeDrain, u); err != nil {
+		return err
+	}
+
 	if isBastion {
 		// We don't want to validate for bastions - they aren't part of the cluster
 	} else if c.CloudOnly {
@@ -435,12 +526,18 @@ func (c *RollingUpdateCluster) drainTerminateAndWait(ctx context.Context, u *clo
 					return fmt.Errorf("failed to drain node %q: %v", nodeName, err)
 				}
 				klog.Infof("Ignoring error draining node %q: %v", nodeName, err)
+			} else {
+				c.Journal.recordDrained(u)
 			}
 		} else {
 			klog.Warningf("Skipping drain of instance %q, because it is not registered in kubernetes", instanceID)
 		}
 	}
 
+	if err := c.runHooks(ctx, api.RollingUpdateHookStageAfterDrain, u); err != nil {
+		return err
+	}
+
 	// GCE often re-uses names, so we delete the node object to prevent the new instance from using the cordoned Node object
 	// Scaleway has the same behavior
 	if (c.Cluster.GetCloudProvider() == api.CloudProviderGCE || c.Cluster.GetCloudProvider() == api.CloudProviderScaleway) &&
@@ -459,6 +556,7 @@ func (c *RollingUpdateCluster) drainTerminateAndWait(ctx context.Context, u *clo
 		klog.Errorf("error deleting instance %q, node %q: %v", instanceID, nodeName, err)
 		return err
 	}
+	c.Journal.recordTerminated(u)
 
 	if err := c.reconcileInstanceGroup(ctx); err != nil {
 		klog.Errorf("error reconciling instance group %q: %v", u.CloudInstanceGroup.HumanName, err)
@@ -507,7 +605,9 @@ func (c *RollingUpdateCluster) maybeValidate(operation string, validateCount int
 	} else {
 		klog.Info("Validating the cluster.")
 
-		if err := c.validateClusterWithTimeout(validateCount, group); err != nil {
+		err := c.validateClusterWithTimeout(validateCount, group)
+		c.Journal.recordValidation(group.InstanceGroup.ObjectMeta.Name, strings.TrimSpace(operation), err)
+		if err != nil {
 
 			if c.FailOnValidate {
 				klog.Errorf("Cluster did not validate within %s", c.ValidationTimeout)
@@ -617,6 +717,7 @@ func (c *RollingUpdateCluster) detachInstance(u *cloudinstances.CloudInstance) e
 		}
 		return fmt.Errorf("error detaching instance %q: %v", id, err)
 	}
+	c.Journal.recordDetached(u)
 
 	return nil
 }
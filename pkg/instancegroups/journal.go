@@ -0,0 +1,537 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/acls"
+	api "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/registry"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// RollingUpdateStatus is the status of a rolling update, or of an instance group within a rolling update.
+type RollingUpdateStatus string
+
+const (
+	RollingUpdateStatusPending    RollingUpdateStatus = "Pending"
+	RollingUpdateStatusInProgress RollingUpdateStatus = "InProgress"
+	RollingUpdateStatusComplete   RollingUpdateStatus = "Complete"
+	RollingUpdateStatusFailed     RollingUpdateStatus = "Failed"
+	// RollingUpdateStatusRolledBack is the status of an instance group whose canary failed, and which was rolled back
+	// to the specification of its instances that needed update.
+	RollingUpdateStatusRolledBack RollingUpdateStatus = "RolledBack"
+)
+
+// RollingUpdateProgress is the progress of a rolling update, as recorded in the state store.
+type RollingUpdateProgress struct {
+	// ClusterName is the name of the cluster being updated.
+	ClusterName string `json:"clusterName"`
+	// Host is the hostname of the machine that last ran the rolling update.
+	Host string `json:"host,omitempty"`
+	// StartTime is when the rolling update was first started.
+	StartTime time.Time `json:"startTime"`
+	// UpdateTime is when the progress was last recorded.
+	UpdateTime time.Time `json:"updateTime"`
+	// Status is the status of the rolling update as a whole.
+	Status RollingUpdateStatus `json:"status"`
+	// Error is the error that stopped the rolling update, if any.
+	Error string `json:"error,omitempty"`
+	// Force is true if all instances are being replaced, even if they do not need updating.
+	Force bool `json:"force,omitempty"`
+	// Options are the options the rolling update was started with, which a resumed rolling update continues with.
+	Options *RecordedOptions `json:"options,omitempty"`
+	// InstanceGroups holds the progress of each instance group, in the order in which they are updated.
+	InstanceGroups []*InstanceGroupProgress `json:"instanceGroups,omitempty"`
+}
+
+// RecordedOptions are the options of a rolling update that are recorded with its progress,
+// so that an interrupted rolling update is resumed with the options it was started with.
+type RecordedOptions struct {
+	CloudOnly            bool            `json:"cloudOnly,omitempty"`
+	FailOnDrainError     bool            `json:"failOnDrainError,omitempty"`
+	FailOnValidate       bool            `json:"failOnValidate,omitempty"`
+	PostDrainDelay       metav1.Duration `json:"postDrainDelay"`
+	ValidationTimeout    metav1.Duration `json:"validationTimeout"`
+	ValidateCount        int32           `json:"validateCount"`
+	DrainTimeout         metav1.Duration `json:"drainTimeout"`
+	ControlPlaneInterval metav1.Duration `json:"controlPlaneInterval"`
+	NodeInterval         metav1.Duration `json:"nodeInterval"`
+	BastionInterval      metav1.Duration `json:"bastionInterval"`
+	Canary               int             `json:"canary,omitempty"`
+	CanarySoakPeriod     metav1.Duration `json:"canarySoakPeriod,omitempty"`
+	CanaryProbes         []string        `json:"canaryProbes,omitempty"`
+	TopologyAware        bool            `json:"topologyAware,omitempty"`
+}
+
+// InstanceGroupProgress is the progress of the rolling update of a single instance group.
+type InstanceGroupProgress struct {
+	// Name is the name of the instance group.
+	Name string `json:"name"`
+	// Status is the status of the rolling update of this instance group.
+	Status RollingUpdateStatus `json:"status"`
+	// Error is the error that stopped the rolling update of this instance group, if any.
+	Error string `json:"error,omitempty"`
+	// Order is the IDs of the instances to be replaced, in the order in which they are replaced.
+	Order []string `json:"order,omitempty"`
+	// Detached is the IDs of the instances that have been detached, so that a replacement could surge.
+	Detached []string `json:"detached,omitempty"`
+	// Drained is the IDs of the instances whose nodes have been drained.
+	Drained []string `json:"drained,omitempty"`
+	// Terminated is the IDs of the instances that have been terminated.
+	Terminated []string `json:"terminated,omitempty"`
+	// Validations are the results of cluster validation during the update of this instance group.
+	Validations []*ValidationProgress `json:"validations,omitempty"`
+	// RollbackAcknowledged is true if the rollback of the instance group was acknowledged,
+	// allowing "kops update cluster" to apply the specification that failed its canary again.
+	RollbackAcknowledged bool `json:"rollbackAcknowledged,omitempty"`
+}
+
+// ValidationProgress is the result of a single cluster validation during a rolling update.
+type ValidationProgress struct {
+	Time      time.Time `json:"time"`
+	Operation string    `json:"operation,omitempty"`
+	Succeeded bool      `json:"succeeded"`
+	Message   string    `json:"message,omitempty"`
+}
+
+// InstanceGroup returns the progress of the named instance group, or nil if it is not part of the rolling update.
+func (p *RollingUpdateProgress) InstanceGroup(name string) *InstanceGroupProgress {
+	for _, ig := range p.InstanceGroups {
+		if ig.Name == name {
+			return ig
+		}
+	}
+	return nil
+}
+
+// InstanceGroupNames returns the names of the instance groups that are part of the rolling update.
+func (p *RollingUpdateProgress) InstanceGroupNames() []string {
+	var names []string
+	for _, ig := range p.InstanceGroups {
+		names = append(names, ig.Name)
+	}
+	return names
+}
+
+// UnacknowledgedRollbacks returns the instance groups that were rolled back after their canary failed,
+// and whose rollback has not been acknowledged. It returns nil on a nil RollingUpdateProgress.
+func (p *RollingUpdateProgress) UnacknowledgedRollbacks() []*InstanceGroupProgress {
+	if p == nil {
+		return nil
+	}
+	var rolledBack []*InstanceGroupProgress
+	for _, ig := range p.InstanceGroups {
+		if ig.rollbackPending() {
+			rolledBack = append(rolledBack, ig)
+		}
+	}
+	return rolledBack
+}
+
+// rollbackPending returns true if the instance group was rolled back and the rollback has not been acknowledged.
+func (ig *InstanceGroupProgress) rollbackPending() bool {
+	return ig.Status == RollingUpdateStatusRolledBack && !ig.RollbackAcknowledged
+}
+
+// AcknowledgeRollbacks records that the rollbacks of the instance groups have been acknowledged.
+func AcknowledgeRollbacks(ctx context.Context, p vfs.Path, cluster *api.Cluster, progress *RollingUpdateProgress) error {
+	for _, ig := range progress.UnacknowledgedRollbacks() {
+		ig.RollbackAcknowledged = true
+	}
+
+	data, err := json.MarshalIndent(progress, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error serializing rolling update progress: %w", err)
+	}
+	acl, err := acls.GetACL(ctx, p, cluster)
+	if err != nil {
+		return err
+	}
+	if err := p.WriteFile(ctx, bytes.NewReader(data), acl); err != nil {
+		return fmt.Errorf("error writing rolling update progress to %s: %w", p, err)
+	}
+	return nil
+}
+
+// RollingUpdateJournalPath returns the path in the state store at which rolling update progress is recorded.
+func RollingUpdateJournalPath(vfsContext *vfs.VFSContext, cluster *api.Cluster) (vfs.Path, error) {
+	configBase, err := registry.ConfigBase(vfsContext, cluster)
+	if err != nil {
+		return nil, err
+	}
+	return configBase.Join(registry.PathRollingUpdateJournal), nil
+}
+
+// ReadRollingUpdateProgress reads the progress recorded at the specified path; it returns (nil, nil) if there is none.
+func ReadRollingUpdateProgress(ctx context.Context, p vfs.Path) (*RollingUpdateProgress, error) {
+	data, err := p.ReadFile(ctx)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading rolling update progress from %s: %w", p, err)
+	}
+	progress := &RollingUpdateProgress{}
+	if err := json.Unmarshal(data, progress); err != nil {
+		return nil, fmt.Errorf("error parsing rolling update progress from %s: %w", p, err)
+	}
+	return progress, nil
+}
+
+// Journal records the progress of a rolling update in the state store,
+// so that an interrupted rolling update can be resumed, and inspected from any machine.
+// A nil Journal records nothing.
+type Journal struct {
+	path    vfs.Path
+	cluster *api.Cluster
+
+	// resumed is true if we are continuing a previous rolling update.
+	resumed bool
+
+	mutex    sync.Mutex
+	progress *RollingUpdateProgress
+	// scheduled is the number of instances of each instance group that have been chosen for replacement.
+	scheduled map[string]int
+}
+
+// NewJournal builds a Journal for a new rolling update; any previously recorded progress will be overwritten.
+func NewJournal(p vfs.Path, cluster *api.Cluster, force bool, options *RecordedOptions) *Journal {
+	return &Journal{
+		path:    p,
+		cluster: cluster,
+		progress: &RollingUpdateProgress{
+			ClusterName: cluster.ObjectMeta.Name,
+			StartTime:   time.Now().UTC(),
+			Status:      RollingUpdateStatusPending,
+			Force:       force,
+			Options:     options,
+		},
+		scheduled: make(map[string]int),
+	}
+}
+
+// RetainRollbacks keeps the unacknowledged rollbacks recorded by a previous rolling update,
+// so that a new rolling update does not allow "kops update cluster" to apply the specification that failed its canary again.
+func (j *Journal) RetainRollbacks(previous *RollingUpdateProgress) {
+	for _, ig := range previous.UnacknowledgedRollbacks() {
+		j.progress.InstanceGroups = append(j.progress.InstanceGroups, &InstanceGroupProgress{
+			Name:   ig.Name,
+			Status: RollingUpdateStatusRolledBack,
+			Error:  ig.Error,
+		})
+	}
+}
+
+// ResumeJournal builds a Journal that continues the rolling update with the specified progress.
+func ResumeJournal(p vfs.Path, cluster *api.Cluster, progress *RollingUpdateProgress) *Journal {
+	return &Journal{
+		path:      p,
+		cluster:   cluster,
+		resumed:   true,
+		progress:  progress,
+		scheduled: make(map[string]int),
+	}
+}
+
+// start records that the rolling update has started, with the instance groups in the order they will be updated.
+func (j *Journal) start(instanceGroupNames []string) {
+	if j == nil {
+		return
+	}
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	for _, name := range instanceGroupNames {
+		if j.progress.InstanceGroup(name) == nil {
+			j.progress.InstanceGroups = append(j.progress.InstanceGroups, &InstanceGroupProgress{
+				Name:   name,
+				Status: RollingUpdateStatusPending,
+			})
+		}
+	}
+	j.progress.Status = RollingUpdateStatusInProgress
+	j.progress.Error = ""
+	j.save()
+}
+
+// finish records the result of the rolling update.
+func (j *Journal) finish(err error) {
+	if j == nil {
+		return
+	}
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	if err != nil {
+		j.progress.Status = RollingUpdateStatusFailed
+		j.progress.Error = err.Error()
+	} else {
+		j.progress.Status = RollingUpdateStatusComplete
+	}
+	j.save()
+}
+
+// isGroupComplete returns true if the instance group was completed by the rolling update we are resuming.
+func (j *Journal) isGroupComplete(name string) bool {
+	if j == nil || !j.resumed {
+		return false
+	}
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	ig := j.progress.InstanceGroup(name)
+	return ig != nil && ig.Status == RollingUpdateStatusComplete
+}
+
+// startGroup records that the rolling update of an instance group has started.
+// An instance group whose rollback has not been acknowledged keeps that status.
+func (j *Journal) startGroup(name string) {
+	j.updateGroup(name, func(ig *InstanceGroupProgress) {
+		if ig.rollbackPending() {
+			return
+		}
+		ig.Status = RollingUpdateStatusInProgress
+		ig.Error = ""
+	})
+}
+
+// finishGroup records the result of the rolling update of an instance group.
+// An instance group whose rollback has not been acknowledged keeps that status.
+func (j *Journal) finishGroup(name string, err error) {
+	j.updateGroup(name, func(ig *InstanceGroupProgress) {
+		if err != nil {
+			if !ig.rollbackPending() {
+				ig.Status = RollingUpdateStatusFailed
+			}
+			ig.Error = err.Error()
+		} else if !ig.rollbackPending() {
+			ig.Status = RollingUpdateStatusComplete
+		}
+	})
+}
+
+// recordRollback records that an instance group was rolled back after its canary failed.
+func (j *Journal) recordRollback(name string, cause error) {
+	j.updateGroup(name, func(ig *InstanceGroupProgress) {
+		ig.Status = RollingUpdateStatusRolledBack
+		ig.Error = cause.Error()
+		ig.RollbackAcknowledged = false
+	})
+}
+
+// filterTerminated removes the instances that were terminated by the rolling update we are resuming;
+// the cloud may still report them while they shut down.
+func (j *Journal) filterTerminated(name string, update []*cloudinstances.CloudInstance) []*cloudinstances.CloudInstance {
+	if j == nil || !j.resumed {
+		return update
+	}
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	ig := j.progress.InstanceGroup(name)
+	if ig == nil {
+		return update
+	}
+
+	var filtered []*cloudinstances.CloudInstance
+	for _, u := range update {
+		if slices.Contains(ig.Terminated, u.ID) {
+			klog.Infof("Skipping instance %q, which was terminated by the rolling update being resumed", u.ID)
+			continue
+		}
+		filtered = append(filtered, u)
+	}
+	return filtered
+}
+
+// orderInstances returns the instances in the order they should be replaced, and records that order.
+// When resuming, instances keep the position they were given by the previous run,
+// so that instances which were already detached or drained are replaced first.
+func (j *Journal) orderInstances(name string, update []*cloudinstances.CloudInstance) []*cloudinstances.CloudInstance {
+	if j == nil {
+		return update
+	}
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	ig := j.progress.InstanceGroup(name)
+	if ig == nil {
+		return update
+	}
+
+	if j.resumed {
+		position := make(map[string]int)
+		for i, id := range ig.Order {
+			position[id] = i
+		}
+		update = slices.Clone(update)
+		slices.SortStableFunc(update, func(a, b *cloudinstances.CloudInstance) int {
+			posA, okA := position[a.ID]
+			posB, okB := position[b.ID]
+			switch {
+			case okA && okB:
+				return posA - posB
+			case okA:
+				return -1
+			case okB:
+				return 1
+			default:
+				return 0
+			}
+		})
+	}
+
+	ig.Order = nil
+	for _, u := range update {
+		ig.Order = append(ig.Order, u.ID)
+	}
+	j.scheduled[name] = 0
+	j.save()
+
+	return update
+}
+
+// recordScheduled records that an instance is the next to be replaced, moving it after the instances
+// already chosen, so that the recorded order is the one the scheduler produces.
+func (j *Journal) recordScheduled(name string, u *cloudinstances.CloudInstance) {
+	if j == nil {
+		return
+	}
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	ig := j.progress.InstanceGroup(name)
+	if ig == nil {
+		return
+	}
+
+	position := min(j.scheduled[name], len(ig.Order))
+	j.scheduled[name] = position + 1
+	current := slices.Index(ig.Order, u.ID)
+	if current == position {
+		return
+	}
+	if current >= 0 {
+		ig.Order = slices.Delete(ig.Order, current, current+1)
+	}
+	ig.Order = slices.Insert(ig.Order, min(position, len(ig.Order)), u.ID)
+	j.save()
+}
+
+// recordDetached records that an instance has been detached.
+func (j *Journal) recordDetached(u *cloudinstances.CloudInstance) {
+	j.updateGroup(instanceGroupName(u), func(ig *InstanceGroupProgress) {
+		ig.Detached = appendUnique(ig.Detached, u.ID)
+	})
+}
+
+// recordDrained records that the node of an instance has been drained.
+func (j *Journal) recordDrained(u *cloudinstances.CloudInstance) {
+	j.updateGroup(instanceGroupName(u), func(ig *InstanceGroupProgress) {
+		ig.Drained = appendUnique(ig.Drained, u.ID)
+	})
+}
+
+// recordTerminated records that an instance has been terminated.
+func (j *Journal) recordTerminated(u *cloudinstances.CloudInstance) {
+	j.updateGroup(instanceGroupName(u), func(ig *InstanceGroupProgress) {
+		ig.Terminated = appendUnique(ig.Terminated, u.ID)
+	})
+}
+
+// recordValidation records the result of a cluster validation.
+func (j *Journal) recordValidation(name string, operation string, err error) {
+	j.updateGroup(name, func(ig *InstanceGroupProgress) {
+		v := &ValidationProgress{
+			Time:      time.Now().UTC(),
+			Operation: operation,
+			Succeeded: err == nil,
+		}
+		if err != nil {
+			v.Message = err.Error()
+		}
+		ig.Validations = append(ig.Validations, v)
+	})
+}
+
+func (j *Journal) updateGroup(name string, fn func(ig *InstanceGroupProgress)) {
+	if j == nil {
+		return
+	}
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	ig := j.progress.InstanceGroup(name)
+	if ig == nil {
+		return
+	}
+	fn(ig)
+	j.save()
+}
+
+// save writes the progress to the state store; it must be called with the mutex held.
+// Failures are logged but do not stop the rolling update.
+func (j *Journal) save() {
+	ctx := context.TODO()
+
+	if hostname, err := os.Hostname(); err == nil {
+		j.progress.Host = hostname
+	}
+	j.progress.UpdateTime = time.Now().UTC()
+
+	data, err := json.MarshalIndent(j.progress, "", "  ")
+	if err != nil {
+		klog.Warningf("error serializing rolling update progress: %v", err)
+		return
+	}
+
+	acl, err := acls.GetACL(ctx, j.path, j.cluster)
+	if err != nil {
+		klog.Warningf("error getting ACL for %s: %v", j.path, err)
+		return
+	}
+
+	if err := j.path.WriteFile(ctx, bytes.NewReader(data), acl); err != nil {
+		klog.Warningf("error recording rolling update progress to %s: %v", j.path, err)
+	}
+}
+
+func instanceGroupName(u *cloudinstances.CloudInstance) string {
+	if u.CloudInstanceGroup == nil || u.CloudInstanceGroup.InstanceGroup == nil {
+		return ""
+	}
+	return u.CloudInstanceGroup.InstanceGroup.ObjectMeta.Name
+}
+
+func appendUnique(ids []string, id string) []string {
+	if slices.Contains(ids, id) {
+		return ids
+	}
+	return append(ids, id)
+}
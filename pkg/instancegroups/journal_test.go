@@ -0,0 +1,168 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/autoscaling"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func TestRollingUpdateRecordsJournal(t *testing.T) {
+	ctx := context.TODO()
+	c, cloud := getTestSetup()
+
+	p := vfs.NewMemFSPath(vfs.NewMemFSContext(), "rolling-update/journal.json")
+	options := &RecordedOptions{FailOnValidate: true, ValidateCount: 2, TopologyAware: true}
+	c.Journal = NewJournal(p, c.Cluster, false, options)
+
+	groups := getGroupsAllNeedUpdate(c.K8sClient, cloud)
+	err := c.RollingUpdate(ctx, groups, &kopsapi.InstanceGroupList{})
+	require.NoError(t, err, "rolling update")
+
+	progress, err := ReadRollingUpdateProgress(ctx, p)
+	require.NoError(t, err, "reading progress")
+	require.NotNil(t, progress)
+
+	assert.Equal(t, RollingUpdateStatusComplete, progress.Status)
+	assert.Equal(t, options, progress.Options)
+	assert.Equal(t, []string{"bastion-1", "master-1", "node-1", "node-2"}, progress.InstanceGroupNames())
+	for _, ig := range progress.InstanceGroups {
+		assert.Equal(t, RollingUpdateStatusComplete, ig.Status, "instance group %s", ig.Name)
+		assert.ElementsMatch(t, ig.Order, ig.Terminated, "instance group %s", ig.Name)
+	}
+	assert.ElementsMatch(t, []string{"node-1a", "node-1b", "node-1c"}, progress.InstanceGroup("node-1").Drained)
+	assert.NotEmpty(t, progress.InstanceGroup("node-1").Validations)
+	// The cluster is not validated before updating a bastion, only after terminating its instance
+	bastionValidations := progress.InstanceGroup("bastion-1").Validations
+	require.Len(t, bastionValidations, 1)
+	assert.Equal(t, "after terminating instance", bastionValidations[0].Operation)
+	assert.True(t, bastionValidations[0].Succeeded)
+}
+
+func TestRollingUpdateResumeJournal(t *testing.T) {
+	ctx := context.TODO()
+	c, cloud := getTestSetup()
+
+	p := vfs.NewMemFSPath(vfs.NewMemFSContext(), "rolling-update/journal.json")
+	c.Journal = ResumeJournal(p, c.Cluster, &RollingUpdateProgress{
+		ClusterName: c.Cluster.Name,
+		Status:      RollingUpdateStatusFailed,
+		InstanceGroups: []*InstanceGroupProgress{
+			{Name: "bastion-1", Status: RollingUpdateStatusComplete},
+			{Name: "master-1", Status: RollingUpdateStatusComplete},
+			{Name: "node-1", Status: RollingUpdateStatusInProgress, Order: []string{"node-1a", "node-1b", "node-1c"}, Terminated: []string{"node-1a"}},
+			{Name: "node-2", Status: RollingUpdateStatusPending},
+		},
+	})
+
+	groups := getGroupsAllNeedUpdate(c.K8sClient, cloud)
+	err := c.RollingUpdate(ctx, groups, &kopsapi.InstanceGroupList{})
+	require.NoError(t, err, "rolling update")
+
+	remaining := map[string]int{}
+	asgGroups, _ := cloud.Autoscaling().DescribeAutoScalingGroups(ctx, &autoscaling.DescribeAutoScalingGroupsInput{})
+	for _, group := range asgGroups.AutoScalingGroups {
+		remaining[aws.ToString(group.AutoScalingGroupName)] = len(group.Instances)
+	}
+	assert.Equal(t, 1, remaining["bastion-1"], "completed group bastion-1 should not be updated")
+	assert.Equal(t, 2, remaining["master-1"], "completed group master-1 should not be updated")
+	assert.Equal(t, 1, remaining["node-1"], "terminated instance node-1a should be skipped")
+	assert.Equal(t, 0, remaining["node-2"], "pending group node-2 should be updated")
+
+	progress, err := ReadRollingUpdateProgress(ctx, p)
+	require.NoError(t, err, "reading progress")
+	assert.Equal(t, RollingUpdateStatusComplete, progress.Status)
+	assert.Equal(t, []string{"node-1a", "node-1b", "node-1c"}, progress.InstanceGroup("node-1").Terminated)
+	assert.Equal(t, RollingUpdateStatusComplete, progress.InstanceGroup("node-2").Status)
+}
+
+func TestJournalRollbacks(t *testing.T) {
+	ctx := context.TODO()
+	c, _ := getTestSetup()
+
+	p := vfs.NewMemFSPath(vfs.NewMemFSContext(), "rolling-update/journal.json")
+	j := NewJournal(p, c.Cluster, false, nil)
+	j.start([]string{"node-1", "node-2"})
+	j.startGroup("node-1")
+	j.recordRollback("node-1", errors.New("canary failed"))
+	j.finishGroup("node-1", errors.New("canary of instance group node-1 failed"))
+	j.startGroup("node-2")
+	j.finishGroup("node-2", nil)
+	j.finish(errors.New("canary of instance group node-1 failed"))
+
+	progress, err := ReadRollingUpdateProgress(ctx, p)
+	require.NoError(t, err, "reading progress")
+	rolledBack := progress.UnacknowledgedRollbacks()
+	require.Len(t, rolledBack, 1)
+	assert.Equal(t, "node-1", rolledBack[0].Name)
+	assert.Equal(t, RollingUpdateStatusComplete, progress.InstanceGroup("node-2").Status)
+
+	// A new rolling update keeps the rollback until it is acknowledged
+	j = NewJournal(p, c.Cluster, false, nil)
+	j.RetainRollbacks(progress)
+	j.start([]string{"node-1"})
+	j.startGroup("node-1")
+	j.finishGroup("node-1", nil)
+	j.finish(nil)
+
+	progress, err = ReadRollingUpdateProgress(ctx, p)
+	require.NoError(t, err, "reading progress")
+	require.Len(t, progress.UnacknowledgedRollbacks(), 1)
+	assert.Equal(t, RollingUpdateStatusRolledBack, progress.InstanceGroup("node-1").Status)
+
+	require.NoError(t, AcknowledgeRollbacks(ctx, p, c.Cluster, progress), "acknowledging rollbacks")
+	progress, err = ReadRollingUpdateProgress(ctx, p)
+	require.NoError(t, err, "reading progress")
+	assert.Empty(t, progress.UnacknowledgedRollbacks())
+	assert.True(t, progress.InstanceGroup("node-1").RollbackAcknowledged)
+
+	var nilProgress *RollingUpdateProgress
+	assert.Empty(t, nilProgress.UnacknowledgedRollbacks())
+}
+
+func TestJournalRecordsScheduledOrder(t *testing.T) {
+	ctx := context.TODO()
+	c, _ := getTestSetup()
+
+	group := &cloudinstances.CloudInstanceGroup{HumanName: "node-1", InstanceGroup: &kopsapi.InstanceGroup{}}
+	var update []*cloudinstances.CloudInstance
+	for _, id := range []string{"a", "b", "c"} {
+		u, _ := group.NewCloudInstance(id, cloudinstances.CloudInstanceStatusNeedsUpdate, nil)
+		update = append(update, u)
+	}
+
+	p := vfs.NewMemFSPath(vfs.NewMemFSContext(), "rolling-update/journal.json")
+	j := NewJournal(p, c.Cluster, false, nil)
+	j.start([]string{"node-1"})
+	j.orderInstances("node-1", update)
+	j.recordScheduled("node-1", update[2])
+	j.recordScheduled("node-1", update[0])
+	j.recordScheduled("node-1", update[1])
+
+	progress, err := ReadRollingUpdateProgress(ctx, p)
+	require.NoError(t, err, "reading progress")
+	assert.Equal(t, []string{"c", "a", "b"}, progress.InstanceGroup("node-1").Order)
+}
@@ -84,14 +84,38 @@ type RollingUpdateCluster struct {
 	// DrainTimeout is the maximum amount of time to wait while draining a node.
 	DrainTimeout time.Duration
 
+	// Canary is the number of instances in each instance group to replace before the rest of the group.
+	// If the cluster does not validate or a canary probe fails before the end of CanarySoakPeriod,
+	// the instance group is rolled back to its previous specification and the canary instances are replaced.
+	Canary int
+
+	// CanarySoakPeriod is the amount of time the cluster must keep validating after the canary instances are replaced
+	CanarySoakPeriod time.Duration
+
+	// CanaryProbes are shell commands that are run repeatedly during the canary soak period; if one exits
+	// with a non-zero status, the canary fails.
+	CanaryProbes []string
+
 	// Options holds user-specified options
 	Options RollingUpdateOptions
+
+	// Journal records progress in the state store, so that an interrupted rolling update can be resumed.
+	// If nil, progress is not recorded.
+	Journal *Journal
+
+	// scheduler orders the replacement of instances if Options.TopologyAware is set
+	scheduler *scheduler
 }
 
 type RollingUpdateOptions struct {
 	// DeregisterControlPlaneNodes controls if we deregister control plane instances from load balacners etc before draining/terminating.
 	// When a cluster only has a single apiserver, we don't want to do this, as we can't drain after deregistering it.
 	DeregisterControlPlaneNodes bool
+
+	// TopologyAware orders the replacement of nodes using PodDisruptionBudgets and zones.
+	// Nodes hosting the fewest pods covered by PodDisruptionBudgets are replaced first, nodes whose
+	// PodDisruptionBudgets allow no disruptions are deferred, and two nodes in the same zone are not drained at once.
+	TopologyAware bool
 }
 
 func (o *RollingUpdateOptions) InitDefaults() {
@@ -113,6 +137,18 @@ func (c *RollingUpdateCluster) RollingUpdate(ctx context.Context, groups map[str
 		return nil
 	}
 
+	if c.Options.TopologyAware && !c.CloudOnly && c.K8sClient != nil {
+		c.scheduler = newScheduler(c.K8sClient, c.DrainTimeout, c.ValidateTickDuration)
+	}
+
+	c.Journal.start(updateOrder(groups))
+	err := c.rollingUpdate(ctx, groups)
+	c.Journal.finish(err)
+	return err
+}
+
+func (c *RollingUpdateCluster) rollingUpdate(ctx context.Context, groups map[string]*cloudinstances.CloudInstanceGroup) error {
+
 	var resultsMutex sync.Mutex
 	results := make(map[string]error)
 
@@ -212,7 +248,7 @@ func (c *RollingUpdateCluster) RollingUpdate(ctx context.Context, groups map[str
 			results[k] = fmt.Errorf("function panic nodes")
 		}
 
-		for _, k := range sortGroups(nodeGroups) {
+		for _, k := range c.scheduler.orderGroups(ctx, nodeGroups) {
 			err := c.rollingUpdateInstanceGroup(ctx, nodeGroups[k], c.NodeInterval)
 			results[k] = err
 			if err != nil {
@@ -237,6 +273,19 @@ func (c *RollingUpdateCluster) RollingUpdate(ctx context.Context, groups map[str
 	return errors.NewAggregate(errs)
 }
 
+// updateOrder returns the names of the instance groups, in the order in which they are updated.
+func updateOrder(groups map[string]*cloudinstances.CloudInstanceGroup) []string {
+	var names []string
+	for _, role := range []api.InstanceGroupRole{api.InstanceGroupRoleBastion, api.InstanceGroupRoleControlPlane, api.InstanceGroupRoleAPIServer, api.InstanceGroupRoleNode} {
+		for _, k := range sortGroups(groups) {
+			if group := groups[k]; group.InstanceGroup.Spec.Role == role {
+				names = append(names, group.InstanceGroup.ObjectMeta.Name)
+			}
+		}
+	}
+	return names
+}
+
 func sortGroups(groupMap map[string]*cloudinstances.CloudInstanceGroup) []string {
 	groups := make([]string, 0, len(groupMap))
 	for group := range groupMap {
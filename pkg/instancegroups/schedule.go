@@ -0,0 +1,303 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"k8s.io/kops/pkg/cloudinstances"
+)
+
+// scheduler chooses the order in which instances are replaced when RollingUpdateOptions.TopologyAware is set.
+// It is shared by all the instance groups of a rolling update, so that it can order the node groups
+// and prevent concurrent drains in the same zone.
+// A nil scheduler replaces instances in the order they were prioritized.
+// It is not used for control-plane and bastion groups, which are not drained for PodDisruptionBudgets
+// and must not wait for nodes being drained in their zone.
+type scheduler struct {
+	k8sClient kubernetes.Interface
+
+	// waitTimeout is the maximum time to wait for an instance that can be replaced without
+	// exceeding a PodDisruptionBudget, before replacing one anyway.
+	waitTimeout time.Duration
+	// pollInterval is the time between checks of PodDisruptionBudgets and zones.
+	pollInterval time.Duration
+
+	mutex sync.Mutex
+	// zoneFree is closed, and replaced, whenever a zone stops draining.
+	zoneFree chan struct{}
+	// busyZones holds the zones in which a node is being drained.
+	busyZones map[string]bool
+}
+
+func newScheduler(k8sClient kubernetes.Interface, waitTimeout, pollInterval time.Duration) *scheduler {
+	s := &scheduler{
+		k8sClient:    k8sClient,
+		waitTimeout:  waitTimeout,
+		pollInterval: pollInterval,
+		busyZones:    make(map[string]bool),
+		zoneFree:     make(chan struct{}),
+	}
+	return s
+}
+
+// instanceZone returns the zone of an instance's node, or "" if it is not known.
+func instanceZone(u *cloudinstances.CloudInstance) string {
+	if u.Node == nil {
+		return ""
+	}
+	return u.Node.Labels[corev1.LabelTopologyZone]
+}
+
+// disruptionState is a snapshot of the pods and PodDisruptionBudgets in the cluster.
+type disruptionState struct {
+	podsByNode map[string][]*corev1.Pod
+	pdbs       []policyv1.PodDisruptionBudget
+	selectors  []labels.Selector
+}
+
+// observe lists the pods and PodDisruptionBudgets in the cluster.
+func (s *scheduler) observe(ctx context.Context) (*disruptionState, error) {
+	pdbs, err := s.k8sClient.PolicyV1().PodDisruptionBudgets("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error listing PodDisruptionBudgets: %w", err)
+	}
+	pods, err := s.k8sClient.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error listing pods: %w", err)
+	}
+
+	state := &disruptionState{
+		podsByNode: make(map[string][]*corev1.Pod),
+	}
+	for i := range pdbs.Items {
+		pdb := pdbs.Items[i]
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil {
+			klog.Warningf("ignoring PodDisruptionBudget %s/%s with invalid selector: %v", pdb.Namespace, pdb.Name, err)
+			continue
+		}
+		state.pdbs = append(state.pdbs, pdb)
+		state.selectors = append(state.selectors, selector)
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Spec.NodeName == "" || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		// Drain does not evict DaemonSet pods
+		if owner := metav1.GetControllerOf(pod); owner != nil && owner.Kind == "DaemonSet" {
+			continue
+		}
+		state.podsByNode[pod.Spec.NodeName] = append(state.podsByNode[pod.Spec.NodeName], pod)
+	}
+	return state, nil
+}
+
+// assess returns the number of pods on the instance's node that are covered by a PodDisruptionBudget,
+// and whether any of those PodDisruptionBudgets currently allows no disruptions.
+func (d *disruptionState) assess(u *cloudinstances.CloudInstance) (sensitive int, blocked bool) {
+	if u.Node == nil {
+		return 0, false
+	}
+	for _, pod := range d.podsByNode[u.Node.Name] {
+		covered := false
+		for i := range d.pdbs {
+			pdb := &d.pdbs[i]
+			if pdb.Namespace != pod.Namespace || !d.selectors[i].Matches(labels.Set(pod.Labels)) {
+				continue
+			}
+			covered = true
+			if pdb.Status.DisruptionsAllowed < 1 {
+				blocked = true
+			}
+		}
+		if covered {
+			sensitive++
+		}
+	}
+	return sensitive, blocked
+}
+
+// next removes and returns the next instance to replace from pending.
+// It prefers instances whose node hosts the fewest pods covered by PodDisruptionBudgets, skipping those
+// whose PodDisruptionBudgets allow no disruptions or whose zone is being drained. If no instance can be
+// chosen within waitTimeout, it returns the best instance regardless of PodDisruptionBudgets.
+// It returns an error if the context is cancelled while waiting.
+func (s *scheduler) next(ctx context.Context, pending []*cloudinstances.CloudInstance) (*cloudinstances.CloudInstance, []*cloudinstances.CloudInstance, error) {
+	if s == nil {
+		return pending[0], pending[1:], nil
+	}
+
+	deadline := time.Now().Add(s.waitTimeout)
+	for {
+		state, err := s.observe(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, pending, fmt.Errorf("choosing the next instance to replace: %w", ctx.Err())
+			}
+			klog.Warningf("unable to check PodDisruptionBudgets, replacing instances in order: %v", err)
+			return pending[0], pending[1:], nil
+		}
+
+		candidates := s.rank(state, pending)
+		for _, candidate := range candidates {
+			if !candidate.blocked && !s.isZoneBusy(instanceZone(candidate.instance)) {
+				return candidate.instance, remove(pending, candidate.instance), nil
+			}
+		}
+
+		if time.Now().After(deadline) {
+			chosen := candidates[0].instance
+			for _, candidate := range candidates {
+				if !s.isZoneBusy(instanceZone(candidate.instance)) {
+					chosen = candidate.instance
+					break
+				}
+			}
+			klog.Warningf("no instance can be replaced without exceeding a PodDisruptionBudget after %s; replacing %q", s.waitTimeout, chosen.ID)
+			return chosen, remove(pending, chosen), nil
+		}
+
+		klog.Infof("Waiting for PodDisruptionBudget headroom or a zone that is not being drained, will retry in %s", s.pollInterval)
+		select {
+		case <-ctx.Done():
+			return nil, pending, fmt.Errorf("choosing the next instance to replace: %w", ctx.Err())
+		case <-time.After(s.pollInterval):
+		}
+	}
+}
+
+type rankedInstance struct {
+	instance  *cloudinstances.CloudInstance
+	sensitive int
+	blocked   bool
+}
+
+// rank orders instances by the number of pods covered by PodDisruptionBudgets on their node,
+// keeping detached instances last so that they are replaced after any surge.
+func (s *scheduler) rank(state *disruptionState, instances []*cloudinstances.CloudInstance) []rankedInstance {
+	ranked := make([]rankedInstance, 0, len(instances))
+	for _, u := range instances {
+		sensitive, blocked := state.assess(u)
+		ranked = append(ranked, rankedInstance{instance: u, sensitive: sensitive, blocked: blocked})
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		iDetached := ranked[i].instance.Status == cloudinstances.CloudInstanceStatusDetached
+		jDetached := ranked[j].instance.Status == cloudinstances.CloudInstanceStatusDetached
+		if iDetached != jDetached {
+			return !iDetached
+		}
+		return ranked[i].sensitive < ranked[j].sensitive
+	})
+	return ranked
+}
+
+// orderGroups returns the keys of the groups, ordered so that the groups whose instances needing update
+// host the fewest pods covered by PodDisruptionBudgets are updated first.
+func (s *scheduler) orderGroups(ctx context.Context, groups map[string]*cloudinstances.CloudInstanceGroup) []string {
+	keys := sortGroups(groups)
+	if s == nil {
+		return keys
+	}
+
+	state, err := s.observe(ctx)
+	if err != nil {
+		klog.Warningf("unable to check PodDisruptionBudgets, updating instance groups in order: %v", err)
+		return keys
+	}
+
+	sensitive := make(map[string]int)
+	for _, k := range keys {
+		for _, u := range groups[k].NeedUpdate {
+			n, _ := state.assess(u)
+			sensitive[k] += n
+		}
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		return sensitive[keys[i]] < sensitive[keys[j]]
+	})
+	return keys
+}
+
+func (s *scheduler) isZoneBusy(zone string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return zone != "" && s.busyZones[zone]
+}
+
+// acquire waits until no other node in the instance's zone is being drained, then marks the zone as draining.
+// It returns an error if the context is cancelled while waiting.
+func (s *scheduler) acquire(ctx context.Context, u *cloudinstances.CloudInstance) error {
+	zone := instanceZone(u)
+	if s == nil || zone == "" {
+		return nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for s.busyZones[zone] {
+		klog.Infof("Waiting for the node being drained in zone %q before replacing instance %q", zone, u.ID)
+		zoneFree := s.zoneFree
+		s.mutex.Unlock()
+		select {
+		case <-ctx.Done():
+			s.mutex.Lock()
+			return fmt.Errorf("waiting for zone %q: %w", zone, ctx.Err())
+		case <-zoneFree:
+		}
+		s.mutex.Lock()
+	}
+	s.busyZones[zone] = true
+	return nil
+}
+
+// release marks the instance's zone as no longer draining.
+func (s *scheduler) release(u *cloudinstances.CloudInstance) {
+	zone := instanceZone(u)
+	if s == nil || zone == "" {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.busyZones, zone)
+	close(s.zoneFree)
+	s.zoneFree = make(chan struct{})
+}
+
+// remove returns the instances other than u.
+func remove(instances []*cloudinstances.CloudInstance, u *cloudinstances.CloudInstance) []*cloudinstances.CloudInstance {
+	result := make([]*cloudinstances.CloudInstance, 0, len(instances)-1)
+	for _, i := range instances {
+		if i != u {
+			result = append(result, i)
+		}
+	}
+	return result
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+)
+
+func makeScheduleInstance(group *cloudinstances.CloudInstanceGroup, id string, zone string) *cloudinstances.CloudInstance {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   id + ".local",
+			Labels: map[string]string{corev1.LabelTopologyZone: zone},
+		},
+	}
+	u, _ := group.NewCloudInstance(id, cloudinstances.CloudInstanceStatusNeedsUpdate, node)
+	return u
+}
+
+func makeSchedulePod(name string, nodeName string, app string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    map[string]string{"app": app},
+		},
+		Spec: corev1.PodSpec{NodeName: nodeName},
+	}
+}
+
+func makeSchedulePDB(app string, disruptionsAllowed int32) *policyv1.PodDisruptionBudget {
+	return &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      app,
+			Namespace: "default",
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": app}},
+		},
+		Status: policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: disruptionsAllowed},
+	}
+}
+
+func getScheduleTestSetup(objects ...runtime.Object) (*scheduler, *cloudinstances.CloudInstanceGroup) {
+	s := newScheduler(fake.NewSimpleClientset(objects...), 10*time.Millisecond, time.Millisecond)
+	group := &cloudinstances.CloudInstanceGroup{
+		HumanName: "nodes",
+		InstanceGroup: &kopsapi.InstanceGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: "nodes"},
+			Spec:       kopsapi.InstanceGroupSpec{Role: kopsapi.InstanceGroupRoleNode},
+		},
+	}
+	return s, group
+}
+
+func TestSchedulerPrefersFewestDisruptionSensitivePods(t *testing.T) {
+	s, group := getScheduleTestSetup(
+		makeSchedulePod("web-1", "a.local", "web"),
+		makeSchedulePod("web-2", "a.local", "web"),
+		makeSchedulePod("web-3", "b.local", "web"),
+		makeSchedulePod("batch-1", "c.local", "batch"),
+		makeSchedulePDB("web", 1),
+	)
+	a := makeScheduleInstance(group, "a", "us-test-1a")
+	b := makeScheduleInstance(group, "b", "us-test-1b")
+	c := makeScheduleInstance(group, "c", "us-test-1c")
+
+	u, pending, err := s.next(context.TODO(), []*cloudinstances.CloudInstance{a, b, c})
+	require.NoError(t, err)
+	assert.Equal(t, "c", u.ID, "node with no pods covered by a PodDisruptionBudget")
+	u, pending, err = s.next(context.TODO(), pending)
+	require.NoError(t, err)
+	assert.Equal(t, "b", u.ID, "node with one pod covered by a PodDisruptionBudget")
+	u, pending, err = s.next(context.TODO(), pending)
+	require.NoError(t, err)
+	assert.Equal(t, "a", u.ID)
+	assert.Empty(t, pending)
+}
+
+func TestSchedulerDefersNodeWithoutPDBHeadroom(t *testing.T) {
+	s, group := getScheduleTestSetup(
+		makeSchedulePod("db-1", "a.local", "db"),
+		makeSchedulePod("web-1", "b.local", "web"),
+		makeSchedulePod("web-2", "b.local", "web"),
+		makeSchedulePDB("db", 0),
+		makeSchedulePDB("web", 2),
+	)
+	a := makeScheduleInstance(group, "a", "us-test-1a")
+	b := makeScheduleInstance(group, "b", "us-test-1b")
+
+	u, pending, err := s.next(context.TODO(), []*cloudinstances.CloudInstance{a, b})
+	require.NoError(t, err)
+	assert.Equal(t, "b", u.ID, "node whose PodDisruptionBudget allows no disruptions should be deferred")
+
+	// Once the wait times out, the blocked node is replaced anyway
+	u, pending, err = s.next(context.TODO(), pending)
+	require.NoError(t, err)
+	assert.Equal(t, "a", u.ID)
+	assert.Empty(t, pending)
+}
+
+func TestSchedulerAvoidsBusyZone(t *testing.T) {
+	s, group := getScheduleTestSetup()
+	a1 := makeScheduleInstance(group, "a1", "us-test-1a")
+	a2 := makeScheduleInstance(group, "a2", "us-test-1a")
+	b1 := makeScheduleInstance(group, "b1", "us-test-1b")
+
+	u, pending, err := s.next(context.TODO(), []*cloudinstances.CloudInstance{a1, a2, b1})
+	require.NoError(t, err)
+	assert.Equal(t, "a1", u.ID)
+	require.NoError(t, s.acquire(context.TODO(), u))
+
+	u, pending, err = s.next(context.TODO(), pending)
+	require.NoError(t, err)
+	assert.Equal(t, "b1", u.ID, "should not drain two nodes in the same zone at once")
+	require.NoError(t, s.acquire(context.TODO(), u))
+
+	s.release(a1)
+	u, pending, err = s.next(context.TODO(), pending)
+	require.NoError(t, err)
+	assert.Equal(t, "a2", u.ID)
+	assert.Empty(t, pending)
+}
+
+func TestSchedulerStopsWaitingWhenCancelled(t *testing.T) {
+	s, group := getScheduleTestSetup(
+		makeSchedulePod("db-1", "a.local", "db"),
+		makeSchedulePDB("db", 0),
+	)
+	s.waitTimeout = time.Hour
+	s.pollInterval = time.Hour
+	a := makeScheduleInstance(group, "a", "us-test-1a")
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	_, pending, err := s.next(ctx, []*cloudinstances.CloudInstance{a})
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Len(t, pending, 1)
+}
+
+func TestSchedulerStopsWaitingForZoneWhenCancelled(t *testing.T) {
+	s, group := getScheduleTestSetup()
+	a1 := makeScheduleInstance(group, "a1", "us-test-1a")
+	a2 := makeScheduleInstance(group, "a2", "us-test-1a")
+	require.NoError(t, s.acquire(context.TODO(), a1))
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	err := s.acquire(ctx, a2)
+	require.ErrorIs(t, err, context.Canceled)
+
+	// The zone is still held by the node being drained, and is acquired once released
+	assert.True(t, s.isZoneBusy("us-test-1a"))
+	done := make(chan error)
+	go func() {
+		done <- s.acquire(context.TODO(), a2)
+	}()
+	s.release(a1)
+	require.NoError(t, <-done)
+	assert.True(t, s.isZoneBusy("us-test-1a"))
+}
+
+func TestSchedulerOrdersGroups(t *testing.T) {
+	s, _ := getScheduleTestSetup(
+		makeSchedulePod("web-1", "a.local", "web"),
+		makeSchedulePDB("web", 1),
+	)
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	for _, name := range []string{"nodes-a", "nodes-b"} {
+		_, group := getScheduleTestSetup()
+		group.HumanName = name
+		groups[name] = group
+	}
+	makeScheduleInstance(groups["nodes-a"], "a", "us-test-1a")
+	makeScheduleInstance(groups["nodes-b"], "b", "us-test-1b")
+
+	assert.Equal(t, []string{"nodes-b", "nodes-a"}, s.orderGroups(context.TODO(), groups))
+
+	var unscheduled *scheduler
+	assert.Equal(t, []string{"nodes-a", "nodes-b"}, unscheduled.orderGroups(context.TODO(), groups))
+}
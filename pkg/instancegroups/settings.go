@@ -40,6 +40,9 @@ func resolveSettings(cluster *kops.Cluster, group *kops.InstanceGroup, numInstan
 		if rollingUpdate.MaxSurge == nil {
 			rollingUpdate.MaxSurge = def.MaxSurge
 		}
+		if rollingUpdate.Hooks == nil {
+			rollingUpdate.Hooks = def.Hooks
+		}
 	}
 
 	if rollingUpdate.DrainAndTerminate == nil {
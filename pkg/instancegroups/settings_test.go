@@ -117,6 +117,50 @@ func assertResolvesValue(t *testing.T, name string, expected interface{}, rollin
 		assert.Equal(t, expected, value.Elem().Interface(), msg)
 }
 
+func TestHooksSettings(t *testing.T) {
+	clusterHooks := []kops.RollingUpdateHook{{Name: "cluster", Stage: kops.RollingUpdateHookStageBeforeDrain}}
+	groupHooks := []kops.RollingUpdateHook{{Name: "group", Stage: kops.RollingUpdateHookStageAfterDrain}}
+
+	for _, tc := range []struct {
+		name     string
+		cluster  *kops.RollingUpdate
+		group    *kops.RollingUpdate
+		expected []kops.RollingUpdateHook
+	}{
+		{
+			name: "nil nil",
+		},
+		{
+			name:     "cluster only",
+			cluster:  &kops.RollingUpdate{Hooks: clusterHooks},
+			expected: clusterHooks,
+		},
+		{
+			name:     "group only",
+			group:    &kops.RollingUpdate{Hooks: groupHooks},
+			expected: groupHooks,
+		},
+		{
+			name:     "group replaces cluster",
+			cluster:  &kops.RollingUpdate{Hooks: clusterHooks},
+			group:    &kops.RollingUpdate{Hooks: groupHooks},
+			expected: groupHooks,
+		},
+		{
+			name:     "group disables cluster",
+			cluster:  &kops.RollingUpdate{Hooks: clusterHooks},
+			group:    &kops.RollingUpdate{Hooks: []kops.RollingUpdateHook{}},
+			expected: []kops.RollingUpdateHook{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cluster := &kops.Cluster{Spec: kops.ClusterSpec{RollingUpdate: tc.cluster}}
+			instanceGroup := &kops.InstanceGroup{Spec: kops.InstanceGroupSpec{RollingUpdate: tc.group}}
+			assert.Equal(t, tc.expected, resolveSettings(cluster, instanceGroup, 1).Hooks)
+		})
+	}
+}
+
 func TestMaxUnavailable(t *testing.T) {
 	for _, tc := range []struct {
 		numInstances int
@@ -46,19 +46,37 @@ type Client struct {
 	// BaseURL is the base URL for the server
 	BaseURL url.URL
 
+	// ClientCertificate is presented to kops-controller to authenticate requests to renew certificates.
+	ClientCertificate *tls.Certificate
+
 	httpClient *http.Client
 }
 
+// Query bootstraps the node, authenticating the request with the Authenticator.
 func (b *Client) Query(ctx context.Context, req any, resp any) error {
+	return b.post(ctx, "/bootstrap", req, resp)
+}
+
+// Renew renews the certificates of the node, authenticating with the ClientCertificate.
+func (b *Client) Renew(ctx context.Context, req any, resp any) error {
+	return b.post(ctx, "/renew", req, resp)
+}
+
+func (b *Client) post(ctx context.Context, p string, req any, resp any) error {
 	if b.httpClient == nil {
 		certPool := x509.NewCertPool()
 		certPool.AppendCertsFromPEM(b.CAs)
 
+		tlsConfig := &tls.Config{
+			RootCAs:    certPool,
+			MinVersion: tls.VersionTLS12,
+		}
+		if b.ClientCertificate != nil {
+			tlsConfig.Certificates = []tls.Certificate{*b.ClientCertificate}
+		}
+
 		transport := &http.Transport{
-			TLSClientConfig: &tls.Config{
-				RootCAs:    certPool,
-				MinVersion: tls.VersionTLS12,
-			},
+			TLSClientConfig: tlsConfig,
 		}
 
 		httpClient := &http.Client{
@@ -84,19 +102,21 @@ func (b *Client) Query(ctx context.Context, req any, resp any) error {
 		return err
 	}
 
-	bootstrapURL := b.BaseURL
-	bootstrapURL.Path = path.Join(bootstrapURL.Path, "/bootstrap")
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", bootstrapURL.String(), bytes.NewReader(reqBytes))
+	requestURL := b.BaseURL
+	requestURL.Path = path.Join(requestURL.Path, p)
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", requestURL.String(), bytes.NewReader(reqBytes))
 	if err != nil {
 		return err
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
 
-	token, err := b.Authenticator.CreateToken(reqBytes)
-	if err != nil {
-		return err
+	if b.Authenticator != nil {
+		token, err := b.Authenticator.CreateToken(reqBytes)
+		if err != nil {
+			return err
+		}
+		httpReq.Header.Set("Authorization", token)
 	}
-	httpReq.Header.Set("Authorization", token)
 
 	response, err := b.httpClient.Do(httpReq)
 	if err != nil {
@@ -73,6 +73,10 @@ func (f fakeStatusCloud) DetachInstance(instance *cloudinstances.CloudInstance)
 	panic("not implemented")
 }
 
+func (f fakeStatusCloud) RollbackGroup(group *cloudinstances.CloudInstanceGroup) error {
+	panic("not implemented")
+}
+
 func (f fakeStatusCloud) DeregisterInstance(instance *cloudinstances.CloudInstance) error {
 	panic("not implemented")
 }
@@ -50,6 +50,13 @@ func (b *KubeProxyOptionsBuilder) BuildOptions(o *kops.Cluster) error {
 		config.CPURequest = resource.NewScaledQuantity(100, resource.Milli)
 	}
 
+	// Use the proxy mode that matches the node firewall; the nftables mode is available from Kubernetes 1.31
+	if config.ProxyMode == "" && clusterSpec.Firewall != nil && clusterSpec.Firewall.Backend == kops.FirewallBackendNFTables {
+		if b.Context.IsKubernetesGTE("1.31") {
+			config.ProxyMode = "nftables"
+		}
+	}
+
 	image, err := Image("kube-proxy", clusterSpec, b.Context.AssetBuilder)
 	if err != nil {
 		return err
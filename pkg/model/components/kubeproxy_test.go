@@ -0,0 +1,84 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"testing"
+
+	api "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/assets"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func Test_Build_KubeProxy_ProxyMode(t *testing.T) {
+	grid := []struct {
+		kubernetesVersion string
+		firewall          *api.FirewallConfig
+		proxyMode         string
+		expected          string
+	}{
+		{
+			kubernetesVersion: "v1.31.0",
+			expected:          "",
+		},
+		{
+			kubernetesVersion: "v1.31.0",
+			firewall:          &api.FirewallConfig{Backend: api.FirewallBackendIPTables},
+			expected:          "",
+		},
+		{
+			kubernetesVersion: "v1.31.0",
+			firewall:          &api.FirewallConfig{Backend: api.FirewallBackendNFTables},
+			expected:          "nftables",
+		},
+		{
+			kubernetesVersion: "v1.31.0",
+			firewall:          &api.FirewallConfig{Backend: api.FirewallBackendNFTables},
+			proxyMode:         "ipvs",
+			expected:          "ipvs",
+		},
+		{
+			kubernetesVersion: "v1.30.0",
+			firewall:          &api.FirewallConfig{Backend: api.FirewallBackendNFTables},
+			expected:          "",
+		},
+	}
+
+	for _, g := range grid {
+		c := buildCluster()
+		c.Spec.KubernetesVersion = g.kubernetesVersion
+		c.Spec.Firewall = g.firewall
+		c.Spec.KubeProxy = &api.KubeProxyConfig{ProxyMode: g.proxyMode}
+		b := assets.NewAssetBuilder(vfs.Context, c.Spec.Assets, false)
+
+		optionsContext, err := NewOptionsContext(c, b, b.KubeletSupportedVersion)
+		if err != nil {
+			t.Fatalf("error from NewOptionsContext: %v", err)
+		}
+
+		kp := &KubeProxyOptionsBuilder{
+			Context: optionsContext,
+		}
+		if err := kp.BuildOptions(c); err != nil {
+			t.Fatalf("unexpected error from BuildOptions: %v", err)
+		}
+
+		if c.Spec.KubeProxy.ProxyMode != g.expected {
+			t.Errorf("kubernetes %s, firewall %v, proxyMode %q: expected proxyMode %q, got %q", g.kubernetesVersion, g.firewall, g.proxyMode, g.expected, c.Spec.KubeProxy.ProxyMode)
+		}
+	}
+}
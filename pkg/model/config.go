@@ -43,7 +43,10 @@ func (b *ConfigBuilder) Build(c *fi.CloudupModelBuilderContext) error {
 		Contents:  fi.NewStringResource(kopsbase.Version),
 	})
 
-	versionedYaml, err := kopscodecs.ToVersionedYamlWithVersion(b.Cluster, v1alpha2.SchemeGroupVersion)
+	// The resource version identifies the stored cluster spec, not the completed one
+	completed := b.Cluster.DeepCopy()
+	completed.ObjectMeta.ResourceVersion = ""
+	versionedYaml, err := kopscodecs.ToVersionedYamlWithVersion(completed, v1alpha2.SchemeGroupVersion)
 	if err != nil {
 		return fmt.Errorf("serializing completed cluster spec: %w", err)
 	}
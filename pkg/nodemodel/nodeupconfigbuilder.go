@@ -440,6 +440,9 @@ func (n *nodeUpConfigBuilder) BuildConfig(ig *kops.InstanceGroup, wellKnownAddre
 	}
 
 	config.Images = n.images[role]
+	if cluster.Spec.Assets != nil {
+		config.AssetSignaturePublicKeys = cluster.Spec.Assets.SignaturePublicKeys
+	}
 
 	if isMaster {
 		for _, etcdCluster := range cluster.Spec.EtcdClusters {
@@ -510,7 +513,11 @@ func (n *nodeUpConfigBuilder) buildWarmPoolImages(ig *kops.InstanceGroup) []stri
 		for _, image := range assetBuilder.ImageAssets {
 			for _, prefix := range desiredImagePrefixes {
 				if strings.HasPrefix(image.DownloadLocation, prefix) {
-					images[image.DownloadLocation] = true
+					if image.Digest != "" {
+						images[image.DownloadLocation+"@"+image.Digest] = true
+					} else {
+						images[image.DownloadLocation] = true
+					}
 				}
 			}
 		}
@@ -0,0 +1,437 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// ExternalSignerTypeHTTP is a signing service implementing the kOps HTTP signing protocol:
+	//   GET  <url>/keys/<key>       returns {"publicKey": "<PEM>"}
+	//   POST <url>/keys/<key>/sign  takes {"digest": "<base64>", "hash": "SHA-256", "padding": "PKCS1v15"}
+	//                               and returns {"signature": "<base64>"}
+	// Requests carry the token as a bearer token. ECDSA signatures are ASN.1 encoded.
+	ExternalSignerTypeHTTP = "http"
+	// ExternalSignerTypeVaultTransit is a key in the HashiCorp Vault transit secrets engine.
+	ExternalSignerTypeVaultTransit = "vault-transit"
+
+	// ExternalSignerTokenEnvVar is the environment variable holding the token for the signing service.
+	// It takes precedence over ExternalSignerConfig.TokenFile.
+	ExternalSignerTokenEnvVar = "KOPS_EXTERNAL_SIGNER_TOKEN"
+
+	// externalSignerPEMType is the PEM block type in which an ExternalSignerConfig is stored in place of a private key.
+	externalSignerPEMType = "KOPS EXTERNAL SIGNER"
+)
+
+// ExternalSignerConfig references a private key that is held by an external signing service.
+// It is stored in place of the private key material of a keypair, so it must not hold credentials.
+type ExternalSignerConfig struct {
+	// Type is the protocol of the signing service, ExternalSignerTypeHTTP or ExternalSignerTypeVaultTransit.
+	Type string `json:"type"`
+	// URL is the base URL of the signing service, or the address of the Vault server.
+	URL string `json:"url"`
+	// Key is the name of the key in the signing service.
+	Key string `json:"key"`
+	// KeyVersion pins the version of a Vault transit key, so that rotating the key in Vault does not change the key of the keypair.
+	// If not set when the keypair is created, the latest version is recorded.
+	KeyVersion int `json:"keyVersion,omitempty"`
+	// Mount is the path at which the Vault transit secrets engine is mounted. Defaults to "transit".
+	Mount string `json:"mount,omitempty"`
+	// TokenFile is the path to a file holding the token used to authenticate to the signing service.
+	TokenFile string `json:"tokenFile,omitempty"`
+	// CAFile is the path to a PEM bundle used to verify the certificate of the signing service.
+	CAFile string `json:"caFile,omitempty"`
+	// PublicKey is the PEM-encoded public key, recorded when the keypair is created.
+	PublicKey string `json:"publicKey,omitempty"`
+}
+
+// ExternalSigner is a crypto.Signer whose private key is held by an external signing service.
+type ExternalSigner struct {
+	config    ExternalSignerConfig
+	publicKey crypto.PublicKey
+}
+
+var _ crypto.Signer = &ExternalSigner{}
+
+// NewExternalSigner builds a signer for the key referenced by config.
+// If config has no public key, it is fetched from the signing service and recorded.
+func NewExternalSigner(ctx context.Context, config ExternalSignerConfig) (*ExternalSigner, error) {
+	s := &ExternalSigner{config: config}
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+
+	if s.config.PublicKey == "" {
+		publicKeyPEM, version, err := s.fetchPublicKey(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("fetching public key of %q from external signer: %w", config.Key, err)
+		}
+		s.config.PublicKey = publicKeyPEM
+		if s.config.Type == ExternalSignerTypeVaultTransit && s.config.KeyVersion == 0 {
+			s.config.KeyVersion = version
+		}
+	}
+
+	publicKey, err := parsePEMPublicKey([]byte(s.config.PublicKey))
+	if err != nil {
+		return nil, err
+	}
+	s.publicKey = publicKey
+	return s, nil
+}
+
+// ParseExternalSignerConfig parses an ExternalSignerConfig from YAML-compatible JSON.
+func ParseExternalSignerConfig(data []byte) (*ExternalSignerConfig, error) {
+	config := &ExternalSignerConfig{}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("parsing external signer configuration: %w", err)
+	}
+	return config, nil
+}
+
+// IsExternalSigner returns true if the private key is held by an external signer.
+func IsExternalSigner(k *PrivateKey) bool {
+	if k == nil {
+		return false
+	}
+	_, ok := k.Key.(*ExternalSigner)
+	return ok
+}
+
+// Config returns the reference to the key, including its recorded public key.
+func (s *ExternalSigner) Config() ExternalSignerConfig {
+	return s.config
+}
+
+// SetTokenFile overrides the path of the token file, for when the file is mounted at a different path.
+func (s *ExternalSigner) SetTokenFile(p string) {
+	s.config.TokenFile = p
+}
+
+// Public implements crypto.Signer
+func (s *ExternalSigner) Public() crypto.PublicKey {
+	return s.publicKey
+}
+
+// Sign implements crypto.Signer, by asking the signing service to sign the digest.
+// The signature is verified against the recorded public key before it is returned.
+func (s *ExternalSigner) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	hash := opts.HashFunc()
+	if hash == 0 {
+		return nil, fmt.Errorf("external signer requires a prehashed digest")
+	}
+	pssOptions, isPSS := opts.(*rsa.PSSOptions)
+
+	var signature []byte
+	var err error
+	switch s.config.Type {
+	case ExternalSignerTypeHTTP:
+		signature, err = s.signHTTP(ctx, digest, hash, isPSS)
+	case ExternalSignerTypeVaultTransit:
+		signature, err = s.signVaultTransit(ctx, digest, hash, isPSS)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("signing with external signer key %q: %w", s.config.Key, err)
+	}
+
+	if err := s.verify(digest, hash, pssOptions, signature); err != nil {
+		return nil, fmt.Errorf("external signer key %q returned a signature that does not match its public key: %w", s.config.Key, err)
+	}
+	return signature, nil
+}
+
+func (s *ExternalSigner) validate() error {
+	switch s.config.Type {
+	case ExternalSignerTypeHTTP, ExternalSignerTypeVaultTransit:
+	default:
+		return fmt.Errorf("unknown external signer type %q, expected %q or %q", s.config.Type, ExternalSignerTypeHTTP, ExternalSignerTypeVaultTransit)
+	}
+	if s.config.URL == "" {
+		return fmt.Errorf("external signer url must be set")
+	}
+	if _, err := url.Parse(s.config.URL); err != nil {
+		return fmt.Errorf("parsing external signer url %q: %w", s.config.URL, err)
+	}
+	if s.config.Key == "" {
+		return fmt.Errorf("external signer key must be set")
+	}
+	return nil
+}
+
+func (s *ExternalSigner) verify(digest []byte, hash crypto.Hash, pssOptions *rsa.PSSOptions, signature []byte) error {
+	switch publicKey := s.publicKey.(type) {
+	case *rsa.PublicKey:
+		if pssOptions != nil {
+			return rsa.VerifyPSS(publicKey, hash, digest, signature, pssOptions)
+		}
+		return rsa.VerifyPKCS1v15(publicKey, hash, digest, signature)
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(publicKey, digest, signature) {
+			return fmt.Errorf("invalid ECDSA signature")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported public key type %T", s.publicKey)
+	}
+}
+
+func (s *ExternalSigner) signHTTP(ctx context.Context, digest []byte, hash crypto.Hash, isPSS bool) ([]byte, error) {
+	request := struct {
+		Digest  string `json:"digest"`
+		Hash    string `json:"hash"`
+		Padding string `json:"padding,omitempty"`
+	}{
+		Digest: base64.StdEncoding.EncodeToString(digest),
+		Hash:   hash.String(),
+	}
+	if _, ok := s.publicKey.(*rsa.PublicKey); ok {
+		request.Padding = "PKCS1v15"
+		if isPSS {
+			request.Padding = "PSS"
+		}
+	}
+
+	var response struct {
+		Signature string `json:"signature"`
+	}
+	if err := s.do(ctx, http.MethodPost, s.httpKeyURL()+"/sign", request, &response); err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(response.Signature)
+}
+
+func (s *ExternalSigner) signVaultTransit(ctx context.Context, digest []byte, hash crypto.Hash, isPSS bool) ([]byte, error) {
+	var hashAlgorithm string
+	switch hash {
+	case crypto.SHA256:
+		hashAlgorithm = "sha2-256"
+	case crypto.SHA384:
+		hashAlgorithm = "sha2-384"
+	case crypto.SHA512:
+		hashAlgorithm = "sha2-512"
+	default:
+		return nil, fmt.Errorf("unsupported hash %v for vault transit", hash)
+	}
+
+	request := map[string]any{
+		"input":       base64.StdEncoding.EncodeToString(digest),
+		"prehashed":   true,
+		"key_version": s.config.KeyVersion,
+	}
+	if _, ok := s.publicKey.(*rsa.PublicKey); ok {
+		request["signature_algorithm"] = "pkcs1v15"
+		if isPSS {
+			request["signature_algorithm"] = "pss"
+			request["salt_length"] = "hash"
+		}
+	}
+
+	var response struct {
+		Data struct {
+			Signature string `json:"signature"`
+		} `json:"data"`
+	}
+	if err := s.do(ctx, http.MethodPost, s.vaultURL("sign", s.config.Key, hashAlgorithm), request, &response); err != nil {
+		return nil, err
+	}
+
+	// Vault signatures are of the form vault:v<version>:<base64>
+	tokens := strings.SplitN(response.Data.Signature, ":", 3)
+	if len(tokens) != 3 || tokens[0] != "vault" {
+		return nil, fmt.Errorf("unexpected vault signature format")
+	}
+	return base64.StdEncoding.DecodeString(tokens[2])
+}
+
+// fetchPublicKey returns the PEM-encoded public key, and for vault the latest key version.
+func (s *ExternalSigner) fetchPublicKey(ctx context.Context) (string, int, error) {
+	switch s.config.Type {
+	case ExternalSignerTypeHTTP:
+		var response struct {
+			PublicKey string `json:"publicKey"`
+		}
+		if err := s.do(ctx, http.MethodGet, s.httpKeyURL(), nil, &response); err != nil {
+			return "", 0, err
+		}
+		return response.PublicKey, 0, nil
+
+	case ExternalSignerTypeVaultTransit:
+		var response struct {
+			Data struct {
+				LatestVersion int `json:"latest_version"`
+				Keys          map[string]struct {
+					PublicKey string `json:"public_key"`
+				} `json:"keys"`
+			} `json:"data"`
+		}
+		if err := s.do(ctx, http.MethodGet, s.vaultURL("keys", s.config.Key), nil, &response); err != nil {
+			return "", 0, err
+		}
+		version := s.config.KeyVersion
+		if version == 0 {
+			version = response.Data.LatestVersion
+		}
+		key, found := response.Data.Keys[strconv.Itoa(version)]
+		if !found || key.PublicKey == "" {
+			return "", 0, fmt.Errorf("vault key %q has no public key for version %d; only asymmetric keys can be used", s.config.Key, version)
+		}
+		return key.PublicKey, version, nil
+	}
+	return "", 0, fmt.Errorf("unknown external signer type %q", s.config.Type)
+}
+
+func (s *ExternalSigner) httpKeyURL() string {
+	return strings.TrimSuffix(s.config.URL, "/") + "/keys/" + url.PathEscape(s.config.Key)
+}
+
+func (s *ExternalSigner) vaultURL(elems ...string) string {
+	mount := s.config.Mount
+	if mount == "" {
+		mount = "transit"
+	}
+	u := strings.TrimSuffix(s.config.URL, "/") + "/v1/" + strings.Trim(mount, "/")
+	for _, elem := range elems {
+		u += "/" + url.PathEscape(elem)
+	}
+	return u
+}
+
+func (s *ExternalSigner) token() (string, error) {
+	if token := os.Getenv(ExternalSignerTokenEnvVar); token != "" {
+		return token, nil
+	}
+	if s.config.TokenFile == "" {
+		return "", fmt.Errorf("no token for the external signer: set %s or tokenFile", ExternalSignerTokenEnvVar)
+	}
+	b, err := os.ReadFile(s.config.TokenFile)
+	if err != nil {
+		return "", fmt.Errorf("reading external signer token: %w", err)
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+func (s *ExternalSigner) httpClient() (*http.Client, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+	if s.config.CAFile != "" {
+		b, err := os.ReadFile(s.config.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading external signer CA bundle: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(b) {
+			return nil, fmt.Errorf("no certificates found in external signer CA bundle %q", s.config.CAFile)
+		}
+		client.Transport = &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{
+				MinVersion: tls.VersionTLS12,
+				RootCAs:    pool,
+			},
+		}
+	}
+	return client, nil
+}
+
+func (s *ExternalSigner) do(ctx context.Context, method string, u string, body any, response any) error {
+	token, err := s.token()
+	if err != nil {
+		return err
+	}
+	client, err := s.httpClient()
+	if err != nil {
+		return err
+	}
+
+	var requestBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		requestBody = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, requestBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.config.Type == ExternalSignerTypeVaultTransit {
+		req.Header.Set("X-Vault-Token", token)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("reading response from %s: %w", u, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d from %s: %s", resp.StatusCode, u, strings.TrimSpace(string(b)))
+	}
+	if err := json.Unmarshal(b, response); err != nil {
+		return fmt.Errorf("parsing response from %s: %w", u, err)
+	}
+	return nil
+}
+
+// writeTo stores the reference to the key, in place of a private key.
+func (s *ExternalSigner) writeTo(w io.Writer) error {
+	b, err := json.Marshal(s.config)
+	if err != nil {
+		return fmt.Errorf("serializing external signer configuration: %w", err)
+	}
+	return pem.Encode(w, &pem.Block{Type: externalSignerPEMType, Bytes: b})
+}
+
+// parseExternalSigner loads a stored reference to a key. It does not contact the signing service.
+func parseExternalSigner(data []byte) (*ExternalSigner, error) {
+	config, err := ParseExternalSignerConfig(data)
+	if err != nil {
+		return nil, err
+	}
+	if config.PublicKey == "" {
+		return nil, fmt.Errorf("external signer configuration for key %q has no public key", config.Key)
+	}
+	return NewExternalSigner(context.Background(), *config)
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/kops/pkg/pki/fakesigner"
+)
+
+// issueExternalCA issues a self-signed CA whose key is held by the external signer.
+func issueExternalCA(t *testing.T, config ExternalSignerConfig) (*Certificate, *PrivateKey) {
+	ctx := context.Background()
+
+	signer, err := NewExternalSigner(ctx, config)
+	require.NoError(t, err)
+
+	caKey := &PrivateKey{Key: signer}
+	caCert, _, _, err := IssueCert(ctx, &IssueCertRequest{
+		Type:       "ca",
+		Subject:    pkix.Name{CommonName: "kubernetes-ca"},
+		PrivateKey: caKey,
+	}, nil)
+	require.NoError(t, err)
+	return caCert, caKey
+}
+
+// issueLeaf issues a leaf certificate from the CA and verifies it chains to the CA.
+func issueLeaf(t *testing.T, caCert *Certificate, caKey *PrivateKey) {
+	ctx := context.Background()
+
+	leaf, _, _, err := IssueCert(ctx, &IssueCertRequest{
+		Signer:         "kubernetes-ca",
+		Type:           "server",
+		Subject:        pkix.Name{CommonName: "kube-apiserver"},
+		AlternateNames: []string{"kubernetes"},
+	}, &mockKeystore{t: t, signer: "kubernetes-ca", cert: caCert, key: caKey})
+	require.NoError(t, err)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(caCert.Certificate)
+	_, err = leaf.Certificate.Verify(x509.VerifyOptions{
+		DNSName:   "kubernetes",
+		Roots:     roots,
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	assert.NoError(t, err, "verifying leaf certificate")
+}
+
+func TestExternalSignerHTTP(t *testing.T) {
+	server := fakesigner.NewServer("secret")
+	defer server.Close()
+	_, err := server.AddKeyVersion("kubernetes-ca")
+	require.NoError(t, err)
+
+	t.Setenv(ExternalSignerTokenEnvVar, "secret")
+
+	caCert, caKey := issueExternalCA(t, ExternalSignerConfig{
+		Type: ExternalSignerTypeHTTP,
+		URL:  server.URL,
+		Key:  "kubernetes-ca",
+	})
+	assert.Equal(t, 1, server.SignCount("kubernetes-ca"), "self-signing the CA")
+
+	issueLeaf(t, caCert, caKey)
+	assert.Equal(t, 2, server.SignCount("kubernetes-ca"), "signing the leaf")
+
+	// The stored form is a reference to the key, which loads without contacting the service
+	data, err := caKey.AsBytes()
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "BEGIN KOPS EXTERNAL SIGNER")
+	assert.NotContains(t, string(data), "secret")
+
+	server.Close()
+	loaded, err := ParsePEMPrivateKey(data)
+	require.NoError(t, err)
+	require.True(t, IsExternalSigner(loaded))
+	assert.Equal(t, caKey.Key.(*ExternalSigner).Config(), loaded.Key.(*ExternalSigner).Config())
+	assert.Equal(t, caCert.PublicKey, loaded.Key.Public())
+
+	_, _, _, err = IssueCert(context.Background(), &IssueCertRequest{
+		Signer:  "kubernetes-ca",
+		Type:    "client",
+		Subject: pkix.Name{CommonName: "admin"},
+	}, &mockKeystore{t: t, signer: "kubernetes-ca", cert: caCert, key: loaded})
+	assert.Error(t, err, "signing must fail when the service is unavailable")
+}
+
+func TestExternalSignerVaultTransit(t *testing.T) {
+	server := fakesigner.NewServer("vault-token")
+	defer server.Close()
+	_, err := server.AddKeyVersion("kops-ca")
+	require.NoError(t, err)
+
+	t.Setenv(ExternalSignerTokenEnvVar, "vault-token")
+
+	caCert, caKey := issueExternalCA(t, ExternalSignerConfig{
+		Type: ExternalSignerTypeVaultTransit,
+		URL:  server.URL,
+		Key:  "kops-ca",
+	})
+	assert.Equal(t, 1, caKey.Key.(*ExternalSigner).Config().KeyVersion, "the latest key version is recorded")
+
+	// Rotating the key in Vault does not change the key of the keypair
+	version, err := server.AddKeyVersion("kops-ca")
+	require.NoError(t, err)
+	require.Equal(t, 2, version)
+
+	issueLeaf(t, caCert, caKey)
+	assert.Equal(t, 2, server.SignCount("kops-ca"))
+}
+
+func TestExternalSignerRejectsMismatchedSignature(t *testing.T) {
+	server := fakesigner.NewServer("secret")
+	defer server.Close()
+	_, err := server.AddKeyVersion("kubernetes-ca")
+	require.NoError(t, err)
+
+	t.Setenv(ExternalSignerTokenEnvVar, "secret")
+
+	caCert, caKey := issueExternalCA(t, ExternalSignerConfig{
+		Type: ExternalSignerTypeHTTP,
+		URL:  server.URL,
+		Key:  "kubernetes-ca",
+	})
+
+	server.WrongKey = true
+	_, _, _, err = IssueCert(context.Background(), &IssueCertRequest{
+		Signer:  "kubernetes-ca",
+		Type:    "client",
+		Subject: pkix.Name{CommonName: "admin"},
+	}, &mockKeystore{t: t, signer: "kubernetes-ca", cert: caCert, key: caKey})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "does not match its public key")
+	}
+}
+
+func TestExternalSignerToken(t *testing.T) {
+	server := fakesigner.NewServer("secret")
+	defer server.Close()
+	_, err := server.AddKeyVersion("kubernetes-ca")
+	require.NoError(t, err)
+
+	config := ExternalSignerConfig{
+		Type: ExternalSignerTypeHTTP,
+		URL:  server.URL,
+		Key:  "kubernetes-ca",
+	}
+
+	t.Setenv(ExternalSignerTokenEnvVar, "")
+	_, err = NewExternalSigner(context.Background(), config)
+	assert.ErrorContains(t, err, "no token for the external signer")
+
+	t.Setenv(ExternalSignerTokenEnvVar, "wrong")
+	_, err = NewExternalSigner(context.Background(), config)
+	assert.ErrorContains(t, err, "unexpected status 403")
+}
+
+func TestExternalSignerConfigValidation(t *testing.T) {
+	grid := []ExternalSignerConfig{
+		{Type: "kms", URL: "https://signer.example.com", Key: "ca"},
+		{Type: ExternalSignerTypeHTTP, Key: "ca"},
+		{Type: ExternalSignerTypeVaultTransit, URL: "https://vault.example.com"},
+	}
+	for _, config := range grid {
+		_, err := NewExternalSigner(context.Background(), config)
+		assert.Error(t, err, "config %+v", config)
+	}
+}
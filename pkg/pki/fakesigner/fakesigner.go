@@ -0,0 +1,239 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fakesigner is a local signing service for tests of external signer keypairs.
+// It implements both the kOps HTTP signing protocol and the subset of the Vault transit API that kOps uses.
+package fakesigner
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Server is a fake signing service holding RSA keys in memory.
+type Server struct {
+	// URL is the base URL of the service.
+	URL string
+
+	token  string
+	server *httptest.Server
+
+	mutex sync.Mutex
+	keys  map[string][]*rsa.PrivateKey
+	signs map[string]int
+	// WrongKey makes the service sign with a key that does not match the published public key.
+	WrongKey bool
+}
+
+// NewServer starts a fake signing service that accepts the given token.
+func NewServer(token string) *Server {
+	s := &Server{
+		token: token,
+		keys:  map[string][]*rsa.PrivateKey{},
+		signs: map[string]int{},
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.server.URL
+	return s
+}
+
+// Close stops the service.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// AddKeyVersion adds a new version of the named key, creating the key if needed, and returns the version.
+func (s *Server) AddKeyVersion(name string) (int, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return 0, err
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.keys[name] = append(s.keys[name], key)
+	return len(s.keys[name]), nil
+}
+
+// SignCount returns the number of signatures made with the named key.
+func (s *Server) SignCount(name string) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.signs[name]
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	var key, action, hashName string
+	var version int
+	var vault bool
+	if rest, ok := strings.CutPrefix(r.URL.Path, "/v1/transit/"); ok {
+		vault = true
+		if r.Header.Get("X-Vault-Token") != s.token {
+			http.Error(w, "permission denied", http.StatusForbidden)
+			return
+		}
+		tokens := strings.Split(rest, "/")
+		switch {
+		case len(tokens) == 2 && tokens[0] == "keys":
+			key = tokens[1]
+		case len(tokens) == 3 && tokens[0] == "sign":
+			key, action, hashName = tokens[1], "sign", tokens[2]
+		default:
+			http.NotFound(w, r)
+			return
+		}
+	} else if rest, ok := strings.CutPrefix(r.URL.Path, "/keys/"); ok {
+		if r.Header.Get("Authorization") != "Bearer "+s.token {
+			http.Error(w, "permission denied", http.StatusForbidden)
+			return
+		}
+		key, action, _ = strings.Cut(rest, "/")
+	} else {
+		http.NotFound(w, r)
+		return
+	}
+
+	s.mutex.Lock()
+	versions := s.keys[key]
+	s.mutex.Unlock()
+	if len(versions) == 0 {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch action {
+	case "":
+		if vault {
+			keys := map[string]any{}
+			for i, k := range versions {
+				keys[strconv.Itoa(i+1)] = map[string]any{"public_key": publicKeyPEM(k)}
+			}
+			writeJSON(w, map[string]any{"data": map[string]any{"latest_version": len(versions), "keys": keys}})
+		} else {
+			writeJSON(w, map[string]any{"publicKey": publicKeyPEM(versions[len(versions)-1])})
+		}
+
+	case "sign":
+		var request struct {
+			Digest             string `json:"digest"`
+			Hash               string `json:"hash"`
+			Padding            string `json:"padding"`
+			Input              string `json:"input"`
+			Prehashed          bool   `json:"prehashed"`
+			KeyVersion         int    `json:"key_version"`
+			SignatureAlgorithm string `json:"signature_algorithm"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		digestB64 := request.Digest
+		hash := crypto.SHA256
+		pss := request.Padding == "PSS"
+		version = len(versions)
+		if vault {
+			if !request.Prehashed {
+				http.Error(w, "expected prehashed input", http.StatusBadRequest)
+				return
+			}
+			digestB64 = request.Input
+			pss = request.SignatureAlgorithm == "pss"
+			switch hashName {
+			case "sha2-256":
+			case "sha2-384":
+				hash = crypto.SHA384
+			case "sha2-512":
+				hash = crypto.SHA512
+			default:
+				http.Error(w, "unsupported hash", http.StatusBadRequest)
+				return
+			}
+			if request.KeyVersion != 0 {
+				version = request.KeyVersion
+			}
+		} else if request.Hash != crypto.SHA256.String() {
+			http.Error(w, "unsupported hash", http.StatusBadRequest)
+			return
+		}
+		if version < 1 || version > len(versions) {
+			http.Error(w, "unknown key version", http.StatusBadRequest)
+			return
+		}
+
+		digest, err := base64.StdEncoding.DecodeString(digestB64)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		signingKey := versions[version-1]
+		if s.WrongKey {
+			signingKey, err = rsa.GenerateKey(rand.Reader, 2048)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+		var signature []byte
+		if pss {
+			signature, err = rsa.SignPSS(rand.Reader, signingKey, hash, digest, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		} else {
+			signature, err = rsa.SignPKCS1v15(rand.Reader, signingKey, hash, digest)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		s.mutex.Lock()
+		s.signs[key]++
+		s.mutex.Unlock()
+
+		encoded := base64.StdEncoding.EncodeToString(signature)
+		if vault {
+			writeJSON(w, map[string]any{"data": map[string]any{"signature": fmt.Sprintf("vault:v%d:%s", version, encoded)}})
+		} else {
+			writeJSON(w, map[string]any{"signature": encoded})
+		}
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func publicKeyPEM(key *rsa.PrivateKey) string {
+	b, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		panic(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: b}))
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
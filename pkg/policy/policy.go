@@ -0,0 +1,303 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package policy evaluates the ClusterPolicy objects stored alongside a cluster
+// against the fully populated cluster and instance group specs.
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/kopscodecs"
+	"k8s.io/kops/pkg/kubemanifest"
+)
+
+const (
+	// Group is the API group of ClusterPolicy objects.
+	Group = "policy.kops.k8s.io"
+	// KindClusterPolicy is the kind of ClusterPolicy objects.
+	KindClusterPolicy = "ClusterPolicy"
+
+	// TargetCluster rules are evaluated against the cluster.
+	TargetCluster = "Cluster"
+	// TargetInstanceGroup rules are evaluated against each instance group.
+	TargetInstanceGroup = "InstanceGroup"
+
+	// ActionDeny rejects the cluster when a rule is violated.
+	ActionDeny = "Deny"
+	// ActionWarn only logs a warning when a rule is violated.
+	ActionWarn = "Warn"
+
+	// ObjectVariable is the name of the CEL variable holding the object a rule is evaluated against.
+	ObjectVariable = "object"
+)
+
+// ClusterPolicy is a set of rules that the cluster and instance group specs must satisfy.
+type ClusterPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ClusterPolicySpec `json:"spec,omitempty"`
+}
+
+// ClusterPolicySpec holds the rules of a ClusterPolicy.
+type ClusterPolicySpec struct {
+	Rules []Rule `json:"rules,omitempty"`
+}
+
+// Rule is a CEL expression that must evaluate to true for the specs to comply with the policy.
+type Rule struct {
+	// Name identifies the rule in violations.
+	Name string `json:"name,omitempty"`
+	// Target is the kind of object the rule is evaluated against: Cluster or InstanceGroup.
+	Target string `json:"target,omitempty"`
+	// Expression is a CEL expression, evaluated against the versioned object bound to the "object" variable, which must evaluate to true.
+	Expression string `json:"expression,omitempty"`
+	// Action is taken when the expression does not evaluate to true: Deny (the default) or Warn.
+	Action string `json:"action,omitempty"`
+	// Message describes the violation.
+	Message string `json:"message,omitempty"`
+}
+
+// Violation is a rule that an object does not satisfy.
+type Violation struct {
+	Policy string
+	Rule   string
+	Action string
+	// Object is the kind and name of the object which violates the rule, e.g. InstanceGroup/nodes.
+	Object  string
+	Message string
+}
+
+func (v *Violation) String() string {
+	message := v.Message
+	if message == "" {
+		message = "policy not satisfied"
+	}
+	return fmt.Sprintf("%s: %s (policy %q, rule %q)", v.Object, message, v.Policy, v.Rule)
+}
+
+// IsClusterPolicy returns true if the object kind is a ClusterPolicy.
+func IsClusterPolicy(gvk schema.GroupVersionKind) bool {
+	return gvk.Group == Group && gvk.Kind == KindClusterPolicy
+}
+
+// Parse converts an unstructured object to a ClusterPolicy.
+func Parse(u *unstructured.Unstructured) (*ClusterPolicy, error) {
+	p := &ClusterPolicy{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, p); err != nil {
+		return nil, fmt.Errorf("parsing %s %q: %w", KindClusterPolicy, u.GetName(), err)
+	}
+	return p, nil
+}
+
+// Validate checks that the rules of the policy are well-formed.
+func (p *ClusterPolicy) Validate(fieldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	names := make(map[string]bool)
+	for i, rule := range p.Spec.Rules {
+		rulePath := fieldPath.Child("spec", "rules").Index(i)
+
+		if rule.Name == "" {
+			allErrs = append(allErrs, field.Required(rulePath.Child("name"), ""))
+		} else if names[rule.Name] {
+			allErrs = append(allErrs, field.Duplicate(rulePath.Child("name"), rule.Name))
+		}
+		names[rule.Name] = true
+
+		allErrs = append(allErrs, isValidValue(rulePath.Child("target"), rule.Target, []string{TargetCluster, TargetInstanceGroup})...)
+		if rule.Action != "" {
+			allErrs = append(allErrs, isValidValue(rulePath.Child("action"), rule.Action, []string{ActionDeny, ActionWarn})...)
+		}
+
+		if rule.Expression == "" {
+			allErrs = append(allErrs, field.Required(rulePath.Child("expression"), ""))
+		} else if _, err := compile(rule.Expression); err != nil {
+			allErrs = append(allErrs, field.Invalid(rulePath.Child("expression"), rule.Expression, err.Error()))
+		}
+	}
+
+	return allErrs
+}
+
+// compile parses and checks a rule expression, which must evaluate to a boolean.
+func compile(expression string) (cel.Program, error) {
+	env, err := cel.NewEnv(cel.Variable(ObjectVariable, cel.DynType))
+	if err != nil {
+		return nil, fmt.Errorf("building CEL environment: %w", err)
+	}
+	ast, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("invalid CEL expression: %w", issues.Err())
+	}
+	if outputType := ast.OutputType(); !outputType.IsExactType(types.BoolType) && !outputType.IsExactType(types.DynType) {
+		return nil, fmt.Errorf("CEL expression must evaluate to a bool, not %s", outputType)
+	}
+	program, err := env.Program(ast)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CEL expression: %w", err)
+	}
+	return program, nil
+}
+
+// isValidValue checks that the value is one of the valid values.
+func isValidValue(fldPath *field.Path, v string, validValues []string) field.ErrorList {
+	for _, valid := range validValues {
+		if v == valid {
+			return nil
+		}
+	}
+	return field.ErrorList{field.NotSupported(fldPath, v, validValues)}
+}
+
+// FromObjects returns the ClusterPolicy objects from a list of additional objects.
+func FromObjects(objects kubemanifest.ObjectList) ([]*ClusterPolicy, error) {
+	var policies []*ClusterPolicy
+	for _, object := range objects {
+		if !IsClusterPolicy(object.GroupVersionKind()) {
+			continue
+		}
+		p, err := Parse(object.ToUnstructured())
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, p)
+	}
+	return policies, nil
+}
+
+// Evaluate returns the rules of the policies that the cluster or instance groups violate.
+// If the cluster is nil, only the rules targeting instance groups are evaluated.
+func Evaluate(policies []*ClusterPolicy, cluster *kops.Cluster, instanceGroups []*kops.InstanceGroup) ([]*Violation, error) {
+	if len(policies) == 0 {
+		return nil, nil
+	}
+
+	type target struct {
+		name string
+		data interface{}
+	}
+	targets := make(map[string][]target)
+
+	if cluster != nil {
+		data, err := toData(cluster)
+		if err != nil {
+			return nil, err
+		}
+		targets[TargetCluster] = append(targets[TargetCluster], target{name: TargetCluster + "/" + cluster.Name, data: data})
+	}
+	for _, ig := range instanceGroups {
+		data, err := toData(ig)
+		if err != nil {
+			return nil, err
+		}
+		targets[TargetInstanceGroup] = append(targets[TargetInstanceGroup], target{name: TargetInstanceGroup + "/" + ig.Name, data: data})
+	}
+
+	var violations []*Violation
+	for _, p := range policies {
+		if errs := p.Validate(field.NewPath(KindClusterPolicy).Key(p.Name)); len(errs) != 0 {
+			return nil, errs.ToAggregate()
+		}
+
+		for _, rule := range p.Spec.Rules {
+			program, err := compile(rule.Expression)
+			if err != nil {
+				return nil, fmt.Errorf("compiling rule %q of policy %q: %w", rule.Name, p.Name, err)
+			}
+			action := rule.Action
+			if action == "" {
+				action = ActionDeny
+			}
+
+			for _, t := range targets[rule.Target] {
+				// An expression which fails to evaluate, e.g. because it selects a field that is not set, is a violation
+				result, _, err := program.Eval(map[string]interface{}{ObjectVariable: t.data})
+				if err == nil && result == types.True {
+					continue
+				}
+				message := rule.Message
+				if err != nil {
+					klog.V(2).Infof("evaluating rule %q of policy %q against %s: %v", rule.Name, p.Name, t.name, err)
+					if message == "" {
+						message = fmt.Sprintf("evaluating expression: %v", err)
+					} else {
+						message = fmt.Sprintf("%s (evaluating expression: %v)", message, err)
+					}
+				}
+				violations = append(violations, &Violation{
+					Policy:  p.Name,
+					Rule:    rule.Name,
+					Action:  action,
+					Object:  t.name,
+					Message: message,
+				})
+			}
+		}
+	}
+	return violations, nil
+}
+
+// toData converts an object to its versioned JSON representation, as seen by users, for evaluation.
+func toData(obj runtime.Object) (interface{}, error) {
+	b, err := kopscodecs.ToVersionedJSON(obj)
+	if err != nil {
+		return nil, fmt.Errorf("serializing %T: %w", obj, err)
+	}
+	var data interface{}
+	if err := json.Unmarshal(b, &data); err != nil {
+		return nil, fmt.Errorf("parsing %T: %w", obj, err)
+	}
+	return data, nil
+}
+
+// Enforce evaluates the ClusterPolicy objects amongst the additional objects against the cluster and instance groups.
+// Violations of Warn rules are logged, and violations of Deny rules are returned as an error.
+func Enforce(objects kubemanifest.ObjectList, cluster *kops.Cluster, instanceGroups []*kops.InstanceGroup) error {
+	policies, err := FromObjects(objects)
+	if err != nil {
+		return err
+	}
+	violations, err := Evaluate(policies, cluster, instanceGroups)
+	if err != nil {
+		return err
+	}
+
+	var denied []string
+	for _, v := range violations {
+		if v.Action == ActionWarn {
+			klog.Warningf("cluster policy violation: %s", v)
+			continue
+		}
+		denied = append(denied, v.String())
+	}
+	if len(denied) != 0 {
+		return fmt.Errorf("cluster does not satisfy its policies:\n  %s", strings.Join(denied, "\n  "))
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/kubemanifest"
+)
+
+const testPolicies = `
+apiVersion: policy.kops.k8s.io/v1alpha1
+kind: ClusterPolicy
+metadata:
+  name: org
+spec:
+  rules:
+  - name: internal-api
+    target: Cluster
+    expression: "object.spec.api.loadBalancer.type == 'Internal'"
+    message: the API load balancer must be internal
+  - name: cost-center
+    target: Cluster
+    expression: "has(object.spec.cloudLabels) && 'cost-center' in object.spec.cloudLabels"
+    action: Warn
+  - name: machine-types
+    target: InstanceGroup
+    expression: "object.spec.machineType in ['m6i.large', 'm6i.xlarge']"
+    message: machine type is not approved
+  - name: no-spot
+    target: InstanceGroup
+    expression: "object.spec.maxPrice == ''"
+    action: Warn
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: unrelated
+`
+
+func testObjects(t *testing.T) kubemanifest.ObjectList {
+	objects, err := kubemanifest.LoadObjectsFrom([]byte(testPolicies))
+	if err != nil {
+		t.Fatalf("error parsing objects: %v", err)
+	}
+	return objects
+}
+
+func testSpecs(lbType kops.LoadBalancerType, machineTypes ...string) (*kops.Cluster, []*kops.InstanceGroup) {
+	cluster := &kops.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "minimal.example.com"},
+		Spec: kops.ClusterSpec{
+			API: kops.APISpec{
+				LoadBalancer: &kops.LoadBalancerAccessSpec{Type: lbType},
+			},
+		},
+	}
+	var instanceGroups []*kops.InstanceGroup
+	for _, machineType := range machineTypes {
+		instanceGroups = append(instanceGroups, &kops.InstanceGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: "ig-" + machineType},
+			Spec:       kops.InstanceGroupSpec{MachineType: machineType},
+		})
+	}
+	return cluster, instanceGroups
+}
+
+func TestEvaluate(t *testing.T) {
+	policies, err := FromObjects(testObjects(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(policies) != 1 {
+		t.Fatalf("expected 1 policy, got %d", len(policies))
+	}
+
+	cluster, instanceGroups := testSpecs(kops.LoadBalancerTypePublic, "m6i.large", "t3.micro")
+	violations, err := Evaluate(policies, cluster, instanceGroups)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var actual []string
+	for _, v := range violations {
+		actual = append(actual, v.Action+" "+v.Object+" "+v.Rule)
+	}
+	expected := []string{
+		"Deny Cluster/minimal.example.com internal-api",
+		"Warn Cluster/minimal.example.com cost-center",
+		"Deny InstanceGroup/ig-t3.micro machine-types",
+		"Warn InstanceGroup/ig-m6i.large no-spot",
+		"Warn InstanceGroup/ig-t3.micro no-spot",
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected violations; expected %v, got %v", expected, actual)
+	}
+
+	// maxPrice is not set, so the expression selecting it fails to evaluate
+	if message := violations[3].Message; !strings.Contains(message, "evaluating expression: no such key: maxPrice") {
+		t.Errorf("unexpected message for an expression which failed to evaluate: %q", message)
+	}
+
+	// Without a cluster, only the rules targeting instance groups are evaluated
+	violations, err = Evaluate(policies, nil, instanceGroups[1:])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	actual = nil
+	for _, v := range violations {
+		actual = append(actual, v.Action+" "+v.Object+" "+v.Rule)
+	}
+	expected = []string{
+		"Deny InstanceGroup/ig-t3.micro machine-types",
+		"Warn InstanceGroup/ig-t3.micro no-spot",
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected violations without a cluster; expected %v, got %v", expected, actual)
+	}
+}
+
+func TestEnforce(t *testing.T) {
+	cluster, instanceGroups := testSpecs(kops.LoadBalancerTypeInternal, "m6i.xlarge")
+	if err := Enforce(testObjects(t), cluster, instanceGroups); err != nil {
+		t.Errorf("unexpected error for compliant specs: %v", err)
+	}
+
+	cluster, instanceGroups = testSpecs(kops.LoadBalancerTypeInternal, "t3.micro")
+	err := Enforce(testObjects(t), cluster, instanceGroups)
+	if err == nil {
+		t.Fatalf("expected an error for a denied machine type")
+	}
+	if !strings.Contains(err.Error(), "InstanceGroup/ig-t3.micro: machine type is not approved") {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if err := Enforce(nil, cluster, instanceGroups); err != nil {
+		t.Errorf("unexpected error without policies: %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	p := &ClusterPolicy{
+		Spec: ClusterPolicySpec{
+			Rules: []Rule{
+				{Name: "a", Target: TargetCluster, Expression: "object.spec.api.loadBalancer.type == 'Public'"},
+				{Name: "a", Target: "Node", Expression: "object.spec.api", Action: "Audit"},
+				{Target: TargetInstanceGroup, Expression: "object.spec.[["},
+				{Name: "b", Target: TargetCluster, Expression: "size(object.spec.cloudLabels)"},
+			},
+		},
+	}
+	var actual []string
+	for _, err := range p.Validate(field.NewPath("policy")) {
+		actual = append(actual, string(err.Type)+"::"+err.Field)
+	}
+	expected := []string{
+		"FieldValueDuplicate::policy.spec.rules[1].name",
+		"FieldValueNotSupported::policy.spec.rules[1].target",
+		"FieldValueNotSupported::policy.spec.rules[1].action",
+		"FieldValueRequired::policy.spec.rules[2].name",
+		"FieldValueInvalid::policy.spec.rules[2].expression",
+		"FieldValueInvalid::policy.spec.rules[3].expression",
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected errors; expected %v, got %v", expected, actual)
+	}
+}
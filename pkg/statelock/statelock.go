@@ -0,0 +1,337 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package statelock implements an advisory lock on a cluster's state store,
+// which commands that change the cluster hold so that they do not overwrite each other's changes.
+package statelock
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"k8s.io/kops/pkg/acls"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/registry"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// leaseDuration is how long a lock is valid without being renewed.
+// The holder renews the lock every third of leaseDuration, so the lock of a command that was killed expires within leaseDuration.
+var leaseDuration = 15 * time.Minute
+
+// Lease is the content of a lock, as recorded in the state store.
+type Lease struct {
+	// ID identifies the holder of the lock.
+	ID string `json:"id"`
+	// ClusterName is the name of the locked cluster.
+	ClusterName string `json:"clusterName"`
+	// Owner is the user running the command holding the lock.
+	Owner string `json:"owner,omitempty"`
+	// Host is the hostname of the machine running the command holding the lock.
+	Host string `json:"host,omitempty"`
+	// Operation is the command holding the lock.
+	Operation string `json:"operation,omitempty"`
+	// AcquireTime is when the lock was acquired.
+	AcquireTime time.Time `json:"acquireTime"`
+	// ExpireTime is when the lock expires, unless it is renewed.
+	ExpireTime time.Time `json:"expireTime"`
+}
+
+// Expired returns true if the lease has not been renewed in time.
+func (l *Lease) Expired() bool {
+	return time.Now().After(l.ExpireTime)
+}
+
+// LockedError is returned when a lock is held by another command.
+type LockedError struct {
+	Lease *Lease
+}
+
+func (e *LockedError) Error() string {
+	l := e.Lease
+	return fmt.Sprintf("the state store for cluster %q is locked by %s on %s, running %q since %s (expires %s); if that command is no longer running, remove the lock with \"kops delete lock --force\"",
+		l.ClusterName, l.Owner, l.Host, l.Operation, l.AcquireTime.Local().Format(time.RFC3339), l.ExpireTime.Local().Format(time.RFC3339))
+}
+
+// LockPath returns the path in the state store of the lock for a cluster.
+func LockPath(vfsContext *vfs.VFSContext, cluster *kops.Cluster) (vfs.Path, error) {
+	configBase, err := registry.ConfigBase(vfsContext, cluster)
+	if err != nil {
+		return nil, err
+	}
+	return configBase.Join(registry.PathStateStoreLock), nil
+}
+
+// ReadLease reads the lease at the specified path; it returns (nil, nil) if the lock is not held.
+func ReadLease(ctx context.Context, p vfs.Path) (*Lease, error) {
+	lease, _, err := readLeaseVersion(ctx, p)
+	return lease, err
+}
+
+// readLeaseVersion reads the lease at the specified path, along with the version of the file if the path supports
+// conditional writes; it returns a nil lease if the lock is not held.
+func readLeaseVersion(ctx context.Context, p vfs.Path) (*Lease, string, error) {
+	var data []byte
+	var version string
+	var err error
+	if cp, ok := p.(vfs.ConditionalPath); ok {
+		data, version, err = cp.ReadFileVersion(ctx)
+	} else {
+		data, err = p.ReadFile(ctx)
+	}
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, "", nil
+		}
+		return nil, "", fmt.Errorf("error reading lock %s: %w", p, err)
+	}
+	lease := &Lease{}
+	if err := json.Unmarshal(data, lease); err != nil {
+		return nil, "", fmt.Errorf("error parsing lock %s: %w", p, err)
+	}
+	return lease, version, nil
+}
+
+// Lock is a held lock on a cluster's state store.
+type Lock struct {
+	path  vfs.Path
+	acl   vfs.ACL
+	lease Lease
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+type heldLockKey struct{}
+
+// WithLock returns a context recording that the caller holds the lock, for commands that run other commands
+// while holding it. Acquire of the same lock with the returned context returns nil instead of failing.
+func WithLock(ctx context.Context, lock *Lock) context.Context {
+	if lock == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, heldLockKey{}, lock)
+}
+
+// Acquire takes the lock on the cluster's state store, for the named operation.
+// It fails with a LockedError if another command holds the lock, and takes over locks that have expired.
+// The lock is renewed in the background until it is released.
+// State stores kept in the Kubernetes API have their own concurrency control, so no lock is taken for them and nil is returned.
+// If the context records that the caller already holds the lock (see WithLock), nil is returned as well.
+func Acquire(ctx context.Context, vfsContext *vfs.VFSContext, cluster *kops.Cluster, operation string) (*Lock, error) {
+	p, err := LockPath(vfsContext, cluster)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := p.(*vfs.KubernetesPath); ok {
+		return nil, nil
+	}
+	acl, err := acls.GetACL(ctx, p, cluster)
+	if err != nil {
+		return nil, err
+	}
+	return acquire(ctx, p, acl, cluster.ObjectMeta.Name, operation)
+}
+
+func acquire(ctx context.Context, p vfs.Path, acl vfs.ACL, clusterName string, operation string) (*Lock, error) {
+	if held, ok := ctx.Value(heldLockKey{}).(*Lock); ok && held.path.Path() == p.Path() {
+		klog.V(2).Infof("state store lock %s is already held", p)
+		return nil, nil
+	}
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("error generating lock id: %w", err)
+	}
+
+	now := time.Now().UTC()
+	l := &Lock{
+		path: p,
+		acl:  acl,
+		lease: Lease{
+			ID:          hex.EncodeToString(id),
+			ClusterName: clusterName,
+			Owner:       currentUser(),
+			Operation:   operation,
+			AcquireTime: now,
+			ExpireTime:  now.Add(leaseDuration),
+		},
+	}
+	if hostname, err := os.Hostname(); err == nil {
+		l.lease.Host = hostname
+	}
+
+	data, err := json.Marshal(&l.lease)
+	if err != nil {
+		return nil, fmt.Errorf("error serializing lock: %w", err)
+	}
+
+	err = p.CreateFile(ctx, bytes.NewReader(data), acl)
+	if errors.Is(err, os.ErrExist) {
+		err = takeOver(ctx, p, acl, data, clusterName)
+	}
+	if err != nil {
+		var lockedErr *LockedError
+		if errors.As(err, &lockedErr) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("error writing lock %s: %w", p, err)
+	}
+
+	klog.V(2).Infof("acquired state store lock %s", p)
+
+	l.stop = make(chan struct{})
+	l.done = make(chan struct{})
+	go l.renewLoop()
+
+	return l, nil
+}
+
+// takeOver replaces an expired lock with the specified lease, returning a LockedError if the lock is held.
+// Where the state store supports conditional writes, the expired lock is only replaced if it was not changed
+// since it was read, so that of several commands taking over the same expired lock only one succeeds.
+// Otherwise the expired lock is removed and the lease created again, which leaves a small window in which
+// a command that read the expired lock can remove a lock just taken over by another command.
+func takeOver(ctx context.Context, p vfs.Path, acl vfs.ACL, data []byte, clusterName string) error {
+	existing, version, err := readLeaseVersion(ctx, p)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		if !existing.Expired() {
+			return &LockedError{Lease: existing}
+		}
+		klog.Warningf("Taking over the expired state store lock held by %s on %s since %s", existing.Owner, existing.Host, existing.AcquireTime.Local().Format(time.RFC3339))
+	}
+
+	if cp, ok := p.(vfs.ConditionalPath); ok && existing != nil {
+		err = cp.ReplaceFile(ctx, bytes.NewReader(data), acl, version)
+	} else {
+		if existing != nil {
+			if err := p.Remove(ctx); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("error removing expired lock %s: %w", p, err)
+			}
+		}
+		err = p.CreateFile(ctx, bytes.NewReader(data), acl)
+	}
+	if errors.Is(err, vfs.ErrVersionMismatch) || errors.Is(err, os.ErrExist) {
+		// Another command took over the lock first
+		existing, err := ReadLease(ctx, p)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return &LockedError{Lease: existing}
+		}
+		return fmt.Errorf("the state store for cluster %q is being locked by another command", clusterName)
+	}
+	return err
+}
+
+// renewLoop renews the lock until it is released, or until another command takes it over.
+func (l *Lock) renewLoop() {
+	defer close(l.done)
+
+	ticker := time.NewTicker(leaseDuration / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			held, err := l.renew(context.TODO())
+			if err != nil {
+				klog.Warningf("error renewing state store lock: %v", err)
+			} else if !held {
+				klog.Warningf("The state store lock for cluster %q was removed or taken over by another command", l.lease.ClusterName)
+				return
+			}
+		}
+	}
+}
+
+// renew extends the expiry of the lock, returning false if the lock is no longer held.
+// Where the state store supports conditional writes, the lock is only rewritten if it was not taken over since it was read.
+func (l *Lock) renew(ctx context.Context) (bool, error) {
+	current, version, err := readLeaseVersion(ctx, l.path)
+	if err != nil {
+		return true, err
+	}
+	if current == nil || current.ID != l.lease.ID {
+		return false, nil
+	}
+
+	l.lease.ExpireTime = time.Now().UTC().Add(leaseDuration)
+	data, err := json.Marshal(&l.lease)
+	if err != nil {
+		return true, fmt.Errorf("error serializing lock: %w", err)
+	}
+	if cp, ok := l.path.(vfs.ConditionalPath); ok {
+		err = cp.ReplaceFile(ctx, bytes.NewReader(data), l.acl, version)
+		if errors.Is(err, vfs.ErrVersionMismatch) {
+			return false, nil
+		}
+	} else {
+		err = l.path.WriteFile(ctx, bytes.NewReader(data), l.acl)
+	}
+	if err != nil {
+		return true, fmt.Errorf("error writing lock %s: %w", l.path, err)
+	}
+	return true, nil
+}
+
+// Release stops renewing the lock and removes it from the state store, unless another command has taken it over.
+// Errors are logged rather than returned, as the lock expires anyway. It is a no-op on a nil Lock.
+func (l *Lock) Release(ctx context.Context) {
+	if l == nil {
+		return
+	}
+	close(l.stop)
+	<-l.done
+
+	current, err := ReadLease(ctx, l.path)
+	if err != nil {
+		klog.Warningf("error releasing state store lock: %v", err)
+		return
+	}
+	if current == nil || current.ID != l.lease.ID {
+		return
+	}
+	if err := l.path.Remove(ctx); err != nil && !os.IsNotExist(err) {
+		klog.Warningf("error removing state store lock %s: %v", l.path, err)
+		return
+	}
+	klog.V(2).Infof("released state store lock %s", l.path)
+}
+
+// currentUser returns the name of the user running kops.
+func currentUser() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statelock
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func TestAcquireAndRelease(t *testing.T) {
+	ctx := context.TODO()
+	p := vfs.NewMemFSPath(vfs.NewMemFSContext(), "cluster/lock.json")
+
+	lock, err := acquire(ctx, p, nil, "test.k8s.local", "update cluster")
+	require.NoError(t, err, "acquiring lock")
+
+	lease, err := ReadLease(ctx, p)
+	require.NoError(t, err, "reading lease")
+	require.NotNil(t, lease)
+	assert.Equal(t, "test.k8s.local", lease.ClusterName)
+	assert.Equal(t, "update cluster", lease.Operation)
+	assert.False(t, lease.Expired())
+
+	_, err = acquire(ctx, p, nil, "test.k8s.local", "edit cluster")
+	var lockedErr *LockedError
+	if assert.True(t, errors.As(err, &lockedErr), "expected LockedError, got %v", err) {
+		assert.Equal(t, lease.ID, lockedErr.Lease.ID)
+		assert.Equal(t, "update cluster", lockedErr.Lease.Operation)
+	}
+
+	lock.Release(ctx)
+	lease, err = ReadLease(ctx, p)
+	require.NoError(t, err, "reading lease")
+	assert.Nil(t, lease, "lock should be removed on release")
+
+	lock, err = acquire(ctx, p, nil, "test.k8s.local", "edit cluster")
+	require.NoError(t, err, "acquiring released lock")
+	lock.Release(ctx)
+}
+
+func TestAcquireTakesOverExpiredLock(t *testing.T) {
+	ctx := context.TODO()
+	p := vfs.NewMemFSPath(vfs.NewMemFSContext(), "cluster/lock.json")
+
+	expired := &Lease{
+		ID:          "expired",
+		ClusterName: "test.k8s.local",
+		AcquireTime: time.Now().Add(-time.Hour),
+		ExpireTime:  time.Now().Add(-time.Minute),
+	}
+	data, err := json.Marshal(expired)
+	require.NoError(t, err)
+	require.NoError(t, p.WriteFile(ctx, bytes.NewReader(data), nil))
+
+	lock, err := acquire(ctx, p, nil, "test.k8s.local", "update cluster")
+	require.NoError(t, err, "acquiring expired lock")
+
+	lease, err := ReadLease(ctx, p)
+	require.NoError(t, err, "reading lease")
+	assert.Equal(t, lock.lease.ID, lease.ID)
+
+	lock.Release(ctx)
+}
+
+// racingPath simulates another command taking over the lock between reading and replacing it.
+type racingPath struct {
+	*vfs.MemFSPath
+	raced bool
+}
+
+func (p *racingPath) ReadFileVersion(ctx context.Context) ([]byte, string, error) {
+	data, version, err := p.MemFSPath.ReadFileVersion(ctx)
+	if err == nil && !p.raced {
+		p.raced = true
+		other := &Lease{
+			ID:          "other",
+			ClusterName: "test.k8s.local",
+			Operation:   "edit cluster",
+			AcquireTime: time.Now(),
+			ExpireTime:  time.Now().Add(time.Hour),
+		}
+		b, err := json.Marshal(other)
+		if err != nil {
+			return nil, "", err
+		}
+		if err := p.MemFSPath.WriteFile(ctx, bytes.NewReader(b), nil); err != nil {
+			return nil, "", err
+		}
+	}
+	return data, version, err
+}
+
+func TestAcquireDoesNotReplaceLockTakenOverConcurrently(t *testing.T) {
+	ctx := context.TODO()
+	p := &racingPath{MemFSPath: vfs.NewMemFSPath(vfs.NewMemFSContext(), "cluster/lock.json")}
+
+	expired := &Lease{
+		ID:          "expired",
+		ClusterName: "test.k8s.local",
+		AcquireTime: time.Now().Add(-time.Hour),
+		ExpireTime:  time.Now().Add(-time.Minute),
+	}
+	data, err := json.Marshal(expired)
+	require.NoError(t, err)
+	require.NoError(t, p.WriteFile(ctx, bytes.NewReader(data), nil))
+
+	_, err = acquire(ctx, p, nil, "test.k8s.local", "update cluster")
+	var lockedErr *LockedError
+	if assert.True(t, errors.As(err, &lockedErr), "expected LockedError, got %v", err) {
+		assert.Equal(t, "other", lockedErr.Lease.ID)
+	}
+
+	lease, err := ReadLease(ctx, p)
+	require.NoError(t, err, "reading lease")
+	assert.Equal(t, "other", lease.ID, "lock taken over by another command should not be replaced")
+}
+
+func TestReleaseKeepsLockTakenOver(t *testing.T) {
+	ctx := context.TODO()
+	p := vfs.NewMemFSPath(vfs.NewMemFSContext(), "cluster/lock.json")
+
+	lock, err := acquire(ctx, p, nil, "test.k8s.local", "update cluster")
+	require.NoError(t, err, "acquiring lock")
+
+	// Simulate "kops delete lock --force" followed by another command acquiring the lock
+	require.NoError(t, p.Remove(ctx))
+	other, err := acquire(ctx, p, nil, "test.k8s.local", "edit cluster")
+	require.NoError(t, err, "acquiring removed lock")
+
+	held, err := lock.renew(ctx)
+	require.NoError(t, err, "renewing lock")
+	assert.False(t, held, "lock should no longer be held")
+
+	lock.Release(ctx)
+	lease, err := ReadLease(ctx, p)
+	require.NoError(t, err, "reading lease")
+	if assert.NotNil(t, lease, "lock taken over should not be removed") {
+		assert.Equal(t, other.lease.ID, lease.ID)
+	}
+
+	other.Release(ctx)
+}
+
+func TestAcquireHeldLock(t *testing.T) {
+	ctx := context.TODO()
+	p := vfs.NewMemFSPath(vfs.NewMemFSContext(), "cluster/lock.json")
+
+	lock, err := acquire(ctx, p, nil, "test.k8s.local", "rotate keypair")
+	require.NoError(t, err, "acquiring lock")
+
+	nested, err := acquire(WithLock(ctx, lock), p, nil, "test.k8s.local", "update cluster")
+	require.NoError(t, err, "acquiring lock held by the caller")
+	assert.Nil(t, nested)
+	nested.Release(ctx)
+
+	lease, err := ReadLease(ctx, p)
+	require.NoError(t, err, "reading lease")
+	if assert.NotNil(t, lease, "lock should still be held") {
+		assert.Equal(t, lock.lease.ID, lease.ID)
+	}
+
+	other := vfs.NewMemFSPath(vfs.NewMemFSContext(), "other/lock.json")
+	otherLock, err := acquire(WithLock(ctx, lock), other, nil, "other.k8s.local", "update cluster")
+	require.NoError(t, err, "acquiring lock of another cluster")
+	assert.NotNil(t, otherLock)
+	otherLock.Release(ctx)
+
+	lock.Release(ctx)
+}
+
+func TestReleaseNil(t *testing.T) {
+	var lock *Lock
+	lock.Release(context.TODO())
+}
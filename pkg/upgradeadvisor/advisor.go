@@ -0,0 +1,274 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package upgradeadvisor finds what would break in a cluster spec, and in the manifests it references,
+// when its Kubernetes version is upgraded.
+package upgradeadvisor
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/channels/pkg/channels"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/validation"
+	"k8s.io/kops/pkg/flagbuilder"
+	"k8s.io/kops/pkg/kubemanifest"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// Severity is how an issue affects the upgrade.
+type Severity string
+
+const (
+	// SeverityBlocking issues must be fixed before the upgrade.
+	SeverityBlocking Severity = "Blocking"
+	// SeverityWarning issues should be reviewed before the upgrade.
+	SeverityWarning Severity = "Warning"
+)
+
+// Issue is something that breaks, or may break, when the cluster is upgraded.
+type Issue struct {
+	Severity Severity `json:"severity"`
+	// Item is the object or field with the issue.
+	Item    string `json:"item"`
+	Message string `json:"message"`
+}
+
+// Advisor analyzes a cluster for an upgrade from its current Kubernetes version.
+type Advisor struct {
+	VFSContext *vfs.VFSContext
+	// To is the Kubernetes version the cluster is upgraded to.
+	To semver.Version
+
+	issues []*Issue
+}
+
+// Analyze returns the issues found in the cluster, its instance groups and its additional objects,
+// sorted with blocking issues first.
+func (a *Advisor) Analyze(cluster *kops.Cluster, instanceGroups []*kops.InstanceGroup, additionalObjects kubemanifest.ObjectList) []*Issue {
+	a.issues = nil
+
+	a.analyzeValidation(cluster)
+
+	a.analyzeFlags("spec.kubelet", "kubelet", cluster.Spec.Kubelet)
+	a.analyzeFlags("spec.controlPlaneKubelet", "kubelet", cluster.Spec.ControlPlaneKubelet)
+	a.analyzeFlags("spec.kubeAPIServer", "kube-apiserver", cluster.Spec.KubeAPIServer)
+	a.analyzeFlags("spec.kubeControllerManager", "kube-controller-manager", cluster.Spec.KubeControllerManager)
+	a.analyzeFlags("spec.kubeScheduler", "kube-scheduler", cluster.Spec.KubeScheduler)
+	a.analyzeFlags("spec.kubeProxy", "kube-proxy", cluster.Spec.KubeProxy)
+	for _, ig := range instanceGroups {
+		a.analyzeFlags("InstanceGroup/"+ig.Name+" spec.kubelet", "kubelet", ig.Spec.Kubelet)
+	}
+
+	a.analyzeNetworking(cluster)
+
+	a.analyzeObjects("additional objects", additionalObjects)
+	a.analyzeAddons(cluster)
+
+	issues := a.issues
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Severity == SeverityBlocking && issues[j].Severity != SeverityBlocking
+	})
+	return issues
+}
+
+func (a *Advisor) addIssue(severity Severity, item string, format string, args ...interface{}) {
+	a.issues = append(a.issues, &Issue{
+		Severity: severity,
+		Item:     item,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// targetMinor returns the major and minor version of the target version.
+func (a *Advisor) targetMinor() semver.Version {
+	return semver.Version{Major: a.To.Major, Minor: a.To.Minor}
+}
+
+// isRemoved returns true if the target version is at or after the minor version.
+func (a *Advisor) isRemoved(minor string) bool {
+	return minor != "" && a.targetMinor().GTE(semver.MustParse(minor+".0"))
+}
+
+// isRemovedInNext returns true if the minor version is the one after the target version.
+func (a *Advisor) isRemovedInNext(minor string) bool {
+	if minor == "" {
+		return false
+	}
+	next := a.targetMinor()
+	next.Minor++
+	return next.EQ(semver.MustParse(minor + ".0"))
+}
+
+// analyzeValidation reports the validation errors of the cluster that only occur at the target version,
+// such as fields or networking providers that kOps no longer supports with that version.
+func (a *Advisor) analyzeValidation(cluster *kops.Cluster) {
+	current := make(map[string]bool)
+	for _, err := range validation.ValidateCluster(cluster, false, a.VFSContext) {
+		current[err.Error()] = true
+	}
+
+	upgraded := cluster.DeepCopy()
+	upgraded.Spec.KubernetesVersion = a.To.String()
+	for _, err := range validation.ValidateCluster(upgraded, false, a.VFSContext) {
+		if current[err.Error()] {
+			continue
+		}
+		a.addIssue(SeverityBlocking, err.Field, "%s", err.ErrorBody())
+	}
+}
+
+// analyzeFlags reports the deprecated or removed flags set by the options of a component.
+func (a *Advisor) analyzeFlags(item string, component string, options interface{}) {
+	if v := reflect.ValueOf(options); v.Kind() == reflect.Ptr && v.IsNil() {
+		return
+	}
+	flags, err := flagbuilder.BuildFlagsList(options)
+	if err != nil {
+		klog.Warningf("unable to build %s flags from %s: %v", component, item, err)
+		return
+	}
+
+	set := make(map[string]bool)
+	for _, flag := range flags {
+		name, _, _ := strings.Cut(strings.TrimPrefix(flag, "--"), "=")
+		set[name] = true
+	}
+
+	for _, change := range flagChanges {
+		if change.Component != component || !set[change.Flag] {
+			continue
+		}
+		replacement := ""
+		if change.Replacement != "" {
+			replacement = "; use " + change.Replacement + " instead"
+		}
+		switch {
+		case a.isRemoved(change.RemovedIn):
+			a.addIssue(SeverityBlocking, item, "%s flag --%s was removed in Kubernetes %s%s", component, change.Flag, change.RemovedIn, replacement)
+		case a.isRemovedInNext(change.RemovedIn):
+			a.addIssue(SeverityWarning, item, "%s flag --%s will be removed in Kubernetes %s%s", component, change.Flag, change.RemovedIn, replacement)
+		case a.isRemoved(change.DeprecatedIn):
+			a.addIssue(SeverityWarning, item, "%s flag --%s is deprecated since Kubernetes %s%s", component, change.Flag, change.DeprecatedIn, replacement)
+		}
+	}
+}
+
+// analyzeNetworking reports overridden networking provider versions that are not supported with the target version.
+func (a *Advisor) analyzeNetworking(cluster *kops.Cluster) {
+	networking := cluster.Spec.Networking
+	if networking.Calico != nil && networking.Calico.Version != "" {
+		a.analyzeNetworkingVersion("spec.networking.calico.version", "calico", networking.Calico.Version)
+	}
+	if networking.Cilium != nil && networking.Cilium.Version != "" {
+		a.analyzeNetworkingVersion("spec.networking.cilium.version", "cilium", networking.Cilium.Version)
+	}
+}
+
+func (a *Advisor) analyzeNetworkingVersion(item string, name string, version string) {
+	v, err := semver.ParseTolerant(version)
+	if err != nil {
+		klog.Warningf("unable to parse %s version %q: %v", name, version, err)
+		return
+	}
+	minor := fmt.Sprintf("%d.%d", v.Major, v.Minor)
+
+	for _, cni := range cniVersions {
+		if cni.Networking != name || cni.Version != minor {
+			continue
+		}
+		if a.targetMinor().GT(semver.MustParse(cni.MaxKubernetesVersion + ".0")) {
+			a.addIssue(SeverityWarning, item, "%s %s supports Kubernetes versions up to %s; remove the override to use the version kOps uses by default, or choose a newer version", name, minor, cni.MaxKubernetesVersion)
+		}
+		return
+	}
+}
+
+// analyzeObjects reports the objects that use API versions which are removed in the target version,
+// or in the version after it.
+func (a *Advisor) analyzeObjects(item string, objects kubemanifest.ObjectList) {
+	for _, object := range objects {
+		gvk := object.GroupVersionKind()
+		for _, api := range removedAPIs {
+			if !api.matches(gvk) {
+				continue
+			}
+			replacement := ""
+			if api.Replacement != "" {
+				replacement = "; migrate to " + api.Replacement
+			}
+			objectItem := fmt.Sprintf("%s: %s/%s", item, gvk.Kind, object.GetName())
+			if a.isRemoved(api.RemovedIn) {
+				a.addIssue(SeverityBlocking, objectItem, "%s %s is no longer served since Kubernetes %s%s", api.GroupVersion, gvk.Kind, api.RemovedIn, replacement)
+			} else if a.isRemovedInNext(api.RemovedIn) {
+				a.addIssue(SeverityWarning, objectItem, "%s %s will no longer be served in Kubernetes %s%s", api.GroupVersion, gvk.Kind, api.RemovedIn, replacement)
+			}
+		}
+	}
+}
+
+// analyzeAddons loads the manifests of the custom addons in spec.addons which apply to the target version.
+func (a *Advisor) analyzeAddons(cluster *kops.Cluster) {
+	for i, addon := range cluster.Spec.Addons {
+		item := fmt.Sprintf("spec.addons[%d]", i)
+
+		location, err := url.Parse(addon.Manifest)
+		if err != nil {
+			a.addIssue(SeverityWarning, item, "unable to parse addon location %q: %v", addon.Manifest, err)
+			continue
+		}
+		addons, err := channels.LoadAddons(a.VFSContext, addon.Manifest, location)
+		if err != nil {
+			a.addIssue(SeverityWarning, item, "unable to check addons: %v", err)
+			continue
+		}
+		menu, err := addons.GetCurrent(a.To)
+		if err != nil {
+			a.addIssue(SeverityWarning, item, "unable to check addons: %v", err)
+			continue
+		}
+
+		var names []string
+		for name := range menu.Addons {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			manifestURL, err := menu.Addons[name].GetManifestFullUrl()
+			if err != nil {
+				a.addIssue(SeverityWarning, item, "unable to check addon %q: %v", name, err)
+				continue
+			}
+			data, err := a.VFSContext.ReadFile(manifestURL.String())
+			if err != nil {
+				a.addIssue(SeverityWarning, item, "unable to read manifest of addon %q: %v", name, err)
+				continue
+			}
+			objects, err := kubemanifest.LoadObjectsFrom(data)
+			if err != nil {
+				a.addIssue(SeverityWarning, item, "unable to parse manifest of addon %q: %v", name, err)
+				continue
+			}
+			a.analyzeObjects(name, objects)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgradeadvisor
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/blang/semver/v4"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/kubemanifest"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func hasIssue(issues []*Issue, severity Severity, item string) bool {
+	for _, issue := range issues {
+		if issue.Severity == severity && issue.Item == item {
+			return true
+		}
+	}
+	return false
+}
+
+func TestAnalyzeFlags(t *testing.T) {
+	cluster := &kops.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "minimal.example.com"},
+		Spec: kops.ClusterSpec{
+			KubernetesVersion: "1.26.0",
+			KubeControllerManager: &kops.KubeControllerManagerConfig{
+				PodEvictionTimeout: &metav1.Duration{Duration: time.Minute},
+			},
+		},
+	}
+	instanceGroups := []*kops.InstanceGroup{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "nodes"},
+			Spec: kops.InstanceGroupSpec{
+				Kubelet: &kops.KubeletConfigSpec{
+					PodInfraContainerImage: "registry.k8s.io/pause:3.9",
+				},
+			},
+		},
+	}
+
+	grid := []struct {
+		to       string
+		severity Severity
+		item     string
+	}{
+		{to: "1.27.3", severity: SeverityBlocking, item: "spec.kubeControllerManager"},
+		{to: "1.26.5", severity: SeverityWarning, item: "spec.kubeControllerManager"},
+		{to: "1.27.3", severity: SeverityWarning, item: "InstanceGroup/nodes spec.kubelet"},
+	}
+	for _, g := range grid {
+		a := &Advisor{VFSContext: vfs.NewTestingVFSContext(), To: semver.MustParse(g.to)}
+		issues := a.Analyze(cluster, instanceGroups, nil)
+		if !hasIssue(issues, g.severity, g.item) {
+			t.Errorf("expected %s issue for %s when upgrading to %s, got %+v", g.severity, g.item, g.to, issues)
+		}
+	}
+
+	a := &Advisor{VFSContext: vfs.NewTestingVFSContext(), To: semver.MustParse("1.26.0")}
+	if issues := a.Analyze(cluster, nil, nil); hasIssue(issues, SeverityBlocking, "spec.kubeControllerManager") {
+		t.Errorf("unexpected blocking issue before the flag is removed: %+v", issues)
+	}
+}
+
+func TestAnalyzeValidation(t *testing.T) {
+	cluster := &kops.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "minimal.example.com"},
+		Spec: kops.ClusterSpec{
+			KubernetesVersion: "1.27.0",
+			Networking: kops.NetworkingSpec{
+				Canal: &kops.CanalNetworkingSpec{},
+			},
+		},
+	}
+
+	a := &Advisor{VFSContext: vfs.NewTestingVFSContext(), To: semver.MustParse("1.28.0")}
+	issues := a.Analyze(cluster, nil, nil)
+	if !hasIssue(issues, SeverityBlocking, "spec.networking.canal") {
+		t.Errorf("expected blocking issue for canal, got %+v", issues)
+	}
+	if issues[0].Severity != SeverityBlocking {
+		t.Errorf("expected blocking issues first, got %+v", issues)
+	}
+}
+
+func TestAnalyzeNetworkingVersion(t *testing.T) {
+	cluster := &kops.Cluster{
+		Spec: kops.ClusterSpec{
+			KubernetesVersion: "1.30.0",
+			Networking: kops.NetworkingSpec{
+				Calico: &kops.CalicoNetworkingSpec{Version: "v3.28.1"},
+			},
+		},
+	}
+
+	a := &Advisor{To: semver.MustParse("1.31.0")}
+	a.analyzeNetworking(cluster)
+	if !hasIssue(a.issues, SeverityWarning, "spec.networking.calico.version") {
+		t.Errorf("expected warning for calico version, got %+v", a.issues)
+	}
+
+	a = &Advisor{To: semver.MustParse("1.30.2")}
+	a.analyzeNetworking(cluster)
+	if len(a.issues) != 0 {
+		t.Errorf("unexpected issues: %+v", a.issues)
+	}
+}
+
+func TestAnalyzeAddons(t *testing.T) {
+	ctx := context.TODO()
+	vfsContext := vfs.NewTestingVFSContext()
+
+	files := map[string]string{
+		"memfs://addons/addons.yaml": `
+kind: Addons
+metadata:
+  name: custom
+spec:
+  addons:
+  - name: custom.addons.example.com
+    version: 1.0.0
+    manifest: v1.0.0.yaml
+`,
+		"memfs://addons/v1.0.0.yaml": `
+apiVersion: policy/v1beta1
+kind: PodDisruptionBudget
+metadata:
+  name: custom
+---
+apiVersion: flowcontrol.apiserver.k8s.io/v1beta3
+kind: FlowSchema
+metadata:
+  name: custom
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: custom
+`,
+	}
+	for location, contents := range files {
+		p, err := vfsContext.BuildVfsPath(location)
+		if err != nil {
+			t.Fatalf("error building vfs path for %s: %v", location, err)
+		}
+		if err := p.WriteFile(ctx, bytes.NewReader([]byte(contents)), nil); err != nil {
+			t.Fatalf("error writing %s: %v", location, err)
+		}
+	}
+
+	cluster := &kops.Cluster{
+		Spec: kops.ClusterSpec{
+			Addons: []kops.AddonSpec{{Manifest: "memfs://addons/addons.yaml"}},
+		},
+	}
+
+	a := &Advisor{VFSContext: vfsContext, To: semver.MustParse("1.31.0")}
+	a.analyzeAddons(cluster)
+	if !hasIssue(a.issues, SeverityBlocking, "custom.addons.example.com: PodDisruptionBudget/custom") {
+		t.Errorf("expected blocking issue for PodDisruptionBudget, got %+v", a.issues)
+	}
+	if !hasIssue(a.issues, SeverityWarning, "custom.addons.example.com: FlowSchema/custom") {
+		t.Errorf("expected warning for FlowSchema, got %+v", a.issues)
+	}
+	if len(a.issues) != 2 {
+		t.Errorf("unexpected issues: %+v", a.issues)
+	}
+}
+
+func TestAnalyzeObjects(t *testing.T) {
+	objects, err := kubemanifest.LoadObjectsFrom([]byte(`
+apiVersion: batch/v1beta1
+kind: CronJob
+metadata:
+  name: cleanup
+`))
+	if err != nil {
+		t.Fatalf("error parsing objects: %v", err)
+	}
+
+	a := &Advisor{To: semver.MustParse("1.24.0")}
+	a.analyzeObjects("additional objects", objects)
+	if !hasIssue(a.issues, SeverityWarning, "additional objects: CronJob/cleanup") {
+		t.Errorf("expected warning for CronJob, got %+v", a.issues)
+	}
+
+	a = &Advisor{To: semver.MustParse("1.25.0")}
+	a.analyzeObjects("additional objects", objects)
+	if !hasIssue(a.issues, SeverityBlocking, "additional objects: CronJob/cleanup") {
+		t.Errorf("expected blocking issue for CronJob, got %+v", a.issues)
+	}
+
+	// Flags of unset components are ignored
+	a.analyzeFlags("spec.kubelet", "kubelet", (*kops.KubeletConfigSpec)(nil))
+	if len(a.issues) != 1 {
+		t.Errorf("unexpected issues: %+v", a.issues)
+	}
+}
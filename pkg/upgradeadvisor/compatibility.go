@@ -0,0 +1,127 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgradeadvisor
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// flagChange is a component flag that kOps exposes in the cluster spec, and that was deprecated or removed upstream.
+type flagChange struct {
+	Component    string
+	Flag         string
+	DeprecatedIn string
+	RemovedIn    string
+	// Replacement describes what should be used instead.
+	Replacement string
+}
+
+// flagChanges lists the flags of kOps-managed components which are deprecated or removed, by Kubernetes version.
+// It is not derived from the components, so it must be updated from the upstream changelog for each Kubernetes minor release.
+var flagChanges = []flagChange{
+	{Component: "kubelet", Flag: "network-plugin", DeprecatedIn: "1.20", RemovedIn: "1.24", Replacement: "the CNI configuration of the container runtime"},
+	{Component: "kubelet", Flag: "network-plugin-mtu", DeprecatedIn: "1.20", RemovedIn: "1.24", Replacement: "the CNI configuration of the container runtime"},
+	{Component: "kubelet", Flag: "image-pull-progress-deadline", DeprecatedIn: "1.20", RemovedIn: "1.24"},
+	{Component: "kubelet", Flag: "enable-cadvisor-json-endpoints", DeprecatedIn: "1.18", RemovedIn: "1.21"},
+	{Component: "kubelet", Flag: "pod-infra-container-image", DeprecatedIn: "1.27", Replacement: "spec.containerd.configAdditions to set the sandbox image"},
+	{Component: "kube-apiserver", Flag: "basic-auth-file", DeprecatedIn: "1.16", RemovedIn: "1.19", Replacement: "token, client certificate or OIDC authentication"},
+	{Component: "kube-apiserver", Flag: "audit-dynamic-configuration", RemovedIn: "1.19", Replacement: "spec.kubeAPIServer.auditWebhookConfigFile"},
+	{Component: "kube-apiserver", Flag: "address", DeprecatedIn: "1.20", RemovedIn: "1.24"},
+	{Component: "kube-apiserver", Flag: "insecure-port", DeprecatedIn: "1.20", RemovedIn: "1.24"},
+	{Component: "kube-apiserver", Flag: "insecure-bind-address", DeprecatedIn: "1.20", RemovedIn: "1.24"},
+	{Component: "kube-controller-manager", Flag: "experimental-cluster-signing-duration", DeprecatedIn: "1.19", RemovedIn: "1.25", Replacement: "cluster-signing-duration"},
+	{Component: "kube-controller-manager", Flag: "pod-eviction-timeout", DeprecatedIn: "1.24", RemovedIn: "1.27", Replacement: "the default tolerations for taint-based evictions"},
+}
+
+// removedAPI is an API version that is no longer served from a Kubernetes version.
+type removedAPI struct {
+	GroupVersion string
+	Kinds        []string
+	RemovedIn    string
+	// Replacement is the API version to migrate to, if any.
+	Replacement string
+}
+
+// removedAPIs lists the API versions removed upstream, by Kubernetes version.
+// Like flagChanges, it must be updated for each Kubernetes minor release.
+// See https://kubernetes.io/docs/reference/using-api/deprecation-guide/
+var removedAPIs = []removedAPI{
+	{GroupVersion: "extensions/v1beta1", Kinds: []string{"DaemonSet", "Deployment", "ReplicaSet"}, RemovedIn: "1.16", Replacement: "apps/v1"},
+	{GroupVersion: "extensions/v1beta1", Kinds: []string{"NetworkPolicy"}, RemovedIn: "1.16", Replacement: "networking.k8s.io/v1"},
+	{GroupVersion: "apps/v1beta1", RemovedIn: "1.16", Replacement: "apps/v1"},
+	{GroupVersion: "apps/v1beta2", RemovedIn: "1.16", Replacement: "apps/v1"},
+	{GroupVersion: "admissionregistration.k8s.io/v1beta1", RemovedIn: "1.22", Replacement: "admissionregistration.k8s.io/v1"},
+	{GroupVersion: "apiextensions.k8s.io/v1beta1", RemovedIn: "1.22", Replacement: "apiextensions.k8s.io/v1"},
+	{GroupVersion: "apiregistration.k8s.io/v1beta1", RemovedIn: "1.22", Replacement: "apiregistration.k8s.io/v1"},
+	{GroupVersion: "authentication.k8s.io/v1beta1", RemovedIn: "1.22", Replacement: "authentication.k8s.io/v1"},
+	{GroupVersion: "authorization.k8s.io/v1beta1", RemovedIn: "1.22", Replacement: "authorization.k8s.io/v1"},
+	{GroupVersion: "certificates.k8s.io/v1beta1", RemovedIn: "1.22", Replacement: "certificates.k8s.io/v1"},
+	{GroupVersion: "coordination.k8s.io/v1beta1", RemovedIn: "1.22", Replacement: "coordination.k8s.io/v1"},
+	{GroupVersion: "extensions/v1beta1", Kinds: []string{"Ingress"}, RemovedIn: "1.22", Replacement: "networking.k8s.io/v1"},
+	{GroupVersion: "networking.k8s.io/v1beta1", RemovedIn: "1.22", Replacement: "networking.k8s.io/v1"},
+	{GroupVersion: "rbac.authorization.k8s.io/v1beta1", RemovedIn: "1.22", Replacement: "rbac.authorization.k8s.io/v1"},
+	{GroupVersion: "scheduling.k8s.io/v1beta1", RemovedIn: "1.22", Replacement: "scheduling.k8s.io/v1"},
+	{GroupVersion: "storage.k8s.io/v1beta1", Kinds: []string{"CSIDriver", "CSINode", "StorageClass", "VolumeAttachment"}, RemovedIn: "1.22", Replacement: "storage.k8s.io/v1"},
+	{GroupVersion: "batch/v1beta1", RemovedIn: "1.25", Replacement: "batch/v1"},
+	{GroupVersion: "discovery.k8s.io/v1beta1", RemovedIn: "1.25", Replacement: "discovery.k8s.io/v1"},
+	{GroupVersion: "events.k8s.io/v1beta1", RemovedIn: "1.25", Replacement: "events.k8s.io/v1"},
+	{GroupVersion: "autoscaling/v2beta1", RemovedIn: "1.25", Replacement: "autoscaling/v2"},
+	{GroupVersion: "policy/v1beta1", Kinds: []string{"PodDisruptionBudget"}, RemovedIn: "1.25", Replacement: "policy/v1"},
+	{GroupVersion: "policy/v1beta1", Kinds: []string{"PodSecurityPolicy"}, RemovedIn: "1.25"},
+	{GroupVersion: "node.k8s.io/v1beta1", RemovedIn: "1.25", Replacement: "node.k8s.io/v1"},
+	{GroupVersion: "flowcontrol.apiserver.k8s.io/v1beta1", RemovedIn: "1.26", Replacement: "flowcontrol.apiserver.k8s.io/v1"},
+	{GroupVersion: "autoscaling/v2beta2", RemovedIn: "1.26", Replacement: "autoscaling/v2"},
+	{GroupVersion: "storage.k8s.io/v1beta1", Kinds: []string{"CSIStorageCapacity"}, RemovedIn: "1.27", Replacement: "storage.k8s.io/v1"},
+	{GroupVersion: "flowcontrol.apiserver.k8s.io/v1beta2", RemovedIn: "1.29", Replacement: "flowcontrol.apiserver.k8s.io/v1"},
+	{GroupVersion: "flowcontrol.apiserver.k8s.io/v1beta3", RemovedIn: "1.32", Replacement: "flowcontrol.apiserver.k8s.io/v1"},
+}
+
+// matches returns true if the API removal applies to objects of the kind.
+func (r *removedAPI) matches(gvk schema.GroupVersionKind) bool {
+	if gvk.GroupVersion().String() != r.GroupVersion {
+		return false
+	}
+	if len(r.Kinds) == 0 {
+		return true
+	}
+	for _, kind := range r.Kinds {
+		if kind == gvk.Kind {
+			return true
+		}
+	}
+	return false
+}
+
+// cniVersion is the newest Kubernetes version that a minor version of a networking provider was tested with upstream.
+type cniVersion struct {
+	Networking string
+	Version    string
+	// MaxKubernetesVersion is the newest Kubernetes version listed as supported by the release.
+	MaxKubernetesVersion string
+}
+
+// cniVersions lists the Kubernetes versions supported by the networking providers whose version can be overridden.
+var cniVersions = []cniVersion{
+	{Networking: "calico", Version: "3.26", MaxKubernetesVersion: "1.28"},
+	{Networking: "calico", Version: "3.27", MaxKubernetesVersion: "1.29"},
+	{Networking: "calico", Version: "3.28", MaxKubernetesVersion: "1.30"},
+	{Networking: "calico", Version: "3.29", MaxKubernetesVersion: "1.31"},
+	{Networking: "calico", Version: "3.30", MaxKubernetesVersion: "1.33"},
+	{Networking: "cilium", Version: "1.15", MaxKubernetesVersion: "1.29"},
+	{Networking: "cilium", Version: "1.16", MaxKubernetesVersion: "1.30"},
+	{Networking: "cilium", Version: "1.17", MaxKubernetesVersion: "1.32"},
+}
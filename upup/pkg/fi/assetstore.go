@@ -32,6 +32,8 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/kops/upup/pkg/fi/utils"
 	"k8s.io/kops/util/pkg/hashing"
+	"k8s.io/kops/util/pkg/signature"
+	"k8s.io/kops/util/pkg/vfs"
 )
 
 type asset struct {
@@ -93,6 +95,9 @@ func (r *assetResource) GetSource() *Source {
 type AssetStore struct {
 	cacheDir string
 	assets   []*asset
+
+	// SignatureVerifier, if set, verifies the detached signature of each asset that is added.
+	SignatureVerifier *signature.Verifier
 }
 
 func NewAssetStore(cacheDir string) *AssetStore {
@@ -200,8 +205,18 @@ func hashFromHTTPHeader(url string) (*hashing.Hash, error) {
 
 // Add an asset into the store, in one of the recognized formats (see Assets in types package)
 func (a *AssetStore) Add(id string) error {
+	urls, hash, err := ParseAsset(id)
+	if err != nil {
+		return err
+	}
+	return a.addURLs(urls, hash)
+}
+
+// ParseAsset parses an asset in one of the recognized formats, "<url>[,<url>...]" or "<hash>@<url>[,<url>...]".
+// The hash is nil if the asset does not specify one.
+func ParseAsset(id string) ([]string, *hashing.Hash, error) {
 	if strings.HasPrefix(id, "http://") || strings.HasPrefix(id, "https://") {
-		return a.addURLs(strings.Split(id, ","), nil)
+		return strings.Split(id, ","), nil, nil
 	}
 	i := strings.Index(id, "@http://")
 	if i == -1 {
@@ -211,12 +226,18 @@ func (a *AssetStore) Add(id string) error {
 		urls := strings.Split(id[i+1:], ",")
 		hash, err := hashing.FromString(id[:i])
 		if err != nil {
-			return err
+			return nil, nil, err
 		}
-		return a.addURLs(urls, hash)
+		return urls, hash, nil
 	}
 	// TODO: local files!
-	return fmt.Errorf("unknown asset format: %q", id)
+	return nil, nil, fmt.Errorf("unknown asset format: %q", id)
+}
+
+// AssetCachePath returns the path in cacheDir to which an asset is downloaded, wherever we get it from.
+// A file with the expected hash at that path is not downloaded again.
+func AssetCachePath(cacheDir string, primaryURL string, hash *hashing.Hash) string {
+	return path.Join(cacheDir, hash.String()+"_"+utils.SanitizeString(path.Base(primaryURL)))
 }
 
 func (a *AssetStore) addURLs(urls []string, hash *hashing.Hash) error {
@@ -243,7 +264,7 @@ func (a *AssetStore) addURLs(urls []string, hash *hashing.Hash) error {
 	// We assume the first url is the "main" url, and download to the base of that _name_, wherever we get it from
 	primaryURL := urls[0]
 	key := path.Base(primaryURL)
-	localFile := path.Join(a.cacheDir, hash.String()+"_"+utils.SanitizeString(key))
+	localFile := AssetCachePath(a.cacheDir, primaryURL, hash)
 
 	for _, url := range urls {
 		_, err = DownloadURL(url, localFile, hash)
@@ -258,6 +279,12 @@ func (a *AssetStore) addURLs(urls []string, hash *hashing.Hash) error {
 		return err
 	}
 
+	if a.SignatureVerifier != nil {
+		if err := a.SignatureVerifier.VerifyFile(vfs.Context, urls, hash); err != nil {
+			return err
+		}
+	}
+
 	assetPath := primaryURL
 	r := NewFileResource(localFile)
 
@@ -42,6 +42,10 @@ type Cloud interface {
 	// DetachInstance causes a cloud instance to no longer be counted against the group's size limits.
 	DetachInstance(instance *cloudinstances.CloudInstance) error
 
+	// RollbackGroup reverts a CloudInstanceGroup to the launch template or instance template that its
+	// instances needing update were created from, so that replacement instances use the previous specification.
+	RollbackGroup(group *cloudinstances.CloudInstanceGroup) error
+
 	// GetCloudGroups returns a map of cloud instances that back a kops cluster.
 	// Detached instances must be returned in the NeedUpdate slice.
 	GetCloudGroups(cluster *kops.Cluster, instancegroups []*kops.InstanceGroup, warnUnmatched bool, nodes []v1.Node) (map[string]*cloudinstances.CloudInstanceGroup, error)
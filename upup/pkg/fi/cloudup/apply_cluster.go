@@ -50,6 +50,7 @@ import (
 	"k8s.io/kops/pkg/model/openstackmodel"
 	"k8s.io/kops/pkg/model/scalewaymodel"
 	"k8s.io/kops/pkg/nodemodel"
+	"k8s.io/kops/pkg/policy"
 	"k8s.io/kops/pkg/predicates"
 	"k8s.io/kops/pkg/templates"
 	"k8s.io/kops/upup/models"
@@ -106,6 +107,9 @@ type ApplyClusterCmd struct {
 	// DryRun is true if this is only a dry run
 	DryRun bool
 
+	// DryRunOutput is where the dry-run report is written; defaults to os.Stdout.
+	DryRunOutput io.Writer
+
 	// AllowKopsDowngrade permits applying with a kops version older than what was last used to apply to the cluster.
 	AllowKopsDowngrade bool
 
@@ -245,6 +249,10 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) (*ApplyResults, error) {
 		return nil, err
 	}
 
+	if err := policy.Enforce(c.AdditionalObjects, c.Cluster, c.InstanceGroups); err != nil {
+		return nil, err
+	}
+
 	err = c.validateKopsVersion()
 	if err != nil {
 		return nil, err
@@ -771,6 +779,9 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) (*ApplyResults, error) {
 
 	case TargetDryRun:
 		var out io.Writer = os.Stdout
+		if c.DryRunOutput != nil {
+			out = c.DryRunOutput
+		}
 		checkExisting := true
 		if c.GetAssets {
 			out = io.Discard
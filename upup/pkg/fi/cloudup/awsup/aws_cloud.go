@@ -701,6 +701,69 @@ func detachInstance(ctx context.Context, c AWSCloud, i *cloudinstances.CloudInst
 	return nil
 }
 
+// RollbackGroup pins an autoscaling group to the launch template version or launch configuration
+// that its instances needing update were created from.
+func (c *awsCloudImplementation) RollbackGroup(g *cloudinstances.CloudInstanceGroup) error {
+	ctx := context.TODO()
+
+	if c.spotinst != nil {
+		return fmt.Errorf("rolling back instance groups is not supported with spotinst")
+	}
+
+	return rollbackGroup(ctx, c, g)
+}
+
+func rollbackGroup(ctx context.Context, c AWSCloud, g *cloudinstances.CloudInstanceGroup) error {
+	asg := g.Raw.(*autoscalingtypes.AutoScalingGroup)
+
+	needUpdate := make(map[string]bool)
+	for _, i := range g.NeedUpdate {
+		needUpdate[i.ID] = true
+	}
+
+	var previous *autoscalingtypes.Instance
+	for i := range asg.Instances {
+		if needUpdate[aws.ToString(asg.Instances[i].InstanceId)] {
+			previous = &asg.Instances[i]
+			break
+		}
+	}
+	if previous == nil {
+		return fmt.Errorf("unable to find previous launch template for autoscaling group %q", g.HumanName)
+	}
+
+	input := &autoscaling.UpdateAutoScalingGroupInput{
+		AutoScalingGroupName: aws.String(g.HumanName),
+	}
+	if previous.LaunchTemplate != nil {
+		launchTemplate := &autoscalingtypes.LaunchTemplateSpecification{
+			LaunchTemplateId: previous.LaunchTemplate.LaunchTemplateId,
+			Version:          previous.LaunchTemplate.Version,
+		}
+		if asg.MixedInstancesPolicy != nil && asg.MixedInstancesPolicy.LaunchTemplate != nil {
+			policy := *asg.MixedInstancesPolicy
+			policyLaunchTemplate := *policy.LaunchTemplate
+			policyLaunchTemplate.LaunchTemplateSpecification = launchTemplate
+			policy.LaunchTemplate = &policyLaunchTemplate
+			input.MixedInstancesPolicy = &policy
+		} else {
+			input.LaunchTemplate = launchTemplate
+		}
+		klog.Infof("Rolling back autoscaling group %s to launch template %s version %s", g.HumanName, aws.ToString(launchTemplate.LaunchTemplateId), aws.ToString(launchTemplate.Version))
+	} else if previous.LaunchConfigurationName != nil {
+		input.LaunchConfigurationName = previous.LaunchConfigurationName
+		klog.Infof("Rolling back autoscaling group %s to launch configuration %s", g.HumanName, aws.ToString(previous.LaunchConfigurationName))
+	} else {
+		return fmt.Errorf("unable to find previous launch template for autoscaling group %q", g.HumanName)
+	}
+
+	if _, err := c.Autoscaling().UpdateAutoScalingGroup(ctx, input); err != nil {
+		return fmt.Errorf("error rolling back autoscaling group %q: %w", g.HumanName, err)
+	}
+
+	return nil
+}
+
 // GetCloudGroups returns a groups of instances that back a kops instance groups
 func (c *awsCloudImplementation) GetCloudGroups(cluster *kops.Cluster, instancegroups []*kops.InstanceGroup, warnUnmatched bool, nodes []v1.Node) (map[string]*cloudinstances.CloudInstanceGroup, error) {
 	ctx := context.TODO()
@@ -101,6 +101,12 @@ func (c *MockAWSCloud) DetachInstance(i *cloudinstances.CloudInstance) error {
 	return detachInstance(ctx, c, i)
 }
 
+func (c *MockAWSCloud) RollbackGroup(g *cloudinstances.CloudInstanceGroup) error {
+	ctx := context.TODO()
+
+	return rollbackGroup(ctx, c, g)
+}
+
 func (c *MockAWSCloud) GetCloudGroups(cluster *kops.Cluster, instancegroups []*kops.InstanceGroup, warnUnmatched bool, nodes []v1.Node) (map[string]*cloudinstances.CloudInstanceGroup, error) {
 	ctx := context.TODO()
 	return getCloudGroups(ctx, c, cluster, instancegroups, warnUnmatched, nodes)
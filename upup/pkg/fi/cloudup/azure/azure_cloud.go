@@ -233,6 +233,10 @@ func (c *azureCloudImplementation) DetachInstance(i *cloudinstances.CloudInstanc
 	return errors.New("DetachInstance not implemented on azureCloud")
 }
 
+func (c *azureCloudImplementation) RollbackGroup(group *cloudinstances.CloudInstanceGroup) error {
+	return errors.New("RollbackGroup not implemented on azureCloud")
+}
+
 // AddClusterTags adds cluster tags to the resource.
 func (c *azureCloudImplementation) AddClusterTags(tags map[string]*string) {
 	for k, v := range c.tags {
@@ -163,6 +163,11 @@ func (c *MockAzureCloud) DetachInstance(i *cloudinstances.CloudInstance) error {
 	return errors.New("DetachInstance not implemented on azureCloud")
 }
 
+// RollbackGroup reverts the instance group to its previous specification.
+func (c *MockAzureCloud) RollbackGroup(group *cloudinstances.CloudInstanceGroup) error {
+	return errors.New("RollbackGroup not implemented on azureCloud")
+}
+
 // GetCloudGroups returns cloud instance groups.
 func (c *MockAzureCloud) GetCloudGroups(
 	cluster *kops.Cluster,
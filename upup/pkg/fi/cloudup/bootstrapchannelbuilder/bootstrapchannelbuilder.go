@@ -42,6 +42,7 @@ import (
 	"k8s.io/kops/pkg/model/components/addonmanifests/kuberouter"
 	"k8s.io/kops/pkg/model/components/addonmanifests/nodeterminationhandler"
 	"k8s.io/kops/pkg/model/iam"
+	"k8s.io/kops/pkg/policy"
 	"k8s.io/kops/pkg/templates"
 	"k8s.io/kops/pkg/wellknownoperators"
 	"k8s.io/kops/upup/pkg/fi"
@@ -224,6 +225,8 @@ func (b *BootstrapChannelBuilder) Build(c *fi.CloudupModelBuilderContext) error
 			switch addon.GroupVersionKind().GroupKind() {
 			case schema.GroupKind{Group: "kubescheduler.config.k8s.io", Kind: "KubeSchedulerConfiguration"}:
 				applyToCluster = false
+			case schema.GroupKind{Group: policy.Group, Kind: policy.KindClusterPolicy}:
+				applyToCluster = false
 			}
 
 			if applyToCluster {
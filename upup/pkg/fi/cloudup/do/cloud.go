@@ -192,6 +192,12 @@ func (c *doCloudImplementation) DetachInstance(i *cloudinstances.CloudInstance)
 	return fmt.Errorf("digital ocean cloud provider does not support surging")
 }
 
+// RollbackGroup is not implemented yet. It needs to revert the group to the previous specification of its instances.
+func (c *doCloudImplementation) RollbackGroup(group *cloudinstances.CloudInstanceGroup) error {
+	klog.V(8).Info("digitalocean cloud provider RollbackGroup not implemented yet")
+	return fmt.Errorf("digital ocean cloud provider does not support rolling back instance groups")
+}
+
 // ProviderID returns the kops api identifier for DigitalOcean cloud provider
 func (c *doCloudImplementation) ProviderID() kops.CloudProviderID {
 	return kops.CloudProviderDO
@@ -78,6 +78,11 @@ func (c *doCloudMockImplementation) DetachInstance(i *cloudinstances.CloudInstan
 	return fmt.Errorf("digital ocean cloud provider does not support surging")
 }
 
+// RollbackGroup is not implemented yet. It needs to revert the group to the previous specification of its instances.
+func (c *doCloudMockImplementation) RollbackGroup(group *cloudinstances.CloudInstanceGroup) error {
+	return fmt.Errorf("digital ocean cloud provider does not support rolling back instance groups")
+}
+
 func (c *doCloudMockImplementation) GetCloudGroups(cluster *kops.Cluster, instancegroups []*kops.InstanceGroup, warnUnmatched bool, nodes []v1.Node) (map[string]*cloudinstances.CloudInstanceGroup, error) {
 	return nil, errors.New("not tested")
 }
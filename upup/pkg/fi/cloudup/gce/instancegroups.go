@@ -63,6 +63,47 @@ func (c *gceCloudImplementation) DetachInstance(i *cloudinstances.CloudInstance)
 	return fmt.Errorf("gce cloud provider does not support surging")
 }
 
+// RollbackGroup sets the InstanceTemplate of a MIG back to the template its instances needing update were created from.
+func (c *gceCloudImplementation) RollbackGroup(g *cloudinstances.CloudInstanceGroup) error {
+	return rollbackCloudInstanceGroup(c, g)
+}
+
+func rollbackCloudInstanceGroup(c GCECloud, g *cloudinstances.CloudInstanceGroup) error {
+	mig := g.Raw.(*compute.InstanceGroupManager)
+
+	needUpdate := make(map[string]bool)
+	for _, i := range g.NeedUpdate {
+		needUpdate[LastComponent(i.ID)] = true
+	}
+
+	instances, err := ListManagedInstances(c, mig)
+	if err != nil {
+		return err
+	}
+
+	previousInstanceTemplate := ""
+	for _, i := range instances {
+		if needUpdate[LastComponent(i.Instance)] && i.Version != nil && i.Version.InstanceTemplate != mig.InstanceTemplate {
+			previousInstanceTemplate = i.Version.InstanceTemplate
+			break
+		}
+	}
+	if previousInstanceTemplate == "" {
+		return fmt.Errorf("unable to find previous InstanceTemplate for InstanceGroupManager %q", mig.Name)
+	}
+
+	klog.Infof("Rolling back InstanceGroupManager %s to InstanceTemplate %s", mig.Name, LastComponent(previousInstanceTemplate))
+	op, err := c.Compute().InstanceGroupManagers().SetInstanceTemplate(c.Project(), LastComponent(mig.Zone), mig.Name, previousInstanceTemplate)
+	if err != nil {
+		return fmt.Errorf("error updating InstanceTemplate for InstanceGroupManager: %v", err)
+	}
+	if err := c.WaitForOp(op); err != nil {
+		return fmt.Errorf("error updating InstanceTemplate for InstanceGroupManager: %v", err)
+	}
+
+	return nil
+}
+
 // recreateCloudInstance recreates the specified instances, managed by an InstanceGroupManager
 func recreateCloudInstance(c GCECloud, i *cloudinstances.CloudInstance) error {
 	mig := i.CloudInstanceGroup.Raw.(*compute.InstanceGroupManager)
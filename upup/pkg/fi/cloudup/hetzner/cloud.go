@@ -292,6 +292,10 @@ func (c *hetznerCloudImplementation) DetachInstance(instance *cloudinstances.Clo
 	return nil
 }
 
+func (c *hetznerCloudImplementation) RollbackGroup(group *cloudinstances.CloudInstanceGroup) error {
+	return fmt.Errorf("hetzner cloud provider does not support rolling back instance groups")
+}
+
 // ProviderID returns the kOps API identifier for Hetzner Cloud
 func (c *hetznerCloudImplementation) ProviderID() kops.CloudProviderID {
 	return kops.CloudProviderHetzner
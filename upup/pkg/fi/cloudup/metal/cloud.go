@@ -72,6 +72,11 @@ func (c *Cloud) DetachInstance(instance *cloudinstances.CloudInstance) error {
 	return fmt.Errorf("method metal.Cloud::DetachInstance not implemented")
 }
 
+// RollbackGroup reverts a CloudInstanceGroup to the previous specification of its instances.
+func (c *Cloud) RollbackGroup(group *cloudinstances.CloudInstanceGroup) error {
+	return fmt.Errorf("method metal.Cloud::RollbackGroup not implemented")
+}
+
 // GetCloudGroups returns a map of cloud instances that back a kops cluster.
 // Detached instances must be returned in the NeedUpdate slice.
 func (c *Cloud) GetCloudGroups(cluster *kops.Cluster, instancegroups []*kops.InstanceGroup, warnUnmatched bool, nodes []v1.Node) (map[string]*cloudinstances.CloudInstanceGroup, error) {
@@ -319,6 +319,16 @@ func detachInstance(c OpenstackCloud, i *cloudinstances.CloudInstance) error {
 	return fmt.Errorf("openstack cloud provider does not support surging")
 }
 
+// RollbackGroup returns an error: OpenStack instance groups do not keep the previous specification of their instances,
+// so they cannot be rolled back after a failed canary.
+func (c *openstackCloud) RollbackGroup(group *cloudinstances.CloudInstanceGroup) error {
+	return rollbackGroup(c, group)
+}
+
+func rollbackGroup(c OpenstackCloud, group *cloudinstances.CloudInstanceGroup) error {
+	return fmt.Errorf("openstack cloud provider does not support rolling back instance groups")
+}
+
 func (c *openstackCloud) GetInstance(id string) (*servers.Server, error) {
 	return getInstance(c, id)
 }
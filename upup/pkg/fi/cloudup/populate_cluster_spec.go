@@ -170,6 +170,10 @@ func (c *populateClusterSpec) run(ctx context.Context, clientset simple.Clientse
 		// We could implement this approach, but it seems better to get all clouds using cluster-readable storage
 		return fmt.Errorf("ConfigStore.Base path is not cluster readable: %v", cluster.Spec.ConfigStore.Base)
 	}
+	if _, ok := configBase.(*vfs.FSPath); ok && cluster.GetCloudProvider() != kopsapi.CloudProviderMetal {
+		// Nodes on a cloud read their configuration from the state store, which they cannot reach on local disk
+		klog.Warningf("The local filesystem state store is only functional for bare-metal clusters; nodes will not be able to read %q", cluster.Spec.ConfigStore.Base)
+	}
 
 	keyStore, err := clientset.KeyStore(cluster)
 	if err != nil {
@@ -0,0 +1,355 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	kopsbase "k8s.io/kops"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/kubemanifest"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+// SavedPlan is the result of a dry-run, saved so that it can be applied later.
+// It records the state store objects the plan was computed against, so that
+// we can refuse to apply a plan that is no longer current.
+type SavedPlan struct {
+	// ClusterName is the name of the cluster the plan was computed for.
+	ClusterName string `json:"clusterName"`
+	// KopsVersion is the version of kOps that computed the plan.
+	KopsVersion string `json:"kopsVersion"`
+	// Options are the options that affect which tasks are part of the plan.
+	Options SavedPlanOptions `json:"options"`
+	// StateStore identifies the state store objects the plan was computed against.
+	StateStore StateStoreGeneration `json:"stateStore"`
+	// Plan is the list of changes that will be applied.
+	Plan *fi.Plan `json:"plan"`
+}
+
+// SavedPlanOptions holds the update options that change which tasks are part of a plan.
+type SavedPlanOptions struct {
+	Phase              string   `json:"phase,omitempty"`
+	InstanceGroups     []string `json:"instanceGroups,omitempty"`
+	InstanceGroupRoles []string `json:"instanceGroupRoles,omitempty"`
+	LifecycleOverrides []string `json:"lifecycleOverrides,omitempty"`
+	Prune              bool     `json:"prune,omitempty"`
+}
+
+// StateStoreGeneration identifies a version of the cluster, instance groups, additional objects,
+// keystore and secrets in the state store.
+type StateStoreGeneration struct {
+	// ClusterGeneration is the generation of the cluster object.
+	ClusterGeneration int64 `json:"clusterGeneration"`
+	// InstanceGroupGenerations holds the generation of each instance group, by name.
+	InstanceGroupGenerations map[string]int64 `json:"instanceGroupGenerations,omitempty"`
+
+	// ClusterHash is a hash of the cluster spec.
+	ClusterHash string `json:"clusterHash"`
+	// InstanceGroupHashes holds a hash of the spec of each instance group, by name.
+	InstanceGroupHashes map[string]string `json:"instanceGroupHashes,omitempty"`
+	// AdditionalObjectsHash is a hash of the additional objects of the cluster.
+	AdditionalObjectsHash string `json:"additionalObjectsHash"`
+	// KeystoreHash is a hash of all the keysets in the keystore.
+	KeystoreHash string `json:"keystoreHash"`
+	// SecretsHash is a hash of all the secrets in the secret store.
+	SecretsHash string `json:"secretsHash"`
+}
+
+// BuildStateStoreGeneration computes the StateStoreGeneration for the specified objects.
+// It should be called with the objects as read from the state store, before they are populated.
+func BuildStateStoreGeneration(cluster *kops.Cluster, instanceGroups []*kops.InstanceGroup, additionalObjects kubemanifest.ObjectList, keyStore fi.CAStore, secretStore fi.SecretStore) (*StateStoreGeneration, error) {
+	g := &StateStoreGeneration{
+		ClusterGeneration:        cluster.GetGeneration(),
+		InstanceGroupGenerations: make(map[string]int64),
+		InstanceGroupHashes:      make(map[string]string),
+	}
+
+	clusterHash, err := hashObject(cluster.Spec)
+	if err != nil {
+		return nil, fmt.Errorf("error hashing cluster spec: %w", err)
+	}
+	g.ClusterHash = clusterHash
+
+	for _, ig := range instanceGroups {
+		igHash, err := hashObject(ig.Spec)
+		if err != nil {
+			return nil, fmt.Errorf("error hashing instance group %q: %w", ig.Name, err)
+		}
+		g.InstanceGroupGenerations[ig.Name] = ig.GetGeneration()
+		g.InstanceGroupHashes[ig.Name] = igHash
+	}
+
+	// Objects are serialized in their stored order, as the order in which they are applied can matter
+	additionalObjectsHash, err := hashObject(additionalObjects)
+	if err != nil {
+		return nil, fmt.Errorf("error hashing additional objects: %w", err)
+	}
+	g.AdditionalObjectsHash = additionalObjectsHash
+
+	keysets, err := keyStore.ListKeysets()
+	if err != nil {
+		return nil, fmt.Errorf("error listing keysets: %w", err)
+	}
+	var keysetNames []string
+	for name := range keysets {
+		keysetNames = append(keysetNames, name)
+	}
+	sort.Strings(keysetNames)
+
+	var keysetSpecs []kops.KeysetSpec
+	for _, name := range keysetNames {
+		o, err := keysets[name].ToAPIObject(name)
+		if err != nil {
+			return nil, fmt.Errorf("error converting keyset %q: %w", name, err)
+		}
+		keysetSpecs = append(keysetSpecs, o.Spec)
+	}
+	keystoreHash, err := hashObject(struct {
+		Names []string
+		Specs []kops.KeysetSpec
+	}{keysetNames, keysetSpecs})
+	if err != nil {
+		return nil, fmt.Errorf("error hashing keystore: %w", err)
+	}
+	g.KeystoreHash = keystoreHash
+
+	secretNames, err := secretStore.ListSecrets()
+	if err != nil {
+		return nil, fmt.Errorf("error listing secrets: %w", err)
+	}
+	sort.Strings(secretNames)
+
+	var secretData [][]byte
+	for _, name := range secretNames {
+		secret, err := secretStore.FindSecret(name)
+		if err != nil {
+			return nil, fmt.Errorf("error reading secret %q: %w", name, err)
+		}
+		if secret == nil {
+			return nil, fmt.Errorf("secret %q was listed but not found", name)
+		}
+		secretData = append(secretData, secret.Data)
+	}
+	secretsHash, err := hashObject(struct {
+		Names []string
+		Data  [][]byte
+	}{secretNames, secretData})
+	if err != nil {
+		return nil, fmt.Errorf("error hashing secrets: %w", err)
+	}
+	g.SecretsHash = secretsHash
+
+	return g, nil
+}
+
+func hashObject(o interface{}) (string, error) {
+	data, err := json.Marshal(o)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// Diff returns a description of each difference between g and current; it returns nil if they are the same.
+func (g *StateStoreGeneration) Diff(current *StateStoreGeneration) []string {
+	var diffs []string
+
+	if g.ClusterHash != current.ClusterHash {
+		diffs = append(diffs, fmt.Sprintf("cluster spec changed (generation %d -> %d)", g.ClusterGeneration, current.ClusterGeneration))
+	}
+
+	names := make(map[string]bool)
+	for name := range g.InstanceGroupHashes {
+		names[name] = true
+	}
+	for name := range current.InstanceGroupHashes {
+		names[name] = true
+	}
+	var sortedNames []string
+	for name := range names {
+		sortedNames = append(sortedNames, name)
+	}
+	sort.Strings(sortedNames)
+
+	for _, name := range sortedNames {
+		planned, inPlan := g.InstanceGroupHashes[name]
+		actual, inStore := current.InstanceGroupHashes[name]
+		switch {
+		case !inPlan:
+			diffs = append(diffs, fmt.Sprintf("instance group %q was created", name))
+		case !inStore:
+			diffs = append(diffs, fmt.Sprintf("instance group %q was deleted", name))
+		case planned != actual:
+			diffs = append(diffs, fmt.Sprintf("instance group %q spec changed (generation %d -> %d)", name, g.InstanceGroupGenerations[name], current.InstanceGroupGenerations[name]))
+		}
+	}
+
+	if g.AdditionalObjectsHash != current.AdditionalObjectsHash {
+		diffs = append(diffs, "additional objects changed")
+	}
+
+	if g.KeystoreHash != current.KeystoreHash {
+		diffs = append(diffs, "keystore changed")
+	}
+
+	if g.SecretsHash != current.SecretsHash {
+		diffs = append(diffs, "secrets changed")
+	}
+
+	return diffs
+}
+
+// Verify checks that the plan can be applied to the current state store objects, with the specified options.
+func (p *SavedPlan) Verify(clusterName string, options *SavedPlanOptions, current *StateStoreGeneration) error {
+	if p.ClusterName != clusterName {
+		return fmt.Errorf("plan was computed for cluster %q, not %q", p.ClusterName, clusterName)
+	}
+	if p.KopsVersion != kopsbase.Version {
+		return fmt.Errorf("plan was computed by kOps version %q, but this is version %q", p.KopsVersion, kopsbase.Version)
+	}
+	// Compare the serialized form, so that nil and empty lists are equivalent
+	plannedOptions, err := hashObject(p.Options)
+	if err != nil {
+		return err
+	}
+	currentOptions, err := hashObject(options)
+	if err != nil {
+		return err
+	}
+	if plannedOptions != currentOptions {
+		return fmt.Errorf("plan was computed with different options (phase, instance groups, lifecycle overrides or prune)")
+	}
+	if diffs := p.StateStore.Diff(current); len(diffs) != 0 {
+		return fmt.Errorf("state store has changed since the plan was computed: %s", strings.Join(diffs, "; "))
+	}
+	return nil
+}
+
+// VerifyChanges checks that the changes computed against the cloud now are the changes in the plan.
+// This catches changes made to the cloud resources, rather than to the state store, since the plan was saved.
+func (p *SavedPlan) VerifyChanges(current *fi.Plan) error {
+	planned, err := planEntries(p.Plan)
+	if err != nil {
+		return err
+	}
+	actual, err := planEntries(current)
+	if err != nil {
+		return err
+	}
+
+	keys := make(map[string]bool)
+	for key := range planned {
+		keys[key] = true
+	}
+	for key := range actual {
+		keys[key] = true
+	}
+	var sortedKeys []string
+	for key := range keys {
+		sortedKeys = append(sortedKeys, key)
+	}
+	sort.Strings(sortedKeys)
+
+	var diffs []string
+	for _, key := range sortedKeys {
+		plannedHash, inPlan := planned[key]
+		actualHash, inCurrent := actual[key]
+		switch {
+		case !inPlan:
+			diffs = append(diffs, fmt.Sprintf("%s is not in the plan", key))
+		case !inCurrent:
+			diffs = append(diffs, fmt.Sprintf("%s is no longer needed", key))
+		case plannedHash != actualHash:
+			diffs = append(diffs, fmt.Sprintf("%s differs from the plan", key))
+		}
+	}
+	if len(diffs) != 0 {
+		return fmt.Errorf("the changes to apply differ from the plan: %s", strings.Join(diffs, "; "))
+	}
+	return nil
+}
+
+// planEntries returns a hash of each change and deletion in the plan, keyed by a description of it.
+func planEntries(plan *fi.Plan) (map[string]string, error) {
+	entries := make(map[string]string)
+	if plan == nil {
+		return entries, nil
+	}
+	for _, change := range plan.Changes {
+		hash, err := hashObject(change)
+		if err != nil {
+			return nil, err
+		}
+		entries[fmt.Sprintf("%s of %s %q", change.Action, change.Kind, change.Name)] = hash
+	}
+	for _, deletion := range plan.Deletions {
+		hash, err := hashObject(deletion)
+		if err != nil {
+			return nil, err
+		}
+		entries[fmt.Sprintf("%s of %s %q", deletion.Action, deletion.Kind, deletion.Item)] = hash
+	}
+	return entries, nil
+}
+
+// NewSavedPlan builds a SavedPlan for the specified cluster.
+func NewSavedPlan(clusterName string, options *SavedPlanOptions, generation *StateStoreGeneration, plan *fi.Plan) *SavedPlan {
+	return &SavedPlan{
+		ClusterName: clusterName,
+		KopsVersion: kopsbase.Version,
+		Options:     *options,
+		StateStore:  *generation,
+		Plan:        plan,
+	}
+}
+
+// WriteSavedPlan writes the plan to a local file, readable only by the owner as the plan can contain secrets.
+func WriteSavedPlan(path string, plan *SavedPlan) error {
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error serializing plan: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("error writing plan file %q: %w", path, err)
+	}
+	// WriteFile keeps the permissions of an existing file
+	if err := os.Chmod(path, 0o600); err != nil {
+		return fmt.Errorf("error setting permissions of plan file %q: %w", path, err)
+	}
+	return nil
+}
+
+// ReadSavedPlan reads a plan from a local file.
+func ReadSavedPlan(path string) (*SavedPlan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading plan file %q: %w", path, err)
+	}
+	plan := &SavedPlan{}
+	if err := json.Unmarshal(data, plan); err != nil {
+		return nil, fmt.Errorf("error parsing plan file %q: %w", path, err)
+	}
+	return plan, nil
+}
@@ -0,0 +1,292 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudup
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/kubemanifest"
+	"k8s.io/kops/pkg/testutils"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+type fakeCAStore struct {
+	fi.CAStore
+	keysets map[string]*fi.Keyset
+}
+
+func (s *fakeCAStore) ListKeysets() (map[string]*fi.Keyset, error) {
+	return s.keysets, nil
+}
+
+type fakeSecretStore struct {
+	fi.SecretStore
+	secrets map[string]*fi.Secret
+}
+
+func (s *fakeSecretStore) ListSecrets() ([]string, error) {
+	var names []string
+	for name := range s.secrets {
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+func (s *fakeSecretStore) FindSecret(id string) (*fi.Secret, error) {
+	return s.secrets[id], nil
+}
+
+// savedPlanTestState holds the state store objects a plan is verified against.
+type savedPlanTestState struct {
+	cluster           *kopsapi.Cluster
+	ig                *kopsapi.InstanceGroup
+	additionalObjects kubemanifest.ObjectList
+	keyStore          *fakeCAStore
+	secretStore       *fakeSecretStore
+	options           *SavedPlanOptions
+}
+
+func (s *savedPlanTestState) generation(t *testing.T) *StateStoreGeneration {
+	generation, err := BuildStateStoreGeneration(s.cluster, []*kopsapi.InstanceGroup{s.ig}, s.additionalObjects, s.keyStore, s.secretStore)
+	if err != nil {
+		t.Fatalf("error building generation: %v", err)
+	}
+	return generation
+}
+
+func TestSavedPlan_Verify(t *testing.T) {
+	grid := []struct {
+		name     string
+		mutate   func(s *savedPlanTestState)
+		expected string
+	}{
+		{
+			name: "unchanged",
+			mutate: func(s *savedPlanTestState) {
+			},
+		},
+		{
+			name: "cluster spec changed",
+			mutate: func(s *savedPlanTestState) {
+				s.cluster.Spec.KubernetesVersion = "1.99.0"
+				s.cluster.SetGeneration(2)
+			},
+			expected: "cluster spec changed (generation 1 -> 2)",
+		},
+		{
+			name: "instance group changed",
+			mutate: func(s *savedPlanTestState) {
+				s.ig.Spec.MaxSize = fi.PtrTo(int32(5))
+			},
+			expected: `instance group "nodes" spec changed`,
+		},
+		{
+			name: "keystore changed",
+			mutate: func(s *savedPlanTestState) {
+				s.keyStore.keysets["kubernetes-ca"] = &fi.Keyset{Items: map[string]*fi.KeysetItem{"2": {Id: "2"}}}
+			},
+			expected: "keystore changed",
+		},
+		{
+			name: "secret changed",
+			mutate: func(s *savedPlanTestState) {
+				s.secretStore.secrets["admin"] = &fi.Secret{Data: []byte("rotated")}
+			},
+			expected: "secrets changed",
+		},
+		{
+			name: "secret created",
+			mutate: func(s *savedPlanTestState) {
+				s.secretStore.secrets["kube"] = &fi.Secret{Data: []byte("secret")}
+			},
+			expected: "secrets changed",
+		},
+		{
+			name: "additional object changed",
+			mutate: func(s *savedPlanTestState) {
+				if err := s.additionalObjects[0].Set("other", "data", "key"); err != nil {
+					panic(err)
+				}
+			},
+			expected: "additional objects changed",
+		},
+		{
+			name: "options changed",
+			mutate: func(s *savedPlanTestState) {
+				s.options.Prune = true
+			},
+			expected: "different options",
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			cluster := testutils.BuildMinimalCluster("testcluster.test.com")
+			cluster.SetGeneration(1)
+			additionalObjects, err := kubemanifest.LoadObjectsFrom([]byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config\ndata:\n  key: value\n"))
+			if err != nil {
+				t.Fatalf("error parsing additional objects: %v", err)
+			}
+			s := &savedPlanTestState{
+				cluster:           cluster,
+				ig:                buildMinimalNodeInstanceGroup("subnet-us-test-1a"),
+				additionalObjects: additionalObjects,
+				keyStore: &fakeCAStore{
+					keysets: map[string]*fi.Keyset{
+						"kubernetes-ca": {Items: map[string]*fi.KeysetItem{"1": {Id: "1"}}},
+					},
+				},
+				secretStore: &fakeSecretStore{
+					secrets: map[string]*fi.Secret{
+						"admin": {Data: []byte("secret")},
+					},
+				},
+				options: &SavedPlanOptions{},
+			}
+
+			// Round-trip through a file, as the command does
+			path := filepath.Join(t.TempDir(), "plan.kops")
+			if err := WriteSavedPlan(path, NewSavedPlan(cluster.Name, s.options, s.generation(t), &fi.Plan{})); err != nil {
+				t.Fatalf("error writing plan: %v", err)
+			}
+			savedPlan, err := ReadSavedPlan(path)
+			if err != nil {
+				t.Fatalf("error reading plan: %v", err)
+			}
+
+			g.mutate(s)
+
+			err = savedPlan.Verify(cluster.Name, s.options, s.generation(t))
+			if g.expected == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected error containing %q, got nil", g.expected)
+			}
+			if !strings.Contains(err.Error(), g.expected) {
+				t.Errorf("expected error containing %q, got %q", g.expected, err.Error())
+			}
+		})
+	}
+}
+
+func TestSavedPlan_VerifyChanges(t *testing.T) {
+	planned := &fi.Plan{
+		Changes: []*fi.PlanChange{
+			{
+				Kind:   "LaunchTemplate",
+				Name:   "nodes",
+				Action: fi.PlanActionUpdate,
+				Fields: []*fi.PlanField{{Name: "ImageID", Old: "ami-1", New: "ami-2"}},
+			},
+		},
+		Deletions: []*fi.PlanDeletion{
+			{Kind: "SecurityGroupRule", Item: "old-rule", Action: fi.PlanActionDelete},
+		},
+	}
+
+	grid := []struct {
+		name     string
+		mutate   func(plan *fi.Plan)
+		expected string
+	}{
+		{
+			name:   "unchanged",
+			mutate: func(plan *fi.Plan) {},
+		},
+		{
+			name: "field changed",
+			mutate: func(plan *fi.Plan) {
+				plan.Changes[0].Fields[0].Old = "ami-0"
+			},
+			expected: `update of LaunchTemplate "nodes" differs from the plan`,
+		},
+		{
+			name: "new change",
+			mutate: func(plan *fi.Plan) {
+				plan.Changes = append(plan.Changes, &fi.PlanChange{Kind: "SecurityGroup", Name: "nodes", Action: fi.PlanActionCreate})
+			},
+			expected: `create of SecurityGroup "nodes" is not in the plan`,
+		},
+		{
+			name: "deletion no longer needed",
+			mutate: func(plan *fi.Plan) {
+				plan.Deletions = nil
+			},
+			expected: `delete of SecurityGroupRule "old-rule" is no longer needed`,
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "plan.kops")
+			if err := WriteSavedPlan(path, NewSavedPlan("testcluster.test.com", &SavedPlanOptions{}, &StateStoreGeneration{}, planned)); err != nil {
+				t.Fatalf("error writing plan: %v", err)
+			}
+			savedPlan, err := ReadSavedPlan(path)
+			if err != nil {
+				t.Fatalf("error reading plan: %v", err)
+			}
+
+			// Build the current plan from the saved one, so that it is a deep copy
+			current, err := ReadSavedPlan(path)
+			if err != nil {
+				t.Fatalf("error reading plan: %v", err)
+			}
+			g.mutate(current.Plan)
+
+			err = savedPlan.VerifyChanges(current.Plan)
+			if g.expected == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected error containing %q, got nil", g.expected)
+			}
+			if !strings.Contains(err.Error(), g.expected) {
+				t.Errorf("expected error containing %q, got %q", g.expected, err.Error())
+			}
+		})
+	}
+}
+
+func TestWriteSavedPlan_Permissions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.kops")
+	// An existing file keeps its permissions unless they are reset
+	if err := os.WriteFile(path, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteSavedPlan(path, NewSavedPlan("testcluster.test.com", &SavedPlanOptions{}, &StateStoreGeneration{}, &fi.Plan{})); err != nil {
+		t.Fatalf("error writing plan: %v", err)
+	}
+	stat, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := stat.Mode().Perm(); perm != 0o600 {
+		t.Errorf("expected permissions 0600, got %#o", perm)
+	}
+}
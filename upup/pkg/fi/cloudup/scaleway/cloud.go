@@ -74,6 +74,7 @@ type ScwCloud interface {
 	FindVPCInfo(id string) (*fi.VPCInfo, error)
 	GetApiIngressStatus(cluster *kops.Cluster) ([]fi.ApiIngressStatus, error)
 	GetCloudGroups(cluster *kops.Cluster, instancegroups []*kops.InstanceGroup, warnUnmatched bool, nodes []v1.Node) (map[string]*cloudinstances.CloudInstanceGroup, error)
+	RollbackGroup(group *cloudinstances.CloudInstanceGroup) error
 
 	GetClusterDNSRecords(clusterName string) ([]*domain.Record, error)
 	GetClusterLoadBalancers(clusterName string) ([]*lb.LB, error)
@@ -300,6 +301,11 @@ func (s *scwCloudImplementation) DetachInstance(i *cloudinstances.CloudInstance)
 	return fmt.Errorf("DetachInstance is not implemented yet for Scaleway")
 }
 
+func (s *scwCloudImplementation) RollbackGroup(group *cloudinstances.CloudInstanceGroup) error {
+	klog.V(8).Infof("Scaleway RollbackGroup is not implemented yet")
+	return fmt.Errorf("RollbackGroup is not implemented yet for Scaleway")
+}
+
 // FindClusterStatus was used before etcd-manager to check the etcd cluster status and prevent unsupported changes.
 func (s *scwCloudImplementation) FindClusterStatus(cluster *kops.Cluster) (*kops.ClusterStatus, error) {
 	klog.V(8).Info("Scaleway FindClusterStatus is not implemented")
@@ -53,6 +53,8 @@ import (
 	apiModel "k8s.io/kops/pkg/apis/kops/model"
 	"k8s.io/kops/pkg/apis/kops/util"
 	"k8s.io/kops/pkg/bootstrap/pkibootstrap"
+	"k8s.io/kops/pkg/bootstrap/tokenbootstrap"
+	"k8s.io/kops/pkg/bootstrap/tpmbootstrap"
 	"k8s.io/kops/pkg/featureflag"
 	"k8s.io/kops/pkg/flagbuilder"
 	"k8s.io/kops/pkg/kubemanifest"
@@ -712,6 +714,8 @@ func (tf *TemplateFunctions) KopsControllerConfig() (string, error) {
 
 		if featureflag.Metal.Enabled() {
 			config.Server.PKI = &pkibootstrap.Options{}
+			config.Server.TPM = &tpmbootstrap.Options{}
+			config.Server.JoinToken = &tokenbootstrap.Options{}
 		}
 
 		switch cluster.GetCloudProvider() {
@@ -774,8 +778,10 @@ func (tf *TemplateFunctions) KopsControllerConfig() (string, error) {
 			}
 
 		case kops.CloudProviderMetal:
-			// Use crypto public/private keys for Metal
+			// Use crypto public/private keys, TPM attestation or join tokens for Metal
 			config.Server.PKI = &pkibootstrap.Options{}
+			config.Server.TPM = &tpmbootstrap.Options{}
+			config.Server.JoinToken = &tokenbootstrap.Options{}
 
 		default:
 			return "", fmt.Errorf("unsupported cloud provider %s", cluster.GetCloudProvider())
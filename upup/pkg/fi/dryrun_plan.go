@@ -0,0 +1,129 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fi
+
+import (
+	"sort"
+)
+
+// PlanAction is the action that will be taken for a task in a Plan.
+type PlanAction string
+
+const (
+	PlanActionCreate PlanAction = "create"
+	PlanActionUpdate PlanAction = "update"
+	PlanActionDelete PlanAction = "delete"
+)
+
+// Plan is a machine-readable form of the changes collected by a DryRunTarget.
+type Plan struct {
+	// Changes are the tasks that will be created or updated.
+	Changes []*PlanChange `json:"changes,omitempty"`
+	// Deletions are the items that will be deleted.
+	Deletions []*PlanDeletion `json:"deletions,omitempty"`
+}
+
+// PlanChange describes a single task that will be created or updated.
+type PlanChange struct {
+	// Kind is the type of the task, e.g. LaunchTemplate.
+	Kind string `json:"kind"`
+	// Name is the name of the task.
+	Name string `json:"name"`
+	// Action is either create or update.
+	Action PlanAction `json:"action"`
+	// Fields are the fields that will be set (on create) or changed (on update).
+	Fields []*PlanField `json:"fields,omitempty"`
+}
+
+// PlanField describes the change to a single field of a task.
+type PlanField struct {
+	// Name is the name of the field.
+	Name string `json:"name"`
+	// Old is the current value of the field; it is empty on create.
+	Old string `json:"old,omitempty"`
+	// New is the value the field will be set to.
+	New string `json:"new,omitempty"`
+}
+
+// PlanDeletion describes an item that will be deleted.
+type PlanDeletion struct {
+	// Kind is the type of the task responsible for the deletion.
+	Kind string `json:"kind"`
+	// Item is a description of the item that will be deleted.
+	Item string `json:"item"`
+	// Action is always delete.
+	Action PlanAction `json:"action"`
+	// Deferred is true if the item will only be deleted when --prune is specified.
+	Deferred bool `json:"deferred,omitempty"`
+}
+
+// BuildPlan returns the changes collected by the target in a structured form.
+func (t *DryRunTarget[T]) BuildPlan(taskMap map[string]Task[T]) (*Plan, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	plan := &Plan{}
+
+	changes := make([]*render[T], len(t.changes))
+	copy(changes, t.changes)
+	// Give everything a consistent ordering
+	sort.Sort(ByTaskKey[T](changes))
+
+	for _, r := range changes {
+		c := &PlanChange{
+			Kind: getTaskName(r.changes),
+			Name: idForTask(taskMap, r.e),
+		}
+
+		var changeList []change
+		if r.aIsNil {
+			c.Action = PlanActionCreate
+			changeList = buildCreateList(r.changes)
+		} else {
+			c.Action = PlanActionUpdate
+			var err error
+			changeList, err = buildChangeList(r.a, r.e, r.changes)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		for _, change := range changeList {
+			c.Fields = append(c.Fields, &PlanField{
+				Name: change.FieldName,
+				Old:  change.OldValue,
+				New:  change.NewValue,
+			})
+		}
+		plan.Changes = append(plan.Changes, c)
+	}
+
+	deletions := make([]Deletion[T], len(t.deletions))
+	copy(deletions, t.deletions)
+	sort.Sort(DeletionByTaskName[T](deletions))
+
+	for _, d := range deletions {
+		plan.Deletions = append(plan.Deletions, &PlanDeletion{
+			Kind:     d.TaskName(),
+			Item:     d.Item(),
+			Action:   PlanActionDelete,
+			Deferred: d.DeferDeletion(),
+		})
+	}
+
+	return plan, nil
+}
@@ -168,56 +168,8 @@ func (t *DryRunTarget[T]) PrintReport(taskMap map[string]Task[T], out io.Writer)
 				taskName := getTaskName(r.changes)
 				fmt.Fprintf(b, "  %s/%s\n", taskName, idForTask(taskMap, r.e))
 
-				changes := reflect.ValueOf(r.changes)
-				if changes.Kind() == reflect.Ptr && !changes.IsNil() {
-					changes = changes.Elem()
-				}
-
-				if changes.Kind() == reflect.Struct {
-					for i := 0; i < changes.NumField(); i++ {
-
-						field := changes.Field(i)
-
-						fieldName := changes.Type().Field(i).Name
-						if changes.Type().Field(i).PkgPath != "" {
-							// Not exported
-							continue
-						}
-
-						fieldValue := reflectutils.ValueAsString(field)
-
-						shouldPrint := true
-						if fieldName == "Name" {
-							// The field name is already printed above, no need to repeat it.
-							shouldPrint = false
-						}
-						if fieldName == "Lifecycle" {
-							// Lifecycle is a "system" field; no need to show it
-							shouldPrint = false
-						}
-						if fieldValue == "<nil>" || fieldValue == "<resource>" {
-							// Uninformative
-							shouldPrint = false
-						}
-						if fieldValue == "id:<nil>" {
-							// Uninformative, but we can often print the name instead
-							name := ""
-							if field.CanInterface() {
-								hasName, ok := field.Interface().(HasName)
-								if ok {
-									name = ValueOf(hasName.GetName())
-								}
-							}
-							if name != "" {
-								fieldValue = "name:" + name
-							} else {
-								shouldPrint = false
-							}
-						}
-						if shouldPrint {
-							fmt.Fprintf(b, "  \t%-20s\t%s\n", fieldName, fieldValue)
-						}
-					}
+				for _, change := range buildCreateList(r.changes) {
+					fmt.Fprintf(b, "  \t%-20s\t%s\n", change.FieldName, change.Description)
 				}
 
 				fmt.Fprintf(b, "\n")
@@ -313,6 +265,70 @@ func (t *DryRunTarget[T]) PrintReport(taskMap map[string]Task[T], out io.Writer)
 type change struct {
 	FieldName   string
 	Description string
+
+	// OldValue and NewValue hold the string forms of the actual and expected values.
+	OldValue string
+	NewValue string
+}
+
+// buildCreateList returns the informative fields of a task that is to be created.
+func buildCreateList[T SubContext](changes Task[T]) []change {
+	var changeList []change
+
+	valC := reflect.ValueOf(changes)
+	if valC.Kind() == reflect.Ptr && !valC.IsNil() {
+		valC = valC.Elem()
+	}
+
+	if valC.Kind() != reflect.Struct {
+		return nil
+	}
+
+	for i := 0; i < valC.NumField(); i++ {
+		field := valC.Field(i)
+
+		fieldName := valC.Type().Field(i).Name
+		if valC.Type().Field(i).PkgPath != "" {
+			// Not exported
+			continue
+		}
+
+		fieldValue := reflectutils.ValueAsString(field)
+
+		shouldPrint := true
+		if fieldName == "Name" {
+			// The field name is already printed above, no need to repeat it.
+			shouldPrint = false
+		}
+		if fieldName == "Lifecycle" {
+			// Lifecycle is a "system" field; no need to show it
+			shouldPrint = false
+		}
+		if fieldValue == "<nil>" || fieldValue == "<resource>" {
+			// Uninformative
+			shouldPrint = false
+		}
+		if fieldValue == "id:<nil>" {
+			// Uninformative, but we can often print the name instead
+			name := ""
+			if field.CanInterface() {
+				hasName, ok := field.Interface().(HasName)
+				if ok {
+					name = ValueOf(hasName.GetName())
+				}
+			}
+			if name != "" {
+				fieldValue = "name:" + name
+			} else {
+				shouldPrint = false
+			}
+		}
+		if shouldPrint {
+			changeList = append(changeList, change{FieldName: fieldName, Description: fieldValue, NewValue: fieldValue})
+		}
+	}
+
+	return changeList
 }
 
 func buildChangeList[T SubContext](a, e, changes Task[T]) ([]change, error) {
@@ -370,6 +386,8 @@ func buildChangeList[T SubContext](a, e, changes Task[T]) ([]change, error) {
 			}
 
 			description := ""
+			oldValue := ""
+			newValue := ""
 			ignored := false
 			if fieldValE.CanInterface() {
 
@@ -381,17 +399,26 @@ func buildChangeList[T SubContext](a, e, changes Task[T]) ([]change, error) {
 					resE, okE := tryResourceAsString(fieldValE)
 					if okA && okE {
 						description = diff.FormatDiff(resA, resE)
+						oldValue = resA
+						newValue = resE
 					}
 				}
 
 				if !ignored && description == "" {
-					description = fmt.Sprintf(" %v -> %v", reflectutils.ValueAsString(fieldValA), reflectutils.ValueAsString(fieldValE))
+					oldValue = reflectutils.ValueAsString(fieldValA)
+					newValue = reflectutils.ValueAsString(fieldValE)
+					description = fmt.Sprintf(" %v -> %v", oldValue, newValue)
 				}
 			}
 			if ignored {
 				continue
 			}
-			changeList = append(changeList, change{FieldName: valC.Type().Field(i).Name, Description: description})
+			changeList = append(changeList, change{
+				FieldName:   valC.Type().Field(i).Name,
+				Description: description,
+				OldValue:    oldValue,
+				NewValue:    newValue,
+			})
 		}
 	} else {
 		return nil, fmt.Errorf("unhandled change type: %v", valC.Type())
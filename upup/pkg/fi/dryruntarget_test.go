@@ -18,6 +18,7 @@ package fi
 
 import (
 	"bytes"
+	"io"
 	"reflect"
 	"testing"
 
@@ -92,3 +93,60 @@ func Test_DryrunTarget_PrintReport(t *testing.T) {
 	err = target.PrintReport(tasks, &out)
 	assert.NoError(t, err, "target.PrintReport()")
 }
+
+func Test_DryrunTarget_BuildPlan(t *testing.T) {
+	builder := assets.NewAssetBuilder(vfs.Context, nil, false)
+	checkExisting := true
+	target := newDryRunTarget[CloudupSubContext](builder, checkExisting, io.Discard)
+	tasks := map[string]CloudupTask{}
+
+	created := &testTask{
+		Name:      PtrTo("created"),
+		Lifecycle: LifecycleSync,
+		Tags:      map[string]string{"key": "value"},
+	}
+	changes := reflect.New(reflect.TypeOf(created).Elem()).Interface().(CloudupTask)
+	_ = BuildChanges((*testTask)(nil), created, changes)
+	assert.NoError(t, target.Render((*testTask)(nil), created, changes), "target.Render()")
+	tasks["testTask/created"] = created
+
+	a := &testTask{
+		Name:      PtrTo("updated"),
+		Lifecycle: LifecycleSync,
+		Tags:      map[string]string{"key": "old"},
+	}
+	e := &testTask{
+		Name:      PtrTo("updated"),
+		Lifecycle: LifecycleSync,
+		Tags:      map[string]string{"key": "new"},
+	}
+	changes = reflect.New(reflect.TypeOf(e).Elem()).Interface().(CloudupTask)
+	_ = BuildChanges(a, e, changes)
+	assert.NoError(t, target.Render(a, e, changes), "target.Render()")
+	tasks["testTask/updated"] = e
+
+	plan, err := target.BuildPlan(tasks)
+	assert.NoError(t, err, "target.BuildPlan()")
+
+	expected := &Plan{
+		Changes: []*PlanChange{
+			{
+				Kind:   "testTask",
+				Name:   "created",
+				Action: PlanActionCreate,
+				Fields: []*PlanField{
+					{Name: "Tags", New: "{key: value}"},
+				},
+			},
+			{
+				Kind:   "testTask",
+				Name:   "updated",
+				Action: PlanActionUpdate,
+				Fields: []*PlanField{
+					{Name: "Tags", Old: "{key: old}", New: "{key: new}"},
+				},
+			},
+		},
+	}
+	assert.Equal(t, expected, plan)
+}
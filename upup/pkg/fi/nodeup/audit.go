@@ -0,0 +1,245 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"k8s.io/kops/pkg/apis/nodeup"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+// ErrDrift is returned by an audit if the node has drifted from its configuration.
+var ErrDrift = errors.New("node has drifted from its configuration")
+
+// ErrAuditFailed is returned by an audit if the node could not be evaluated against its whole configuration.
+// The errors are included in the report.
+var ErrAuditFailed = errors.New("audit failed")
+
+// AuditDriftType describes how a resource on the node differs from its configuration.
+type AuditDriftType string
+
+const (
+	// AuditDriftMissing is a resource that is in the configuration, but not on the node.
+	AuditDriftMissing AuditDriftType = "Missing"
+	// AuditDriftModified is a resource whose state on the node differs from the configuration.
+	AuditDriftModified AuditDriftType = "Modified"
+)
+
+// auditIgnoredKinds are task kinds that do not inspect the node, so would always be reported as missing.
+var auditIgnoredKinds = map[string]bool{
+	"AptSource":          true,
+	"Chattr":             true,
+	"LoadImageTask":      true,
+	"UpdateEtcHostsTask": true,
+	"UpdatePackages":     true,
+}
+
+// AuditResource is a resource on the node that has drifted from the configuration.
+type AuditResource struct {
+	Kind  string         `json:"kind"`
+	Name  string         `json:"name"`
+	Drift AuditDriftType `json:"drift"`
+	// Fields are the fields whose value on the node differs from the configuration.
+	Fields []*fi.PlanField `json:"fields,omitempty"`
+}
+
+// AuditReport is the result of auditing a node.
+type AuditReport struct {
+	Hostname      string    `json:"hostname,omitempty"`
+	InstanceGroup string    `json:"instanceGroup,omitempty"`
+	Time          time.Time `json:"time"`
+	// Drifted is true if any resource has drifted from the configuration.
+	Drifted bool `json:"drifted"`
+	// Files are files and directories that are missing, or whose contents, mode or ownership differ.
+	Files []*AuditResource `json:"files,omitempty"`
+	// Services are systemd units that are missing, stopped, disabled or whose definition differs.
+	Services []*AuditResource `json:"services,omitempty"`
+	// Packages are packages that are not installed, or not at the expected version.
+	Packages []*AuditResource `json:"packages,omitempty"`
+	// Other are any other resources that have drifted, such as users and archives,
+	// and the nodeup configuration itself if it changed since the node booted.
+	Other []*AuditResource `json:"other,omitempty"`
+	// Errors are the errors that prevented parts of the configuration from being evaluated.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// auditConfigDrift returns the drift of the nodeup configuration, as identified by its hash, since the node booted,
+// or nil if it has not changed.
+func auditConfigDrift(bootHash, currentHash string) *AuditResource {
+	if bootHash == "" || bootHash == currentHash {
+		return nil
+	}
+	return &AuditResource{
+		Kind:  "NodeupConfig",
+		Name:  "nodeupconfig.yaml",
+		Drift: AuditDriftModified,
+		Fields: []*fi.PlanField{
+			{Name: "Hash", Old: bootHash, New: currentHash},
+		},
+	}
+}
+
+// prepareAuditTasks adapts the tasks so that they can be evaluated against the node without changing it.
+// Certificates, keys and kubeconfigs are not regenerated, so only the existence, mode and ownership of their files is checked.
+func prepareAuditTasks(taskMap map[string]fi.NodeupTask) {
+	for _, key := range removeCredentialTasks(taskMap) {
+		file := taskMap[key].(*nodetasks.File)
+		file.Contents = nil
+		file.IfNotExists = true
+	}
+}
+
+// removeCredentialTasks removes the tasks that must not be repeated on a running node:
+// certificates, keys and kubeconfigs are generated on the node, or requested from kops-controller, when it boots,
+// and images are pulled into the warm pool.
+// It returns the keys of the file tasks whose contents were generated by the removed tasks.
+func removeCredentialTasks(taskMap map[string]fi.NodeupTask) []string {
+	var generatedFiles []string
+	for key, task := range taskMap {
+		switch t := task.(type) {
+		case *nodetasks.BootstrapClientTask, *nodetasks.IssueCert, *nodetasks.KubeConfig, *nodetasks.PullImageTask:
+			delete(taskMap, key)
+		case *nodetasks.File:
+			if _, ok := t.Contents.(*fi.NodeupTaskDependentResource); ok {
+				generatedFiles = append(generatedFiles, key)
+			}
+		}
+	}
+	return generatedFiles
+}
+
+// buildAuditReport classifies the changes that nodeup would make to the node.
+func buildAuditReport(plan *fi.Plan) *AuditReport {
+	report := &AuditReport{}
+
+	for _, c := range plan.Changes {
+		if auditIgnoredKinds[c.Kind] {
+			continue
+		}
+		r := &AuditResource{
+			Kind:   c.Kind,
+			Name:   c.Name,
+			Drift:  AuditDriftModified,
+			Fields: c.Fields,
+		}
+		if c.Action == fi.PlanActionCreate {
+			r.Drift = AuditDriftMissing
+		}
+
+		switch c.Kind {
+		case "File":
+			redactContents(r.Fields)
+			report.Files = append(report.Files, r)
+		case "Service":
+			report.Services = append(report.Services, r)
+		case "Package":
+			report.Packages = append(report.Packages, r)
+		default:
+			report.Other = append(report.Other, r)
+		}
+		report.Drifted = true
+	}
+
+	return report
+}
+
+// writeAuditReport writes the report of the changes collected by the target as JSON, along with the drift of the
+// nodeup configuration, if any, and the error running the tasks, if any.
+// It returns ErrAuditFailed if the tasks could not all be evaluated, or else ErrDrift if the node has drifted from its configuration.
+func writeAuditReport(out io.Writer, target *fi.NodeupDryRunTarget, taskMap map[string]fi.NodeupTask, bootConfig *nodeup.BootConfig, configDrift *AuditResource, runErr error) error {
+	report := &AuditReport{}
+	if target != nil {
+		plan, err := target.BuildPlan(taskMap)
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("error building plan: %v", err))
+		} else {
+			report = buildAuditReport(plan)
+		}
+	}
+	if runErr != nil {
+		report.Errors = append([]string{runErr.Error()}, report.Errors...)
+	}
+	if configDrift != nil {
+		report.Other = append([]*AuditResource{configDrift}, report.Other...)
+		report.Drifted = true
+	}
+	if bootConfig != nil {
+		report.InstanceGroup = bootConfig.InstanceGroupName
+	}
+
+	if err := report.write(out); err != nil {
+		return err
+	}
+
+	if len(report.Errors) != 0 {
+		return fmt.Errorf("%w: %s", ErrAuditFailed, strings.Join(report.Errors, "; "))
+	}
+	if report.Drifted {
+		return ErrDrift
+	}
+	return nil
+}
+
+// WriteAuditErrorReport writes a report holding only the error that prevented the node from being audited,
+// so that the output of an audit is always a report.
+func WriteAuditErrorReport(out io.Writer, err error) error {
+	report := &AuditReport{Errors: []string{err.Error()}}
+	return report.write(out)
+}
+
+// write writes the report as JSON.
+func (r *AuditReport) write(out io.Writer) error {
+	r.Time = time.Now().UTC()
+	if hostname, err := os.Hostname(); err == nil {
+		r.Hostname = hostname
+	}
+
+	j, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to marshal JSON: %w", err)
+	}
+	if _, err := out.Write(append(j, '\n')); err != nil {
+		return fmt.Errorf("error writing to output: %w", err)
+	}
+	return nil
+}
+
+// redactContents replaces file contents with their digests, as files may hold secrets.
+func redactContents(fields []*fi.PlanField) {
+	for _, f := range fields {
+		if f.Name != "Contents" {
+			continue
+		}
+		f.Old = contentsDigest(f.Old)
+		f.New = contentsDigest(f.New)
+	}
+}
+
+func contentsDigest(s string) string {
+	hash := sha256.Sum256([]byte(s))
+	return "sha256:" + hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeup
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/kops/pkg/apis/nodeup"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+func TestBuildAuditReport(t *testing.T) {
+	plan := &fi.Plan{
+		Changes: []*fi.PlanChange{
+			{Kind: "File", Name: "/etc/kubernetes/kubelet.conf", Action: fi.PlanActionUpdate, Fields: []*fi.PlanField{
+				{Name: "Contents", Old: "edited", New: "expected"},
+				{Name: "Mode", Old: "0644", New: "0600"},
+			}},
+			{Kind: "Service", Name: "kubelet.service", Action: fi.PlanActionUpdate, Fields: []*fi.PlanField{
+				{Name: "Running", Old: "false", New: "true"},
+			}},
+			{Kind: "Package", Name: "conntrack", Action: fi.PlanActionCreate},
+			{Kind: "UserTask", Name: "kops-controller", Action: fi.PlanActionCreate},
+			{Kind: "LoadImageTask", Name: "LoadImage.0", Action: fi.PlanActionCreate},
+		},
+	}
+
+	report := buildAuditReport(plan)
+
+	assert.True(t, report.Drifted)
+	expectedFiles := []*AuditResource{
+		{Kind: "File", Name: "/etc/kubernetes/kubelet.conf", Drift: AuditDriftModified, Fields: []*fi.PlanField{
+			{Name: "Contents", Old: contentsDigest("edited"), New: contentsDigest("expected")},
+			{Name: "Mode", Old: "0644", New: "0600"},
+		}},
+	}
+	assert.Equal(t, expectedFiles, report.Files)
+	assert.Equal(t, []*AuditResource{
+		{Kind: "Service", Name: "kubelet.service", Drift: AuditDriftModified, Fields: []*fi.PlanField{{Name: "Running", Old: "false", New: "true"}}},
+	}, report.Services)
+	assert.Equal(t, []*AuditResource{
+		{Kind: "Package", Name: "conntrack", Drift: AuditDriftMissing},
+	}, report.Packages)
+	assert.Equal(t, []*AuditResource{
+		{Kind: "UserTask", Name: "kops-controller", Drift: AuditDriftMissing},
+	}, report.Other)
+}
+
+func TestBuildAuditReportNoDrift(t *testing.T) {
+	plan := &fi.Plan{
+		Changes: []*fi.PlanChange{
+			{Kind: "UpdatePackages", Name: "UpdatePackages", Action: fi.PlanActionCreate},
+		},
+	}
+
+	report := buildAuditReport(plan)
+
+	assert.False(t, report.Drifted)
+	assert.Empty(t, report.Other)
+}
+
+func TestPrepareAuditTasks(t *testing.T) {
+	issueCert := &nodetasks.IssueCert{Name: "kubelet"}
+	cert, _, _ := issueCert.GetResources()
+	generated := &nodetasks.File{Path: "/srv/kubernetes/kubelet.crt", Contents: cert, Type: nodetasks.FileType_File}
+	static := &nodetasks.File{Path: "/etc/sysctl.d/99-k8s-general.conf", Contents: fi.NewStringResource("net.ipv4.ip_forward=1"), Type: nodetasks.FileType_File}
+
+	taskMap := map[string]fi.NodeupTask{
+		"IssueCert/kubelet":                      issueCert,
+		"BootstrapClient":                        &nodetasks.BootstrapClientTask{},
+		"File//srv/kubernetes/kubelet.crt":       generated,
+		"File//etc/sysctl.d/99-k8s-general.conf": static,
+	}
+
+	prepareAuditTasks(taskMap)
+
+	assert.Len(t, taskMap, 2)
+	assert.Nil(t, generated.Contents)
+	assert.True(t, generated.IfNotExists)
+	assert.NotNil(t, static.Contents)
+	assert.False(t, static.IfNotExists)
+}
+
+func TestAuditConfigDrift(t *testing.T) {
+	assert.Nil(t, auditConfigDrift("", "current"), "nodes booted without a hash should not be reported")
+	assert.Nil(t, auditConfigDrift("current", "current"))
+	assert.Equal(t, &AuditResource{
+		Kind:   "NodeupConfig",
+		Name:   "nodeupconfig.yaml",
+		Drift:  AuditDriftModified,
+		Fields: []*fi.PlanField{{Name: "Hash", Old: "boot", New: "current"}},
+	}, auditConfigDrift("boot", "current"))
+}
+
+func TestWriteAuditReportErrors(t *testing.T) {
+	var out bytes.Buffer
+	configDrift := auditConfigDrift("boot", "current")
+	err := writeAuditReport(&out, nil, nil, &nodeup.BootConfig{InstanceGroupName: "nodes"}, configDrift, errors.New("error running tasks: boom"))
+	assert.ErrorIs(t, err, ErrAuditFailed)
+
+	report := &AuditReport{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), report))
+	assert.Equal(t, "nodes", report.InstanceGroup)
+	assert.True(t, report.Drifted)
+	assert.Equal(t, []*AuditResource{configDrift}, report.Other)
+	assert.Equal(t, []string{"error running tasks: boom"}, report.Errors)
+
+	out.Reset()
+	err = writeAuditReport(&out, nil, nil, nil, configDrift, nil)
+	assert.ErrorIs(t, err, ErrDrift)
+}
@@ -48,7 +48,7 @@ import (
 	"k8s.io/kops/pkg/apis/nodeup"
 	"k8s.io/kops/pkg/assets"
 	"k8s.io/kops/pkg/bootstrap"
-	"k8s.io/kops/pkg/bootstrap/pkibootstrap"
+	"k8s.io/kops/pkg/bootstrap/metalbootstrap"
 	"k8s.io/kops/pkg/configserver"
 	"k8s.io/kops/pkg/kopscontrollerclient"
 	"k8s.io/kops/pkg/wellknownports"
@@ -66,6 +66,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi/utils"
 	"k8s.io/kops/util/pkg/architectures"
 	"k8s.io/kops/util/pkg/distributions"
+	"k8s.io/kops/util/pkg/signature"
 	"k8s.io/kops/util/pkg/vfs"
 )
 
@@ -77,6 +78,9 @@ type NodeUpCommand struct {
 	CacheDir       string
 	ConfigLocation string
 	Target         string
+
+	// reconcileInterval is the reconcile interval of the configuration last read by a reconcile run.
+	reconcileInterval time.Duration
 }
 
 // Run is responsible for perform the nodeup process
@@ -155,7 +159,12 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 		return fmt.Errorf("no instance group defined in nodeup config")
 	}
 
-	if bootConfig.NodeupConfigHash != "" {
+	// The configuration is expected to have changed since the node booted when reconciling it,
+	// and a change is reported as drift by an audit
+	var configDrift *AuditResource
+	if c.Target == "audit" {
+		configDrift = auditConfigDrift(bootConfig.NodeupConfigHash, base64.StdEncoding.EncodeToString(nodeupConfigHash[:]))
+	} else if bootConfig.NodeupConfigHash != "" && c.Target != "reconcile" {
 		if want, got := bootConfig.NodeupConfigHash, base64.StdEncoding.EncodeToString(nodeupConfigHash[:]); got != want {
 			return fmt.Errorf("nodeup config hash mismatch (was %q, expected %q)", got, want)
 		}
@@ -166,6 +175,42 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 		return err
 	}
 
+	var replacements []string
+	if c.Target == "reconcile" {
+		if nodeupConfig.ReconcileInterval == nil {
+			return ErrReconcileDisabled
+		}
+		c.reconcileInterval = nodeupConfig.ReconcileInterval.Duration
+
+		// Worker nodes renew their certificates from kops-controller; the control plane issues its own when it boots
+		var renewCertificates func() error
+		if bootConfig.InstanceGroupRole != api.InstanceGroupRoleControlPlane && nodeupConfig.APIServerConfig == nil {
+			renewCertificates = func() error {
+				distribution, err := distributions.FindDistribution("/")
+				if err != nil {
+					return fmt.Errorf("error determining OS distribution: %w", err)
+				}
+				return RenewCertificates(ctx, &RenewCertificatesOptions{
+					ClusterName:      nodeupConfig.ClusterName,
+					SrvKubernetesDir: (&model.NodeupModelContext{Distribution: distribution}).PathSrvKubernetes(),
+					Force:            true,
+				})
+			}
+		}
+
+		var apply bool
+		replacements, apply, err = reconcileConfig(&nodeupConfig, base64.StdEncoding.EncodeToString(nodeupConfigHash[:]), &bootConfig, renewCertificates)
+		if err != nil {
+			return fmt.Errorf("error reading applied configuration: %w", err)
+		}
+		if len(replacements) != 0 {
+			klog.Warningf("not applying changes that require replacing the node: %v", replacements)
+		}
+		if !apply {
+			return reportReplacements(ctx, &nodeupConfig, replacements)
+		}
+	}
+
 	architecture, err := architectures.FindArchitecture()
 	if err != nil {
 		return fmt.Errorf("error determining OS architecture: %v", err)
@@ -178,6 +223,12 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 
 	configAssets := nodeupConfig.Assets[architecture]
 	assetStore := fi.NewAssetStore(c.CacheDir)
+	if len(nodeupConfig.AssetSignaturePublicKeys) != 0 {
+		assetStore.SignatureVerifier, err = signature.NewVerifier(nodeupConfig.AssetSignaturePublicKeys)
+		if err != nil {
+			return fmt.Errorf("error building signature verifier: %w", err)
+		}
+	}
 	for _, asset := range configAssets {
 		err := assetStore.Add(asset)
 		if err != nil {
@@ -284,8 +335,11 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 		}
 	}
 
-	if err := loadKernelModules(modelContext); err != nil {
-		return err
+	// An audit must not change the node, and the kernel modules are already loaded when reconciling it
+	if c.Target != "audit" && c.Target != "reconcile" {
+		if err := loadKernelModules(modelContext); err != nil {
+			return err
+		}
 	}
 
 	loader := &Loader{}
@@ -316,8 +370,21 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 	loader.Builders = append(loader.Builders, &model.KopsControllerBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.WarmPoolBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.PrefixBuilder{NodeupModelContext: modelContext})
+	loader.Builders = append(loader.Builders, &model.SysextBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.NerdctlBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.CrictlBuilder{NodeupModelContext: modelContext})
+	if nodeupPath, err := os.Executable(); err != nil {
+		klog.Warningf("cannot determine nodeup location; not installing node reconciler or certificate renewal: %v", err)
+	} else {
+		loader.Builders = append(loader.Builders, &model.NodeReconcilerBuilder{
+			NodeupModelContext: modelContext,
+			Command:            []string{nodeupPath, "reconcile", "--conf=" + c.ConfigLocation, "--cache=" + c.CacheDir},
+		})
+		loader.Builders = append(loader.Builders, &model.CertificateRenewalBuilder{
+			NodeupModelContext: modelContext,
+			NodeupPath:         nodeupPath,
+		})
+	}
 
 	loader.Builders = append(loader.Builders, &networking.CommonBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &networking.CalicoBuilder{NodeupModelContext: modelContext})
@@ -334,13 +401,15 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 
 	for i, image := range nodeupConfig.Images[architecture] {
 		taskMap["LoadImage."+strconv.Itoa(i)] = &nodetasks.LoadImageTask{
-			Sources: image.Sources,
-			Hash:    image.Hash,
+			Sources:             image.Sources,
+			Hash:                image.Hash,
+			SignaturePublicKeys: nodeupConfig.AssetSignaturePublicKeys,
 		}
 	}
 	// Protokube load image task is in ProtokubeBuilder
 
 	var target fi.NodeupTarget
+	var auditTarget *fi.NodeupDryRunTarget
 
 	switch c.Target {
 	case "direct":
@@ -351,12 +420,30 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 	case "dryrun":
 		assetBuilder := assets.NewAssetBuilder(vfs.Context, nil, false)
 		target = fi.NewNodeupDryRunTarget(assetBuilder, out)
+	case "audit":
+		assetBuilder := assets.NewAssetBuilder(vfs.Context, nil, false)
+		auditTarget = fi.NewNodeupDryRunTarget(assetBuilder, out)
+		target = auditTarget
+		prepareAuditTasks(taskMap)
+	case "reconcile":
+		target = &local.LocalTarget{
+			CacheDir: c.CacheDir,
+			Cloud:    cloud,
+		}
+		prepareReconcileTasks(taskMap)
 	default:
 		return fmt.Errorf("unsupported target type %q", c.Target)
 	}
 
 	context, err := fi.NewNodeupContext(ctx, target, keyStore, &bootConfig, &nodeupConfig, taskMap)
 	if err != nil {
+		if auditTarget != nil {
+			return writeAuditReport(out, nil, taskMap, &bootConfig, configDrift, fmt.Errorf("error building context: %w", err))
+		}
+		// The reconciler logs the error and retries on its next run
+		if c.Target == "reconcile" {
+			return fmt.Errorf("error building context: %w", err)
+		}
 		klog.Exitf("error building context: %v", err)
 	}
 
@@ -364,15 +451,40 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 	options.InitDefaults()
 
 	err = context.RunTasks(options)
+	if auditTarget != nil {
+		// Errors are included in the report, along with the drift found by the tasks that could be evaluated
+		if err != nil {
+			err = fmt.Errorf("error running tasks: %w", err)
+		}
+		return writeAuditReport(out, auditTarget, taskMap, &bootConfig, configDrift, err)
+	}
 	if err != nil {
+		if c.Target == "reconcile" {
+			return fmt.Errorf("error running tasks: %w", err)
+		}
 		klog.Exitf("error running tasks: %v", err)
 	}
 
 	err = target.Finish(taskMap)
 	if err != nil {
+		if c.Target == "reconcile" {
+			return fmt.Errorf("error closing target: %w", err)
+		}
 		klog.Exitf("error closing target: %v", err)
 	}
 
+	switch c.Target {
+	case "direct":
+		if err := writeAppliedConfig(&nodeupConfig); err != nil {
+			klog.Warningf("error recording applied configuration: %v", err)
+		}
+	case "reconcile":
+		if err := writeAppliedConfig(&nodeupConfig); err != nil {
+			return fmt.Errorf("error recording applied configuration: %w", err)
+		}
+		return reportReplacements(ctx, &nodeupConfig, replacements)
+	}
+
 	if nodeupConfig.EnableLifecycleHook {
 		if bootConfig.CloudProvider == api.CloudProviderAWS {
 			err := completeWarmingLifecycleAction(ctx, cloud.(awsup.AWSCloud), modelContext)
@@ -677,7 +789,7 @@ func getNodeConfigFromServers(ctx context.Context, bootConfig *nodeup.BootConfig
 		authenticator = a
 
 	case "metal":
-		a, err := pkibootstrap.NewAuthenticatorFromFile("/etc/kubernetes/kops/pki/machine/private.pem")
+		a, err := metalbootstrap.NewAuthenticator()
 		if err != nil {
 			return nil, err
 		}
@@ -31,6 +31,8 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/nodeup/local"
 	"k8s.io/kops/util/pkg/hashing"
+	"k8s.io/kops/util/pkg/signature"
+	"k8s.io/kops/util/pkg/vfs"
 )
 
 // Archive task downloads and extracts a tar file
@@ -50,6 +52,9 @@ type Archive struct {
 
 	// MapFiles is the list of files to extract with corresponding directories to extract
 	MapFiles map[string]string `json:"mapFiles,omitempty"`
+
+	// SignaturePublicKeys, if set, are the public keys one of which must have signed the archive.
+	SignaturePublicKeys []string `json:"signaturePublicKeys,omitempty"`
 }
 
 const (
@@ -154,6 +159,16 @@ func (_ *Archive) RenderLocal(t *local.LocalTarget, a, e, changes *Archive) erro
 			return err
 		}
 
+		if len(e.SignaturePublicKeys) != 0 {
+			verifier, err := signature.NewVerifier(e.SignaturePublicKeys)
+			if err != nil {
+				return err
+			}
+			if err := verifier.VerifyFile(vfs.Context, []string{e.Source}, hash); err != nil {
+				return err
+			}
+		}
+
 		if len(e.MapFiles) == 0 {
 			targetDir := e.TargetDir
 			if err := os.MkdirAll(targetDir, 0o755); err != nil {
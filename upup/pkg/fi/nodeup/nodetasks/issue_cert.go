@@ -24,11 +24,14 @@ import (
 	"hash/fnv"
 	"io"
 	"net"
+	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 	"time"
 
 	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/certinventory"
 	"k8s.io/kops/pkg/pki"
 	"k8s.io/kops/upup/pkg/fi"
 )
@@ -180,9 +183,33 @@ func (e *IssueCert) Run(c *fi.NodeupContext) error {
 		certResource.Resource = fi.NewBytesResource(b.Bytes())
 	}
 
+	// Record the certificate so that kops-controller can report its expiry.
+	// This is best-effort; the certificate itself has been issued.
+	record := certinventory.NewCertificate(e.Name, nodeName(c), e.Signer, e.KeypairID, certificate.Certificate)
+	if err := certinventory.Write(certinventory.Dir, record); err != nil {
+		klog.Warningf("failed to record certificate %q in inventory: %v", e.Name, err)
+	}
+
 	return nil
 }
 
+// nodeName returns the name of the local Node.
+// This mirrors NodeupModelContext.NodeName.
+func nodeName(c *fi.NodeupContext) string {
+	var name string
+	if c.T.NodeupConfig != nil {
+		name = c.T.NodeupConfig.KubeletConfig.HostnameOverride
+	}
+	if name == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			klog.Warningf("cannot determine hostname: %v", err)
+		}
+		name = hostname
+	}
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
 type hasAsBytes interface {
 	AsBytes() ([]byte, error)
 }
@@ -21,7 +21,6 @@ import (
 	"os"
 	"os/exec"
 	"path"
-	"path/filepath"
 	"strings"
 
 	"k8s.io/klog/v2"
@@ -30,6 +29,8 @@ import (
 	"k8s.io/kops/upup/pkg/fi/nodeup/local"
 	"k8s.io/kops/upup/pkg/fi/utils"
 	"k8s.io/kops/util/pkg/hashing"
+	"k8s.io/kops/util/pkg/signature"
+	"k8s.io/kops/util/pkg/vfs"
 )
 
 // LoadImageTask is responsible for downloading a docker image
@@ -38,6 +39,9 @@ type LoadImageTask struct {
 	Sources []string
 	Hash    string
 	Runtime string
+
+	// SignaturePublicKeys, if set, are the public keys one of which must have signed the image file.
+	SignaturePublicKeys []string `json:"signaturePublicKeys,omitempty"`
 }
 
 var (
@@ -100,8 +104,7 @@ func (_ *LoadImageTask) RenderLocal(t *local.LocalTarget, a, e, changes *LoadIma
 
 	// We assume the first url is the "main" url, and download to a local file based on that _name_, wherever we get it from
 	primaryURL := urls[0]
-	key := path.Base(primaryURL)
-	localFile := filepath.Join(t.CacheDir, hash.String()+"_"+utils.SanitizeString(key))
+	localFile := fi.AssetCachePath(t.CacheDir, primaryURL, hash)
 
 	for _, url := range urls {
 		_, err = fi.DownloadURL(url, localFile, hash)
@@ -118,6 +121,16 @@ func (_ *LoadImageTask) RenderLocal(t *local.LocalTarget, a, e, changes *LoadIma
 		return err
 	}
 
+	if len(e.SignaturePublicKeys) != 0 {
+		verifier, err := signature.NewVerifier(e.SignaturePublicKeys)
+		if err != nil {
+			return err
+		}
+		if err := verifier.VerifyFile(vfs.Context, urls, hash); err != nil {
+			return err
+		}
+	}
+
 	// containerd can't import gzipped container images, if the image is gzipped extract it to tmp dir
 	// TODO: Improve the naive gzip format detection by checking the content type bytes "\x1F\x8B\x08"
 	var tarFile string
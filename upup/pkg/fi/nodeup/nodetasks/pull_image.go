@@ -21,13 +21,18 @@ import (
 	"os/exec"
 	"strings"
 
+	"github.com/google/go-containerregistry/pkg/name"
 	"k8s.io/klog/v2"
 	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/signature"
 )
 
 // PullImageTask is responsible for pulling a docker image
 type PullImageTask struct {
 	Name string
+
+	// SignaturePublicKeys, if set, are the public keys one of which must have signed the image.
+	SignaturePublicKeys []string `json:"signaturePublicKeys,omitempty"`
 }
 
 var (
@@ -56,8 +61,26 @@ func (t *PullImageTask) GetName() *string {
 }
 
 func (e *PullImageTask) Run(c *fi.NodeupContext) error {
+	image := e.Name
+	if len(e.SignaturePublicKeys) != 0 {
+		verifier, err := signature.NewVerifier(e.SignaturePublicKeys)
+		if err != nil {
+			return err
+		}
+		digest, err := verifier.VerifyImage(image)
+		if err != nil {
+			return err
+		}
+		// Pull the image that was verified, even if the tag is pushed again
+		ref, err := name.ParseReference(image)
+		if err != nil {
+			return fmt.Errorf("parsing reference %q: %w", image, err)
+		}
+		image = ref.Context().Digest(digest).Name()
+	}
+
 	// Pull the container image
-	args := []string{"ctr", "--namespace", "k8s.io", "images", "pull", e.Name}
+	args := []string{"ctr", "--namespace", "k8s.io", "images", "pull", image}
 	human := strings.Join(args, " ")
 
 	klog.Infof("running command %s", human)
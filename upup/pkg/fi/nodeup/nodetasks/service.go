@@ -40,6 +40,7 @@ const (
 	centosSystemdSystemPath      = "/usr/lib/systemd/system"
 	flatcarSystemdSystemPath     = "/etc/systemd/system"
 	containerosSystemdSystemPath = "/etc/systemd/system"
+	immutableSystemdSystemPath   = "/etc/systemd/system"
 
 	containerdService = "containerd.service"
 	dockerService     = "docker.service"
@@ -88,7 +89,7 @@ func (s *Service) GetDependencies(tasks map[string]fi.NodeupTask) []fi.NodeupTas
 		// launching a custom Kubernetes build), they all depend on
 		// the "docker.service" Service task.
 		switch v := v.(type) {
-		case *Package, *UpdatePackages, *UserTask, *GroupTask, *Chattr, *BindMount, *Archive, *Prefix, *UpdateEtcHostsTask:
+		case *Package, *UpdatePackages, *UserTask, *GroupTask, *Chattr, *BindMount, *Archive, *Prefix, *Sysext, *UpdateEtcHostsTask:
 			deps = append(deps, v)
 		case *Service, *PullImageTask, *IssueCert, *BootstrapClientTask, *KubeConfig:
 			// ignore
@@ -178,6 +179,8 @@ func (_ *Service) systemdSystemPath() (string, error) {
 		return flatcarSystemdSystemPath, nil
 	} else if d == distributions.DistributionContainerOS {
 		return containerosSystemdSystemPath, nil
+	} else if d.IsImmutable() {
+		return immutableSystemdSystemPath, nil
 	} else {
 		return "", fmt.Errorf("unsupported systemd system")
 	}
@@ -420,6 +423,31 @@ func (s *Service) RenderLocal(_ *local.LocalTarget, a, e, changes *Service) erro
 	return nil
 }
 
+// RemoveService disables the named systemd unit and removes its definition.
+// A running unit is not stopped, so that a service can remove itself before exiting.
+func RemoveService(name string) error {
+	systemdSystemPath, err := (&Service{}).systemdSystemPath()
+	if err != nil {
+		return err
+	}
+
+	klog.Infof("Disabling service %q", name)
+	if output, err := exec.Command("systemctl", "disable", name).CombinedOutput(); err != nil {
+		return fmt.Errorf("error doing 'systemctl disable %s': %v\nOutput: %s", name, err, output)
+	}
+
+	servicePath := path.Join(systemdSystemPath, name)
+	if err := os.Remove(servicePath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error removing systemd service file %q: %v", servicePath, err)
+	}
+
+	klog.Infof("Reloading systemd configuration")
+	if output, err := exec.Command("systemctl", "daemon-reload").CombinedOutput(); err != nil {
+		return fmt.Errorf("error doing systemd daemon-reload: %v\nOutput: %s", err, output)
+	}
+	return nil
+}
+
 func (s *Service) GetName() *string {
 	return &s.Name
 }
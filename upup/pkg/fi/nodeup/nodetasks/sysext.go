@@ -0,0 +1,219 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodetasks
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/local"
+)
+
+// SysextExtensionsDir is the directory from which systemd-sysext merges directory-based extensions.
+const SysextExtensionsDir = "/var/lib/extensions"
+
+// sysextContentHashKey is the key of the extension-release field holding the hash of the extension's contents.
+const sysextContentHashKey = "KOPS_CONTENT_HASH"
+
+// Sysext merges a directory-based systemd-sysext extension into /usr,
+// so that the files written into the extension can be used on an immutable OS.
+type Sysext struct {
+	// Name is the name of the extension, a directory in SysextExtensionsDir.
+	Name string
+	// Release is the content of the extension-release file, which identifies the extension to systemd-sysext.
+	Release string `json:"release,omitempty"`
+	// ContentHash is the hash of the files in the extension. It is recorded in the extension-release file,
+	// so that the extension is merged again when its files change.
+	ContentHash string `json:"contentHash,omitempty"`
+}
+
+var (
+	_ fi.NodeupTask            = &Sysext{}
+	_ fi.HasName               = &Sysext{}
+	_ fi.NodeupHasDependencies = &Sysext{}
+)
+
+func (e *Sysext) GetName() *string {
+	return &e.Name
+}
+
+// String returns a string representation, implementing the Stringer interface
+func (e *Sysext) String() string {
+	return fmt.Sprintf("Sysext: %s", e.Name)
+}
+
+// Dir returns the directory that holds the extension.
+func (e *Sysext) Dir() string {
+	return filepath.Join(SysextExtensionsDir, e.Name)
+}
+
+// releasePath returns the path of the extension-release file, relative to the root of the extension.
+func (e *Sysext) releasePath() string {
+	return filepath.Join("usr/lib/extension-release.d", "extension-release."+e.Name)
+}
+
+// GetDependencies implements HasDependencies::GetDependencies; the extension is merged once its files are written.
+func (e *Sysext) GetDependencies(tasks map[string]fi.NodeupTask) []fi.NodeupTask {
+	var deps []fi.NodeupTask
+	for _, task := range tasks {
+		if f, ok := task.(*File); ok && strings.HasPrefix(f.Path, e.Dir()+"/") {
+			deps = append(deps, f)
+		}
+	}
+	return deps
+}
+
+func (e *Sysext) Find(c *fi.NodeupContext) (*Sysext, error) {
+	// Once the extension is merged, its extension-release file is visible under /usr
+	b, err := os.ReadFile(filepath.Join("/", e.releasePath()))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading extension-release of %q: %w", e.Name, err)
+	}
+
+	actual := &Sysext{
+		Name: e.Name,
+	}
+	actual.Release, actual.ContentHash = parseSysextRelease(string(b))
+	return actual, nil
+}
+
+func (e *Sysext) Run(c *fi.NodeupContext) error {
+	// The files of the extension have been written by the tasks we depend on
+	hash, err := hashSysextContents(e.Dir(), e.releasePath())
+	if err != nil {
+		return fmt.Errorf("error hashing contents of extension %q: %w", e.Name, err)
+	}
+	e.ContentHash = hash
+
+	return fi.NodeupDefaultDeltaRunMethod(e, c)
+}
+
+// renderRelease returns the content of the extension-release file, including the content hash.
+func (e *Sysext) renderRelease() string {
+	release := e.Release
+	if release != "" && !strings.HasSuffix(release, "\n") {
+		release += "\n"
+	}
+	if e.ContentHash != "" {
+		release += sysextContentHashKey + "=" + e.ContentHash + "\n"
+	}
+	return release
+}
+
+// parseSysextRelease splits the content hash from the content of an extension-release file.
+func parseSysextRelease(data string) (release string, contentHash string) {
+	var lines []string
+	for _, line := range strings.SplitAfter(data, "\n") {
+		if value, found := strings.CutPrefix(line, sysextContentHashKey+"="); found {
+			contentHash = strings.TrimSpace(value)
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, ""), contentHash
+}
+
+// hashSysextContents returns a hash of the paths, modes and contents of the files and symlinks in the extension
+// directory, except for its extension-release file.
+func hashSysextContents(dir string, releasePath string) (string, error) {
+	hasher := sha256.New()
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == dir {
+				return fs.SkipAll
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		relativePath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if relativePath == releasePath {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(hasher, "%s %s\n", relativePath, info.Mode())
+
+		switch {
+		case info.Mode()&fs.ModeSymlink != 0:
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(hasher, "-> %s\n", target)
+		case info.Mode().IsRegular():
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			if _, err := io.Copy(hasher, f); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+func (_ *Sysext) CheckChanges(a, e, changes *Sysext) error {
+	return nil
+}
+
+func (_ *Sysext) RenderLocal(t *local.LocalTarget, a, e, changes *Sysext) error {
+	releasePath := filepath.Join(e.Dir(), e.releasePath())
+	if err := fi.WriteFile(releasePath, fi.NewStringResource(e.renderRelease()), 0o644, 0o755, "", ""); err != nil {
+		return fmt.Errorf("error writing extension-release of %q: %w", e.Name, err)
+	}
+
+	// Merge the extension now, and whenever the node boots. Refreshing merges the extension again
+	// when its content hash changed, so that /usr holds the files that were written to the extension.
+	for _, args := range [][]string{
+		{"systemctl", "enable", "systemd-sysext.service"},
+		{"systemd-sysext", "refresh"},
+	} {
+		klog.Infof("running command %s", args)
+		cmd := exec.Command(args[0], args[1:]...)
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("error running %q: %v: %s", strings.Join(args, " "), err, string(output))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodetasks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSysextRelease(t *testing.T) {
+	e := &Sysext{Name: "kops", Release: "ID=_any\n", ContentHash: "abc123"}
+	rendered := e.renderRelease()
+	if rendered != "ID=_any\nKOPS_CONTENT_HASH=abc123\n" {
+		t.Errorf("unexpected extension-release %q", rendered)
+	}
+
+	release, contentHash := parseSysextRelease(rendered)
+	if release != e.Release || contentHash != e.ContentHash {
+		t.Errorf("expected release %q and hash %q, got %q and %q", e.Release, e.ContentHash, release, contentHash)
+	}
+
+	release, contentHash = parseSysextRelease("ID=_any\n")
+	if release != "ID=_any\n" || contentHash != "" {
+		t.Errorf("expected release without hash, got %q and %q", release, contentHash)
+	}
+}
+
+func TestHashSysextContents(t *testing.T) {
+	dir := t.TempDir()
+	e := &Sysext{Name: "kops"}
+
+	missing, err := hashSysextContents(filepath.Join(dir, "missing"), e.releasePath())
+	if err != nil {
+		t.Fatalf("error hashing missing extension: %v", err)
+	}
+
+	writeFile := func(path string, contents string) {
+		t.Helper()
+		path = filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	hash := func() string {
+		t.Helper()
+		h, err := hashSysextContents(dir, e.releasePath())
+		if err != nil {
+			t.Fatalf("error hashing extension: %v", err)
+		}
+		return h
+	}
+
+	writeFile("usr/bin/containerd", "v1")
+	initial := hash()
+	if initial == missing {
+		t.Errorf("expected the hash to change when files are added")
+	}
+
+	writeFile(e.releasePath(), "ID=_any\nKOPS_CONTENT_HASH="+initial+"\n")
+	if h := hash(); h != initial {
+		t.Errorf("expected the extension-release file not to change the hash")
+	}
+
+	writeFile("usr/bin/containerd", "v2")
+	if h := hash(); h == initial {
+		t.Errorf("expected the hash to change when a file changes")
+	}
+}
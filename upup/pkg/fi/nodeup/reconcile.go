@@ -0,0 +1,289 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/nodeup/pkg/model"
+	"k8s.io/kops/pkg/apis/nodeup"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+	"k8s.io/kops/upup/pkg/fi/utils"
+)
+
+// ErrReconcileDisabled is returned when reconciling a node of a cluster that does not have node reconciliation enabled.
+var ErrReconcileDisabled = errors.New("node reconciliation is not enabled")
+
+const (
+	// appliedConfigPath is where nodeup records the configuration it applied to the node.
+	appliedConfigPath = "/var/lib/kops/applied-nodeup-config.yaml"
+
+	// defaultReconcileDelay is how long the reconciler waits before its first run, if the interval is not known yet.
+	defaultReconcileDelay = 10 * time.Minute
+
+	// NodeConditionReplacementRequired is the node condition reporting configuration changes that the reconciler did not apply,
+	// because they require replacing the node.
+	NodeConditionReplacementRequired v1.NodeConditionType = "KopsReplacementRequired"
+)
+
+// replacementFields are the fields of the nodeup configuration that can only be changed by replacing the node,
+// as they change the binaries, images or packages installed on it, or how its network, firewall or disks are set up.
+var replacementFields = []string{
+	"KubernetesVersion",
+	"Assets",
+	"Images",
+	"Packages",
+	"Networking",
+	"VolumeMounts",
+	"UsesLegacyGossip",
+	"UsesNoneDNS",
+	"FirewallBackend",
+}
+
+// credentialFields are the fields of the nodeup configuration that only change the certificates issued to the node when it boots,
+// such as the keypairs that sign them after a keypair rotation. The reconciler does not issue certificates, so worker nodes
+// renew theirs from kops-controller when these fields change, and other nodes must be replaced.
+var credentialFields = []string{
+	"KeypairIDs",
+	"ApiserverAdditionalIPs",
+}
+
+// RunReconciler applies the latest configuration to the node every reconcile interval, until node reconciliation is disabled.
+func (c *NodeUpCommand) RunReconciler(out io.Writer) error {
+	interval := defaultReconcileDelay
+	if applied, err := readAppliedConfig(); err != nil {
+		klog.Warningf("error reading applied configuration: %v", err)
+	} else if applied != nil && applied.ReconcileInterval != nil {
+		interval = applied.ReconcileInterval.Duration
+	}
+
+	for {
+		// Wait first, so that the reconciler does not run while nodeup is configuring the node at boot
+		klog.Infof("next reconciliation in %v", interval)
+		time.Sleep(interval)
+
+		err := c.Run(out)
+		if errors.Is(err, ErrReconcileDisabled) {
+			klog.Infof("node reconciliation has been disabled; removing %s and exiting", model.NodeReconcilerServiceName)
+			if err := nodetasks.RemoveService(model.NodeReconcilerServiceName); err != nil {
+				klog.Warningf("error removing %s: %v", model.NodeReconcilerServiceName, err)
+			}
+			return nil
+		}
+		if err != nil {
+			klog.Warningf("error reconciling node: %v", err)
+		}
+		if c.reconcileInterval != 0 {
+			interval = c.reconcileInterval
+		}
+	}
+}
+
+// prepareReconcileTasks removes the tasks that must not run every time the node is reconciled.
+func prepareReconcileTasks(taskMap map[string]fi.NodeupTask) {
+	// Credentials are only issued when the node boots; the files holding them are kept as they are
+	for _, key := range removeCredentialTasks(taskMap) {
+		delete(taskMap, key)
+	}
+	for key, task := range taskMap {
+		switch task.(type) {
+		case *nodetasks.UpdatePackages, *nodetasks.LoadImageTask:
+			delete(taskMap, key)
+		}
+	}
+	// The reconciler must not restart itself
+	delete(taskMap, "Service/"+model.NodeReconcilerServiceName)
+}
+
+// reconcileConfig prepares the latest configuration to be applied to the running node.
+// Changes to fields that require replacing the node are reverted to the values last applied,
+// so that only the changes that can be made in place are applied; the names of those fields are returned.
+// Changes to the fields that only change the node's certificates are applied by calling renewCertificates;
+// if it is nil or fails, they are reverted and returned as well, so that they are reported until the node is replaced.
+// If the configuration applied to the node is not known, no changes can be applied and apply is false.
+func reconcileConfig(config *nodeup.Config, configHash string, bootConfig *nodeup.BootConfig, renewCertificates func() error) ([]string, bool, error) {
+	applied, err := readAppliedConfig()
+	if err != nil {
+		return nil, false, err
+	}
+	if applied == nil {
+		// The node was configured by a version of nodeup that did not record the configuration,
+		// so it can only be reconciled if it still has the configuration it booted with.
+		if bootConfig.NodeupConfigHash == configHash {
+			return nil, true, nil
+		}
+		return []string{"NodeupConfig"}, false, nil
+	}
+
+	replacements := revertReplacementFields(config, applied)
+	return append(replacements, reconcileCredentialFields(config, applied, renewCertificates)...), true, nil
+}
+
+// revertReplacementFields sets the fields of config that require replacing the node to their applied values,
+// and returns the names of the fields that were changed.
+func revertReplacementFields(config, applied *nodeup.Config) []string {
+	return revertFields(config, applied, replacementFields)
+}
+
+// reconcileCredentialFields renews the node's certificates if the fields that change them were changed.
+// If the certificates cannot be renewed, the fields are reverted to their applied values and their names are returned.
+func reconcileCredentialFields(config, applied *nodeup.Config, renewCertificates func() error) []string {
+	changed := changedFields(config, applied, credentialFields)
+	if len(changed) == 0 {
+		return nil
+	}
+	if renewCertificates != nil {
+		klog.Infof("renewing the node's certificates, as %s changed", strings.Join(changed, ", "))
+		err := renewCertificates()
+		if err == nil {
+			return nil
+		}
+		klog.Warningf("error renewing certificates: %v", err)
+	}
+	return revertFields(config, applied, credentialFields)
+}
+
+// revertFields sets the named fields of config to their applied values, and returns the names of the fields that were changed.
+func revertFields(config, applied *nodeup.Config, names []string) []string {
+	changed := changedFields(config, applied, names)
+	latest := reflect.ValueOf(config).Elem()
+	previous := reflect.ValueOf(applied).Elem()
+	for _, name := range changed {
+		latest.FieldByName(name).Set(previous.FieldByName(name))
+	}
+	return changed
+}
+
+// changedFields returns the names of the named fields whose value in config differs from their applied value.
+func changedFields(config, applied *nodeup.Config, names []string) []string {
+	var changed []string
+	latest := reflect.ValueOf(config).Elem()
+	previous := reflect.ValueOf(applied).Elem()
+	for _, name := range names {
+		if !reflect.DeepEqual(latest.FieldByName(name).Interface(), previous.FieldByName(name).Interface()) {
+			changed = append(changed, name)
+		}
+	}
+	return changed
+}
+
+// readAppliedConfig reads the configuration last applied to the node; it returns (nil, nil) if it was not recorded.
+func readAppliedConfig() (*nodeup.Config, error) {
+	b, err := os.ReadFile(appliedConfigPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	config := &nodeup.Config{}
+	if err := utils.YamlUnmarshal(b, config); err != nil {
+		return nil, fmt.Errorf("error parsing %q: %w", appliedConfigPath, err)
+	}
+	return config, nil
+}
+
+// writeAppliedConfig records the configuration applied to the node, for the reconciler to compare against.
+func writeAppliedConfig(config *nodeup.Config) error {
+	b, err := utils.YamlMarshal(config)
+	if err != nil {
+		return fmt.Errorf("error serializing configuration: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(appliedConfigPath), 0o755); err != nil {
+		return fmt.Errorf("error creating directory for %q: %w", appliedConfigPath, err)
+	}
+	tmp := appliedConfigPath + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o600); err != nil {
+		return fmt.Errorf("error writing %q: %w", tmp, err)
+	}
+	return os.Rename(tmp, appliedConfigPath)
+}
+
+// reportReplacements sets the replacement condition on the node.
+func reportReplacements(ctx context.Context, config *nodeup.Config, replacements []string) error {
+	modelContext := &model.NodeupModelContext{NodeupConfig: config}
+	nodeName, err := modelContext.NodeName()
+	if err != nil {
+		return err
+	}
+	return setReplacementCondition(ctx, modelContext.KubeletKubeConfig(), nodeName, replacements)
+}
+
+// setReplacementCondition reports on the node whether configuration changes were not applied because they require replacing the node.
+func setReplacementCondition(ctx context.Context, kubeconfigPath string, nodeName string, replacements []string) error {
+	config, err := clientcmd.BuildConfigFromFlags("", kubeconfigPath)
+	if err != nil {
+		return fmt.Errorf("error loading kubeconfig %q: %w", kubeconfigPath, err)
+	}
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("error building kubernetes client: %w", err)
+	}
+
+	node, err := client.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("error getting node %q: %w", nodeName, err)
+	}
+
+	now := metav1.Now()
+	condition := v1.NodeCondition{
+		Type:               NodeConditionReplacementRequired,
+		Status:             v1.ConditionFalse,
+		Reason:             "ConfigurationApplied",
+		Message:            "The latest kOps configuration has been applied to the node",
+		LastHeartbeatTime:  now,
+		LastTransitionTime: now,
+	}
+	if len(replacements) != 0 {
+		condition.Status = v1.ConditionTrue
+		condition.Reason = "ConfigurationChanged"
+		condition.Message = fmt.Sprintf("Changes to %s require replacing the node", strings.Join(replacements, ", "))
+	}
+	for _, existing := range node.Status.Conditions {
+		if existing.Type == condition.Type && existing.Status == condition.Status {
+			condition.LastTransitionTime = existing.LastTransitionTime
+		}
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": []v1.NodeCondition{condition},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error building patch: %w", err)
+	}
+	if _, err := client.CoreV1().Nodes().PatchStatus(ctx, nodeName, patch); err != nil {
+		return fmt.Errorf("error updating conditions of node %q: %w", nodeName, err)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeup
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/kops/pkg/apis/nodeup"
+)
+
+func TestRevertReplacementFields(t *testing.T) {
+	applied := &nodeup.Config{
+		KubernetesVersion: "1.32.0",
+		Packages:          []string{"conntrack"},
+		SysctlParameters:  []string{"net.ipv4.ip_forward=1"},
+	}
+	config := &nodeup.Config{
+		KubernetesVersion: "1.33.0",
+		Packages:          []string{"conntrack"},
+		SysctlParameters:  []string{"net.ipv4.ip_forward=1", "vm.max_map_count=262144"},
+	}
+
+	replacements := revertReplacementFields(config, applied)
+
+	assert.Equal(t, []string{"KubernetesVersion"}, replacements)
+	assert.Equal(t, "1.32.0", config.KubernetesVersion)
+	assert.Equal(t, []string{"net.ipv4.ip_forward=1", "vm.max_map_count=262144"}, config.SysctlParameters)
+}
+
+func TestRevertReplacementFieldsUnchanged(t *testing.T) {
+	applied := &nodeup.Config{KubernetesVersion: "1.33.0", Packages: []string{"conntrack"}}
+	config := &nodeup.Config{KubernetesVersion: "1.33.0", Packages: []string{"conntrack"}}
+
+	assert.Empty(t, revertReplacementFields(config, applied))
+}
+
+func TestReconcileCredentialFields(t *testing.T) {
+	grid := []struct {
+		name             string
+		renew            func() error
+		expectedRenewals int
+		expected         []string
+		expectedIDs      map[string]string
+	}{
+		{
+			name:             "renewed",
+			renew:            func() error { return nil },
+			expectedRenewals: 1,
+			expectedIDs:      map[string]string{"kubernetes-ca": "2"},
+		},
+		{
+			name:             "renewal failed",
+			renew:            func() error { return errors.New("kops-controller unreachable") },
+			expectedRenewals: 1,
+			expected:         []string{"KeypairIDs"},
+			expectedIDs:      map[string]string{"kubernetes-ca": "1"},
+		},
+		{
+			name:        "cannot renew",
+			expected:    []string{"KeypairIDs"},
+			expectedIDs: map[string]string{"kubernetes-ca": "1"},
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			applied := &nodeup.Config{KeypairIDs: map[string]string{"kubernetes-ca": "1"}}
+			config := &nodeup.Config{KeypairIDs: map[string]string{"kubernetes-ca": "2"}}
+
+			renewals := 0
+			var renew func() error
+			if g.renew != nil {
+				renew = func() error {
+					renewals++
+					return g.renew()
+				}
+			}
+
+			assert.Equal(t, g.expected, reconcileCredentialFields(config, applied, renew))
+			assert.Equal(t, g.expectedRenewals, renewals)
+			assert.Equal(t, g.expectedIDs, config.KeypairIDs)
+		})
+	}
+}
+
+func TestReconcileCredentialFieldsUnchanged(t *testing.T) {
+	applied := &nodeup.Config{KeypairIDs: map[string]string{"kubernetes-ca": "1"}}
+	config := &nodeup.Config{KeypairIDs: map[string]string{"kubernetes-ca": "1"}}
+
+	renew := func() error {
+		t.Errorf("certificates should not be renewed")
+		return nil
+	}
+	assert.Empty(t, reconcileCredentialFields(config, applied, renew))
+}
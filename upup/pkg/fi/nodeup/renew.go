@@ -0,0 +1,375 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeup
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/nodeup"
+	"k8s.io/kops/pkg/kopscontrollerclient"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/pkg/wellknownports"
+)
+
+// RenewCertificatesOptions configures the renewal of the certificates that a node got from kops-controller.
+type RenewCertificatesOptions struct {
+	// ClusterName is the name of the cluster, used to reach kops-controller.
+	ClusterName string
+	// SrvKubernetesDir is the directory that holds the kubelet serving certificate.
+	SrvKubernetesDir string
+	// RenewBefore is how long before they expire that certificates are renewed.
+	RenewBefore time.Duration
+	// Force renews the certificates even if they do not expire soon.
+	Force bool
+}
+
+// renewableCertificate is a certificate that the node gets from kops-controller when it bootstraps,
+// and where nodeup writes it.
+type renewableCertificate struct {
+	// name is the name of the certificate in the bootstrap protocol.
+	name string
+	// kubeconfig is the kubeconfig holding the certificate and key, if they are in a kubeconfig.
+	kubeconfig string
+	// certPath and keyPath hold the certificate and key, if they are not in a kubeconfig.
+	certPath string
+	keyPath  string
+	// pod is the prefix of the name of the pod that uses the certificate, if it is not the kubelet.
+	// The pod is restarted to use the renewed certificate.
+	pod string
+
+	cert *x509.Certificate
+	key  *pki.PrivateKey
+}
+
+func renewableCertificates(srvKubernetesDir string) []*renewableCertificate {
+	return []*renewableCertificate{
+		{name: "kubelet", kubeconfig: "/var/lib/kubelet/kubeconfig"},
+		{name: "kubelet-server", certPath: filepath.Join(srvKubernetesDir, "kubelet-server.crt"), keyPath: filepath.Join(srvKubernetesDir, "kubelet-server.key")},
+		{name: "kube-proxy", kubeconfig: "/var/lib/kube-proxy/kubeconfig", pod: "kube-proxy-"},
+		{name: "kube-router", kubeconfig: "/var/lib/kube-router/kubeconfig", pod: "kube-router-"},
+	}
+}
+
+// RenewCertificates renews the certificates the node got from kops-controller, when one of them expires within RenewBefore.
+// The node authenticates with its current kubelet client certificate, so it must renew it before it expires.
+func RenewCertificates(ctx context.Context, options *RenewCertificatesOptions) error {
+	var certs []*renewableCertificate
+	var kubelet *renewableCertificate
+	for _, cert := range renewableCertificates(options.SrvKubernetesDir) {
+		found, err := cert.load()
+		if err != nil {
+			return err
+		}
+		if !found {
+			continue
+		}
+		certs = append(certs, cert)
+		if cert.name == "kubelet" {
+			kubelet = cert
+		}
+	}
+	if kubelet == nil {
+		return fmt.Errorf("kubelet client certificate not found; only nodes that bootstrapped with kops-controller can renew their certificates")
+	}
+
+	now := time.Now()
+	expiry := earliestExpiry(certs)
+	if !options.Force && expiry.After(now.Add(options.RenewBefore)) {
+		klog.Infof("certificates expire at %v; not renewing them until %v", expiry, expiry.Add(-options.RenewBefore))
+		return nil
+	}
+	if kubelet.cert.NotAfter.Before(now) {
+		return fmt.Errorf("kubelet client certificate expired at %v; the node must be replaced", kubelet.cert.NotAfter)
+	}
+
+	client, err := buildRenewClient(options.ClusterName, kubelet)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	req := &nodeup.RenewRequest{
+		APIVersion: nodeup.BootstrapAPIVersion,
+		Certs:      map[string]string{},
+	}
+	keys := map[string]*pki.PrivateKey{}
+	for _, cert := range certs {
+		key, err := pki.GeneratePrivateKey()
+		if err != nil {
+			return fmt.Errorf("generating private key: %w", err)
+		}
+		keys[cert.name] = key
+
+		pkData, err := x509.MarshalPKIXPublicKey(key.Key.Public())
+		if err != nil {
+			return fmt.Errorf("marshalling public key: %w", err)
+		}
+		req.Certs[cert.name] = string(pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: pkData}))
+
+		if cert.name == "kubelet-server" {
+			req.KubeletServerCertificate = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.cert.Raw}))
+		}
+	}
+
+	var resp nodeup.BootstrapResponse
+	if err := client.Renew(ctx, req, &resp); err != nil {
+		return fmt.Errorf("renewing certificates: %w", err)
+	}
+
+	restartKubelet := false
+	for _, cert := range certs {
+		issued, ok := resp.Certs[cert.name]
+		if !ok {
+			return fmt.Errorf("kops-controller did not return a %q certificate", cert.name)
+		}
+		if _, err := pki.ParsePEMCertificate([]byte(issued)); err != nil {
+			return fmt.Errorf("parsing %q certificate: %w", cert.name, err)
+		}
+		if err := cert.save([]byte(issued), keys[cert.name]); err != nil {
+			return err
+		}
+		klog.Infof("renewed %q certificate", cert.name)
+		if cert.pod == "" {
+			restartKubelet = true
+		}
+	}
+
+	if restartKubelet {
+		klog.Infof("restarting kubelet to use the renewed certificates")
+		if output, err := exec.Command("systemctl", "restart", "kubelet.service").CombinedOutput(); err != nil {
+			return fmt.Errorf("error restarting kubelet: %w\nOutput: %s", err, output)
+		}
+	}
+	for _, cert := range certs {
+		if cert.pod != "" {
+			if err := restartPods(cert.pod); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// buildRenewClient builds a client for kops-controller that authenticates with the kubelet client certificate.
+// kops-controller's certificate is issued by the same CA as the kubelet's, so we trust the CA in the kubelet kubeconfig.
+func buildRenewClient(clusterName string, kubelet *renewableCertificate) (*kopscontrollerclient.Client, error) {
+	config, err := clientcmd.LoadFromFile(kubelet.kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("error loading kubeconfig %q: %w", kubelet.kubeconfig, err)
+	}
+	var cas []byte
+	for _, cluster := range config.Clusters {
+		cas = append(cas, cluster.CertificateAuthorityData...)
+	}
+	if len(cas) == 0 {
+		return nil, fmt.Errorf("kubeconfig %q did not have a certificate authority", kubelet.kubeconfig)
+	}
+
+	keyBytes, err := kubelet.key.AsBytes()
+	if err != nil {
+		return nil, err
+	}
+	clientCertificate, err := tls.X509KeyPair(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: kubelet.cert.Raw}), keyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("loading kubelet client certificate: %w", err)
+	}
+
+	return &kopscontrollerclient.Client{
+		CAs: cas,
+		BaseURL: url.URL{
+			Scheme: "https",
+			Host:   net.JoinHostPort("kops-controller.internal."+clusterName, strconv.Itoa(wellknownports.KopsControllerPort)),
+			Path:   "/",
+		},
+		ClientCertificate: &clientCertificate,
+	}, nil
+}
+
+func earliestExpiry(certs []*renewableCertificate) time.Time {
+	var expiry time.Time
+	for _, cert := range certs {
+		if expiry.IsZero() || cert.cert.NotAfter.Before(expiry) {
+			expiry = cert.cert.NotAfter
+		}
+	}
+	return expiry
+}
+
+// load reads the certificate and key; it returns false if the node does not have the certificate.
+func (c *renewableCertificate) load() (bool, error) {
+	var certBytes, keyBytes []byte
+	if c.kubeconfig != "" {
+		if _, err := os.Stat(c.kubeconfig); os.IsNotExist(err) {
+			return false, nil
+		}
+		config, err := clientcmd.LoadFromFile(c.kubeconfig)
+		if err != nil {
+			return false, fmt.Errorf("error loading kubeconfig %q: %w", c.kubeconfig, err)
+		}
+		authInfo, err := currentAuthInfo(config)
+		if err != nil {
+			return false, fmt.Errorf("kubeconfig %q: %w", c.kubeconfig, err)
+		}
+		certBytes, keyBytes = authInfo.ClientCertificateData, authInfo.ClientKeyData
+	} else {
+		var err error
+		if certBytes, err = os.ReadFile(c.certPath); os.IsNotExist(err) {
+			return false, nil
+		} else if err != nil {
+			return false, err
+		}
+		if keyBytes, err = os.ReadFile(c.keyPath); err != nil {
+			return false, err
+		}
+	}
+
+	cert, err := pki.ParsePEMCertificate(certBytes)
+	if err != nil {
+		return false, fmt.Errorf("parsing %q certificate: %w", c.name, err)
+	}
+	key, err := pki.ParsePEMPrivateKey(keyBytes)
+	if err != nil {
+		return false, fmt.Errorf("parsing %q key: %w", c.name, err)
+	}
+	c.cert = cert.Certificate
+	c.key = key
+	return true, nil
+}
+
+// save replaces the certificate and key.
+func (c *renewableCertificate) save(cert []byte, key *pki.PrivateKey) error {
+	keyBytes, err := key.AsBytes()
+	if err != nil {
+		return err
+	}
+
+	if c.kubeconfig == "" {
+		// Stage both files before replacing either, and replace the key first,
+		// so the certificate on disk never refers to a key that isn't there yet.
+		certTmp, err := stageFile(c.certPath, cert)
+		if err != nil {
+			return err
+		}
+		keyTmp, err := stageFile(c.keyPath, keyBytes)
+		if err != nil {
+			_ = os.Remove(certTmp)
+			return err
+		}
+		if err := renameFile(keyTmp, c.keyPath); err != nil {
+			_ = os.Remove(certTmp)
+			return err
+		}
+		return renameFile(certTmp, c.certPath)
+	}
+
+	b, err := os.ReadFile(c.kubeconfig)
+	if err != nil {
+		return err
+	}
+	b, err = replaceKubeconfigCertificate(b, cert, keyBytes)
+	if err != nil {
+		return fmt.Errorf("kubeconfig %q: %w", c.kubeconfig, err)
+	}
+	return replaceFile(c.kubeconfig, b)
+}
+
+// replaceKubeconfigCertificate replaces the client certificate and key of the current context of the kubeconfig.
+func replaceKubeconfigCertificate(kubeconfig []byte, cert, key []byte) ([]byte, error) {
+	config, err := clientcmd.Load(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	authInfo, err := currentAuthInfo(config)
+	if err != nil {
+		return nil, err
+	}
+	authInfo.ClientCertificateData = cert
+	authInfo.ClientKeyData = key
+	return clientcmd.Write(*config)
+}
+
+func currentAuthInfo(config *clientcmdapi.Config) (*clientcmdapi.AuthInfo, error) {
+	kubeContext := config.Contexts[config.CurrentContext]
+	if kubeContext == nil {
+		return nil, fmt.Errorf("current context %q not found", config.CurrentContext)
+	}
+	authInfo := config.AuthInfos[kubeContext.AuthInfo]
+	if authInfo == nil {
+		return nil, fmt.Errorf("user %q not found", kubeContext.AuthInfo)
+	}
+	return authInfo, nil
+}
+
+// replaceFile atomically replaces the contents of the file, keeping its permissions.
+func replaceFile(p string, data []byte) error {
+	tmp, err := stageFile(p, data)
+	if err != nil {
+		return err
+	}
+	return renameFile(tmp, p)
+}
+
+// stageFile writes the new contents of the file next to it, keeping its permissions, and returns the temporary path.
+func stageFile(p string, data []byte) (string, error) {
+	stat, err := os.Stat(p)
+	if err != nil {
+		return "", err
+	}
+	tmp := p + ".tmp"
+	if err := os.WriteFile(tmp, data, stat.Mode().Perm()); err != nil {
+		return "", fmt.Errorf("error writing %q: %w", tmp, err)
+	}
+	return tmp, nil
+}
+
+// renameFile moves a staged file into place.
+func renameFile(tmp, p string) error {
+	if err := os.Rename(tmp, p); err != nil {
+		return fmt.Errorf("error replacing %q: %w", p, err)
+	}
+	return nil
+}
+
+// restartPods stops the pods whose names start with prefix, so that the kubelet restarts them.
+func restartPods(prefix string) error {
+	output, err := exec.Command("crictl", "pods", "--name", "^"+prefix, "--quiet").Output()
+	if err != nil {
+		return fmt.Errorf("error listing %s pods: %w", strings.TrimSuffix(prefix, "-"), err)
+	}
+	for _, id := range strings.Fields(string(output)) {
+		klog.Infof("restarting pod %s to use the renewed certificates", id)
+		if output, err := exec.Command("crictl", "stopp", id).CombinedOutput(); err != nil {
+			return fmt.Errorf("error stopping pod %s: %w\nOutput: %s", id, err, output)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeup
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/kops/pkg/pki"
+)
+
+func buildTestCertificate(t *testing.T, notAfter time.Time) ([]byte, *pki.PrivateKey) {
+	key, err := pki.GeneratePrivateKey()
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "system:node:node1"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Key.Public(), key.Key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), key
+}
+
+func TestRenewableCertificateKubeconfig(t *testing.T) {
+	dir := t.TempDir()
+	expiry := time.Now().Add(10 * 24 * time.Hour).Truncate(time.Second)
+	cert, key := buildTestCertificate(t, expiry)
+	keyBytes, err := key.AsBytes()
+	require.NoError(t, err)
+
+	config := clientcmdapi.NewConfig()
+	config.Clusters["local"] = &clientcmdapi.Cluster{Server: "https://api.internal.example.com", CertificateAuthorityData: []byte("ca")}
+	config.AuthInfos["kubelet"] = &clientcmdapi.AuthInfo{ClientCertificateData: cert, ClientKeyData: keyBytes}
+	config.Contexts["service-account-context"] = &clientcmdapi.Context{Cluster: "local", AuthInfo: "kubelet"}
+	config.CurrentContext = "service-account-context"
+	kubeconfig := filepath.Join(dir, "kubeconfig")
+	require.NoError(t, clientcmd.WriteToFile(*config, kubeconfig))
+	require.NoError(t, os.Chmod(kubeconfig, 0o400))
+
+	missing := &renewableCertificate{name: "kube-router", kubeconfig: filepath.Join(dir, "missing")}
+	found, err := missing.load()
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	c := &renewableCertificate{name: "kubelet", kubeconfig: kubeconfig}
+	found, err = c.load()
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.True(t, c.cert.NotAfter.Equal(expiry))
+
+	renewedExpiry := time.Now().Add(400 * 24 * time.Hour).Truncate(time.Second)
+	renewed, renewedKey := buildTestCertificate(t, renewedExpiry)
+	require.NoError(t, c.save(renewed, renewedKey))
+
+	c = &renewableCertificate{name: "kubelet", kubeconfig: kubeconfig}
+	found, err = c.load()
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.True(t, c.cert.NotAfter.Equal(renewedExpiry))
+
+	stat, err := os.Stat(kubeconfig)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o400), stat.Mode().Perm())
+
+	saved, err := clientcmd.LoadFromFile(kubeconfig)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("ca"), saved.Clusters["local"].CertificateAuthorityData)
+	assert.Equal(t, "https://api.internal.example.com", saved.Clusters["local"].Server)
+}
+
+func TestRenewableCertificateFiles(t *testing.T) {
+	dir := t.TempDir()
+	cert, key := buildTestCertificate(t, time.Now().Add(24*time.Hour))
+	keyBytes, err := key.AsBytes()
+	require.NoError(t, err)
+
+	certs := renewableCertificates(dir)
+	var c *renewableCertificate
+	for _, cert := range certs {
+		if cert.name == "kubelet-server" {
+			c = cert
+		}
+	}
+	require.NotNil(t, c)
+
+	found, err := c.load()
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "kubelet-server.crt"), cert, 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "kubelet-server.key"), keyBytes, 0o400))
+	found, err = c.load()
+	require.NoError(t, err)
+	require.True(t, found)
+
+	renewed, renewedKey := buildTestCertificate(t, time.Now().Add(48*time.Hour))
+	require.NoError(t, c.save(renewed, renewedKey))
+
+	b, err := os.ReadFile(filepath.Join(dir, "kubelet-server.crt"))
+	require.NoError(t, err)
+	assert.Equal(t, renewed, b)
+	stat, err := os.Stat(filepath.Join(dir, "kubelet-server.key"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o400), stat.Mode().Perm())
+}
+
+func TestEarliestExpiry(t *testing.T) {
+	now := time.Now()
+	certs := []*renewableCertificate{
+		{cert: &x509.Certificate{NotAfter: now.Add(48 * time.Hour)}},
+		{cert: &x509.Certificate{NotAfter: now.Add(24 * time.Hour)}},
+		{cert: &x509.Certificate{NotAfter: now.Add(72 * time.Hour)}},
+	}
+	assert.Equal(t, now.Add(24*time.Hour), earliestExpiry(certs))
+}
@@ -37,6 +37,9 @@ type Distribution struct {
 
 	// version is a numeric identifier for comparison purposes within a particular project
 	version float32
+
+	// sysext is true if binaries are installed as systemd-sysext extensions, because /usr is read-only
+	sysext bool
 }
 
 var (
@@ -59,8 +62,9 @@ var (
 	DistributionAmazonLinux2023 = Distribution{packageFormat: "rpm", project: "amazonlinux2023", id: "amzn", version: 2023}
 
 	// Immutable distros
-	DistributionFlatcar     = Distribution{packageFormat: "", project: "flatcar", id: "flatcar", version: 0}
-	DistributionContainerOS = Distribution{packageFormat: "", project: "containeros", id: "containeros", version: 0}
+	DistributionFlatcar      = Distribution{packageFormat: "", project: "flatcar", id: "flatcar", version: 0}
+	DistributionContainerOS  = Distribution{packageFormat: "", project: "containeros", id: "containeros", version: 0}
+	DistributionFedoraCoreOS = Distribution{packageFormat: "", project: "fedora-coreos", id: "fedora-coreos", version: 0, sysext: true}
 )
 
 // IsDebianFamily returns true if this distribution uses deb packages and generally follows debian package names
@@ -78,6 +82,16 @@ func (d *Distribution) IsRHELFamily() bool {
 	return d.packageFormat == "rpm"
 }
 
+// IsImmutable returns true if this distribution does not have a package manager, so nodeup cannot install packages
+func (d *Distribution) IsImmutable() bool {
+	return d.packageFormat == ""
+}
+
+// UsesSysext returns true if this distribution has a read-only /usr, and binaries are installed as systemd-sysext extensions
+func (d *Distribution) UsesSysext() bool {
+	return d.sysext
+}
+
 // HasDNF returns true if this distribution uses dnf
 func (d *Distribution) HasDNF() bool {
 	if !d.IsRHELFamily() {
@@ -115,7 +129,7 @@ func (d *Distribution) DefaultUsers() ([]string, error) {
 		return []string{"ec2-user"}, nil
 	case "rocky":
 		return []string{"rocky"}, nil
-	case "flatcar":
+	case "flatcar", "fedora-coreos":
 		return []string{"core"}, nil
 	default:
 		return nil, fmt.Errorf("unknown distro %v", d)
@@ -39,6 +39,9 @@ func FindDistribution(rootfs string) (Distribution, error) {
 			if strings.HasPrefix(line, "VERSION_ID=") {
 				osRelease["VERSION_ID"] = strings.Trim(line[11:], "\"")
 			}
+			if strings.HasPrefix(line, "VARIANT_ID=") {
+				osRelease["VARIANT_ID"] = strings.Trim(line[11:], "\"")
+			}
 		}
 	} else {
 		return Distribution{}, fmt.Errorf("reading /etc/os-release: %v", err)
@@ -46,6 +49,11 @@ func FindDistribution(rootfs string) (Distribution, error) {
 
 	distro := fmt.Sprintf("%s-%s", osRelease["ID"], osRelease["VERSION_ID"])
 
+	// Image-based variants share the ID and VERSION_ID of the distro they are built from
+	if osRelease["ID"] == "fedora" && osRelease["VARIANT_ID"] == "coreos" {
+		return DistributionFedoraCoreOS, nil
+	}
+
 	// Most distros have a fixed VERSION_ID
 	switch distro {
 	case "amzn-2":
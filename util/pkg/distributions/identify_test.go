@@ -84,6 +84,11 @@ func TestFindDistribution(t *testing.T) {
 			err:      nil,
 			expected: DistributionDebian12,
 		},
+		{
+			rootfs:   "fedoracoreos",
+			err:      nil,
+			expected: DistributionFedoraCoreOS,
+		},
 		{
 			rootfs:   "flatcar",
 			err:      nil,
@@ -0,0 +1,257 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package signature verifies the signatures of file and image assets against a set of public keys.
+//
+// Images are verified using the signatures that `cosign sign --key` stores alongside the image in its repository.
+// Files are verified using a detached signature of their SHA256 hash, stored next to the file with a ".sig" suffix,
+// either as written by `cosign sign-blob --key` or by `openssl dgst -sha256 -sign`.
+package signature
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/util/pkg/hashing"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+const (
+	// FileSignatureSuffix is appended to the location of a file to find its detached signature.
+	FileSignatureSuffix = ".sig"
+
+	// cosignSignatureAnnotation is the layer annotation holding the signature of a cosign signature payload.
+	cosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+	// cosignSignatureType is the type of a cosign signature payload.
+	cosignSignatureType = "cosign container image signature"
+)
+
+// Verifier verifies signatures made with any of a set of public keys.
+type Verifier struct {
+	publicKeys []crypto.PublicKey
+}
+
+// NewVerifier builds a Verifier for the PEM-encoded ECDSA or RSA public keys.
+func NewVerifier(publicKeys []string) (*Verifier, error) {
+	if len(publicKeys) == 0 {
+		return nil, fmt.Errorf("no public keys specified")
+	}
+
+	v := &Verifier{}
+	for i, publicKey := range publicKeys {
+		key, err := ParsePublicKey(publicKey)
+		if err != nil {
+			return nil, fmt.Errorf("public key %d: %w", i, err)
+		}
+		v.publicKeys = append(v.publicKeys, key)
+	}
+	return v, nil
+}
+
+// ParsePublicKey parses a PEM-encoded ECDSA or RSA public key.
+func ParsePublicKey(publicKey string) (crypto.PublicKey, error) {
+	block, _ := pem.Decode([]byte(publicKey))
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, fmt.Errorf("not a PEM-encoded PUBLIC KEY")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	switch key.(type) {
+	case *ecdsa.PublicKey, *rsa.PublicKey:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T", key)
+	}
+}
+
+// verifyDigest checks that sig is a signature of the SHA256 digest made with one of the public keys.
+func (v *Verifier) verifyDigest(digest []byte, sig []byte) error {
+	for _, publicKey := range v.publicKeys {
+		switch key := publicKey.(type) {
+		case *ecdsa.PublicKey:
+			if ecdsa.VerifyASN1(key, digest, sig) {
+				return nil
+			}
+		case *rsa.PublicKey:
+			if rsa.VerifyPKCS1v15(key, crypto.SHA256, digest, sig) == nil {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("signature was not made with any of the public keys")
+}
+
+// VerifyHash checks that sig is a detached signature, raw or base64-encoded, of a file with the hash.
+func (v *Verifier) VerifyHash(hash *hashing.Hash, sig []byte) error {
+	if hash == nil || hash.Algorithm != hashing.HashAlgorithmSHA256 {
+		return fmt.Errorf("a SHA256 hash is required to verify a signature")
+	}
+	return v.verifyDigest(hash.HashValue, decodeSignature(sig))
+}
+
+// decodeSignature returns the signature as written by cosign, which base64-encodes it, or by openssl, which does not.
+func decodeSignature(sig []byte) []byte {
+	if decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig))); err == nil {
+		return decoded
+	}
+	return sig
+}
+
+// VerifyFile reads the detached signature of a file, from the first of its locations that has one,
+// and checks that it is a signature of a file with the hash.
+func (v *Verifier) VerifyFile(vfsContext *vfs.VFSContext, urls []string, hash *hashing.Hash) error {
+	var errs []error
+	for _, u := range urls {
+		sig, err := vfsContext.ReadFile(u + FileSignatureSuffix)
+		if err != nil {
+			klog.Warningf("unable to read signature of %q: %v", u, err)
+			errs = append(errs, err)
+			continue
+		}
+		if err := v.VerifyHash(hash, sig); err != nil {
+			return fmt.Errorf("verifying signature of %q: %w", u, err)
+		}
+		return nil
+	}
+	return fmt.Errorf("unable to read signature of %q: %w", urls[0], errors.Join(errs...))
+}
+
+// SignatureTag returns the tag at which cosign stores the signatures of the image with the digest.
+func SignatureTag(repository name.Repository, digest string) name.Tag {
+	return repository.Tag(strings.Replace(digest, ":", "-", 1) + FileSignatureSuffix)
+}
+
+// VerifyImage checks that the image is signed with one of the public keys, and returns the verified digest.
+// The image should be used by that digest, as its tag may have been pushed again since.
+func (v *Verifier) VerifyImage(image string) (string, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return "", fmt.Errorf("parsing reference %q: %w", image, err)
+	}
+	options := []remote.Option{remote.WithAuthFromKeychain(authn.DefaultKeychain)}
+
+	desc, err := remote.Get(ref, options...)
+	if err != nil {
+		return "", fmt.Errorf("fetching %q: %w", image, err)
+	}
+	digest := desc.Digest.String()
+
+	sigRef := SignatureTag(ref.Context(), digest)
+	sigImage, err := remote.Image(sigRef, options...)
+	if err != nil {
+		return "", fmt.Errorf("fetching signatures of %q from %q: %w", image, sigRef, err)
+	}
+	manifest, err := sigImage.Manifest()
+	if err != nil {
+		return "", fmt.Errorf("reading signatures of %q: %w", image, err)
+	}
+	readBlob := func(h v1.Hash) ([]byte, error) {
+		layer, err := sigImage.LayerByDigest(h)
+		if err != nil {
+			return nil, err
+		}
+		rc, err := layer.Compressed()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return io.ReadAll(rc)
+	}
+
+	if err := v.verifyImageSignatures(manifest, readBlob, digest); err != nil {
+		return "", fmt.Errorf("verifying signatures of %q: %w", image, err)
+	}
+	return digest, nil
+}
+
+// simpleSigningPayload is the payload that cosign signs for an image.
+type simpleSigningPayload struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+}
+
+// verifyImageSignatures checks that one of the layers of a cosign signature manifest is a payload for the digest,
+// signed with one of the public keys.
+func (v *Verifier) verifyImageSignatures(manifest *v1.Manifest, readBlob func(v1.Hash) ([]byte, error), digest string) error {
+	var errs []error
+	for _, layer := range manifest.Layers {
+		sig, found := layer.Annotations[cosignSignatureAnnotation]
+		if !found {
+			continue
+		}
+		payload, err := readBlob(layer.Digest)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("reading payload %s: %w", layer.Digest, err))
+			continue
+		}
+		if err := v.verifyImagePayload(payload, sig, digest); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		return nil
+	}
+	if len(errs) == 0 {
+		return fmt.Errorf("no signatures found")
+	}
+	return errors.Join(errs...)
+}
+
+// verifyImagePayload checks that the payload is signed with one of the public keys, and refers to the digest.
+func (v *Verifier) verifyImagePayload(payload []byte, sig string, digest string) error {
+	decoded, err := base64.StdEncoding.DecodeString(sig)
+	if err != nil {
+		return fmt.Errorf("decoding signature: %w", err)
+	}
+	payloadDigest := sha256.Sum256(payload)
+	if err := v.verifyDigest(payloadDigest[:], decoded); err != nil {
+		return err
+	}
+
+	// Only trust the payload once its signature is verified
+	var p simpleSigningPayload
+	if err := json.NewDecoder(bytes.NewReader(payload)).Decode(&p); err != nil {
+		return fmt.Errorf("parsing signed payload: %w", err)
+	}
+	if p.Critical.Type != cosignSignatureType {
+		return fmt.Errorf("signed payload has unexpected type %q", p.Critical.Type)
+	}
+	if p.Critical.Image.DockerManifestDigest != digest {
+		return fmt.Errorf("signed payload is for digest %q, not %q", p.Critical.Image.DockerManifestDigest, digest)
+	}
+	return nil
+}
@@ -0,0 +1,243 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signature
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"k8s.io/kops/util/pkg/hashing"
+)
+
+func publicKeyPEM(t *testing.T, key crypto.PublicKey) string {
+	b, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		t.Fatalf("error marshaling public key: %v", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: b}))
+}
+
+func newECDSAKey(t *testing.T) *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("error generating key: %v", err)
+	}
+	return key
+}
+
+func newVerifier(t *testing.T, keys ...crypto.PublicKey) *Verifier {
+	var publicKeys []string
+	for _, key := range keys {
+		publicKeys = append(publicKeys, publicKeyPEM(t, key))
+	}
+	v, err := NewVerifier(publicKeys)
+	if err != nil {
+		t.Fatalf("error building verifier: %v", err)
+	}
+	return v
+}
+
+func TestNewVerifier(t *testing.T) {
+	if _, err := NewVerifier(nil); err == nil {
+		t.Errorf("expected an error without public keys")
+	}
+	if _, err := NewVerifier([]string{"not a key"}); err == nil {
+		t.Errorf("expected an error for a key that is not PEM-encoded")
+	}
+	newVerifier(t, &newECDSAKey(t).PublicKey)
+}
+
+func TestVerifyHash(t *testing.T) {
+	key := newECDSAKey(t)
+	other := newECDSAKey(t)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("error generating key: %v", err)
+	}
+
+	data := []byte("kubelet")
+	digest := sha256.Sum256(data)
+	hash := &hashing.Hash{Algorithm: hashing.HashAlgorithmSHA256, HashValue: digest[:]}
+
+	ecdsaSig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatalf("error signing: %v", err)
+	}
+	rsaSig, err := rsa.SignPKCS1v15(rand.Reader, rsaKey, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatalf("error signing: %v", err)
+	}
+
+	grid := []struct {
+		name     string
+		verifier *Verifier
+		hash     *hashing.Hash
+		sig      []byte
+		valid    bool
+	}{
+		{
+			name:     "cosign signature",
+			verifier: newVerifier(t, &key.PublicKey),
+			hash:     hash,
+			sig:      []byte(base64.StdEncoding.EncodeToString(ecdsaSig) + "\n"),
+			valid:    true,
+		},
+		{
+			name:     "raw signature",
+			verifier: newVerifier(t, &key.PublicKey),
+			hash:     hash,
+			sig:      ecdsaSig,
+			valid:    true,
+		},
+		{
+			name:     "rsa signature with any of the keys",
+			verifier: newVerifier(t, &key.PublicKey, &rsaKey.PublicKey),
+			hash:     hash,
+			sig:      rsaSig,
+			valid:    true,
+		},
+		{
+			name:     "other key",
+			verifier: newVerifier(t, &other.PublicKey),
+			hash:     hash,
+			sig:      ecdsaSig,
+		},
+		{
+			name:     "other file",
+			verifier: newVerifier(t, &key.PublicKey),
+			hash:     hashing.MustFromString("9b6ff15a1f8cd3a4ffb2d7d3bc1b0b7c4e4ee4b7b8a2bb1d8f1e1c4b9b6d2c1e"),
+			sig:      ecdsaSig,
+		},
+		{
+			name:     "sha1 hash",
+			verifier: newVerifier(t, &key.PublicKey),
+			hash:     hashing.MustFromString("da39a3ee5e6b4b0d3255bfef95601890afd80709"),
+			sig:      ecdsaSig,
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			err := g.verifier.VerifyHash(g.hash, g.sig)
+			if g.valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !g.valid && err == nil {
+				t.Errorf("expected signature to be rejected")
+			}
+		})
+	}
+}
+
+func TestSignatureTag(t *testing.T) {
+	repository, err := name.NewRepository("registry.example.com/kube-proxy")
+	if err != nil {
+		t.Fatalf("error parsing repository: %v", err)
+	}
+	tag := SignatureTag(repository, "sha256:abc123")
+	if expected := "registry.example.com/kube-proxy:sha256-abc123.sig"; tag.String() != expected {
+		t.Errorf("unexpected tag; expected %q, got %q", expected, tag.String())
+	}
+}
+
+func TestVerifyImageSignatures(t *testing.T) {
+	key := newECDSAKey(t)
+	v := newVerifier(t, &key.PublicKey)
+
+	digest := "sha256:" + fmt.Sprintf("%x", sha256.Sum256([]byte("manifest")))
+	signedManifest := func(payload string) (*v1.Manifest, map[v1.Hash][]byte) {
+		payloadDigest := sha256.Sum256([]byte(payload))
+		sig, err := ecdsa.SignASN1(rand.Reader, key, payloadDigest[:])
+		if err != nil {
+			t.Fatalf("error signing: %v", err)
+		}
+		h := v1.Hash{Algorithm: "sha256", Hex: fmt.Sprintf("%x", payloadDigest)}
+		manifest := &v1.Manifest{
+			Layers: []v1.Descriptor{
+				{
+					Digest:      h,
+					Annotations: map[string]string{cosignSignatureAnnotation: base64.StdEncoding.EncodeToString(sig)},
+				},
+			},
+		}
+		return manifest, map[v1.Hash][]byte{h: []byte(payload)}
+	}
+	payloadFor := func(digest string) string {
+		return `{"critical":{"identity":{"docker-reference":"registry.example.com/kube-proxy"},"image":{"docker-manifest-digest":"` + digest + `"},"type":"cosign container image signature"},"optional":null}`
+	}
+
+	grid := []struct {
+		name    string
+		payload string
+		valid   bool
+	}{
+		{
+			name:    "signed digest",
+			payload: payloadFor(digest),
+			valid:   true,
+		},
+		{
+			name:    "signed other digest",
+			payload: payloadFor("sha256:0000"),
+		},
+		{
+			name:    "signed other type",
+			payload: `{"critical":{"image":{"docker-manifest-digest":"` + digest + `"},"type":"attestation"}}`,
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			manifest, blobs := signedManifest(g.payload)
+			readBlob := func(h v1.Hash) ([]byte, error) {
+				return blobs[h], nil
+			}
+			err := v.verifyImageSignatures(manifest, readBlob, digest)
+			if g.valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !g.valid && err == nil {
+				t.Errorf("expected signatures to be rejected")
+			}
+		})
+	}
+
+	t.Run("tampered payload", func(t *testing.T) {
+		manifest, _ := signedManifest(payloadFor("sha256:0000"))
+		readBlob := func(h v1.Hash) ([]byte, error) {
+			return []byte(payloadFor(digest)), nil
+		}
+		if err := v.verifyImageSignatures(manifest, readBlob, digest); err == nil {
+			t.Errorf("expected signatures to be rejected")
+		}
+	})
+
+	t.Run("unsigned", func(t *testing.T) {
+		if err := v.verifyImageSignatures(&v1.Manifest{}, nil, digest); err == nil {
+			t.Errorf("expected an error without signatures")
+		}
+	})
+}
@@ -27,8 +27,10 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
 	"k8s.io/klog/v2"
 	"k8s.io/kops/util/pkg/hashing"
@@ -43,8 +45,9 @@ type AzureBlobPath struct {
 }
 
 var (
-	_ Path    = &AzureBlobPath{}
-	_ HasHash = &AzureBlobPath{}
+	_ Path            = &AzureBlobPath{}
+	_ HasHash         = &AzureBlobPath{}
+	_ ConditionalPath = &AzureBlobPath{}
 )
 
 // NewAzureBlobPath returns a new AzureBlobPath.
@@ -103,29 +106,39 @@ func (p *AzureBlobPath) Join(relativePath ...string) Path {
 
 // ReadFile returns the content of the blob.
 func (p *AzureBlobPath) ReadFile(ctx context.Context) ([]byte, error) {
+	b, _, err := p.ReadFileVersion(ctx)
+	return b, err
+}
+
+// ReadFileVersion returns the content of the blob and its ETag.
+func (p *AzureBlobPath) ReadFileVersion(ctx context.Context) ([]byte, string, error) {
 	klog.V(8).Infof("Reading file: %s - %s", p.container, p.key)
 
 	client, err := p.getClient(ctx)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	get, err := client.DownloadStream(ctx, p.container, p.key, nil)
 	if err != nil {
 		if bloberror.HasCode(err, bloberror.ContainerNotFound) || bloberror.HasCode(err, bloberror.BlobNotFound) {
-			return nil, os.ErrNotExist
+			return nil, "", os.ErrNotExist
 		}
-		return nil, err
+		return nil, "", err
 	}
 
 	b := &bytes.Buffer{}
 	retryReader := get.NewRetryReader(ctx, &azblob.RetryReaderOptions{})
 	_, err = b.ReadFrom(retryReader)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
-	return b.Bytes(), nil
+	var etag string
+	if get.ETag != nil {
+		etag = string(*get.ETag)
+	}
+	return b.Bytes(), etag, nil
 }
 
 // WriteTo writes the content of the blob to the writer.
@@ -149,6 +162,7 @@ func (p *AzureBlobPath) WriteTo(w io.Writer) (int64, error) {
 
 // createFileLockAzureBLob prevents concurrent creates on the same
 // file while maintaining atomicity of writes.
+// The upload is also conditional on the blob not existing, which Azure enforces across processes.
 var createFileLockAzureBlob sync.Mutex
 
 // CreateFile writes the file contents only if the file does not already exist.
@@ -166,13 +180,30 @@ func (p *AzureBlobPath) CreateFile(ctx context.Context, data io.ReadSeeker, acl
 	if !os.IsNotExist(err) {
 		return err
 	}
-	return p.WriteFile(ctx, data, acl)
+	return p.writeFile(ctx, data, true, "")
 }
 
 // WriteFile writes the blob to the reader.
 func (p *AzureBlobPath) WriteFile(ctx context.Context, data io.ReadSeeker, acl ACL) error {
 	klog.V(8).Infof("Writing file: %s - %s", p.container, p.key)
 
+	return p.writeFile(ctx, data, false, "")
+}
+
+// ReplaceFile writes the blob only if it still has the specified ETag.
+func (p *AzureBlobPath) ReplaceFile(ctx context.Context, data io.ReadSeeker, acl ACL, version string) error {
+	klog.V(8).Infof("Replacing file: %s - %s", p.container, p.key)
+
+	if version == "" {
+		return ErrVersionMismatch
+	}
+	return p.writeFile(ctx, data, false, version)
+}
+
+// writeFile uploads the blob; if createOnly is set, the upload is conditional on the blob not existing,
+// and os.ErrExist is returned if it does. If ifMatch is set, the upload is conditional on the blob having
+// that ETag, and ErrVersionMismatch is returned if it does not.
+func (p *AzureBlobPath) writeFile(ctx context.Context, data io.ReadSeeker, createOnly bool, ifMatch string) error {
 	client, err := p.getClient(ctx)
 	if err != nil {
 		return err
@@ -183,7 +214,32 @@ func (p *AzureBlobPath) WriteFile(ctx context.Context, data io.ReadSeeker, acl A
 		return err
 	}
 
-	_, err = client.UploadStream(ctx, p.container, p.key, data, nil)
+	var opts *azblob.UploadStreamOptions
+	if createOnly {
+		opts = &azblob.UploadStreamOptions{
+			AccessConditions: &blob.AccessConditions{
+				ModifiedAccessConditions: &blob.ModifiedAccessConditions{
+					IfNoneMatch: to.Ptr(azcore.ETagAny),
+				},
+			},
+		}
+	} else if ifMatch != "" {
+		opts = &azblob.UploadStreamOptions{
+			AccessConditions: &blob.AccessConditions{
+				ModifiedAccessConditions: &blob.ModifiedAccessConditions{
+					IfMatch: to.Ptr(azcore.ETag(ifMatch)),
+				},
+			},
+		}
+	}
+
+	_, err = client.UploadStream(ctx, p.container, p.key, data, opts)
+	if createOnly && bloberror.HasCode(err, bloberror.BlobAlreadyExists, bloberror.ConditionNotMet) {
+		return os.ErrExist
+	}
+	if ifMatch != "" && bloberror.HasCode(err, bloberror.ConditionNotMet, bloberror.BlobNotFound) {
+		return ErrVersionMismatch
+	}
 	return err
 }
 
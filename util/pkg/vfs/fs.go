@@ -23,7 +23,7 @@ import (
 	"io"
 	"os"
 	"path"
-	"sync"
+	"strings"
 	"syscall"
 
 	"k8s.io/klog/v2"
@@ -31,13 +31,26 @@ import (
 	"k8s.io/kops/util/pkg/hashing"
 )
 
+const (
+	// fsLockFileName is the name of the file that is locked while writing to a directory
+	fsLockFileName = ".kops.lock"
+	// fsTempFilePrefix is the prefix of the temporary files that are written before being moved into place
+	fsTempFilePrefix = ".kops.tmp-"
+)
+
+// isFSInternalFile returns true if the file is used to implement atomic writes, and should not be listed.
+func isFSInternalFile(name string) bool {
+	return name == fsLockFileName || strings.HasPrefix(name, fsTempFilePrefix)
+}
+
 type FSPath struct {
 	location string
 }
 
 var (
-	_ Path    = &FSPath{}
-	_ HasHash = &FSPath{}
+	_ Path            = &FSPath{}
+	_ HasHash         = &FSPath{}
+	_ ConditionalPath = &FSPath{}
 )
 
 func NewFSPath(location string) *FSPath {
@@ -52,13 +65,74 @@ func (p *FSPath) Join(relativePath ...string) Path {
 }
 
 func (p *FSPath) WriteFile(ctx context.Context, data io.ReadSeeker, acl ACL) error {
+	return p.writeFile(data, false, "")
+}
+
+// CreateFile implements Path::CreateFile.
+// The file is created atomically, even if other processes are writing to the same directory.
+func (p *FSPath) CreateFile(ctx context.Context, data io.ReadSeeker, acl ACL) error {
+	return p.writeFile(data, true, "")
+}
+
+// ReadFileVersion implements ConditionalPath::ReadFileVersion; the version is the hash of the contents.
+func (p *FSPath) ReadFileVersion(ctx context.Context) ([]byte, string, error) {
+	data, err := p.ReadFile(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, contentVersion(data), nil
+}
+
+// ReplaceFile implements ConditionalPath::ReplaceFile.
+// The contents are compared while holding the lock that serializes writers to the directory.
+func (p *FSPath) ReplaceFile(ctx context.Context, data io.ReadSeeker, acl ACL, version string) error {
+	if version == "" {
+		return ErrVersionMismatch
+	}
+	return p.writeFile(data, false, version)
+}
+
+// writeFile writes the file to a temporary file in the same directory and then moves it into place,
+// so that readers never see a partially written file. Writers to the same directory are serialized
+// with a file lock, which is shared with other kops processes.
+// If ifVersion is set, the file is only written if its current contents have that version.
+func (p *FSPath) writeFile(data io.ReadSeeker, failIfExists bool, ifVersion string) error {
 	dir := path.Dir(p.location)
 	err := os.MkdirAll(dir, 0o755)
 	if err != nil {
 		return fmt.Errorf("error creating directories %q: %v", dir, err)
 	}
 
-	f, err := os.CreateTemp(dir, "tmp")
+	unlock, err := lockDir(dir)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if failIfExists {
+		_, err := os.Stat(p.location)
+		if err == nil {
+			return os.ErrExist
+		}
+		if !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	if ifVersion != "" {
+		current, err := os.ReadFile(p.location)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return ErrVersionMismatch
+			}
+			return err
+		}
+		if contentVersion(current) != ifVersion {
+			return ErrVersionMismatch
+		}
+	}
+
+	f, err := os.CreateTemp(dir, fsTempFilePrefix)
 	if err != nil {
 		return fmt.Errorf("error creating temp file in %q: %v", dir, err)
 	}
@@ -68,6 +142,10 @@ func (p *FSPath) WriteFile(ctx context.Context, data io.ReadSeeker, acl ACL) err
 
 	_, err = io.Copy(f, data)
 
+	if err == nil {
+		err = f.Sync()
+	}
+
 	if closeErr := f.Close(); err == nil {
 		err = closeErr
 	}
@@ -80,6 +158,7 @@ func (p *FSPath) WriteFile(ctx context.Context, data io.ReadSeeker, acl ACL) err
 	}
 
 	if err == nil {
+		syncDir(dir)
 		return nil
 	}
 
@@ -91,29 +170,6 @@ func (p *FSPath) WriteFile(ctx context.Context, data io.ReadSeeker, acl ACL) err
 	return err
 }
 
-// To prevent concurrent creates on the same file while maintaining atomicity of writes,
-// we take a process-wide lock during the operation.
-// Not a great approach, but fine for a single process (with low concurrency)
-// TODO: should we take a file lock or equivalent here?  Can we use RENAME_NOREPLACE ?
-var createFileLock sync.Mutex
-
-func (p *FSPath) CreateFile(ctx context.Context, data io.ReadSeeker, acl ACL) error {
-	createFileLock.Lock()
-	defer createFileLock.Unlock()
-
-	// Check if exists
-	_, err := os.Stat(p.location)
-	if err == nil {
-		return os.ErrExist
-	}
-
-	if !os.IsNotExist(err) {
-		return err
-	}
-
-	return p.WriteFile(ctx, data, acl)
-}
-
 // ReadFile implements Path::ReadFile
 func (p *FSPath) ReadFile(ctx context.Context) ([]byte, error) {
 	file, err := os.ReadFile(p.location)
@@ -144,15 +200,23 @@ func (p *FSPath) ReadDir() ([]Path, error) {
 	}
 	var paths []Path
 	for _, f := range files {
+		if isFSInternalFile(f.Name()) {
+			continue
+		}
 		paths = append(paths, NewFSPath(path.Join(p.location, f.Name())))
 	}
 	return paths, nil
 }
 
+// ReadTree implements Path::ReadTree.
+// As with the object store backends, listing a directory that does not exist returns no files.
 func (p *FSPath) ReadTree(ctx context.Context) ([]Path, error) {
 	var paths []Path
 	err := readTree(p.location, &paths)
 	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
 		return nil, err
 	}
 	return paths, nil
@@ -166,6 +230,9 @@ func readTree(base string, dest *[]Path) error {
 		return err
 	}
 	for _, f := range files {
+		if isFSInternalFile(f.Name()) {
+			continue
+		}
 		p := path.Join(base, f.Name())
 		if f.IsDir() {
 			err = readTree(p, dest)
@@ -208,11 +275,20 @@ func (p *FSPath) RemoveAll(ctx context.Context) error {
 		}
 	}
 
-	return nil
+	// Object stores do not have directories, so remove the ones we emptied
+	// to avoid them being listed by ReadDir.
+	return removeEmptyDirs(p.location)
 }
 
+// RemoveAllVersions implements Path::RemoveAllVersions.
+// The local filesystem does not keep versions, so this removes the file,
+// returning os.ErrNotExist if it did not exist.
 func (p *FSPath) RemoveAllVersions(ctx context.Context) error {
-	return p.Remove(ctx)
+	err := os.Remove(p.location)
+	if os.IsNotExist(err) {
+		return os.ErrNotExist
+	}
+	return err
 }
 
 func (p *FSPath) PreferredHash() (*hashing.Hash, error) {
@@ -224,3 +300,103 @@ func (p *FSPath) Hash(a hashing.HashAlgorithm) (*hashing.Hash, error) {
 
 	return a.HashFile(p.location)
 }
+
+// lockDir takes an exclusive lock on a directory, which is shared with other processes.
+// It returns a function that releases the lock and removes the lock file.
+func lockDir(dir string) (func(), error) {
+	lockPath := path.Join(dir, fsLockFileName)
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("error opening lock file %q: %w", lockPath, err)
+		}
+		if err := lockFile(f); err != nil {
+			try.CloseFile(f)
+			return nil, fmt.Errorf("error locking %q: %w", lockPath, err)
+		}
+
+		// The lock file is removed when the lock is released, so we may have locked a file that
+		// was removed while we waited, and another process may be holding a lock on its replacement.
+		held, err := f.Stat()
+		if err != nil {
+			_ = unlockFile(f)
+			try.CloseFile(f)
+			return nil, fmt.Errorf("error checking lock file %q: %w", lockPath, err)
+		}
+		current, err := os.Stat(lockPath)
+		if err != nil || !os.SameFile(held, current) {
+			_ = unlockFile(f)
+			try.CloseFile(f)
+			if err != nil && !os.IsNotExist(err) {
+				return nil, fmt.Errorf("error checking lock file %q: %w", lockPath, err)
+			}
+			continue
+		}
+
+		return func() {
+			// Remove the file while it is still locked, so no other process locks it after we release it.
+			// Not all platforms allow removing an open file, in which case it is left in place.
+			if err := os.Remove(lockPath); err != nil && !os.IsNotExist(err) {
+				klog.V(2).Infof("unable to remove lock file %q: %v", lockPath, err)
+			}
+			if err := unlockFile(f); err != nil {
+				klog.Warningf("error unlocking %q: %v", lockPath, err)
+			}
+			try.CloseFile(f)
+		}, nil
+	}
+}
+
+// syncDir flushes a directory, so that a file renamed into it survives a crash.
+// Not all platforms support this, so errors are only logged.
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		klog.V(2).Infof("unable to open directory %q to sync: %v", dir, err)
+		return
+	}
+	defer try.CloseFile(d)
+	if err := d.Sync(); err != nil {
+		klog.V(2).Infof("unable to sync directory %q: %v", dir, err)
+	}
+}
+
+// removeEmptyDirs removes dir and the directories below it that contain no files, other than lock files left behind
+// on platforms that cannot remove them.
+func removeEmptyDirs(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	empty := true
+	for _, entry := range entries {
+		p := path.Join(dir, entry.Name())
+		switch {
+		case entry.IsDir():
+			if err := removeEmptyDirs(p); err != nil {
+				return err
+			}
+			if _, err := os.Stat(p); err == nil {
+				empty = false
+			}
+		case entry.Name() == fsLockFileName:
+		default:
+			empty = false
+		}
+	}
+	if !empty {
+		return nil
+	}
+
+	if err := os.Remove(path.Join(dir, fsLockFileName)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error removing lock file in %q: %w", dir, err)
+	}
+	if err := os.Remove(dir); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error removing directory %q: %w", dir, err)
+	}
+	return nil
+}
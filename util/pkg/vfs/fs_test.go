@@ -18,8 +18,14 @@ package vfs
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
 	"os"
 	"path"
+	"reflect"
+	"runtime"
+	"sort"
+	"sync"
 	"testing"
 
 	"k8s.io/kops/pkg/testutils/testcontext"
@@ -100,3 +106,139 @@ func TestWriteTo(t *testing.T) {
 		}
 	}
 }
+
+func TestCreateFileConcurrent(t *testing.T) {
+	ctx := testcontext.ForTest(t)
+	fspath := NewFSPath(path.Join(t.TempDir(), "SubDir", "test1"))
+
+	const writers = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- fspath.CreateFile(ctx, bytes.NewReader([]byte(fmt.Sprintf("writer %d", i))), nil)
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	created := 0
+	for err := range errs {
+		if err == nil {
+			created++
+		} else if err != os.ErrExist {
+			t.Errorf("Expected to get os.ErrExist, got: %v", err)
+		}
+	}
+	if created != 1 {
+		t.Errorf("Expected exactly one CreateFile to succeed, got %d", created)
+	}
+
+	// Windows does not allow removing the lock file while it is open
+	if runtime.GOOS != "windows" {
+		lockPath := path.Join(path.Dir(fspath.Path()), fsLockFileName)
+		if _, err := os.Stat(lockPath); !os.IsNotExist(err) {
+			t.Errorf("Expected lock file %q to be removed, got: %v", lockPath, err)
+		}
+	}
+}
+
+func TestReplaceFile(t *testing.T) {
+	ctx := testcontext.ForTest(t)
+
+	for _, p := range []ConditionalPath{
+		NewFSPath(path.Join(t.TempDir(), "SubDir", "test1")),
+		NewMemFSPath(NewMemFSContext(), "SubDir/test1"),
+	} {
+		t.Run(fmt.Sprintf("%T", p), func(t *testing.T) {
+			if err := p.ReplaceFile(ctx, bytes.NewReader([]byte("data")), nil, "missing"); !errors.Is(err, ErrVersionMismatch) {
+				t.Errorf("Expected ErrVersionMismatch replacing a missing file, got: %v", err)
+			}
+
+			if err := p.CreateFile(ctx, bytes.NewReader([]byte("version 1")), nil); err != nil {
+				t.Fatalf("Error creating file: %v", err)
+			}
+			_, version1, err := p.ReadFileVersion(ctx)
+			if err != nil {
+				t.Fatalf("Error reading file: %v", err)
+			}
+
+			if err := p.ReplaceFile(ctx, bytes.NewReader([]byte("version 2")), nil, version1); err != nil {
+				t.Fatalf("Error replacing file: %v", err)
+			}
+			data, version2, err := p.ReadFileVersion(ctx)
+			if err != nil {
+				t.Fatalf("Error reading file: %v", err)
+			}
+			if string(data) != "version 2" {
+				t.Errorf("Expected file content %q, got %q", "version 2", data)
+			}
+			if version2 == version1 {
+				t.Errorf("Expected version to change when the file is replaced")
+			}
+
+			// A writer that read the first version must not overwrite the second
+			if err := p.ReplaceFile(ctx, bytes.NewReader([]byte("version 3")), nil, version1); !errors.Is(err, ErrVersionMismatch) {
+				t.Errorf("Expected ErrVersionMismatch, got: %v", err)
+			}
+			data, err = p.ReadFile(ctx)
+			if err != nil {
+				t.Fatalf("Error reading file: %v", err)
+			}
+			if string(data) != "version 2" {
+				t.Errorf("Expected file content %q, got %q", "version 2", data)
+			}
+		})
+	}
+}
+
+func TestReadTreeAndRemoveAll(t *testing.T) {
+	ctx := testcontext.ForTest(t)
+	base := NewFSPath(t.TempDir())
+
+	for _, p := range []string{"cluster/config", "cluster/pki/ca.crt", "cluster/instancegroup/nodes"} {
+		if err := base.Join(p).WriteFile(ctx, bytes.NewReader([]byte(p)), nil); err != nil {
+			t.Fatalf("Error writing file %s, error: %v", p, err)
+		}
+	}
+
+	tree, err := base.Join("cluster").ReadTree(ctx)
+	if err != nil {
+		t.Fatalf("Error reading tree: %v", err)
+	}
+	var files []string
+	for _, p := range tree {
+		rel, err := RelativePath(base, p)
+		if err != nil {
+			t.Fatalf("Error getting relative path: %v", err)
+		}
+		files = append(files, rel)
+	}
+	sort.Strings(files)
+	expected := []string{"cluster/config", "cluster/instancegroup/nodes", "cluster/pki/ca.crt"}
+	if !reflect.DeepEqual(files, expected) {
+		t.Errorf("Expected ReadTree to return %v, got %v", expected, files)
+	}
+
+	if err := base.Join("cluster").RemoveAll(ctx); err != nil {
+		t.Fatalf("Error removing tree: %v", err)
+	}
+	dirs, err := base.ReadDir()
+	if err != nil {
+		t.Fatalf("Error reading directory: %v", err)
+	}
+	if len(dirs) != 0 {
+		t.Errorf("Expected no directories after RemoveAll, got %v", dirs)
+	}
+
+	tree, err = base.Join("cluster").ReadTree(ctx)
+	if err != nil || len(tree) != 0 {
+		t.Errorf("Expected ReadTree of a missing directory to return no files, got %v, %v", tree, err)
+	}
+
+	if err := base.Join("cluster", "config").RemoveAllVersions(ctx); err != os.ErrNotExist {
+		t.Errorf("Expected RemoveAllVersions of a missing file to return os.ErrNotExist, got: %v", err)
+	}
+}
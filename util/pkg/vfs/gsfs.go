@@ -25,6 +25,7 @@ import (
 	"net/http"
 	"os"
 	"path"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -49,9 +50,10 @@ type GSPath struct {
 }
 
 var (
-	_ Path          = &GSPath{}
-	_ TerraformPath = &GSPath{}
-	_ HasHash       = &GSPath{}
+	_ Path            = &GSPath{}
+	_ TerraformPath   = &GSPath{}
+	_ HasHash         = &GSPath{}
+	_ ConditionalPath = &GSPath{}
 )
 
 // gcsReadBackoff is the backoff strategy for GCS read retries
@@ -173,6 +175,13 @@ func (p *GSPath) Join(relativePath ...string) Path {
 }
 
 func (p *GSPath) WriteFile(ctx context.Context, data io.ReadSeeker, acl ACL) error {
+	return p.writeFile(ctx, data, acl, false, 0)
+}
+
+// writeFile writes the object; if createOnly is set, the write is conditional on the object not existing,
+// and os.ErrExist is returned if it does. If ifGeneration is set, the write is conditional on the object
+// having that generation, and ErrVersionMismatch is returned if it does not.
+func (p *GSPath) writeFile(ctx context.Context, data io.ReadSeeker, acl ACL, createOnly bool, ifGeneration int64) error {
 	md5Hash, err := hashing.HashAlgorithmMD5.Hash(data)
 	if err != nil {
 		return err
@@ -204,8 +213,23 @@ func (p *GSPath) WriteFile(ctx context.Context, data io.ReadSeeker, acl ACL) err
 			return false, err
 		}
 
-		_, err = client.Objects.Insert(p.bucket, obj).Context(ctx).Media(data).Do()
+		call := client.Objects.Insert(p.bucket, obj).Context(ctx).Media(data)
+		if createOnly {
+			// Generation 0 matches only if there is no live version of the object
+			call = call.IfGenerationMatch(0)
+		} else if ifGeneration != 0 {
+			call = call.IfGenerationMatch(ifGeneration)
+		}
+		_, err = call.Do()
 		if err != nil {
+			if createOnly && isGCSPreconditionFailed(err) {
+				// Not recoverable
+				return true, os.ErrExist
+			}
+			if ifGeneration != 0 && (isGCSPreconditionFailed(err) || isGCSNotFound(err)) {
+				// Not recoverable
+				return true, ErrVersionMismatch
+			}
 			return false, fmt.Errorf("error writing %s: %v", p, err)
 		}
 
@@ -223,8 +247,7 @@ func (p *GSPath) WriteFile(ctx context.Context, data io.ReadSeeker, acl ACL) err
 
 // To prevent concurrent creates on the same file while maintaining atomicity of writes,
 // we take a process-wide lock during the operation.
-// Not a great approach, but fine for a single process (with low concurrency)
-// TODO: should we enable versioning?
+// The write is also conditional on the object not existing, which GCS enforces across processes.
 var createFileLockGCS sync.Mutex
 
 func (p *GSPath) CreateFile(ctx context.Context, data io.ReadSeeker, acl ACL) error {
@@ -241,7 +264,43 @@ func (p *GSPath) CreateFile(ctx context.Context, data io.ReadSeeker, acl ACL) er
 		return err
 	}
 
-	return p.WriteFile(ctx, data, acl)
+	return p.writeFile(ctx, data, acl, true, 0)
+}
+
+// ReadFileVersion implements ConditionalPath::ReadFileVersion; the version is the generation of the object.
+func (p *GSPath) ReadFileVersion(ctx context.Context) ([]byte, string, error) {
+	var b bytes.Buffer
+	var generation string
+	done, err := RetryWithBackoff(gcsReadBackoff, func() (bool, error) {
+		b.Reset()
+		var err error
+		_, generation, err = p.readObject(ctx, &b)
+		if err != nil {
+			if os.IsNotExist(err) {
+				// Not recoverable
+				return true, err
+			}
+			return false, err
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, "", err
+	} else if done {
+		return b.Bytes(), generation, nil
+	} else {
+		// Shouldn't happen - we always return a non-nil error with false
+		return nil, "", wait.ErrWaitTimeout
+	}
+}
+
+// ReplaceFile implements ConditionalPath::ReplaceFile.
+func (p *GSPath) ReplaceFile(ctx context.Context, data io.ReadSeeker, acl ACL, version string) error {
+	generation, err := strconv.ParseInt(version, 10, 64)
+	if err != nil || generation == 0 {
+		return ErrVersionMismatch
+	}
+	return p.writeFile(ctx, data, acl, false, generation)
 }
 
 // ReadFile implements Path::ReadFile
@@ -274,26 +333,33 @@ func (p *GSPath) ReadFile(ctx context.Context) ([]byte, error) {
 func (p *GSPath) WriteTo(out io.Writer) (int64, error) {
 	ctx := context.TODO()
 
+	n, _, err := p.readObject(ctx, out)
+	return n, err
+}
+
+// readObject copies the object to out, and returns the number of bytes copied and the generation of the object.
+func (p *GSPath) readObject(ctx context.Context, out io.Writer) (int64, string, error) {
 	klog.V(4).Infof("Reading file %q", p)
 
 	client, err := p.getStorageClient(ctx)
 	if err != nil {
-		return 0, err
+		return 0, "", err
 	}
 
 	response, err := client.Objects.Get(p.bucket, p.key).Context(ctx).Download()
 	if err != nil {
 		if isGCSNotFound(err) {
-			return 0, os.ErrNotExist
+			return 0, "", os.ErrNotExist
 		}
-		return 0, fmt.Errorf("error reading %s: %v", p, err)
+		return 0, "", fmt.Errorf("error reading %s: %v", p, err)
 	}
 	if response == nil {
-		return 0, fmt.Errorf("no response returned from reading %s", p)
+		return 0, "", fmt.Errorf("no response returned from reading %s", p)
 	}
 	defer response.Body.Close()
 
-	return io.Copy(out, response.Body)
+	n, err := io.Copy(out, response.Body)
+	return n, response.Header.Get("X-Goog-Generation"), err
 }
 
 // ReadDir implements Path::ReadDir
@@ -525,6 +591,11 @@ func isGCSNotFound(err error) bool {
 	return ok && ae.Code == http.StatusNotFound
 }
 
+func isGCSPreconditionFailed(err error) bool {
+	ae, ok := err.(*googleapi.Error)
+	return ok && ae.Code == http.StatusPreconditionFailed
+}
+
 func (p *GSPath) getStorageClient(ctx context.Context) (*storage.Service, error) {
 	return p.vfsContext.getGCSClient(ctx)
 }
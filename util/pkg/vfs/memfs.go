@@ -39,8 +39,9 @@ type MemFSPath struct {
 }
 
 var (
-	_ Path          = &MemFSPath{}
-	_ TerraformPath = &MemFSPath{}
+	_ Path            = &MemFSPath{}
+	_ TerraformPath   = &MemFSPath{}
+	_ ConditionalPath = &MemFSPath{}
 )
 
 type MemFSContext struct {
@@ -124,6 +125,35 @@ func (p *MemFSPath) CreateFile(ctx context.Context, data io.ReadSeeker, acl ACL)
 	return p.WriteFile(ctx, data, acl)
 }
 
+// ReadFileVersion implements ConditionalPath::ReadFileVersion; the version is the hash of the contents.
+func (p *MemFSPath) ReadFileVersion(ctx context.Context) ([]byte, string, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.contents == nil {
+		return nil, "", os.ErrNotExist
+	}
+	return p.contents, contentVersion(p.contents), nil
+}
+
+// ReplaceFile implements ConditionalPath::ReplaceFile.
+func (p *MemFSPath) ReplaceFile(ctx context.Context, r io.ReadSeeker, acl ACL, version string) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("error reading data: %v", err)
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.contents == nil || contentVersion(p.contents) != version {
+		return ErrVersionMismatch
+	}
+	p.contents = data
+	p.acl = acl
+	return nil
+}
+
 // ReadFile implements Path::ReadFile
 func (p *MemFSPath) ReadFile(ctx context.Context) ([]byte, error) {
 	if p.contents == nil {
@@ -54,9 +54,10 @@ type S3Path struct {
 }
 
 var (
-	_ Path          = &S3Path{}
-	_ TerraformPath = &S3Path{}
-	_ HasHash       = &S3Path{}
+	_ Path            = &S3Path{}
+	_ TerraformPath   = &S3Path{}
+	_ HasHash         = &S3Path{}
+	_ ConditionalPath = &S3Path{}
 )
 
 // S3Acl is an ACL implementation for objects on S3
@@ -315,17 +316,42 @@ func (p *S3Path) WriteFile(ctx context.Context, data io.ReadSeeker, aclObj ACL)
 	ctx, span := tracer.Start(ctx, "S3Path::WriteFile", trace.WithAttributes(attribute.String("path", p.String())))
 	defer span.End()
 
+	return p.writeFile(ctx, data, aclObj, false, "")
+}
+
+// conditionalWritesUnsupported records the buckets of S3-compatible stores that reject conditional writes,
+// which are then written unconditionally.
+var conditionalWritesUnsupported sync.Map
+
+// writeFile writes the object; if createOnly is set, the write is conditional on the object not existing,
+// and os.ErrExist is returned if it does. If ifMatch is set, the write is conditional on the object having
+// that ETag, and ErrVersionMismatch is returned if it does not.
+// If the store rejects conditional writes, the object is written unconditionally.
+func (p *S3Path) writeFile(ctx context.Context, data io.ReadSeeker, aclObj ACL, createOnly bool, ifMatch string) error {
 	client, err := p.client(ctx)
 	if err != nil {
 		return err
 	}
 
+	conditional := createOnly || ifMatch != ""
+	if _, found := conditionalWritesUnsupported.Load(p.bucket); found && conditional {
+		createOnly = false
+		ifMatch = ""
+		conditional = false
+	}
+
 	klog.V(4).Infof("Writing file %q", p)
 
 	request := &s3.PutObjectInput{}
 	request.Body = data
 	request.Bucket = aws.String(p.bucket)
 	request.Key = aws.String(p.key)
+	if createOnly {
+		request.IfNoneMatch = aws.String("*")
+	}
+	if ifMatch != "" {
+		request.IfMatch = aws.String(ifMatch)
+	}
 
 	var sseLog string
 	request.ServerSideEncryption, sseLog, _ = p.getServerSideEncryption(ctx)
@@ -344,6 +370,20 @@ func (p *S3Path) WriteFile(ctx context.Context, data io.ReadSeeker, aclObj ACL)
 
 	_, err = client.PutObject(ctx, request)
 	if err != nil {
+		if conditional && isS3ConditionalWriteUnsupported(err) {
+			klog.Warningf("S3 bucket %q does not support conditional writes, writing without them: %v", p.bucket, err)
+			conditionalWritesUnsupported.Store(p.bucket, true)
+			if _, err := data.Seek(0, io.SeekStart); err != nil {
+				return fmt.Errorf("error writing %s: %w", p, err)
+			}
+			return p.writeFile(ctx, data, aclObj, false, "")
+		}
+		if createOnly && isS3PreconditionFailed(err) {
+			return os.ErrExist
+		}
+		if ifMatch != "" && (isS3PreconditionFailed(err) || AWSErrorCode(err) == "NoSuchKey") {
+			return ErrVersionMismatch
+		}
 		if len(request.ACL) > 0 {
 			return fmt.Errorf("error writing %s (with ACL=%q): %v", p, request.ACL, err)
 		}
@@ -355,8 +395,8 @@ func (p *S3Path) WriteFile(ctx context.Context, data io.ReadSeeker, aclObj ACL)
 
 // To prevent concurrent creates on the same file while maintaining atomicity of writes,
 // we take a process-wide lock during the operation.
-// Not a great approach, but fine for a single process (with low concurrency)
-// TODO: should we enable versioning?
+// The write is also conditional on the object not existing, which S3 enforces across processes;
+// S3-compatible stores that ignore or reject conditional writes only get the process-wide guarantee.
 var createFileLockS3 sync.Mutex
 
 func (p *S3Path) CreateFile(ctx context.Context, data io.ReadSeeker, acl ACL) error {
@@ -373,7 +413,32 @@ func (p *S3Path) CreateFile(ctx context.Context, data io.ReadSeeker, acl ACL) er
 		return err
 	}
 
-	return p.WriteFile(ctx, data, acl)
+	return p.writeFile(ctx, data, acl, true, "")
+}
+
+// ReadFileVersion implements ConditionalPath::ReadFileVersion; the version is the ETag of the object.
+func (p *S3Path) ReadFileVersion(ctx context.Context) ([]byte, string, error) {
+	ctx, span := tracer.Start(ctx, "S3Path::ReadFileVersion", trace.WithAttributes(attribute.String("path", p.String())))
+	defer span.End()
+
+	var b bytes.Buffer
+	_, etag, err := p.readObject(ctx, &b)
+	if err != nil {
+		return nil, "", err
+	}
+	return b.Bytes(), etag, nil
+}
+
+// ReplaceFile implements ConditionalPath::ReplaceFile.
+// On S3-compatible stores that ignore or reject conditional writes, the file is replaced unconditionally.
+func (p *S3Path) ReplaceFile(ctx context.Context, data io.ReadSeeker, acl ACL, version string) error {
+	ctx, span := tracer.Start(ctx, "S3Path::ReplaceFile", trace.WithAttributes(attribute.String("path", p.String())))
+	defer span.End()
+
+	if version == "" {
+		return ErrVersionMismatch
+	}
+	return p.writeFile(ctx, data, acl, false, version)
 }
 
 // ReadFile implements Path::ReadFile
@@ -397,9 +462,15 @@ func (p *S3Path) WriteTo(out io.Writer) (int64, error) {
 
 // WriteToWithContext implements io.WriterTo, but adds a context
 func (p *S3Path) WriteToWithContext(ctx context.Context, out io.Writer) (int64, error) {
+	n, _, err := p.readObject(ctx, out)
+	return n, err
+}
+
+// readObject copies the object to out, and returns the number of bytes copied and the ETag of the object.
+func (p *S3Path) readObject(ctx context.Context, out io.Writer) (int64, string, error) {
 	client, err := p.client(ctx)
 	if err != nil {
-		return 0, err
+		return 0, "", err
 	}
 
 	klog.V(4).Infof("Reading file %q", p)
@@ -411,17 +482,17 @@ func (p *S3Path) WriteToWithContext(ctx context.Context, out io.Writer) (int64,
 	response, err := client.GetObject(ctx, request)
 	if err != nil {
 		if AWSErrorCode(err) == "NoSuchKey" {
-			return 0, os.ErrNotExist
+			return 0, "", os.ErrNotExist
 		}
-		return 0, fmt.Errorf("error fetching %s: %v", p, err)
+		return 0, "", fmt.Errorf("error fetching %s: %v", p, err)
 	}
 	defer response.Body.Close()
 
 	n, err := io.Copy(out, response.Body)
 	if err != nil {
-		return n, fmt.Errorf("error reading %s: %v", p, err)
+		return n, "", fmt.Errorf("error reading %s: %v", p, err)
 	}
-	return n, nil
+	return n, aws.ToString(response.ETag), nil
 }
 
 func (p *S3Path) ReadDir() ([]Path, error) {
@@ -781,6 +852,20 @@ func (p *S3Path) RenderTerraform(w *terraformWriter.TerraformWriter, name string
 
 }
 
+// isS3PreconditionFailed returns true if the error is from a conditional request whose condition was not met.
+// S3 returns ConditionalRequestConflict if a conflicting write is in progress.
+func isS3PreconditionFailed(err error) bool {
+	code := AWSErrorCode(err)
+	return code == "PreconditionFailed" || code == "ConditionalRequestConflict"
+}
+
+// isS3ConditionalWriteUnsupported returns true if the error is how S3-compatible stores reject
+// the If-None-Match and If-Match headers of conditional writes.
+func isS3ConditionalWriteUnsupported(err error) bool {
+	code := AWSErrorCode(err)
+	return code == "NotImplemented" || code == "NotSupported"
+}
+
 // AWSErrorCode returns the aws error code, if it is an smity.APIError, otherwise ""
 func AWSErrorCode(err error) string {
 	var apiErr smithy.APIError
@@ -16,7 +16,18 @@ limitations under the License.
 
 package vfs
 
-import "testing"
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
 
 func Test_S3Path_Parse(t *testing.T) {
 	grid := []struct {
@@ -138,3 +149,87 @@ func TestGetHTTPsUrl(t *testing.T) {
 		})
 	}
 }
+
+// TestS3PathConditionalWritesUnsupported checks that writes fall back to unconditional writes,
+// against an S3-compatible store that rejects the If-None-Match and If-Match headers.
+func TestS3PathConditionalWritesUnsupported(t *testing.T) {
+	var mutex sync.Mutex
+	objects := make(map[string][]byte)
+	var conditionalRequests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		switch r.Method {
+		case http.MethodGet:
+			data, found := objects[r.URL.Path]
+			if !found {
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, `<Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`)
+				return
+			}
+			w.Header().Set("ETag", `"1"`)
+			w.Write(data)
+		case http.MethodPut:
+			if r.Header.Get("If-None-Match") != "" || r.Header.Get("If-Match") != "" {
+				conditionalRequests++
+				w.WriteHeader(http.StatusNotImplemented)
+				fmt.Fprint(w, `<Error><Code>NotImplemented</Code><Message>A header you provided implies functionality that is not implemented.</Message></Error>`)
+				return
+			}
+			data, err := io.ReadAll(r.Body)
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			objects[r.URL.Path] = data
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("S3_ENDPOINT", server.URL)
+	t.Setenv("S3_ACCESS_KEY_ID", "access-key")
+	t.Setenv("S3_SECRET_ACCESS_KEY", "secret-key")
+	t.Setenv("S3_REGION", "us-east-1")
+
+	ctx := context.Background()
+	s3Context := NewS3Context()
+	bucket := "conditional-writes-unsupported"
+	// Whether a bucket supports conditional writes is remembered for the process
+	t.Cleanup(func() { conditionalWritesUnsupported.Delete(bucket) })
+
+	p := newS3Path(s3Context, "s3", bucket, "cluster/.lock", false)
+	if err := p.CreateFile(ctx, bytes.NewReader([]byte("lock")), nil); err != nil {
+		t.Fatalf("unexpected error creating file: %v", err)
+	}
+	if conditionalRequests != 1 {
+		t.Errorf("expected 1 conditional write, got %d", conditionalRequests)
+	}
+	if err := p.CreateFile(ctx, bytes.NewReader([]byte("other")), nil); !os.IsExist(err) {
+		t.Errorf("expected the file to exist, got %v", err)
+	}
+
+	// The store is known not to support conditional writes, so they are not attempted again
+	data, version, err := p.ReadFileVersion(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error reading file: %v", err)
+	}
+	if !strings.Contains(string(data), "lock") {
+		t.Errorf("unexpected file contents %q", data)
+	}
+	if err := p.ReplaceFile(ctx, bytes.NewReader([]byte("renewed")), nil, version); err != nil {
+		t.Fatalf("unexpected error replacing file: %v", err)
+	}
+	if conditionalRequests != 1 {
+		t.Errorf("expected 1 conditional write, got %d", conditionalRequests)
+	}
+	data, err = p.ReadFile(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error reading file: %v", err)
+	}
+	if !strings.Contains(string(data), "renewed") {
+		t.Errorf("unexpected file contents %q", data)
+	}
+}
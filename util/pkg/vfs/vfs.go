@@ -18,6 +18,9 @@ package vfs
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"strings"
@@ -84,6 +87,28 @@ type TerraformPath interface {
 	RenderTerraform(writer *terraformWriter.TerraformWriter, name string, data io.Reader, acl ACL) error
 }
 
+// ErrVersionMismatch is returned by ConditionalPath::ReplaceFile if the file was changed or removed since its version was read.
+var ErrVersionMismatch = errors.New("file was changed since it was read")
+
+// ConditionalPath is a Path whose store supports conditional writes, so that processes that read,
+// modify and write the same file do not overwrite each other's changes.
+type ConditionalPath interface {
+	Path
+
+	// ReadFileVersion returns the contents of the file and an opaque version identifying them.
+	// If the file did not exist, err = os.ErrNotExist
+	ReadFileVersion(ctx context.Context) ([]byte, string, error)
+	// ReplaceFile writes the file contents, but only if the file still has the specified version;
+	// otherwise it returns ErrVersionMismatch.
+	ReplaceFile(ctx context.Context, data io.ReadSeeker, acl ACL, version string) error
+}
+
+// contentVersion is the version of a file in stores that do not version files, the hash of its contents.
+func contentVersion(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
 type HasHash interface {
 	// Returns the hash of the file contents, with the preferred hash algorithm
 	PreferredHash() (*hashing.Hash, error)